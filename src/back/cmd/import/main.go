@@ -2,195 +2,245 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/CreateLab/laritmo/internal/config"
 	"github.com/CreateLab/laritmo/internal/database"
-	sq "github.com/Masterminds/squirrel"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/services"
 )
 
-const repoPath = "../../tmp/AspITMO" 
+type importFlags struct {
+	configPath    string
+	repoPath      string
+	ref           string
+	courseID      int
+	createCourse  bool
+	name          string
+	semester      string
+	description   string
+	lecturesGlob  string
+	labsGlob      string
+	examQuestions string
+	gradeSheets   string
+	githubBase    string
+	prune         bool
+	dryRun        bool
+	jsonOutput    bool
+}
+
+func parseFlags(args []string, output io.Writer) (*importFlags, error) {
+	defaultConfig := os.Getenv("CONFIG_PATH")
+	if defaultConfig == "" {
+		defaultConfig = "configs/config.local.yaml"
+	}
+
+	f := &importFlags{}
+	fs := flag.NewFlagSet("laritmo import", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintln(output, "Usage: laritmo import (--course-id ID | --create-course --name NAME --semester SEMESTER) --repo PATH [flags]")
+		fmt.Fprintln(output)
+		fmt.Fprintln(output, "Upserts lectures, labs, exam questions and grade sheets of a course from a git repository.")
+		fmt.Fprintln(output)
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&f.configPath, "config", defaultConfig, "path to config file (env CONFIG_PATH)")
+	fs.StringVar(&f.repoPath, "repo", "", "course repository: working copy or bare repo (required)")
+	fs.StringVar(&f.ref, "ref", "", "revision to read from a bare repository (default HEAD)")
+	fs.IntVar(&f.courseID, "course-id", 0, "existing course to import into")
+	fs.BoolVar(&f.createCourse, "create-course", false, "find the course by --name and --semester or create it")
+	fs.StringVar(&f.name, "name", "", "course name for --create-course")
+	fs.StringVar(&f.semester, "semester", "", "course semester for --create-course, e.g. 2024-2025")
+	fs.StringVar(&f.description, "description", "", "course description for --create-course")
+	fs.StringVar(&f.lecturesGlob, "lectures-glob", services.DefaultLecturesGlob, "lecture files pattern relative to the repository root")
+	fs.StringVar(&f.labsGlob, "labs-glob", services.DefaultLabsGlob, "lab files pattern relative to the repository root")
+	fs.StringVar(&f.examQuestions, "exam-questions", "", "JSON or CSV file with exam questions relative to the repository root")
+	fs.StringVar(&f.gradeSheets, "grade-sheets", "", "JSON or CSV file with grade sheets relative to the repository root")
+	fs.StringVar(&f.githubBase, "github-base", "", "URL prefix for links to source files, e.g. https://github.com/CreateLab/AspITMO/blob/main")
+	fs.BoolVar(&f.prune, "prune", false, "delete records whose source was removed from the repository")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "print the changes without writing to the database")
+	fs.BoolVar(&f.jsonOutput, "json", false, "print the report as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case f.repoPath == "":
+		return nil, errors.New("--repo is required")
+	case f.courseID != 0 && f.createCourse:
+		return nil, errors.New("--course-id and --create-course are mutually exclusive")
+	case f.courseID == 0 && !f.createCourse:
+		return nil, errors.New("either --course-id or --create-course is required")
+	case f.createCourse && (f.name == "" || f.semester == ""):
+		return nil, errors.New("--create-course requires --name and --semester")
+	}
+
+	return f, nil
+}
 
 func main() {
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	f, err := parseFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
 	slog.SetDefault(logger)
 
 	ctx := context.Background()
 
-	
-	cfg, err := config.Load("configs/config.local.yaml")
+	cfg, err := config.Load(f.configPath)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to load config", "error", err)
 		os.Exit(1)
 	}
 
-	
-	db, err := database.Connect(cfg.Database.DSN())
+	db, err := database.Connect(cfg.Database.GetDriver(), cfg.Database.DSN())
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to connect to database", "error", err)
 		os.Exit(1)
 	}
 	defer db.Close()
 
-	
-	courseID, err := createCourse(ctx, db)
-	if err != nil {
-		slog.ErrorContext(ctx, "Failed to create course", "error", err)
-		os.Exit(1)
-	}
-	slog.InfoContext(ctx, "✅ Course created", "course_id", courseID)
+	courseRepo := repository.NewCourseRepository(db)
 
-	
-	if err := importLectures(ctx, db, courseID); err != nil {
-		slog.ErrorContext(ctx, "Failed to import lectures", "error", err)
+	courseID, courseAction, err := resolveCourse(ctx, courseRepo, f)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to resolve course", "error", err)
 		os.Exit(1)
 	}
 
-	
-	if err := importLabs(ctx, db, courseID); err != nil {
-		slog.ErrorContext(ctx, "Failed to import labs", "error", err)
+	syncService := services.NewContentSyncService(
+		courseRepo,
+		repository.NewLectureRepository(db),
+		repository.NewLabRepository(db),
+		repository.NewExamQuestionRepository(db),
+		repository.NewGradeSheetRepository(db),
+		services.NewAnnouncementService(repository.NewAnnouncementRepository(db), courseRepo),
+		logger,
+	)
+
+	report, err := syncService.Sync(ctx, courseID, services.ContentSyncOptions{
+		RepoPath:          f.repoPath,
+		Ref:               f.ref,
+		LecturesGlob:      f.lecturesGlob,
+		LabsGlob:          f.labsGlob,
+		ExamQuestionsFile: f.examQuestions,
+		GradeSheetsFile:   f.gradeSheets,
+		GithubBase:        f.githubBase,
+		Prune:             f.prune,
+		DryRun:            f.dryRun,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to import course content", "error", err)
 		os.Exit(1)
 	}
 
-	slog.InfoContext(ctx, "🎉 Import completed successfully!")
-}
-
-func createCourse(ctx context.Context, db *sql.DB) (int64, error) {
-	query, args, _ := sq.Insert("courses").
-		Columns("name", "semester", "description").
-		Values("ASP.NET Core", "2024-2025", "Основы разработки Web API на платформе ASP.NET Core").
-		ToSql()
-
-	result, err := db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return 0, fmt.Errorf("failed to create course: %w", err)
+	if f.jsonOutput {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+		return
 	}
-
-	return result.LastInsertId()
+	printSummary(os.Stdout, courseAction, report)
 }
 
-func importLectures(ctx context.Context, db *sql.DB, courseID int64) error {
-	lecturesPath := filepath.Join(repoPath, "lections")
-
-	
-	files, err := filepath.Glob(filepath.Join(lecturesPath, "L*.md"))
-	if err != nil {
-		return err
-	}
-
-	slog.InfoContext(ctx, "📚 Found lectures", "count", len(files))
-
-	for _, file := range files {
-		
-		weekNum := extractNumber(filepath.Base(file), "L")
-
-		
-		content, err := os.ReadFile(file)
+// resolveCourse возвращает курс для импорта. --create-course повторно использует курс
+// с теми же названием и семестром, поэтому повторный импорт не создаёт дубликатов.
+func resolveCourse(ctx context.Context, repo *repository.CourseRepository, f *importFlags) (int, string, error) {
+	if !f.createCourse {
+		course, err := repo.GetByID(ctx, f.courseID)
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", file, err)
+			return 0, "", err
 		}
-
-		
-		title := extractTitle(string(content))
-
-		// GitHub URL
-		githubURL := fmt.Sprintf("https://github.com/CreateLab/AspITMO/blob/main/lections/%s", filepath.Base(file))
-
-		
-		query, args, _ := sq.Insert("lectures").
-			Columns("course_id", "week", "title", "content", "github_url").
-			Values(courseID, weekNum, title, string(content), githubURL).
-			ToSql()
-
-		_, err = db.ExecContext(ctx, query, args...)
-		if err != nil {
-			return fmt.Errorf("failed to insert lecture %s: %w", file, err)
+		if course == nil {
+			return 0, "", fmt.Errorf("course %d not found", f.courseID)
 		}
-
-		slog.InfoContext(ctx, "  ✓ Lecture imported", "week", weekNum, "title", truncate(title, 50))
+		return course.ID, fmt.Sprintf("course %d %q (%s)", course.ID, course.Name, course.Semester), nil
 	}
 
-	return nil
-}
-
-func importLabs(ctx context.Context, db *sql.DB, courseID int64) error {
-	
-	files, err := filepath.Glob(filepath.Join(repoPath, "Lab*.md"))
+	course, err := repo.FindByNameAndSemester(ctx, f.name, f.semester)
 	if err != nil {
-		return err
+		return 0, "", err
 	}
 
-	slog.InfoContext(ctx, "🔬 Found labs", "count", len(files))
-
-	for _, file := range files {
-		
-		labNum := extractNumber(filepath.Base(file), "Lab")
-
-		
-		content, err := os.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", file, err)
+	if course == nil {
+		if f.dryRun {
+			return 0, fmt.Sprintf("course %q (%s) would be created", f.name, f.semester), nil
 		}
-
-		
-		title := extractTitle(string(content))
-
-		// GitHub URL
-		githubURL := fmt.Sprintf("https://github.com/CreateLab/AspITMO/blob/main/%s", filepath.Base(file))
-
-		
-		query, args, _ := sq.Insert("labs").
-			Columns("course_id", "number", "title", "description", "max_score", "github_url").
-			Values(courseID, labNum, title, string(content), 100, githubURL).
-			ToSql()
-
-		_, err = db.ExecContext(ctx, query, args...)
+		created, err := repo.Create(ctx, f.name, f.semester, f.description)
 		if err != nil {
-			return fmt.Errorf("failed to insert lab %s: %w", file, err)
+			return 0, "", fmt.Errorf("failed to create course: %w", err)
 		}
-
-		slog.InfoContext(ctx, "  ✓ Lab imported", "number", labNum, "title", truncate(title, 50))
+		return created.ID, fmt.Sprintf("course %d %q (%s) created", created.ID, f.name, f.semester), nil
 	}
 
-	return nil
-}
-
-
-func extractTitle(content string) string {
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "# ") {
-			return strings.TrimPrefix(line, "# ")
+	label := fmt.Sprintf("course %d %q (%s)", course.ID, course.Name, course.Semester)
+	if f.description == "" || f.description == course.Description {
+		return course.ID, label + " unchanged", nil
+	}
+	if !f.dryRun {
+		if err := repo.Update(ctx, course.ID, course.Name, course.Semester, f.description); err != nil {
+			return 0, "", fmt.Errorf("failed to update course: %w", err)
 		}
 	}
-	return "Untitled"
+	return course.ID, label + " updated", nil
 }
 
+func printSummary(w io.Writer, courseAction string, report *models.ContentSyncReport) {
+	if report.DryRun {
+		fmt.Fprintln(w, "Dry run: no changes were written")
+	}
+	fmt.Fprintf(w, "Course:         %s\n", courseAction)
+	printDiff(w, "Lectures", report.Lectures)
+	printDiff(w, "Labs", report.Labs)
+	printCounts(w, "Exam questions", report.ExamQuestions)
+	printCounts(w, "Grade sheets", report.GradeSheets)
+}
 
-func extractNumber(filename, prefix string) int {
-	re := regexp.MustCompile(prefix + `(\d+)\.md`)
-	matches := re.FindStringSubmatch(filename)
-	if len(matches) > 1 {
-		var num int
-		fmt.Sscanf(matches[1], "%d", &num)
-		return num
+func printDiff(w io.Writer, title string, diff models.ContentSyncDiff) {
+	fmt.Fprintf(w, "%-15s created %d, updated %d, unchanged %d, deleted %d, stale %d\n",
+		title+":", len(diff.Created), len(diff.Updated), len(diff.Unchanged), len(diff.Deleted), len(diff.Stale))
+	printNumbers(w, "created", diff.Created)
+	printNumbers(w, "updated", diff.Updated)
+	printNumbers(w, "deleted", diff.Deleted)
+	printNumbers(w, "stale", diff.Stale)
+	if len(diff.Skipped) > 0 {
+		fmt.Fprintf(w, "  skipped: %s\n", strings.Join(diff.Skipped, ", "))
 	}
-	return 0
 }
 
+func printNumbers(w io.Writer, label string, numbers []int) {
+	if len(numbers) == 0 {
+		return
+	}
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = fmt.Sprint(n)
+	}
+	fmt.Fprintf(w, "  %s: %s\n", label, strings.Join(parts, ", "))
+}
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+func printCounts(w io.Writer, title string, counts *models.ContentSyncCounts) {
+	if counts == nil {
+		return
 	}
-	return s[:max] + "..."
+	fmt.Fprintf(w, "%-15s created %d, updated %d, unchanged %d, deleted %d, stale %d\n",
+		title+":", counts.Created, counts.Updated, counts.Unchanged, counts.Deleted, counts.Stale)
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/backup"
+)
+
+// schemaVersion возвращает версию последней применённой встроенной миграции
+func (a *app) schemaVersion(ctx context.Context) (int64, error) {
+	runner, err := a.migrator("")
+	if err != nil {
+		return 0, err
+	}
+	return runner.Version(ctx)
+}
+
+func backupCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("backup create", "[--out FILE]")
+	out := fs.String("out", "", "archive to write (default: laritmo-backup-YYYYMMDD-HHMMSS.zip in the current directory)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = "laritmo-backup-" + time.Now().Format("20060102-150405") + ".zip"
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+	version, err := a.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Архив пишется во временный файл рядом и переименовывается только целиком;
+	// в нём хэши паролей, поэтому доступ только у владельца
+	tmp, err := os.CreateTemp(filepath.Dir(*out), filepath.Base(*out)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	tables, err := backup.Write(ctx, db, tmp, version)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), *out); err != nil {
+		return fmt.Errorf("failed to save backup file: %w", err)
+	}
+
+	fmt.Fprintf(a.stdout, "Backup written to %s (schema version %d)\n", *out, version)
+	return printTableSummaries(a, tables)
+}
+
+func backupRestore(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("backup restore", "--file FILE")
+	file := fs.String("file", "", "archive created by backup create (required)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usageErrorf("--file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+	version, err := a.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	tables, err := backup.Restore(ctx, db, f, info.Size(), version)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Restored %s; row counts and course references verified\n", *file)
+	return printTableSummaries(a, tables)
+}
+
+func printTableSummaries(a *app, tables []backup.TableSummary) error {
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS\tSHA-256")
+	for _, t := range tables {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", t.Name, t.Rows, t.SHA256)
+	}
+	return w.Flush()
+}
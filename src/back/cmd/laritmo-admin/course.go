@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+func courseList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("course list", "[--semester SEMESTER] [--status all|active|archived] [--json]")
+	semester := fs.String("semester", "", "show only courses of this semester")
+	status := fs.String("status", "all", "all, active or archived")
+	jsonOutput := fs.Bool("json", false, "print courses as JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	filter := repository.CourseFilter{Semester: *semester}
+	switch *status {
+	case "all":
+	case "active", "archived":
+		archived := *status == "archived"
+		filter.Archived = &archived
+	default:
+		return usageErrorf("--status must be all, active or archived")
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+
+	courses, _, err := repository.NewCourseRepository(db).GetAll(ctx, filter, repository.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		if courses == nil {
+			courses = []models.Course{}
+		}
+		return printJSON(a, courses)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEMESTER\tNAME\tSTATUS")
+	for _, c := range courses {
+		status := "active"
+		if c.ArchivedAt != nil {
+			status = "archived " + c.ArchivedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", c.ID, c.Semester, c.Name, status)
+	}
+	return w.Flush()
+}
+
+func courseClone(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("course clone", "--id ID --semester SEMESTER [--name NAME] [--shift-days N] [--with-grade-sheets]")
+	id := fs.Int("id", 0, "course to copy (required)")
+	semester := fs.String("semester", "", "semester of the copy, e.g. 2025-2026 (required)")
+	name := fs.String("name", "", "name of the copy (default: same as the source)")
+	description := fs.String("description", "", "description of the copy (default: same as the source)")
+	shiftDays := fs.Int("shift-days", 0, "move lab deadlines by this many days, e.g. 364 to keep weekdays")
+	withGradeSheets := fs.Bool("with-grade-sheets", false, "copy grade sheet links as well")
+	jsonOutput := fs.Bool("json", false, "print the summary as JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return usageErrorf("--id is required")
+	}
+	if *semester == "" {
+		return usageErrorf("--semester is required")
+	}
+
+	opts := repository.CourseCloneOptions{
+		Name:              *name,
+		Semester:          *semester,
+		DeadlineShiftDays: *shiftDays,
+		WithGradeSheets:   *withGradeSheets,
+	}
+	if *description != "" {
+		opts.Description = description
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+
+	summary, err := repository.NewCourseRepository(db).Clone(ctx, *id, opts)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		return fmt.Errorf("course %d not found", *id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return printJSON(a, summary)
+	}
+
+	fmt.Fprintf(a.stdout, "Cloned course %d into %d %q (%s)\n", summary.SourceID, summary.Course.ID, summary.Course.Name, summary.Course.Semester)
+	fmt.Fprintf(a.stdout, "Lectures: %d, labs: %d, exam questions: %d, grade sheets: %d\n",
+		summary.Lectures, summary.Labs, summary.ExamQuestions, summary.GradeSheets)
+	return nil
+}
+
+func courseArchive(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("course archive", "--id ID [--restore]")
+	id := fs.Int("id", 0, "course to archive (required)")
+	restore := fs.Bool("restore", false, "return an archived course to the active list")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return usageErrorf("--id is required")
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+
+	repo := repository.NewCourseRepository(db)
+	course, err := repo.GetByID(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if course == nil {
+		return fmt.Errorf("course %d not found", *id)
+	}
+
+	if (course.ArchivedAt != nil) != *restore {
+		state := "active"
+		if course.ArchivedAt != nil {
+			state = "archived"
+		}
+		fmt.Fprintf(a.stdout, "Course %d %q is already %s\n", course.ID, course.Name, state)
+		return nil
+	}
+	if err := repo.SetArchived(ctx, course.ID, !*restore); err != nil {
+		return err
+	}
+
+	if *restore {
+		fmt.Fprintf(a.stdout, "Course %d %q restored from the archive\n", course.ID, course.Name)
+		return nil
+	}
+	fmt.Fprintf(a.stdout, "Course %d %q archived\n", course.ID, course.Name)
+	return nil
+}
@@ -0,0 +1,211 @@
+// laritmo-admin - утилита администрирования: пользователи, курсы, миграции и резервные копии базы.
+//
+//	laritmo-admin [--config PATH] <group> <command> [flags]
+//
+// Все команды работают без диалогов, если параметры переданы флагами
+// и переменными окружения, поэтому их можно вызывать из скриптов деплоя.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/CreateLab/laritmo/internal/config"
+	"github.com/CreateLab/laritmo/internal/database"
+)
+
+type command struct {
+	summary string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+var groups = map[string]map[string]command{
+	"user": {
+		"create":         {"create a user", userCreate},
+		"list":           {"list users", userList},
+		"set-role":       {"change the role of a user", userSetRole},
+		"reset-password": {"set a new password for a user", userResetPassword},
+		"disable":        {"block or unblock login of a user", userDisable},
+	},
+	"course": {
+		"list":    {"list courses", courseList},
+		"clone":   {"copy a course with its content into a new semester", courseClone},
+		"archive": {"move a course to the archive or restore it", courseArchive},
+	},
+	"backup": {
+		"create":  {"write all courses, materials and users to a checksummed archive", backupCreate},
+		"restore": {"load an archive into an empty database and verify it", backupRestore},
+	},
+	"migrate": {
+		"up":     {"apply all pending migrations", migrateUp},
+		"down":   {"roll back the last applied migration", migrateDown},
+		"status": {"show applied and pending migrations", migrateStatus},
+	},
+}
+
+// usageError - неверные аргументы командной строки; завершает процесс с кодом 2
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+type app struct {
+	configPath string
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+	getenv     func(string) string
+
+	cfg *config.Config
+	db  *database.DB
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a := &app{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	err := a.run(ctx, os.Args[1:])
+	a.close()
+
+	var usage *usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.As(err, &usage):
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	defaultConfig := a.getenv("CONFIG_PATH")
+	if defaultConfig == "" {
+		defaultConfig = "configs/config.local.yaml"
+	}
+
+	fs := flag.NewFlagSet("laritmo-admin", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.configPath, "config", defaultConfig, "path to config file (env CONFIG_PATH)")
+	fs.Usage = func() { a.usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		a.usage(fs)
+		return usageErrorf("command is required")
+	}
+
+	group, ok := groups[args[0]]
+	if !ok {
+		return usageErrorf("unknown command %q, see laritmo-admin --help", args[0])
+	}
+	if len(args) < 2 {
+		return usageErrorf("%s requires a subcommand: %s", args[0], strings.Join(sortedKeys(group), ", "))
+	}
+
+	cmd, ok := group[args[1]]
+	if !ok {
+		return usageErrorf("unknown command %q, expected one of: %s", args[0]+" "+args[1], strings.Join(sortedKeys(group), ", "))
+	}
+
+	return cmd.run(ctx, a, args[2:])
+}
+
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "Usage: laritmo-admin [--config PATH] <command> <subcommand> [flags]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Commands:")
+	for _, name := range sortedKeys(groups) {
+		for _, sub := range sortedKeys(groups[name]) {
+			fmt.Fprintf(a.stderr, "  %-24s %s\n", name+" "+sub, groups[name][sub].summary)
+		}
+	}
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Run laritmo-admin <command> <subcommand> --help for command flags.")
+	fmt.Fprintln(a.stderr)
+	fs.PrintDefaults()
+}
+
+// flags создаёт набор флагов подкоманды с единообразной справкой
+func (a *app) flags(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet("laritmo-admin "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: laritmo-admin %s %s\n\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse разбирает флаги подкоманды и запрещает лишние позиционные аргументы
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	return nil
+}
+
+// open загружает конфиг и подключается к базе при первом обращении
+func (a *app) open() (*database.DB, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database.GetDriver(), cfg.Database.DSN())
+	if err != nil {
+		return nil, err
+	}
+
+	a.cfg = cfg
+	a.db = db
+	return db, nil
+}
+
+func (a *app) close() {
+	if a.db != nil {
+		a.db.Close()
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
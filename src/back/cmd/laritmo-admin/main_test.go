@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(stdin string, env map[string]string) (*app, *bytes.Buffer) {
+	var stderr bytes.Buffer
+	return &app{
+		stdin:  strings.NewReader(stdin),
+		stdout: &bytes.Buffer{},
+		stderr: &stderr,
+		getenv: func(key string) string { return env[key] },
+	}, &stderr
+}
+
+// Ошибки аргументов должны обнаруживаться до подключения к базе
+func TestRun_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no command", nil},
+		{"unknown group", []string{"lecture", "list"}},
+		{"missing subcommand", []string{"user"}},
+		{"unknown subcommand", []string{"user", "delete"}},
+		{"create without username", []string{"user", "create", "--email", "a@b.c"}},
+		{"create with invalid role", []string{"user", "create", "--username", "a", "--email", "a@b.c", "--role", "root"}},
+		{"set-role without role", []string{"user", "set-role", "--username", "a"}},
+		{"unknown flag", []string{"user", "list", "--verbose"}},
+		{"extra argument", []string{"course", "list", "2024"}},
+		{"clone without semester", []string{"course", "clone", "--id", "1"}},
+		{"list with invalid status", []string{"course", "list", "--status", "deleted"}},
+		{"restore without file", []string{"backup", "restore"}},
+		{"create without password source", []string{"user", "create", "--username", "a", "--email", "a@b.c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := newTestApp("", nil)
+
+			err := a.run(context.Background(), tt.args)
+
+			var usage *usageError
+			assert.True(t, errors.As(err, &usage), "expected usage error, got %v", err)
+			assert.Nil(t, a.db)
+		})
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	a, stderr := newTestApp("", nil)
+
+	err := a.run(context.Background(), []string{"--help"})
+
+	assert.ErrorIs(t, err, flag.ErrHelp)
+	assert.Contains(t, stderr.String(), "user reset-password")
+	assert.Contains(t, stderr.String(), "migrate status")
+}
+
+func TestReadPassword(t *testing.T) {
+	t.Run("from stdin", func(t *testing.T) {
+		a, _ := newTestApp("correct horse\r\nnext line\n", map[string]string{passwordEnv: "ignored-env"})
+
+		password, err := a.readPassword(true, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, "correct horse", password)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		a, _ := newTestApp("", map[string]string{passwordEnv: "from-env-123"})
+
+		password, err := a.readPassword(false, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, "from-env-123", password)
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		a, _ := newTestApp("short\n", nil)
+
+		_, err := a.readPassword(true, false)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("empty stdin", func(t *testing.T) {
+		a, _ := newTestApp("", nil)
+
+		_, err := a.readPassword(true, false)
+
+		assert.Error(t, err)
+	})
+}
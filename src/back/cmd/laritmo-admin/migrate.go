@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/migrate"
+	"github.com/CreateLab/laritmo/migrations"
+)
+
+func migrateFlags(a *app, name string) (*flag.FlagSet, *string) {
+	fs := a.flags("migrate "+name, "[--dir DIR]")
+	dir := fs.String("dir", "", "directory with goose-style *.sql migrations for the configured driver (default: migrations built into the binary)")
+	return fs, dir
+}
+
+func (a *app) migrator(dir string) (*migrate.Runner, error) {
+	db, err := a.open()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		fsys, err := migrations.For(db.Dialect)
+		if err != nil {
+			return nil, err
+		}
+		return migrate.NewRunner(db, fsys)
+	}
+	return migrate.NewRunner(db, os.DirFS(dir))
+}
+
+func migrateUp(ctx context.Context, a *app, args []string) error {
+	fs, dir := migrateFlags(a, "up")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	runner, err := a.migrator(*dir)
+	if err != nil {
+		return err
+	}
+
+	applied, err := runner.Up(ctx)
+	for _, m := range applied {
+		fmt.Fprintf(a.stdout, "Applied %d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(a.stdout, "No pending migrations")
+	}
+	return nil
+}
+
+func migrateDown(ctx context.Context, a *app, args []string) error {
+	fs, dir := migrateFlags(a, "down")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	runner, err := a.migrator(*dir)
+	if err != nil {
+		return err
+	}
+
+	m, err := runner.Down(ctx)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		fmt.Fprintln(a.stdout, "No applied migrations")
+		return nil
+	}
+	fmt.Fprintf(a.stdout, "Rolled back %d_%s\n", m.Version, m.Name)
+	return nil
+}
+
+func migrateStatus(ctx context.Context, a *app, args []string) error {
+	fs, dir := migrateFlags(a, "status")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	runner, err := a.migrator(*dir)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
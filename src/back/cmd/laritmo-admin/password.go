@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// passwordEnv - переменная окружения с паролем для неинтерактивных скриптов
+const passwordEnv = "LARITMO_ADMIN_PASSWORD"
+
+const minPasswordLength = 8
+
+// readPassword берёт пароль из stdin (--password-stdin), из LARITMO_ADMIN_PASSWORD
+// или спрашивает в терминале без эха. Пароль намеренно нельзя передать флагом:
+// аргументы видны в списке процессов и истории shell.
+func (a *app) readPassword(fromStdin, confirm bool) (string, error) {
+	var password string
+	switch {
+	case fromStdin:
+		line, err := readLine(a.stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		password = line
+	case a.getenv(passwordEnv) != "":
+		password = a.getenv(passwordEnv)
+	default:
+		f, ok := a.stdin.(*os.File)
+		if !ok || !term.IsTerminal(int(f.Fd())) {
+			return "", usageErrorf("password required: use --password-stdin, set %s or run in a terminal", passwordEnv)
+		}
+
+		var err error
+		if password, err = a.prompt(f, "Password: "); err != nil {
+			return "", err
+		}
+		if confirm {
+			repeated, err := a.prompt(f, "Repeat password: ")
+			if err != nil {
+				return "", err
+			}
+			if repeated != password {
+				return "", errors.New("passwords do not match")
+			}
+		}
+	}
+
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, nil
+}
+
+func (a *app) prompt(f *os.File, label string) (string, error) {
+	fmt.Fprint(a.stderr, label)
+	password, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(a.stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
+
+// readLine читает одну строку без буферизации сверх неё, чтобы не съесть следующие данные stdin
+func readLine(r io.Reader) (string, error) {
+	var sb strings.Builder
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			sb.WriteByte(buf[0])
+		}
+		if errors.Is(err, io.EOF) {
+			if sb.Len() == 0 {
+				return "", io.ErrUnexpectedEOF
+			}
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(sb.String(), "\r"), nil
+}
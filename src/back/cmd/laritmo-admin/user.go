@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func validRole(role string) bool {
+	return role == models.RoleAdmin || role == models.RoleStudent
+}
+
+func userCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("user create", "--username NAME --email EMAIL [--role admin|student] [--password-stdin]")
+	username := fs.String("username", "", "login name (required)")
+	email := fs.String("email", "", "email address (required)")
+	role := fs.String("role", models.RoleStudent, "role: admin or student")
+	fromStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin instead of "+passwordEnv+" or a prompt")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	switch {
+	case *username == "":
+		return usageErrorf("--username is required")
+	case *email == "" || !strings.Contains(*email, "@"):
+		return usageErrorf("--email is required and must be an email address")
+	case !validRole(*role):
+		return usageErrorf("--role must be admin or student")
+	}
+
+	password, err := a.readPassword(*fromStdin, true)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("password hashing error: %w", err)
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+
+	user, err := repository.NewUserRepository(db).Create(ctx, *username, *email, string(hash), *role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Created %s %q (id %d, %s)\n", user.Role, user.Username, user.ID, user.Email)
+	return nil
+}
+
+func userList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("user list", "[--role admin|student] [--json]")
+	role := fs.String("role", "", "show only users with this role")
+	jsonOutput := fs.Bool("json", false, "print users as JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *role != "" && !validRole(*role) {
+		return usageErrorf("--role must be admin or student")
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+
+	users, _, err := repository.NewUserRepository(db).GetAll(ctx, repository.UserFilter{Role: *role}, repository.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		if users == nil {
+			users = []models.User{}
+		}
+		return printJSON(a, users)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tROLE\tSTATUS\tCREATED")
+	for _, u := range users {
+		status := "active"
+		if u.DisabledAt != nil {
+			status = "disabled"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", u.ID, u.Username, u.Email, u.Role, status, u.CreatedAt.Format("2006-01-02"))
+	}
+	return w.Flush()
+}
+
+func userSetRole(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("user set-role", "--username NAME --role admin|student")
+	username := fs.String("username", "", "login name (required)")
+	role := fs.String("role", "", "new role: admin or student (required)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageErrorf("--username is required")
+	}
+	if !validRole(*role) {
+		return usageErrorf("--role must be admin or student")
+	}
+
+	repo, user, err := a.findUser(ctx, *username)
+	if err != nil {
+		return err
+	}
+	if user.Role == *role {
+		fmt.Fprintf(a.stdout, "User %q is already %s\n", user.Username, user.Role)
+		return nil
+	}
+	if err := repo.SetRole(ctx, user.ID, *role); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "User %q: role %s -> %s\n", user.Username, user.Role, *role)
+	return nil
+}
+
+func userResetPassword(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("user reset-password", "--username NAME [--password-stdin]")
+	username := fs.String("username", "", "login name (required)")
+	fromStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin instead of "+passwordEnv+" or a prompt")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageErrorf("--username is required")
+	}
+
+	repo, user, err := a.findUser(ctx, *username)
+	if err != nil {
+		return err
+	}
+
+	password, err := a.readPassword(*fromStdin, true)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("password hashing error: %w", err)
+	}
+	if err := repo.SetPasswordHash(ctx, user.ID, string(hash)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Password of %q updated\n", user.Username)
+	return nil
+}
+
+func userDisable(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("user disable", "--username NAME [--enable]")
+	username := fs.String("username", "", "login name (required)")
+	enable := fs.Bool("enable", false, "unblock a previously disabled user")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageErrorf("--username is required")
+	}
+
+	repo, user, err := a.findUser(ctx, *username)
+	if err != nil {
+		return err
+	}
+	if err := repo.SetDisabled(ctx, user.ID, !*enable); err != nil {
+		return err
+	}
+
+	if *enable {
+		fmt.Fprintf(a.stdout, "User %q enabled\n", user.Username)
+		return nil
+	}
+	fmt.Fprintf(a.stdout, "User %q disabled\n", user.Username)
+	return nil
+}
+
+func (a *app) findUser(ctx context.Context, username string) (*repository.UserRepository, *models.User, error) {
+	db, err := a.open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := repository.NewUserRepository(db)
+	user, err := repo.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("user %q not found", username)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return repo, user, nil
+}
+
+func printJSON(a *app, v any) error {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
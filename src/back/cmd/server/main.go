@@ -9,14 +9,15 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Часовые пояса для расписаний, даже если в образе нет системной базы tzdata
+	_ "time/tzdata"
 
 	_ "github.com/CreateLab/laritmo/docs"
 	"github.com/CreateLab/laritmo/internal/auth"
 	"github.com/CreateLab/laritmo/internal/config"
-	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/handlers"
+	"github.com/CreateLab/laritmo/internal/jobs"
 	"github.com/CreateLab/laritmo/internal/middleware"
-	"github.com/CreateLab/laritmo/internal/repository"
 	"github.com/CreateLab/laritmo/internal/services"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -90,31 +91,94 @@ func main() {
 		slog.InfoContext(ctx, "ℹ️ New Relic APM is disabled")
 	}
 
-	db, err := database.Connect(cfg.Database.DSN())
+	store, closeStore, err := openStore(ctx, cfg)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to connect to database", "error", err)
+		slog.ErrorContext(ctx, "Failed to open storage", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
-	courseRepo := repository.NewCourseRepository(db)
-	lectureRepo := repository.NewLectureRepository(db)
-	labRepo := repository.NewLabRepository(db)
-	gradeSheetRepo := repository.NewGradeSheetRepository(db)
-	examQuestionRepo := repository.NewExamQuestionRepository(db)
-	userRepo := repository.NewUserRepository(db)
+	defer closeStore()
 
 	jwtManager := auth.NewJWTManager(cfg.Auth.JWTSecret, cfg.Auth.JWTExpirationHours)
-	courseHandler := handlers.NewCourseHandler(courseRepo, logger)
-	lectureHandler := handlers.NewLectureHandler(lectureRepo, logger)
-	labHandler := handlers.NewLabHandler(labRepo, logger)
-	gradeSheetHandler := handlers.NewGradeSheetHandler(gradeSheetRepo, logger)
-	examQuestionHandler := handlers.NewExamQuestionHandler(examQuestionRepo, logger)
-
-	ticketService := services.NewTicketService(examQuestionRepo) // examQuestionRepo реализует ExamQuestionRepositoryInterface
+	courseHandler := handlers.NewCourseHandler(store.Courses, logger)
+	courseArchiveHandler := handlers.NewCourseArchiveHandler(
+		services.NewCourseArchiveService(store.Courses, store.Lectures, store.Labs, store.ExamQuestions, store.GradeSheets),
+		logger,
+	)
+	markdownService := services.NewMarkdownService()
+	announcementService := services.NewAnnouncementService(store.Announcements, store.Courses)
+	announcementHandler := handlers.NewAnnouncementHandler(announcementService, logger)
+	calendarLocation, err := cfg.Calendar.GetLocation()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load calendar timezone", "error", err)
+		os.Exit(1)
+	}
+	mailSender, err := openMailSender(cfg.Notifications, logger)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to open mail transport", "error", err)
+		os.Exit(1)
+	}
+	notificationService := services.NewNotificationService(
+		store.Notifications, store.Labs, store.GradeSheets, store.Courses, store.Jobs,
+		cfg.Notifications.GetFrom(), cfg.Jobs.GetMaxAttempts(), calendarLocation, logger,
+	)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, logger)
+	lectureHandler := handlers.NewLectureHandler(store.Lectures, markdownService, announcementService, logger)
+	labHandler := handlers.NewLabHandler(store.Labs, markdownService, announcementService, notificationService, logger)
+	gradeSheetHandler := handlers.NewGradeSheetHandler(store.GradeSheets, notificationService, logger)
+	examQuestionHandler := handlers.NewExamQuestionHandler(store.ExamQuestions, logger)
+
+	ticketService := services.NewTicketService(store.ExamQuestions)
 	documentService := services.NewDocumentService()
-	ticketHandler := handlers.NewTicketHandler(ticketService, documentService, courseRepo, logger)
+	ticketHandler := handlers.NewTicketHandler(ticketService, documentService, store.Courses, logger)
+
+	searchHandler := handlers.NewSearchHandler(services.NewSearchService(store.Search), logger)
+
+	contentSyncService := services.NewContentSyncService(store.Courses, store.Lectures, store.Labs, store.ExamQuestions, store.GradeSheets, announcementService, logger)
+	contentSyncHandler := handlers.NewContentSyncHandler(contentSyncService, cfg.Sync.ReposRoot, logger)
+
+	authHandler := handlers.NewAuthHandler(store.Users, jwtManager, logger)
+	jobHandler := handlers.NewJobHandler(store.Jobs, logger)
+	auditService := services.NewAuditService(store.AuditLog, map[string]services.AuditSnapshot{
+		"course":        services.SnapshotOf(store.Courses.GetByID),
+		"lecture":       services.SnapshotOf(store.Lectures.GetByID),
+		"lab":           services.SnapshotOf(store.Labs.GetByID),
+		"grade_sheet":   services.SnapshotOf(store.GradeSheets.GetByID),
+		"exam_question": services.SnapshotOf(store.ExamQuestions.GetByID),
+		"attachment":    services.SnapshotOf(store.Attachments.GetByID),
+		"announcement":  services.SnapshotOf(store.Announcements.GetByID),
+	})
+	auditHandler := handlers.NewAuditHandler(auditService, logger)
+	trashService := services.NewTrashService(store.Trash, cfg.Trash.GetRetention(), logger)
+	trashHandler := handlers.NewTrashHandler(trashService, logger)
+	publicationService := services.NewPublicationService(store.Publication, announcementService, notificationService, logger)
+	attachmentStorage, err := openAttachmentStorage(cfg.Attachments)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to open attachment storage", "error", err)
+		os.Exit(1)
+	}
+	attachmentService := services.NewAttachmentService(
+		store.Attachments, store.Courses, store.Lectures, store.Labs, attachmentStorage,
+		services.AttachmentLimits{MaxFileSize: cfg.Attachments.GetMaxFileSize(), CourseQuota: cfg.Attachments.GetCourseQuota()},
+		logger,
+	)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, logger)
+	scheduleHandler := handlers.NewScheduleHandler(
+		services.NewScheduleService(store.Schedules, store.Courses, store.Lectures, store.Labs, calendarLocation),
+		logger,
+	)
+	ticketBatchHandler := handlers.NewTicketBatchHandler(store.Courses, store.Jobs, cfg.Jobs.GetMaxAttempts(), logger)
+
+	jobPool := jobs.NewPool(store.Jobs, logger, jobs.Options{
+		Workers:           cfg.Jobs.GetWorkers(),
+		PollInterval:      cfg.Jobs.GetPollInterval(),
+		VisibilityTimeout: cfg.Jobs.GetVisibilityTimeout(),
+	})
+	jobPool.Register(services.TicketDocumentJobType, services.NewTicketDocumentJob(ticketService, documentService, store.Courses, store.Jobs, logger).Handle)
+	jobPool.Register(services.NotificationEmailJobType, services.NewNotificationEmailJob(mailSender, logger).Handle)
+	jobPool.Register(services.ContentSyncJobType, services.NewContentSyncJob(&cfg.Sync, services.GitRepoUpdater{}, contentSyncService, store.ContentSyncEvents, logger).Handle)
 
-	authHandler := handlers.NewAuthHandler(userRepo, jwtManager, logger)
+	webhookService := services.NewWebhookService(&cfg.Sync, store.ContentSyncEvents, store.Jobs, cfg.Jobs.GetMaxAttempts())
+	webhookHandler := handlers.NewWebhookHandler(webhookService, store.ContentSyncEvents, logger)
 
 	gin.SetMode(cfg.Server.Mode)
 	r := gin.Default()
@@ -128,25 +192,34 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "https://localhost:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-None-Match", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-Total-Count", "ETag", "X-Request-ID"},
 		AllowCredentials: true,
 	}))
 
+	r.Use(middleware.RequestID())
+
+	// Тайм-аут ставится на группы маршрутов: загрузки, архивы и выгрузки ниже длятся дольше
+	queryTimeout := middleware.QueryTimeout(cfg.Database.GetQueryTimeout())
+
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
 	api := r.Group("/api")
+	api.Use(queryTimeout)
+	api.Use(middleware.ETag())
 
 	api.GET("/courses", courseHandler.GetAll)
 	api.GET("/courses/:id", courseHandler.GetByID)
 
 	api.GET("/lectures", lectureHandler.GetAll)
 	api.GET("/lectures/:id", lectureHandler.GetByID)
+	api.GET("/lectures/:id/attachments", attachmentHandler.ListByLecture)
 
 	api.GET("/labs", labHandler.GetAll)
 	api.GET("/labs/:id", labHandler.GetByID)
+	api.GET("/labs/:id/attachments", attachmentHandler.ListByLab)
 
 	api.GET("/grade-sheets", gradeSheetHandler.GetAll)
 	api.GET("/grade-sheets/:id", gradeSheetHandler.GetByID)
@@ -155,38 +228,117 @@ func main() {
 	api.GET("/exam-questions/:id", examQuestionHandler.GetByID)
 
 	api.GET("/courses/:id/tickets/random", ticketHandler.GetRandomTicket)
+	api.GET("/courses/:id/schedule", scheduleHandler.Get)
+	api.GET("/courses/:id/calendar.ics", scheduleHandler.Calendar)
+	api.GET("/courses/:id/announcements", announcementHandler.ListByCourse)
+	api.GET("/courses/:id/feed.atom", announcementHandler.Feed)
+
+	api.GET("/search", searchHandler.Search)
+
+	api.POST("/webhooks/git", webhookHandler.Receive)
+
+	// Файлы отдаются потоком мимо ETag, который буферизует ответ целиком
+	r.GET("/api/attachments/:id", attachmentHandler.Download)
 
 	loginGroup := api.Group("/auth")
 	loginGroup.Use(middleware.RateLimitMiddleware(cfg.Auth.GetRateLimitRequests(), cfg.Auth.GetRateLimitBurst()))
 	loginGroup.POST("/login", authHandler.Login)
 
+	me := r.Group("/api/me")
+	me.Use(queryTimeout)
+	me.Use(middleware.AuthMiddleware(jwtManager, store.Users, logger))
+	{
+		me.GET("/notifications", notificationHandler.GetPreferences)
+		me.PUT("/notifications", notificationHandler.SetPreferences)
+	}
+
+	adminAccess := []gin.HandlerFunc{
+		middleware.AuthMiddleware(jwtManager, store.Users, logger),
+		middleware.AdminOnly(),
+		middleware.Audit("/api/admin", auditService, logger),
+	}
+
 	admin := r.Group("/api/admin")
-	admin.Use(middleware.AuthMiddleware(jwtManager))
-	admin.Use(middleware.AdminOnly())
+	admin.Use(queryTimeout)
+	admin.Use(adminAccess...)
 	{
 		admin.POST("/courses", courseHandler.Create)
 		admin.PUT("/courses/:id", courseHandler.Update)
 		admin.DELETE("/courses/:id", courseHandler.Delete)
+		admin.POST("/courses/:id/clone", courseHandler.Clone)
+		admin.PUT("/courses/:id/schedule", scheduleHandler.Set)
+		admin.DELETE("/courses/:id/schedule", scheduleHandler.Delete)
+		admin.GET("/courses/:id/sync-events", webhookHandler.GetEvents)
 
 		admin.POST("/lectures", lectureHandler.Create)
 		admin.PUT("/lectures/:id", lectureHandler.Update)
+		admin.PUT("/lectures/:id/publication", lectureHandler.SetPublication)
 		admin.DELETE("/lectures/:id", lectureHandler.Delete)
 
 		admin.POST("/labs", labHandler.Create)
 		admin.PUT("/labs/:id", labHandler.Update)
+		admin.PUT("/labs/:id/publication", labHandler.SetPublication)
 		admin.DELETE("/labs/:id", labHandler.Delete)
 
+		admin.DELETE("/attachments/:id", attachmentHandler.Delete)
+		admin.GET("/courses/:id/attachments/usage", attachmentHandler.Usage)
+
+		admin.GET("/announcements", announcementHandler.GetAll)
+		admin.POST("/announcements", announcementHandler.Create)
+		admin.PUT("/announcements/:id", announcementHandler.Update)
+		admin.DELETE("/announcements/:id", announcementHandler.Delete)
+
 		admin.POST("/grade-sheets", gradeSheetHandler.Create)
 		admin.PUT("/grade-sheets/:id", gradeSheetHandler.Update)
+		admin.PUT("/grade-sheets/:id/publication", gradeSheetHandler.SetPublication)
 		admin.DELETE("/grade-sheets/:id", gradeSheetHandler.Delete)
 
 		admin.POST("/exam-questions", examQuestionHandler.Create)
 		admin.POST("/exam-questions/bulk", examQuestionHandler.BulkCreateJSON)
-		admin.POST("/exam-questions/upload", examQuestionHandler.BulkUploadFile)
 		admin.PUT("/exam-questions/:id", examQuestionHandler.Update)
 		admin.DELETE("/exam-questions/:id", examQuestionHandler.Delete)
 
-		admin.POST("/courses/:id/tickets/generate", ticketHandler.GenerateTicketsDocument)
+		admin.POST("/tickets/generate-async", ticketBatchHandler.GenerateAsync)
+
+		admin.GET("/jobs/:id", jobHandler.GetByID)
+
+		admin.GET("/audit", auditHandler.GetAll)
+
+		admin.GET("/trash", trashHandler.GetAll)
+		admin.POST("/courses/:id/restore", trashHandler.RestoreCourse)
+		admin.POST("/lectures/:id/restore", trashHandler.RestoreLecture)
+		admin.POST("/labs/:id/restore", trashHandler.RestoreLab)
+		admin.POST("/grade-sheets/:id/restore", trashHandler.RestoreGradeSheet)
+		admin.POST("/exam-questions/:id/restore", trashHandler.RestoreExamQuestion)
+	}
+
+	// Предпросмотр: те же публичные обработчики, но с черновиками
+	preview := admin.Group("/preview", handlers.StaffPreview())
+	{
+		preview.GET("/lectures", lectureHandler.GetAll)
+		preview.GET("/lectures/:id", lectureHandler.GetByID)
+		preview.GET("/lectures/:id/attachments", attachmentHandler.ListByLecture)
+		preview.GET("/labs", labHandler.GetAll)
+		preview.GET("/labs/:id", labHandler.GetByID)
+		preview.GET("/labs/:id/attachments", attachmentHandler.ListByLab)
+		preview.GET("/grade-sheets", gradeSheetHandler.GetAll)
+		preview.GET("/grade-sheets/:id", gradeSheetHandler.GetByID)
+	}
+
+	// Передача файлов, архивы, синхронизация с git и генерация документов без тайм-аута на запрос
+	transfers := r.Group("/api/admin")
+	transfers.Use(adminAccess...)
+	{
+		transfers.GET("/courses/:id/export", courseArchiveHandler.Export)
+		transfers.POST("/courses/import", courseArchiveHandler.Import)
+		transfers.POST("/courses/:id/sync", contentSyncHandler.Sync)
+		transfers.POST("/lectures/:id/attachments", attachmentHandler.UploadToLecture)
+		transfers.POST("/labs/:id/attachments", attachmentHandler.UploadToLab)
+		transfers.POST("/exam-questions/upload", examQuestionHandler.BulkUploadFile)
+		transfers.POST("/courses/:id/tickets/generate", ticketHandler.GenerateTicketsDocument)
+		transfers.GET("/jobs/:id/result", jobHandler.DownloadResult)
+		transfers.GET("/audit/export", auditHandler.ExportCSV)
+		transfers.GET("/preview/attachments/:id", handlers.StaffPreview(), attachmentHandler.Download)
 	}
 
 	r.Static("/assets", "./web/assets")
@@ -219,6 +371,21 @@ func main() {
 		Handler: r,
 	}
 
+	jobPool.Start(ctx)
+
+	purgeCtx, stopPurge := context.WithCancel(ctx)
+	go trashService.RunPurge(purgeCtx, cfg.Trash.GetPurgeInterval())
+
+	publishCtx, stopPublishing := context.WithCancel(ctx)
+	go publicationService.Run(publishCtx, cfg.Publication.GetInterval())
+
+	// Вложения окончательно удалённых материалов чистятся вместе с корзиной
+	attachmentsCtx, stopAttachments := context.WithCancel(ctx)
+	go attachmentService.Run(attachmentsCtx, cfg.Trash.GetPurgeInterval())
+
+	remindersCtx, stopReminders := context.WithCancel(ctx)
+	go notificationService.Run(remindersCtx, cfg.Notifications.GetReminderInterval())
+
 	go func() {
 		protocol := "HTTP"
 		url := fmt.Sprintf("http://%s:%d", cfg.Server.Host, cfg.Server.Port)
@@ -254,5 +421,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	stopPurge()
+	stopPublishing()
+	stopAttachments()
+	stopReminders()
+
+	if err := jobPool.Shutdown(shutdownCtx); err != nil {
+		slog.ErrorContext(ctx, "Job worker pool shutdown error", "error", err)
+	}
+
 	slog.InfoContext(ctx, "Server stopped successfully")
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/config"
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/demo"
+	"github.com/CreateLab/laritmo/internal/mail"
+	"github.com/CreateLab/laritmo/internal/migrate"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/cached"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/storage"
+	"github.com/CreateLab/laritmo/migrations"
+	"github.com/redis/go-redis/v9"
+)
+
+// openStore возвращает хранилища сервера и функцию их закрытия.
+// В демо-режиме это in-memory хранилище с примером курса, иначе - SQL-репозитории
+// выбранной СУБД; миграции применяются или проверяются согласно database.auto_migrate.
+// При включённом cache.enabled публичные чтения идут через кэш.
+func openStore(ctx context.Context, cfg *config.Config) (*repository.Store, func(), error) {
+	store, closeStore, err := openBackendStore(ctx, cfg)
+	if err != nil || !cfg.Cache.Enabled {
+		return store, closeStore, err
+	}
+
+	backend, closeCache, err := openCacheBackend(ctx, cfg.Cache)
+	if err != nil {
+		closeStore()
+		return nil, nil, err
+	}
+	slog.InfoContext(ctx, "✅ Cache enabled", "backend", cfg.Cache.GetBackend(), "ttl", cfg.Cache.GetTTL())
+
+	c := cache.New(backend, cfg.Cache.GetTTL(), slog.Default())
+	return cached.Wrap(store, c), func() {
+		closeCache()
+		closeStore()
+	}, nil
+}
+
+func openCacheBackend(ctx context.Context, cfg config.CacheConfig) (cache.Backend, func(), error) {
+	switch cfg.GetBackend() {
+	case "memory":
+		return cache.NewLRU(cfg.GetMaxEntries()), func() {}, nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		if err := client.Ping(ctx).Err(); err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("failed to connect to cache redis: %w", err)
+		}
+		return cache.NewRedis(client), func() { client.Close() }, nil
+	}
+	return nil, nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+}
+
+// openAttachmentStorage выбирает хранилище файлов вложений по attachments.backend
+func openAttachmentStorage(cfg config.AttachmentsConfig) (storage.Storage, error) {
+	switch cfg.GetBackend() {
+	case "local":
+		return storage.NewLocal(cfg.GetDir())
+	case "s3":
+		return storage.NewS3(storage.S3Options{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		})
+	}
+	return nil, fmt.Errorf("unknown attachments backend %q", cfg.Backend)
+}
+
+func openMailSender(cfg config.NotificationsConfig, logger *slog.Logger) (mail.Sender, error) {
+	switch cfg.GetTransport() {
+	case "log":
+		return mail.NewLog(logger), nil
+	case "file":
+		return mail.NewFile(cfg.GetDir())
+	case "smtp":
+		if cfg.SMTP.Host == "" {
+			return nil, errors.New("notifications.smtp.host is required for the smtp transport")
+		}
+		return mail.NewSMTP(mail.SMTPOptions{
+			Host:        cfg.SMTP.Host,
+			Port:        cfg.SMTP.GetPort(),
+			Username:    cfg.SMTP.Username,
+			Password:    cfg.SMTP.Password,
+			ImplicitTLS: cfg.SMTP.ImplicitTLS,
+			Timeout:     cfg.SMTP.GetTimeout(),
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown notifications transport %q", cfg.Transport)
+}
+
+func openBackendStore(ctx context.Context, cfg *config.Config) (*repository.Store, func(), error) {
+	if cfg.Demo.Enabled {
+		store := memory.NewStore(memory.NewDB())
+		password, err := demo.Seed(ctx, store, cfg.Demo.AdminPassword)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		slog.WarnContext(ctx, "⚠️ Demo mode: data is kept in memory and lost on restart")
+		if cfg.Demo.AdminPassword == "" {
+			slog.InfoContext(ctx, "Demo admin created", "username", demo.AdminUsername, "password", password)
+		}
+		return store, func() {}, nil
+	}
+
+	db, err := database.Connect(cfg.Database.GetDriver(), cfg.Database.DSN())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := migrateOnStart(ctx, db, cfg.Database.AutoMigrate); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	return repository.NewStore(db), func() { db.Close() }, nil
+}
+
+func migrateOnStart(ctx context.Context, db *database.DB, autoMigrate bool) error {
+	migrationsFS, err := migrations.For(db.Dialect)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+	migrator, err := migrate.NewRunner(db, migrationsFS)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	if autoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		slog.InfoContext(ctx, "Database migrations applied", "count", len(applied))
+	} else if pending, err := migrator.Pending(ctx); err != nil {
+		slog.WarnContext(ctx, "⚠️ Failed to check migrations", "error", err)
+	} else if pending > 0 {
+		slog.WarnContext(ctx, "⚠️ Database has pending migrations: run laritmo-admin migrate up or enable database.auto_migrate", "pending", pending)
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+// Package backup выгружает таблицы Laritmo в архив и восстанавливает их в пустую базу
+// без mysqldump и других внешних утилит.
+//
+// Архив - zip, в котором файлы идут в порядке записи:
+//
+//	backup.json           версия формата, драйвер, версия схемы и колонки таблиц
+//	tables/<table>.jsonl  строки таблицы в порядке id, по JSON-объекту на строку
+//	checksums.json        число строк и SHA-256 каждого файла таблицы
+//
+// Время хранится в RFC 3339, поэтому архив можно восстановить и в базу другого драйвера
+// с той же версией схемы.
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+)
+
+// FormatVersion - версия формата архива, которую пишет Write
+const FormatVersion = 1
+
+const (
+	headerFile    = "backup.json"
+	checksumsFile = "checksums.json"
+	tablesDir     = "tables/"
+)
+
+var (
+	// ErrInvalidArchive - архив повреждён или не совпадают контрольные суммы
+	ErrInvalidArchive = errors.New("invalid backup archive")
+	// ErrSchemaMismatch - версия схемы базы отличается от версии в архиве
+	ErrSchemaMismatch = errors.New("database schema version does not match the backup")
+	// ErrNotEmpty - в базе для восстановления уже есть данные
+	ErrNotEmpty = errors.New("database is not empty")
+)
+
+// table - таблица в архиве; foreignKey ссылается на courses.id
+type table struct {
+	name       string
+	foreignKey string
+}
+
+// tables перечислены в порядке восстановления: курсы раньше материалов, которые на них ссылаются
+var tables = []table{
+	{name: "courses"},
+	{name: "lectures", foreignKey: "course_id"},
+	{name: "labs", foreignKey: "course_id"},
+	{name: "grade_sheets", foreignKey: "course_id"},
+	{name: "exam_questions", foreignKey: "course_id"},
+	{name: "course_schedules", foreignKey: "course_id"},
+	{name: "course_timetable_slots", foreignKey: "course_id"},
+	// Только записи о вложениях: сами файлы лежат в хранилище вложений и в архив не входят
+	{name: "attachments", foreignKey: "course_id"},
+	{name: "announcements", foreignKey: "course_id"},
+	{name: "lab_deadline_reminders"},
+	{name: "users"},
+	{name: "notification_preferences"},
+}
+
+// Header - описание архива, которое читается до восстановления строк
+type Header struct {
+	FormatVersion int              `json:"format_version"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Driver        database.Dialect `json:"driver"`
+	SchemaVersion int64            `json:"schema_version"`
+	Tables        []TableHeader    `json:"tables"`
+}
+
+// TableHeader - колонки таблицы в порядке значений строк
+type TableHeader struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	// TimeColumns - колонки с датой и временем, записанные строками RFC 3339
+	TimeColumns []string `json:"time_columns,omitempty"`
+}
+
+// TableSummary - сколько строк таблицы записано или восстановлено и SHA-256 её файла в архиве
+type TableSummary struct {
+	Name   string `json:"name"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+type checksums struct {
+	Tables []TableSummary `json:"tables"`
+}
+
+// columnKind - как значение колонки кодируется в JSON
+type columnKind int
+
+const (
+	kindText columnKind = iota
+	kindInt
+	kindTime
+)
+
+func kindOf(ct *sql.ColumnType) columnKind {
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	switch {
+	case strings.Contains(name, "TIME"), strings.Contains(name, "DATE"):
+		return kindTime
+	case strings.Contains(name, "INT"), name == "SERIAL":
+		return kindInt
+	}
+	return kindText
+}
+
+// Write выгружает таблицы в w одним согласованным снимком; schemaVersion - версия
+// последней применённой миграции, без совпадения которой архив не восстановится
+func Write(ctx context.Context, db *database.DB, w io.Writer, schemaVersion int64) ([]TableSummary, error) {
+	// SQLite с _txlock=immediate и одним соединением и так даёт согласованный снимок,
+	// а уровни изоляции кроме serializable драйвер не поддерживает
+	var opts *sql.TxOptions
+	if db.Dialect != database.SQLite {
+		opts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	header := Header{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+		Driver:        db.Dialect,
+		SchemaVersion: schemaVersion,
+	}
+
+	// Колонки читаются заранее, потому что заголовок пишется в архив первым
+	kinds := make(map[string][]columnKind, len(tables))
+	for _, t := range tables {
+		th, columnKinds, err := describeTable(ctx, tx, t.name)
+		if err != nil {
+			return nil, err
+		}
+		header.Tables = append(header.Tables, th)
+		kinds[t.name] = columnKinds
+	}
+
+	archive := zip.NewWriter(w)
+	create := func(name string) (io.Writer, error) {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: header.CreatedAt})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to backup: %w", name, err)
+		}
+		return f, nil
+	}
+
+	if err := writeJSON(create, headerFile, header); err != nil {
+		return nil, err
+	}
+
+	var sums checksums
+	for _, th := range header.Tables {
+		f, err := create(tablesDir + th.Name + ".jsonl")
+		if err != nil {
+			return nil, err
+		}
+		sum, err := writeTable(ctx, tx, f, th, kinds[th.Name])
+		if err != nil {
+			return nil, err
+		}
+		sums.Tables = append(sums.Tables, *sum)
+	}
+
+	if err := writeJSON(create, checksumsFile, sums); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup: %w", err)
+	}
+
+	return sums.Tables, nil
+}
+
+func describeTable(ctx context.Context, tx *sql.Tx, name string) (TableHeader, []columnKind, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT * FROM "+name+" WHERE 1 = 0")
+	if err != nil {
+		return TableHeader{}, nil, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return TableHeader{}, nil, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+
+	th := TableHeader{Name: name}
+	kinds := make([]columnKind, len(types))
+	for i, ct := range types {
+		th.Columns = append(th.Columns, ct.Name())
+		kinds[i] = kindOf(ct)
+		if kinds[i] == kindTime {
+			th.TimeColumns = append(th.TimeColumns, ct.Name())
+		}
+	}
+	return th, kinds, rows.Err()
+}
+
+func writeTable(ctx context.Context, tx *sql.Tx, w io.Writer, th TableHeader, kinds []columnKind) (*TableSummary, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT "+strings.Join(th.Columns, ", ")+" FROM "+th.Name+" ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", th.Name, err)
+	}
+	defer rows.Close()
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(io.MultiWriter(w, hash))
+	encoder.SetEscapeHTML(false)
+
+	sum := &TableSummary{Name: th.Name}
+	values := make([]any, len(th.Columns))
+	ptrs := make([]any, len(values))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("scan error for %s: %w", th.Name, err)
+		}
+
+		row := make(map[string]any, len(values))
+		for i, v := range values {
+			encoded, err := encodeValue(v, kinds[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", th.Name, th.Columns[i], err)
+			}
+			row[th.Columns[i]] = encoded
+		}
+		if err := encoder.Encode(row); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", th.Name, err)
+		}
+		sum.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", th.Name, err)
+	}
+
+	sum.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return sum, nil
+}
+
+// encodeValue приводит значение драйвера к JSON: MySQL без подготовленных запросов
+// возвращает числа и строки как []byte, а SQLite - время строкой, если не смог его разобрать
+func encodeValue(v any, kind columnKind) (any, error) {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case string:
+		switch kind {
+		case kindInt:
+			return strconv.ParseInt(v, 10, 64)
+		case kindTime:
+			t, err := parseTime(v)
+			if err != nil {
+				return nil, err
+			}
+			return t.Format(time.RFC3339Nano), nil
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// parseTime разбирает время в RFC 3339 или в формате CURRENT_TIMESTAMP
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", time.DateTime} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+func writeJSON(create func(string) (io.Writer, error), name string, v any) error {
+	f, err := create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
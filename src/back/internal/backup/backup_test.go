@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/config"
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/migrate"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDB открывает мигрированную SQLite в памяти и возвращает её с версией схемы
+func newTestDB(t *testing.T) (*database.DB, int64) {
+	t.Helper()
+
+	cfg := config.DatabaseConfig{Driver: "sqlite", Path: ":memory:"}
+	db, err := database.Connect(cfg.GetDriver(), cfg.DSN())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	fsys, err := migrations.For(db.Dialect)
+	require.NoError(t, err)
+	runner, err := migrate.NewRunner(db, fsys)
+	require.NoError(t, err)
+	_, err = runner.Up(context.Background())
+	require.NoError(t, err)
+	version, err := runner.Version(context.Background())
+	require.NoError(t, err)
+
+	return db, version
+}
+
+func seed(t *testing.T, db *database.DB) {
+	t.Helper()
+	ctx := context.Background()
+	store := repository.NewStore(db)
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "Основы")
+	require.NoError(t, err)
+	other, err := store.Courses.Create(ctx, "C#", "2025-fall", "")
+	require.NoError(t, err)
+	require.NoError(t, store.Courses.SetArchived(ctx, other.ID, true))
+
+	for week := 1; week <= 150; week++ {
+		_, err = store.Lectures.Create(ctx, course.ID, week, "Лекция", "Текст с \"кавычками\" и <тегами>\nи переводом строки", "", "", nil)
+		require.NoError(t, err)
+	}
+	deadline := "2025-10-01 23:59:00"
+	_, err = store.Labs.Create(ctx, course.ID, 1, 10, "Калькулятор", "", "https://github.com/example/lab1", &deadline, "", nil)
+	require.NoError(t, err)
+	lab, err := store.Labs.Create(ctx, course.ID, 2, 10, "Удалённая", "", "", nil, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Labs.Delete(ctx, lab.ID))
+	_, err = store.ExamQuestions.Create(ctx, course.ID, 1, "Основы", "Что такое срез?")
+	require.NoError(t, err)
+	_, err = store.GradeSheets.Create(ctx, course.ID, "https://example.com/sheet", "", "", nil)
+	require.NoError(t, err)
+	_, err = store.Schedules.Set(ctx, &models.CourseSchedule{
+		CourseID:  course.ID,
+		StartDate: "2025-09-01",
+		Slots:     []models.TimetableSlot{{Weekday: 3, StartTime: "09:00", DurationMinutes: 90, Room: "210"}},
+	})
+	require.NoError(t, err)
+	lectures, err := store.Lectures.GetByCourseID(ctx, course.ID)
+	require.NoError(t, err)
+	_, err = store.Attachments.Create(ctx, &models.Attachment{
+		CourseID: course.ID, LectureID: &lectures[0].ID, FileName: "slides.pdf",
+		ContentType: "application/pdf", Size: 1024, StorageKey: "courses/1/slides",
+	})
+	require.NoError(t, err)
+	// Вложение окончательно удалённого курса ждёт очистки без ссылок
+	_, err = db.ExecContext(ctx, "INSERT INTO attachments (file_name, content_type, size, storage_key) VALUES ('old.pdf', 'application/pdf', 10, 'courses/9/old')")
+	require.NoError(t, err)
+	_, err = store.Announcements.Create(ctx, course.ID, "Перенос дедлайна", "Новый дедлайн: 08.10.2025 23:59.", models.AnnouncementKindLabDeadline)
+	require.NoError(t, err)
+	_, err = store.Users.Create(ctx, "admin", "admin@example.com", "$2a$10$hash", "admin")
+	require.NoError(t, err)
+}
+
+func TestWriteRestore(t *testing.T) {
+	ctx := context.Background()
+	source, version := newTestDB(t)
+	seed(t, source)
+
+	var buf bytes.Buffer
+	written, err := Write(ctx, source, &buf, version)
+	require.NoError(t, err)
+	require.Len(t, written, len(tables))
+	assert.Equal(t, 150, written[1].Rows)
+
+	header, err := ReadHeader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, version, header.SchemaVersion)
+	assert.Equal(t, database.SQLite, header.Driver)
+
+	target, _ := newTestDB(t)
+	restored, err := Restore(ctx, target, bytes.NewReader(buf.Bytes()), int64(buf.Len()), version)
+	require.NoError(t, err)
+	assert.Equal(t, written, restored)
+
+	store := repository.NewStore(target)
+	courses, total, err := store.Courses.GetAll(ctx, repository.CourseFilter{}, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.NotNil(t, courses[0].ArchivedAt, "archived_at survives the round trip")
+
+	labs, err := store.Labs.GetByCourseID(ctx, courses[1].ID)
+	require.NoError(t, err)
+	require.Len(t, labs, 1, "soft-deleted lab is restored into trash, not into the course")
+	require.NotNil(t, labs[0].Deadline)
+	assert.Equal(t, "2025-10-01 23:59:00", labs[0].Deadline.Format(time.DateTime))
+
+	schedule, err := store.Schedules.Get(ctx, courses[1].ID)
+	require.NoError(t, err)
+	require.NotNil(t, schedule)
+	assert.Equal(t, "2025-09-01", schedule.StartDate)
+	require.Len(t, schedule.Slots, 1)
+
+	user, err := store.Users.GetByUsername(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "$2a$10$hash", user.PasswordHash)
+
+	created, err := store.Courses.Create(ctx, "Новый", "2026-spring", "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, created.ID, "ids continue after restored rows")
+
+	_, err = Restore(ctx, target, bytes.NewReader(buf.Bytes()), int64(buf.Len()), version)
+	assert.ErrorIs(t, err, ErrNotEmpty)
+}
+
+func TestRestore_Rejects(t *testing.T) {
+	ctx := context.Background()
+	source, version := newTestDB(t)
+	seed(t, source)
+
+	var buf bytes.Buffer
+	_, err := Write(ctx, source, &buf, version)
+	require.NoError(t, err)
+
+	// rewrite копирует архив, подменяя содержимое одного файла
+	rewrite := func(name string, edit func(string) string) []byte {
+		src, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		w := zip.NewWriter(&out)
+		for _, f := range src.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+			if f.Name == name {
+				data = []byte(edit(string(data)))
+			}
+			dst, err := w.Create(f.Name)
+			require.NoError(t, err)
+			_, err = dst.Write(data)
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		return out.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		version int64
+		err     error
+	}{
+		{"schema mismatch", buf.Bytes(), version + 1, ErrSchemaMismatch},
+		{"not a zip", []byte("backup"), version, ErrInvalidArchive},
+		{"tampered rows", rewrite("tables/exam_questions.jsonl", func(s string) string {
+			return strings.Replace(s, "срез", "канал", 1)
+		}), version, ErrInvalidArchive},
+		{"unknown column", rewrite("backup.json", func(s string) string {
+			return strings.Replace(s, `"semester"`, `"semester; DROP TABLE users"`, 1)
+		}), version, ErrInvalidArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _ := newTestDB(t)
+
+			_, err := Restore(ctx, target, bytes.NewReader(tt.data), int64(len(tt.data)), tt.version)
+			assert.ErrorIs(t, err, tt.err)
+
+			courses, total, err := repository.NewCourseRepository(target).GetAll(ctx, repository.CourseFilter{}, repository.ListOptions{})
+			require.NoError(t, err)
+			assert.Zero(t, total, "failed restore leaves the database empty: %v", courses)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// restoreBatchSize - сколько строк вставляется одним INSERT
+const restoreBatchSize = 100
+
+// ReadHeader читает описание архива, не восстанавливая строки
+func ReadHeader(r io.ReaderAt, size int64) (*Header, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	var header Header
+	if err := readJSON(archive, headerFile, &header); err != nil {
+		return nil, err
+	}
+	if header.FormatVersion < 1 || header.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, header.FormatVersion)
+	}
+	return &header, nil
+}
+
+// Restore восстанавливает архив в пустую базу с той же версией схемы в одной транзакции.
+// Перед фиксацией сверяются контрольные суммы файлов, число строк в таблицах
+// и ссылки материалов на курсы; при любом расхождении база остаётся пустой.
+func Restore(ctx context.Context, db *database.DB, r io.ReaderAt, size int64, schemaVersion int64) ([]TableSummary, error) {
+	header, err := ReadHeader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if header.SchemaVersion != schemaVersion {
+		return nil, fmt.Errorf("%w: backup has %d, database has %d", ErrSchemaMismatch, header.SchemaVersion, schemaVersion)
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	var sums checksums
+	if err := readJSON(archive, checksumsFile, &sums); err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]TableSummary, len(sums.Tables))
+	for _, sum := range sums.Tables {
+		expected[sum.Name] = sum
+	}
+	headers := make(map[string]TableHeader, len(header.Tables))
+	for _, th := range header.Tables {
+		headers[th.Name] = th
+	}
+	for _, t := range tables {
+		if _, ok := headers[t.name]; !ok {
+			return nil, fmt.Errorf("%w: table %s is missing", ErrInvalidArchive, t.name)
+		}
+		if _, ok := expected[t.name]; !ok {
+			return nil, fmt.Errorf("%w: checksum of %s is missing", ErrInvalidArchive, t.name)
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sb := db.Dialect.Builder()
+	for _, t := range tables {
+		count, err := countRows(ctx, tx, sb, t.name)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%w: %s has %d rows", ErrNotEmpty, t.name, count)
+		}
+	}
+
+	var restored []TableSummary
+	for _, t := range tables {
+		sum, err := restoreTable(ctx, tx, sb, archive, headers[t.name])
+		if err != nil {
+			return nil, err
+		}
+		if want := expected[t.name]; sum.SHA256 != want.SHA256 || sum.Rows != want.Rows {
+			return nil, fmt.Errorf("%w: %s does not match its checksum", ErrInvalidArchive, t.name)
+		}
+		restored = append(restored, *sum)
+	}
+
+	if err := verify(ctx, tx, sb, restored); err != nil {
+		return nil, err
+	}
+
+	if db.Dialect == database.Postgres {
+		// Явные id не сдвигают последовательности SERIAL, иначе новые записи получили бы занятые id
+		for _, t := range tables {
+			query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 1), MAX(id) IS NOT NULL) FROM %[1]s", t.name)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return nil, fmt.Errorf("failed to reset id sequence of %s: %w", t.name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return restored, nil
+}
+
+func restoreTable(ctx context.Context, tx *sql.Tx, sb sq.StatementBuilderType, archive *zip.Reader, th TableHeader) (*TableSummary, error) {
+	// Имена колонок попадают в SQL, поэтому принимаются только колонки, которые есть в базе
+	target, _, err := describeTable(ctx, tx, th.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range th.Columns {
+		if !slices.Contains(target.Columns, column) {
+			return nil, fmt.Errorf("%w: %s has unknown column %q", ErrInvalidArchive, th.Name, column)
+		}
+	}
+
+	name := tablesDir + th.Name + ".jsonl"
+	f, err := archive.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	decoder := json.NewDecoder(io.TeeReader(f, hash))
+	decoder.UseNumber()
+
+	sum := &TableSummary{Name: th.Name}
+	insert := sb.Insert(th.Name).Columns(th.Columns...)
+	batch := 0
+	flush := func() error {
+		if batch == 0 {
+			return nil
+		}
+		query, args, err := insert.ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", th.Name, err)
+		}
+		insert = sb.Insert(th.Name).Columns(th.Columns...)
+		batch = 0
+		return nil
+	}
+
+	for {
+		var row map[string]any
+		err := decoder.Decode(&row)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s row %d: %v", ErrInvalidArchive, name, sum.Rows+1, err)
+		}
+
+		values := make([]any, len(th.Columns))
+		for i, column := range th.Columns {
+			values[i], err = decodeValue(row[column], slices.Contains(th.TimeColumns, column))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s row %d, %s: %v", ErrInvalidArchive, name, sum.Rows+1, column, err)
+			}
+		}
+		insert = insert.Values(values...)
+		batch++
+		sum.Rows++
+
+		if batch == restoreBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	// Контрольная сумма считается по всему файлу, даже если после последней строки что-то осталось
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+
+	sum.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return sum, nil
+}
+
+// decodeValue переводит значение из JSON в аргумент запроса
+func decodeValue(v any, isTime bool) (any, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case string:
+		if isTime {
+			return parseTime(v)
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// verify сверяет число строк в базе с архивом и проверяет, что материалы ссылаются на существующие курсы;
+// пустая ссылка допустима: так остаются вложения окончательно удалённых курсов до их очистки
+func verify(ctx context.Context, tx *sql.Tx, sb sq.StatementBuilderType, restored []TableSummary) error {
+	for i, t := range tables {
+		count, err := countRows(ctx, tx, sb, t.name)
+		if err != nil {
+			return err
+		}
+		if count != restored[i].Rows {
+			return fmt.Errorf("%s has %d rows after restore, backup has %d", t.name, count, restored[i].Rows)
+		}
+
+		if t.foreignKey == "" {
+			continue
+		}
+		query, args, err := sb.Select("COUNT(*)").
+			From(t.name + " t").
+			LeftJoin("courses c ON c.id = t." + t.foreignKey).
+			Where(sq.Eq{"c.id": nil}).
+			Where(sq.NotEq{"t." + t.foreignKey: nil}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+		var orphans int
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&orphans); err != nil {
+			return fmt.Errorf("failed to check references of %s: %w", t.name, err)
+		}
+		if orphans > 0 {
+			return fmt.Errorf("%w: %d rows of %s reference missing courses", ErrInvalidArchive, orphans, t.name)
+		}
+	}
+	return nil
+}
+
+func countRows(ctx context.Context, tx *sql.Tx, sb sq.StatementBuilderType, name string) (int, error) {
+	query, args, err := sb.Select("COUNT(*)").From(name).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", name, err)
+	}
+	return count, nil
+}
+
+func readJSON(archive *zip.Reader, name string, v any) error {
+	f, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	return nil
+}
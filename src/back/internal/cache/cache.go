@@ -0,0 +1,108 @@
+// Package cache - read-through кэш для публичных данных.
+// Значения хранятся в Backend в JSON; инвалидация не удаляет ключи, а увеличивает
+// поколение пространства имён, поэтому работает одинаково для LRU в памяти и для Redis,
+// где поколения общие для всех экземпляров сервера.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// keyPrefix отделяет ключи laritmo в общем Redis
+const keyPrefix = "laritmo:"
+
+// Backend - хранилище закэшированных значений
+type Backend interface {
+	// Get возвращает значение и false, если ключа нет или его TTL истёк
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr атомарно увеличивает счётчик и возвращает новое значение.
+	// Счётчики не имеют TTL и не вытесняются, иначе поколение могло бы откатиться назад.
+	Incr(ctx context.Context, key string) (int64, error)
+	// Counter возвращает текущее значение счётчика или 0, если его нет
+	Counter(ctx context.Context, key string) (int64, error)
+}
+
+type Cache struct {
+	backend Backend
+	ttl     time.Duration
+	logger  *slog.Logger
+}
+
+func New(backend Backend, ttl time.Duration, logger *slog.Logger) *Cache {
+	return &Cache{
+		backend: backend,
+		ttl:     ttl,
+		logger:  logger,
+	}
+}
+
+// Fetch возвращает значение из кэша или вызывает load и кэширует результат.
+// key - любое значение, сериализуемое в JSON, например фильтр и параметры страницы.
+// Ошибки кэша только пишутся в лог: данные тогда читаются напрямую через load.
+func Fetch[T any](ctx context.Context, c *Cache, namespace string, key any, load func() (T, error)) (T, error) {
+	fullKey, err := c.key(ctx, namespace, key)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Cache key error", "namespace", namespace, "error", err)
+		return load()
+	}
+
+	data, ok, err := c.backend.Get(ctx, fullKey)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Cache read error", "key", fullKey, "error", err)
+	}
+	if ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+		c.logger.WarnContext(ctx, "Cache entry is corrupted", "key", fullKey, "error", err)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	if data, err := json.Marshal(value); err != nil {
+		c.logger.WarnContext(ctx, "Cache encode error", "key", fullKey, "error", err)
+	} else if err := c.backend.Set(ctx, fullKey, data, c.ttl); err != nil {
+		c.logger.WarnContext(ctx, "Cache write error", "key", fullKey, "error", err)
+	}
+
+	return value, nil
+}
+
+// Invalidate делает недоступными все записи пространств имён
+func (c *Cache) Invalidate(ctx context.Context, namespaces ...string) {
+	// Запрос мог уже завершиться по таймауту, а инвалидация должна пройти
+	ctx = context.WithoutCancel(ctx)
+	for _, ns := range namespaces {
+		if _, err := c.backend.Incr(ctx, generationKey(ns)); err != nil {
+			// Запись уже сохранена в БД; устаревшие данные исчезнут по TTL
+			c.logger.ErrorContext(ctx, "Cache invalidation failed", "namespace", ns, "error", err)
+		}
+	}
+}
+
+func (c *Cache) key(ctx context.Context, namespace string, key any) (string, error) {
+	generation, err := c.backend.Counter(ctx, generationKey(namespace))
+	if err != nil {
+		return "", fmt.Errorf("failed to read generation: %w", err)
+	}
+
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	return fmt.Sprintf("%s%s:%d:%s", keyPrefix, namespace, generation, encoded), nil
+}
+
+func generationKey(namespace string) string {
+	return keyPrefix + "generation:" + namespace
+}
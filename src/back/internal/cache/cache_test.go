@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(backend Backend) *Cache {
+	return New(backend, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestFetch(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(NewLRU(100))
+
+	loads := 0
+	load := func() (*item, error) {
+		loads++
+		return &item{ID: 1, Name: "Go"}, nil
+	}
+
+	first, err := Fetch(ctx, c, "courses", 1, load)
+	require.NoError(t, err)
+	second, err := Fetch(ctx, c, "courses", 1, load)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, loads, "second read must come from cache")
+
+	_, err = Fetch(ctx, c, "courses", 2, load)
+	require.NoError(t, err)
+	_, err = Fetch(ctx, c, "lectures", 1, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads, "keys and namespaces must not collide")
+
+	c.Invalidate(ctx, "courses")
+	_, err = Fetch(ctx, c, "courses", 1, load)
+	require.NoError(t, err)
+	assert.Equal(t, 4, loads, "invalidation must force reload")
+
+	_, err = Fetch(ctx, c, "lectures", 1, load)
+	require.NoError(t, err)
+	assert.Equal(t, 4, loads, "other namespaces must stay cached")
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(NewLRU(100))
+
+	loads := 0
+	failing := func() ([]item, error) {
+		loads++
+		return nil, errors.New("db is down")
+	}
+
+	_, err := Fetch(ctx, c, "courses", "all", failing)
+	require.Error(t, err)
+	_, err = Fetch(ctx, c, "courses", "all", failing)
+	require.Error(t, err)
+	assert.Equal(t, 2, loads)
+}
+
+type brokenBackend struct{}
+
+func (brokenBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (brokenBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (brokenBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (brokenBackend) Counter(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestFetchFallsBackToLoadWhenBackendFails(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(brokenBackend{})
+
+	value, err := Fetch(ctx, c, "courses", 1, func() (item, error) {
+		return item{ID: 1, Name: "Go"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, item{ID: 1, Name: "Go"}, value)
+
+	c.Invalidate(ctx, "courses")
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU - Backend в памяти процесса: не больше maxEntries значений, при переполнении
+// вытесняется давно не читавшееся. Подходит для одного экземпляра сервера и для тестов.
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	counters   map[string]int64
+	now        func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewLRU(maxEntries int) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		counters:   make(map[string]int64),
+		now:        time.Now,
+	}
+}
+
+func (l *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !l.now().Before(entry.expiresAt) {
+		l.remove(elem)
+		return nil, false, nil
+	}
+
+	l.order.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+func (l *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = l.now().Add(ttl)
+	}
+
+	if elem, ok := l.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(elem)
+		return nil
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for l.maxEntries > 0 && l.order.Len() > l.maxEntries {
+		l.remove(l.order.Back())
+	}
+
+	return nil
+}
+
+func (l *LRU) Incr(ctx context.Context, key string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.counters[key]++
+	return l.counters[key], nil
+}
+
+func (l *LRU) Counter(ctx context.Context, key string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.counters[key], nil
+}
+
+// Len возвращает число хранимых значений, включая ещё не удалённые просроченные
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *LRU) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.entries, elem.Value.(*lruEntry).key)
+}
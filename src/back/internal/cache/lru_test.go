@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		l := NewLRU(2)
+		require.NoError(t, l.Set(ctx, "a", []byte("1"), 0))
+		require.NoError(t, l.Set(ctx, "b", []byte("2"), 0))
+
+		_, ok, _ := l.Get(ctx, "a")
+		require.True(t, ok)
+		require.NoError(t, l.Set(ctx, "c", []byte("3"), 0))
+
+		_, ok, _ = l.Get(ctx, "b")
+		assert.False(t, ok, "b was read least recently")
+		value, ok, _ := l.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+		assert.Equal(t, 2, l.Len())
+	})
+
+	t.Run("expires entries after ttl", func(t *testing.T) {
+		now := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+		l := NewLRU(10)
+		l.now = func() time.Time { return now }
+
+		require.NoError(t, l.Set(ctx, "a", []byte("1"), time.Minute))
+		_, ok, _ := l.Get(ctx, "a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		_, ok, _ = l.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, l.Len())
+	})
+
+	t.Run("counters are not evicted", func(t *testing.T) {
+		l := NewLRU(1)
+		n, err := l.Incr(ctx, "gen")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		require.NoError(t, l.Set(ctx, "a", []byte("1"), 0))
+		require.NoError(t, l.Set(ctx, "b", []byte("2"), 0))
+
+		n, err = l.Counter(ctx, "gen")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	r := NewRedis(client)
+
+	_, ok, err := r.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, r.Set(ctx, "a", []byte("1"), time.Minute))
+	value, ok, err := r.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	server.FastForward(time.Minute)
+	_, ok, err = r.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	n, err := r.Counter(ctx, "gen")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	_, err = r.Incr(ctx, "gen")
+	require.NoError(t, err)
+	n, err = r.Counter(ctx, "gen")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestRedisSharesInvalidationBetweenInstances(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+
+	newInstance := func() *Cache {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return newTestCache(NewRedis(client))
+	}
+	first, second := newInstance(), newInstance()
+
+	version := 1
+	load := func() (int, error) { return version, nil }
+
+	got, err := Fetch(ctx, first, "courses", "all", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got)
+
+	version = 2
+	got, err = Fetch(ctx, second, "courses", "all", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got, "second instance must read the shared entry")
+
+	first.Invalidate(ctx, "courses")
+	got, err = Fetch(ctx, second, "courses", "all", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got)
+}
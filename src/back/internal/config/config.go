@@ -2,16 +2,120 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Auth     AuthConfig     `mapstructure:"auth"`
-	NewRelic NewRelicConfig `mapstructure:"newrelic"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	NewRelic    NewRelicConfig    `mapstructure:"newrelic"`
+	Jobs        JobsConfig        `mapstructure:"jobs"`
+	Sync        SyncConfig        `mapstructure:"sync"`
+	Demo        DemoConfig        `mapstructure:"demo"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	Trash       TrashConfig       `mapstructure:"trash"`
+	Calendar    CalendarConfig    `mapstructure:"calendar"`
+	Publication PublicationConfig `mapstructure:"publication"`
+	Attachments AttachmentsConfig `mapstructure:"attachments"`
+	// Notifications - email-уведомления студентов
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+}
+
+// NotificationsConfig - письма о новых лабораторных, близких дедлайнах и опубликованных оценках
+type NotificationsConfig struct {
+	// Transport - log (только запись в лог, по умолчанию), file (файлы .eml в Dir) или smtp
+	Transport string `mapstructure:"transport"`
+	// From - адрес отправителя, например "Laritmo <noreply@example.com>"
+	From string `mapstructure:"from"`
+	Dir  string `mapstructure:"dir"`
+	// ReminderIntervalMinutes - как часто сервер ищет лабораторные с дедлайном в ближайшие сутки
+	ReminderIntervalMinutes int        `mapstructure:"reminder_interval_minutes"`
+	SMTP                    SMTPConfig `mapstructure:"smtp"`
+}
+
+// SMTPConfig - SMTP-сервер для отправки писем
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// ImplicitTLS - TLS с самого подключения (обычно порт 465); иначе STARTTLS, если сервер его предлагает
+	ImplicitTLS    bool `mapstructure:"implicit_tls"`
+	TimeoutSeconds int  `mapstructure:"timeout_seconds"`
+}
+
+// AttachmentsConfig - файлы, прикреплённые к лекциям и лабораторным
+type AttachmentsConfig struct {
+	// Backend - local (каталог Dir, по умолчанию) или s3 (S3-совместимое хранилище)
+	Backend string `mapstructure:"backend"`
+	Dir     string `mapstructure:"dir"`
+	// MaxFileSizeMB - наибольший размер одного файла
+	MaxFileSizeMB int `mapstructure:"max_file_size_mb"`
+	// CourseQuotaMB - сколько места могут занимать все вложения одного курса
+	CourseQuotaMB int             `mapstructure:"course_quota_mb"`
+	S3            S3StorageConfig `mapstructure:"s3"`
+}
+
+// S3StorageConfig - бакет S3-совместимого хранилища (AWS S3, MinIO, Yandex Object Storage)
+type S3StorageConfig struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+}
+
+// PublicationConfig - публикация черновиков по расписанию
+type PublicationConfig struct {
+	// IntervalSeconds - как часто сервер публикует черновики с наступившим publish_at
+	IntervalSeconds int `mapstructure:"interval_seconds"`
+}
+
+// CalendarConfig - расписание занятий и iCalendar-подписка курсов
+type CalendarConfig struct {
+	// Timezone - часовой пояс IANA, в котором заданы время занятий и дедлайны лабораторных
+	Timezone string `mapstructure:"timezone"`
+}
+
+// TrashConfig - корзина мягко удалённых записей и их окончательное удаление
+type TrashConfig struct {
+	// RetentionDays - сколько дней запись хранится в корзине до окончательного удаления
+	RetentionDays int `mapstructure:"retention_days"`
+	// PurgeIntervalMinutes - как часто сервер очищает корзину от просроченных записей
+	PurgeIntervalMinutes int `mapstructure:"purge_interval_minutes"`
+}
+
+// CacheConfig - кэш публичных GET-запросов к курсам, лекциям и вопросам к экзамену
+type CacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Backend - memory (LRU в памяти процесса, по умолчанию) или redis (общий для всех экземпляров)
+	Backend    string           `mapstructure:"backend"`
+	TTLSeconds int              `mapstructure:"ttl_seconds"`
+	MaxEntries int              `mapstructure:"max_entries"`
+	Redis      RedisCacheConfig `mapstructure:"redis"`
+}
+
+// RedisCacheConfig - подключение к Redis или совместимому серверу (Valkey, KeyDB)
+type RedisCacheConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+// DemoConfig - демо-режим: сервер работает без базы данных на хранилище в памяти
+// с примером курса; все изменения теряются при перезапуске
+type DemoConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// AdminPassword - пароль пользователя admin; если пуст, генерируется при старте и пишется в лог
+	AdminPassword string `mapstructure:"admin_password"`
 }
 
 type NewRelicConfig struct {
@@ -31,11 +135,21 @@ type ServerConfig struct {
 }
 
 type DatabaseConfig struct {
+	// Driver - СУБД: mysql (по умолчанию), postgres или sqlite
+	Driver   string `mapstructure:"driver"`
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	Name     string `mapstructure:"name"`
+	// SSLMode - параметр sslmode для PostgreSQL; по умолчанию disable
+	SSLMode string `mapstructure:"sslmode"`
+	// Path - файл базы SQLite или :memory:; host, port и учётные данные при этом не нужны
+	Path string `mapstructure:"path"`
+	// AutoMigrate - применять встроенные миграции при старте сервера
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+	// QueryTimeoutSeconds - сколько может длиться работа с базой в рамках одного HTTP-запроса
+	QueryTimeoutSeconds int `mapstructure:"query_timeout_seconds"`
 }
 
 type AuthConfig struct {
@@ -45,6 +159,61 @@ type AuthConfig struct {
 	RateLimitBurst     int    `mapstructure:"rate_limit_burst"`
 }
 
+type JobsConfig struct {
+	Workers                  int `mapstructure:"workers"`
+	PollIntervalSeconds      int `mapstructure:"poll_interval_seconds"`
+	VisibilityTimeoutSeconds int `mapstructure:"visibility_timeout_seconds"`
+	MaxAttempts              int `mapstructure:"max_attempts"`
+}
+
+// SyncConfig - синхронизация материалов курсов с git-репозиториями
+type SyncConfig struct {
+	// ReposRoot - каталог с клонами репозиториев; админский endpoint принимает пути только внутри него
+	ReposRoot string          `mapstructure:"repos_root"`
+	Webhooks  []WebhookSource `mapstructure:"webhooks"`
+}
+
+// WebhookSource - репозиторий, push в который запускает синхронизацию курса
+type WebhookSource struct {
+	// Repository - полное имя репозитория на хостинге, например CreateLab/AspITMO
+	Repository string `mapstructure:"repository"`
+	CourseID   int    `mapstructure:"course_id"`
+	// RepoPath - клон репозитория относительно repos_root
+	RepoPath     string `mapstructure:"repo_path"`
+	Branch       string `mapstructure:"branch"`
+	LecturesGlob string `mapstructure:"lectures_glob"`
+	LabsGlob     string `mapstructure:"labs_glob"`
+	GithubBase   string `mapstructure:"github_base"`
+	Prune        bool   `mapstructure:"prune"`
+	Secret       string `mapstructure:"secret"`
+	// SecretEnv - имя переменной окружения с секретом, чтобы не хранить его в конфиге
+	SecretEnv string `mapstructure:"secret_env"`
+}
+
+func (w *WebhookSource) GetBranch() string {
+	if w.Branch == "" {
+		return "main"
+	}
+	return w.Branch
+}
+
+func (w *WebhookSource) GetSecret() string {
+	if w.SecretEnv != "" {
+		return os.Getenv(w.SecretEnv)
+	}
+	return w.Secret
+}
+
+// FindWebhook возвращает источник по полному имени репозитория без учёта регистра
+func (s *SyncConfig) FindWebhook(repository string) *WebhookSource {
+	for i := range s.Webhooks {
+		if strings.EqualFold(s.Webhooks[i].Repository, repository) {
+			return &s.Webhooks[i]
+		}
+	}
+	return nil
+}
+
 func (a *AuthConfig) GetRateLimitRequests() int {
 	if a.RateLimitRequests <= 0 {
 		return 5
@@ -59,12 +228,237 @@ func (a *AuthConfig) GetRateLimitBurst() int {
 	return a.RateLimitBurst
 }
 
+func (j *JobsConfig) GetWorkers() int {
+	if j.Workers <= 0 {
+		return 2
+	}
+	return j.Workers
+}
+
+func (j *JobsConfig) GetPollInterval() time.Duration {
+	if j.PollIntervalSeconds <= 0 {
+		return 2 * time.Second
+	}
+	return time.Duration(j.PollIntervalSeconds) * time.Second
+}
+
+func (j *JobsConfig) GetVisibilityTimeout() time.Duration {
+	if j.VisibilityTimeoutSeconds <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(j.VisibilityTimeoutSeconds) * time.Second
+}
+
+func (j *JobsConfig) GetMaxAttempts() int {
+	if j.MaxAttempts <= 0 {
+		return 5
+	}
+	return j.MaxAttempts
+}
+
+func (c CacheConfig) GetBackend() string {
+	if c.Backend == "" {
+		return "memory"
+	}
+	return strings.ToLower(c.Backend)
+}
+
+func (c CacheConfig) GetTTL() time.Duration {
+	if c.TTLSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(c.TTLSeconds) * time.Second
+}
+
+func (c CacheConfig) GetMaxEntries() int {
+	if c.MaxEntries <= 0 {
+		return 10000
+	}
+	return c.MaxEntries
+}
+
+func (t TrashConfig) GetRetention() time.Duration {
+	if t.RetentionDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(t.RetentionDays) * 24 * time.Hour
+}
+
+func (t TrashConfig) GetPurgeInterval() time.Duration {
+	if t.PurgeIntervalMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(t.PurgeIntervalMinutes) * time.Minute
+}
+
+func (p PublicationConfig) GetInterval() time.Duration {
+	if p.IntervalSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(p.IntervalSeconds) * time.Second
+}
+
+func (a AttachmentsConfig) GetBackend() string {
+	if a.Backend == "" {
+		return "local"
+	}
+	return strings.ToLower(a.Backend)
+}
+
+func (a AttachmentsConfig) GetDir() string {
+	if a.Dir == "" {
+		return "data/attachments"
+	}
+	return a.Dir
+}
+
+// GetMaxFileSize возвращает наибольший размер файла в байтах, по умолчанию 50 МБ
+func (a AttachmentsConfig) GetMaxFileSize() int64 {
+	if a.MaxFileSizeMB <= 0 {
+		return 50 << 20
+	}
+	return int64(a.MaxFileSizeMB) << 20
+}
+
+// GetCourseQuota возвращает квоту курса в байтах, по умолчанию 1 ГБ
+func (a AttachmentsConfig) GetCourseQuota() int64 {
+	if a.CourseQuotaMB <= 0 {
+		return 1 << 30
+	}
+	return int64(a.CourseQuotaMB) << 20
+}
+
+// GetLocation загружает часовой пояс расписания, по умолчанию Europe/Moscow
+func (c CalendarConfig) GetLocation() (*time.Location, error) {
+	name := c.Timezone
+	if name == "" {
+		name = "Europe/Moscow"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+func (n NotificationsConfig) GetTransport() string {
+	if n.Transport == "" {
+		return "log"
+	}
+	return strings.ToLower(n.Transport)
+}
+
+func (n NotificationsConfig) GetFrom() string {
+	if n.From == "" {
+		return "Laritmo <noreply@localhost>"
+	}
+	return n.From
+}
+
+func (n NotificationsConfig) GetDir() string {
+	if n.Dir == "" {
+		return "data/mail"
+	}
+	return n.Dir
+}
+
+func (n NotificationsConfig) GetReminderInterval() time.Duration {
+	if n.ReminderIntervalMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(n.ReminderIntervalMinutes) * time.Minute
+}
+
+func (s SMTPConfig) GetPort() int {
+	if s.Port > 0 {
+		return s.Port
+	}
+	if s.ImplicitTLS {
+		return 465
+	}
+	return 587
+}
+
+func (s SMTPConfig) GetTimeout() time.Duration {
+	if s.TimeoutSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+func (d DatabaseConfig) GetQueryTimeout() time.Duration {
+	if d.QueryTimeoutSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(d.QueryTimeoutSeconds) * time.Second
+}
+
+func (d DatabaseConfig) GetDriver() string {
+	if d.Driver == "" {
+		return "mysql"
+	}
+	return strings.ToLower(d.Driver)
+}
+
+func (d DatabaseConfig) isSQLite() bool {
+	return d.GetDriver() == "sqlite" || d.GetDriver() == "sqlite3"
+}
+
+func (d DatabaseConfig) isPostgres() bool {
+	switch d.GetDriver() {
+	case "postgres", "postgresql", "pgx":
+		return true
+	}
+	return false
+}
+
+func (d DatabaseConfig) GetPort() int {
+	if d.Port > 0 {
+		return d.Port
+	}
+	if d.isPostgres() {
+		return 5432
+	}
+	return 3306
+}
+
+func (d DatabaseConfig) GetSSLMode() string {
+	if d.SSLMode == "" {
+		return "disable"
+	}
+	return d.SSLMode
+}
+
+// DSN строит строку подключения в формате выбранного драйвера
 func (d DatabaseConfig) DSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	if d.isSQLite() {
+		// Внешние ключи нужны для ON DELETE CASCADE; immediate-транзакции сразу берут
+		// блокировку записи, чтобы параллельные воркеры очереди не получали SQLITE_BUSY
+		params := url.Values{
+			"_pragma":      {"foreign_keys(1)", "busy_timeout(5000)"},
+			"_txlock":      {"immediate"},
+			"_time_format": {"sqlite"},
+		}
+		return "file:" + d.Path + "?" + params.Encode()
+	}
+
+	if d.isPostgres() {
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(d.User, d.Password),
+			Host:     net.JoinHostPort(d.Host, strconv.Itoa(d.GetPort())),
+			Path:     "/" + d.Name,
+			RawQuery: url.Values{"sslmode": {d.GetSSLMode()}}.Encode(),
+		}
+		return dsn.String()
+	}
+
+	// clientFoundRows: RowsAffected считает найденные строки, а не изменённые, как в PostgreSQL и SQLite
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local&clientFoundRows=true",
 		d.User,
 		d.Password,
 		d.Host,
-		d.Port,
+		d.GetPort(),
 		d.Name,
 	)
 }
@@ -74,8 +468,30 @@ func Load(configPath string) (*Config, error) {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	viper.BindEnv("database.driver", "LARITMO_DATABASE_DRIVER")
+	viper.BindEnv("database.path", "LARITMO_DATABASE_PATH")
 	viper.BindEnv("database.password", "LARITMO_DATABASE_PASSWORD")
+	viper.BindEnv("database.auto_migrate", "LARITMO_DATABASE_AUTO_MIGRATE")
+	viper.BindEnv("database.query_timeout_seconds", "LARITMO_DATABASE_QUERY_TIMEOUT_SECONDS")
 	viper.BindEnv("auth.jwt_secret", "LARITMO_AUTH_JWT_SECRET")
+	viper.BindEnv("demo.enabled", "LARITMO_DEMO_ENABLED")
+	viper.BindEnv("demo.admin_password", "LARITMO_DEMO_ADMIN_PASSWORD")
+	viper.BindEnv("cache.enabled", "LARITMO_CACHE_ENABLED")
+	viper.BindEnv("cache.backend", "LARITMO_CACHE_BACKEND")
+	viper.BindEnv("cache.redis.addr", "LARITMO_CACHE_REDIS_ADDR")
+	viper.BindEnv("cache.redis.password", "LARITMO_CACHE_REDIS_PASSWORD")
+	viper.BindEnv("trash.retention_days", "LARITMO_TRASH_RETENTION_DAYS")
+	viper.BindEnv("calendar.timezone", "LARITMO_CALENDAR_TIMEZONE")
+	viper.BindEnv("publication.interval_seconds", "LARITMO_PUBLICATION_INTERVAL_SECONDS")
+	viper.BindEnv("attachments.backend", "LARITMO_ATTACHMENTS_BACKEND")
+	viper.BindEnv("attachments.dir", "LARITMO_ATTACHMENTS_DIR")
+	viper.BindEnv("attachments.s3.access_key", "LARITMO_ATTACHMENTS_S3_ACCESS_KEY")
+	viper.BindEnv("attachments.s3.secret_key", "LARITMO_ATTACHMENTS_S3_SECRET_KEY")
+	viper.BindEnv("notifications.transport", "LARITMO_NOTIFICATIONS_TRANSPORT")
+	viper.BindEnv("notifications.from", "LARITMO_NOTIFICATIONS_FROM")
+	viper.BindEnv("notifications.smtp.host", "LARITMO_NOTIFICATIONS_SMTP_HOST")
+	viper.BindEnv("notifications.smtp.username", "LARITMO_NOTIFICATIONS_SMTP_USERNAME")
+	viper.BindEnv("notifications.smtp.password", "LARITMO_NOTIFICATIONS_SMTP_PASSWORD")
 
 	// New Relic configuration from environment variables
 	viper.BindEnv("newrelic.enabled", "NEWRELIC_ENABLED")
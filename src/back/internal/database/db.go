@@ -6,23 +6,56 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/newrelic/go-agent/v3/integrations/nrmysql"
 )
 
-func Connect(dsn string) (*sql.DB, error) {
-	// Используем nrmysql драйвер для автоматического трейсинга SQL-запросов в New Relic
-	db, err := sql.Open("nrmysql", dsn)
+// sqliteDriver - чистый Go-драйвер modernc.org/sqlite, он работает и в сборках без cgo;
+// дополнительные функции для него регистрируются в sqlite.go
+const sqliteDriver = "sqlite"
+
+// DB - пул соединений вместе с диалектом SQL базы
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+// Connect открывает соединение с базой; driver - значение database.driver из конфига
+func Connect(driver, dsn string) (*DB, error) {
+	dialect, err := ParseDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	// Для MySQL используем nrmysql драйвер для автоматического трейсинга SQL-запросов в New Relic
+	driverName := "nrmysql"
+	switch dialect {
+	case Postgres:
+		driverName = "pgx"
+	case SQLite:
+		driverName = sqliteDriver
+	}
+
+	db, err := sql.Open(driverName, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	if dialect == SQLite {
+		// SQLite допускает одного писателя, а база :memory: живёт, пока открыто её соединение
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(0)
+	} else {
+		db.SetMaxOpenConns(25)
+		db.SetMaxIdleConns(5)
+		db.SetConnMaxLifetime(5 * time.Minute)
+	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	return db, nil
+	return &DB{DB: db, Dialect: dialect}, nil
 }
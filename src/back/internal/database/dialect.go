@@ -0,0 +1,48 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Dialect - диалект SQL поддерживаемой СУБД
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// ParseDialect возвращает диалект по значению database.driver; пустое значение - MySQL
+func ParseDialect(driver string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", "mysql":
+		return MySQL, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	}
+	return "", fmt.Errorf("unsupported database driver %q", driver)
+}
+
+// Placeholder - формат параметров запроса: ? в MySQL, $n в PostgreSQL
+func (d Dialect) Placeholder() sq.PlaceholderFormat {
+	if d == Postgres {
+		return sq.Dollar
+	}
+	return sq.Question
+}
+
+// Builder возвращает squirrel-builder с плейсхолдерами диалекта
+func (d Dialect) Builder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(d.Placeholder())
+}
+
+// Rebind переводит запрос с плейсхолдерами ? в формат диалекта
+func (d Dialect) Rebind(query string) (string, error) {
+	return d.Placeholder().ReplacePlaceholders(query)
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"database/sql/driver"
+	"strings"
+
+	"modernc.org/sqlite"
+)
+
+func init() {
+	// Встроенные lower и LIKE в SQLite не различают регистр только для ASCII,
+	// а поиску нужна кириллица
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case []byte:
+			return strings.ToLower(string(v)), nil
+		default:
+			return v, nil
+		}
+	})
+}
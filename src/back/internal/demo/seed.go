@@ -0,0 +1,105 @@
+// Package demo заполняет пустое хранилище примером курса, чтобы сервер можно было
+// показать без базы данных: demo.enabled включает in-memory хранилище и этот набор данных.
+package demo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AdminUsername - логин администратора демо-стенда
+const AdminUsername = "admin"
+
+const semester = "2025-2026"
+
+var demoLectures = []struct {
+	title   string
+	content string
+}{
+	{"Введение в Go", "# Введение в Go\n\nУстановка инструментов, структура модуля и первая программа.\n\n```go\nfmt.Println(\"Hello, Laritmo!\")\n```"},
+	{"Типы и интерфейсы", "# Типы и интерфейсы\n\nСтруктуры, методы и неявная реализация интерфейсов."},
+	{"Конкурентность", "# Конкурентность\n\nГорутины, каналы и пакет `sync`."},
+}
+
+var demoLabs = []struct {
+	title       string
+	description string
+}{
+	{"Консольный калькулятор", "Реализуйте калькулятор с разбором выражений и тестами."},
+	{"HTTP-сервис заметок", "Напишите REST API заметок на net/http с хранением в памяти."},
+}
+
+var demoExamQuestions = []struct {
+	section  string
+	question string
+}{
+	{"Основы", "Чем срез отличается от массива?"},
+	{"Основы", "Как работает defer и в каком порядке выполняются отложенные вызовы?"},
+	{"Основы", "Что такое нулевое значение типа?"},
+	{"Интерфейсы", "Как проверить, что тип реализует интерфейс, на этапе компиляции?"},
+	{"Интерфейсы", "Чем nil-интерфейс отличается от интерфейса с nil-указателем?"},
+	{"Конкурентность", "Когда использовать канал, а когда sync.Mutex?"},
+	{"Конкурентность", "Как отменить группу горутин через context?"},
+}
+
+// Seed создаёт администратора и курс с лекциями, лабораторными, вопросами к экзамену и ведомостью.
+// Если adminPassword пуст, генерируется случайный пароль; Seed возвращает использованный пароль.
+func Seed(ctx context.Context, store *repository.Store, adminPassword string) (string, error) {
+	if adminPassword == "" {
+		buf := make([]byte, 8)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate admin password: %w", err)
+		}
+		adminPassword = hex.EncodeToString(buf)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash admin password: %w", err)
+	}
+	if _, err := store.Users.Create(ctx, AdminUsername, "admin@laritmo.local", string(hash), models.RoleAdmin); err != nil {
+		return "", fmt.Errorf("failed to create demo admin: %w", err)
+	}
+
+	course, err := store.Courses.Create(ctx, "Программирование на Go", semester, "Демонстрационный курс: данные хранятся в памяти и сбрасываются при перезапуске сервера")
+	if err != nil {
+		return "", fmt.Errorf("failed to create demo course: %w", err)
+	}
+
+	for i, l := range demoLectures {
+		if _, err := store.Lectures.Create(ctx, course.ID, i+1, l.title, l.content, "", "", nil); err != nil {
+			return "", fmt.Errorf("failed to create demo lecture: %w", err)
+		}
+	}
+
+	for i, l := range demoLabs {
+		deadline := time.Now().AddDate(0, 0, 14*(i+1)).Format(time.DateOnly) + " 23:59:59"
+		if _, err := store.Labs.Create(ctx, course.ID, i+1, 10, l.title, l.description, "", &deadline, "", nil); err != nil {
+			return "", fmt.Errorf("failed to create demo lab: %w", err)
+		}
+	}
+
+	// Вопросы нумеруются внутри раздела, как при импорте
+	numbers := make(map[string]int)
+	questions := make([]models.ExamQuestion, len(demoExamQuestions))
+	for i, q := range demoExamQuestions {
+		numbers[q.section]++
+		questions[i] = models.ExamQuestion{CourseID: course.ID, Number: numbers[q.section], Section: q.section, Question: q.question}
+	}
+	if err := store.ExamQuestions.BulkCreate(ctx, questions); err != nil {
+		return "", fmt.Errorf("failed to create demo exam questions: %w", err)
+	}
+
+	if _, err := store.GradeSheets.Create(ctx, course.ID, "https://docs.google.com/spreadsheets/d/demo", "Ведомость демо-курса", "", nil); err != nil {
+		return "", fmt.Errorf("failed to create demo grade sheet: %w", err)
+	}
+
+	return adminPassword, nil
+}
@@ -0,0 +1,44 @@
+package demo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+
+	password, err := Seed(ctx, store, "")
+	require.NoError(t, err)
+	assert.Len(t, password, 16)
+
+	admin, err := store.Users.GetByUsername(ctx, AdminUsername)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, admin.Role)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(password)))
+
+	courses, total, err := store.Courses.GetAll(ctx, repository.CourseFilter{}, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+
+	lectures, err := store.Lectures.GetByCourseID(ctx, courses[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, lectures, len(demoLectures))
+
+	labs, err := store.Labs.GetByCourseID(ctx, courses[0].ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, labs)
+	assert.NotNil(t, labs[0].Deadline)
+
+	questions, err := store.ExamQuestions.GetByCourseID(ctx, courses[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, questions, len(demoExamQuestions))
+}
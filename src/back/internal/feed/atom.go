@@ -0,0 +1,101 @@
+// Package feed формирует ленты новостей в формате Atom (RFC 4287) для подписки
+// из RSS-читалок и агрегаторов.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// ContentType - MIME-тип ленты
+const ContentType = "application/atom+xml; charset=utf-8"
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// Feed - лента с записями
+type Feed struct {
+	// ID - постоянный идентификатор ленты (IRI), не меняется при переименовании
+	ID    string
+	Title string
+	// Author указывается у ленты и относится ко всем записям
+	Author string
+	// Updated - время последнего изменения ленты
+	Updated time.Time
+	Entries []Entry
+}
+
+// Entry - запись ленты. Текст записывается как есть (type="text"), без разметки.
+type Entry struct {
+	// ID должен оставаться тем же при обновлении, чтобы читалки заменяли запись, а не дублировали
+	ID        string
+	Title     string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// Write записывает ленту в кодировке UTF-8; время записывается в UTC
+func Write(w io.Writer, f Feed) error {
+	doc := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: formatTime(f.Updated),
+	}
+	if f.Author != "" {
+		doc.Author = &atomAuthor{Name: f.Author}
+	}
+
+	for _, e := range f.Entries {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Published: formatTime(e.Published),
+			Updated:   formatTime(e.Updated),
+			Content:   atomContent{Type: "text", Text: e.Content},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
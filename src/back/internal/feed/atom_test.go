@@ -0,0 +1,61 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	published := time.Date(2026, 9, 3, 10, 0, 0, 0, moscow)
+
+	var buf bytes.Buffer
+	err := Write(&buf, Feed{
+		ID:      "urn:laritmo:course:1",
+		Title:   "Сети & протоколы",
+		Author:  "Laritmo",
+		Updated: published.Add(time.Hour),
+		Entries: []Entry{{
+			ID:        "urn:laritmo:announcement:7",
+			Title:     "Перенос <дедлайна>",
+			Content:   "Новый дедлайн: 10.09.2026 23:59",
+			Published: published,
+			Updated:   published.Add(time.Hour),
+		}},
+	})
+	require.NoError(t, err)
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, out, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, out, "<title>Сети &amp; протоколы</title>")
+	assert.Contains(t, out, "<updated>2026-09-03T08:00:00Z</updated>", "times are written in UTC")
+	assert.Contains(t, out, "<published>2026-09-03T07:00:00Z</published>")
+	assert.Contains(t, out, "<title>Перенос &lt;дедлайна&gt;</title>")
+	assert.Contains(t, out, `<content type="text">Новый дедлайн: 10.09.2026 23:59</content>`)
+	assert.Contains(t, out, "<author>\n    <name>Laritmo</name>\n  </author>")
+
+	var parsed struct {
+		Entries []struct {
+			ID string `xml:"id"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &parsed))
+	require.Len(t, parsed.Entries, 1)
+	assert.Equal(t, "urn:laritmo:announcement:7", parsed.Entries[0].ID)
+}
+
+func TestWrite_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, Feed{ID: "urn:test", Title: "Пусто", Updated: time.Unix(0, 0)}))
+
+	assert.NotContains(t, buf.String(), "<entry>")
+	assert.NotContains(t, buf.String(), "<author>")
+	assert.Contains(t, buf.String(), "<updated>1970-01-01T00:00:00Z</updated>")
+}
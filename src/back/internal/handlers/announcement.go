@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/feed"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AnnouncementServiceInterface - интерфейс для объявлений курсов и их ленты
+type AnnouncementServiceInterface interface {
+	GetAll(ctx context.Context, filter repository.AnnouncementFilter, opts repository.ListOptions) ([]models.Announcement, int, error)
+	ListByCourse(ctx context.Context, courseID int, opts repository.ListOptions) ([]models.Announcement, int, error)
+	Create(ctx context.Context, courseID int, title, body string) (*models.Announcement, error)
+	Update(ctx context.Context, id int, title, body string) (*models.Announcement, error)
+	Delete(ctx context.Context, id int) error
+	Feed(ctx context.Context, courseID int, w io.Writer) error
+}
+
+// AnnouncerInterface - интерфейс для автоматических объявлений при изменении лекций и лабораторных
+type AnnouncerInterface interface {
+	LabUpdated(ctx context.Context, before, after *models.Lab) error
+	LecturePublished(ctx context.Context, courseID int, title string) error
+}
+
+type AnnouncementHandler struct {
+	service AnnouncementServiceInterface
+	logger  *slog.Logger
+}
+
+type CreateAnnouncementRequest struct {
+	CourseID int    `json:"course_id" binding:"required"`
+	Title    string `json:"title" binding:"required"`
+	Body     string `json:"body" binding:"required"`
+}
+
+type UpdateAnnouncementRequest struct {
+	Title string `json:"title" binding:"required"`
+	Body  string `json:"body" binding:"required"`
+}
+
+func NewAnnouncementHandler(service AnnouncementServiceInterface, logger *slog.Logger) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListByCourse godoc
+// @Summary      List course announcements
+// @Description  Get a page of course announcements, newest first; total count is returned in X-Total-Count. Includes teacher posts and automatic notices about moved deadlines and published lectures
+// @Tags         courses
+// @Produce      json
+// @Param        id      path      int     true   "Course ID"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix with - for descending: id, created_at, updated_at"
+// @Param        limit   query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset  query     int     false  "Page offset"  default(0)
+// @Success      200     {array}   models.Announcement
+// @Header       200     {int}     X-Total-Count  "Total number of course announcements"
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /api/courses/{id}/announcements [get]
+func (h *AnnouncementHandler) ListByCourse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	announcements, total, err := h.service.ListByCourse(c.Request.Context(), id, opts)
+	h.respondList(c, announcements, total, err)
+}
+
+// Feed godoc
+// @Summary      Get course news feed
+// @Description  Atom feed with the latest 50 course announcements for subscribing from feed readers
+// @Tags         courses
+// @Produce      application/atom+xml
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {string}  string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/courses/{id}/feed.atom [get]
+func (h *AnnouncementHandler) Feed(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var buf bytes.Buffer
+	err = h.service.Feed(c.Request.Context(), id, &buf)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to build feed", "error", err, "course_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	c.Data(http.StatusOK, feed.ContentType, buf.Bytes())
+}
+
+// GetAll godoc
+// @Summary      List announcements
+// @Description  Get a page of announcements of all courses, newest first, with optional course and kind filters; total count is returned in X-Total-Count (admin only)
+// @Tags         admin-announcements
+// @Security     BearerAuth
+// @Produce      json
+// @Param        course_id  query     int     false  "Course ID filter"
+// @Param        kind       query     string  false  "Kind filter"  Enums(manual, lab_deadline, lecture_published)
+// @Param        sort       query     string  false  "Comma-separated sort fields, prefix with - for descending: id, course_id, created_at, updated_at"
+// @Param        limit      query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset     query     int     false  "Page offset"  default(0)
+// @Success      200        {array}   models.Announcement
+// @Header       200        {int}     X-Total-Count  "Total number of matching announcements"
+// @Failure      400        {object}  map[string]string
+// @Failure      401        {object}  map[string]string
+// @Failure      403        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /api/admin/announcements [get]
+func (h *AnnouncementHandler) GetAll(c *gin.Context) {
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	var filter repository.AnnouncementFilter
+	if filter.CourseID, ok = parseIntQuery(c, "course_id"); !ok {
+		return
+	}
+	filter.Kind = c.Query("kind")
+
+	announcements, total, err := h.service.GetAll(c.Request.Context(), filter, opts)
+	h.respondList(c, announcements, total, err)
+}
+
+func (h *AnnouncementHandler) respondList(c *gin.Context, announcements []models.Announcement, total int, err error) {
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get announcements", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get announcements"})
+		return
+	}
+
+	if announcements == nil {
+		announcements = []models.Announcement{}
+	}
+
+	setTotalCount(c, total)
+	c.JSON(http.StatusOK, announcements)
+}
+
+// Create godoc
+// @Summary      Create announcement
+// @Description  Post an announcement to a course; it is published immediately (admin only)
+// @Tags         admin-announcements
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        announcement  body      CreateAnnouncementRequest  true  "Announcement"
+// @Success      201           {object}  models.Announcement
+// @Failure      400           {object}  map[string]string
+// @Failure      401           {object}  map[string]string
+// @Failure      403           {object}  map[string]string
+// @Failure      404           {object}  map[string]string
+// @Failure      500           {object}  map[string]string
+// @Router       /api/admin/announcements [post]
+func (h *AnnouncementHandler) Create(c *gin.Context) {
+	var req CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	announcement, err := h.service.Create(c.Request.Context(), req.CourseID, req.Title, req.Body)
+	if errors.Is(err, services.ErrInvalidAnnouncement) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to create announcement", "error", err, "course_id", req.CourseID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create announcement"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Announcement created", "id", announcement.ID, "course_id", announcement.CourseID)
+	c.JSON(http.StatusCreated, announcement)
+}
+
+// Update godoc
+// @Summary      Update announcement
+// @Description  Change the title and text of an announcement, including an automatic one (admin only)
+// @Tags         admin-announcements
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id            path      int                        true  "Announcement ID"
+// @Param        announcement  body      UpdateAnnouncementRequest  true  "Announcement"
+// @Success      200           {object}  models.Announcement
+// @Failure      400           {object}  map[string]string
+// @Failure      401           {object}  map[string]string
+// @Failure      403           {object}  map[string]string
+// @Failure      404           {object}  map[string]string
+// @Failure      500           {object}  map[string]string
+// @Router       /api/admin/announcements/{id} [put]
+func (h *AnnouncementHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req UpdateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	announcement, err := h.service.Update(c.Request.Context(), id, req.Title, req.Body)
+	if errors.Is(err, services.ErrInvalidAnnouncement) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrAnnouncementNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to update announcement", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update announcement"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Announcement updated", "id", id)
+	c.JSON(http.StatusOK, announcement)
+}
+
+// Delete godoc
+// @Summary      Delete announcement
+// @Description  Delete an announcement permanently; it also disappears from the course feed (admin only)
+// @Tags         admin-announcements
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Announcement ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/admin/announcements/{id} [delete]
+func (h *AnnouncementHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = h.service.Delete(c.Request.Context(), id)
+	if errors.Is(err, services.ErrAnnouncementNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to delete announcement", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete announcement"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Announcement deleted", "id", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement deleted"})
+}
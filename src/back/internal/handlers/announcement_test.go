@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/feed"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnouncementHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Сети", "2026-fall", "")
+	require.NoError(t, err)
+	deadline := "2026-10-01T23:59:00Z"
+	lab, err := store.Labs.Create(ctx, course.ID, 1, 10, "Сокеты", "описание", "", &deadline, "", nil)
+	require.NoError(t, err)
+
+	service := services.NewAnnouncementService(store.Announcements, store.Courses)
+	handler := NewAnnouncementHandler(service, slog.Default())
+	labHandler := NewLabHandler(store.Labs, services.NewMarkdownService(), service, &fakeNotifier{}, slog.Default())
+
+	router := gin.New()
+	router.GET("/api/courses/:id/announcements", handler.ListByCourse)
+	router.GET("/api/courses/:id/feed.atom", handler.Feed)
+	router.GET("/api/admin/announcements", handler.GetAll)
+	router.POST("/api/admin/announcements", handler.Create)
+	router.PUT("/api/admin/announcements/:id", handler.Update)
+	router.DELETE("/api/admin/announcements/:id", handler.Delete)
+	router.PUT("/api/admin/labs/:id", labHandler.Update)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	list := func(path string) []models.Announcement {
+		w := serve(http.MethodGet, path, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var announcements []models.Announcement
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &announcements))
+		return announcements
+	}
+	coursePath := "/api/courses/" + strconv.Itoa(course.ID)
+
+	w := serve(http.MethodPost, "/api/admin/announcements",
+		`{"course_id": 1, "title": " Лекции в среду не будет ", "body": "Переносим на пятницу."}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.Announcement
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "Лекции в среду не будет", created.Title)
+	assert.Equal(t, models.AnnouncementKindManual, created.Kind)
+
+	t.Run("moving a deadline posts an announcement", func(t *testing.T) {
+		body := `{"course_id": 1, "number": 1, "title": "Сокеты", "description": "описание", "max_score": 10, "deadline": "2026-10-08T23:59:00Z"}`
+		require.Equal(t, http.StatusOK, serve(http.MethodPut, "/api/admin/labs/"+strconv.Itoa(lab.ID), body).Code)
+		require.Equal(t, http.StatusOK, serve(http.MethodPut, "/api/admin/labs/"+strconv.Itoa(lab.ID), body).Code,
+			"saving the same deadline again")
+
+		announcements := list(coursePath + "/announcements")
+		require.Len(t, announcements, 2)
+		assert.Equal(t, models.AnnouncementKindLabDeadline, announcements[0].Kind, "newest first")
+		assert.Equal(t, "Изменён дедлайн: лабораторная 1. Сокеты", announcements[0].Title)
+		assert.Equal(t, "Новый дедлайн: 08.10.2026 23:59. Прежний дедлайн: 01.10.2026 23:59.", announcements[0].Body)
+
+		assert.Len(t, list("/api/admin/announcements?kind=manual"), 1)
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		w := serve(http.MethodGet, coursePath+"/feed.atom", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, feed.ContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "<title>Сети (2026-fall): объявления</title>")
+		assert.Contains(t, w.Body.String(), "<id>urn:laritmo:announcement:"+strconv.Itoa(created.ID)+"</id>")
+
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/courses/999/feed.atom", "").Code)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		path := "/api/admin/announcements/" + strconv.Itoa(created.ID)
+		w := serve(http.MethodPut, path, `{"title": "Лекция в пятницу", "body": "Аудитория 305."}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var updated models.Announcement
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, "Лекция в пятницу", updated.Title)
+
+		require.Equal(t, http.StatusOK, serve(http.MethodDelete, path, "").Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, path, "").Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPut, path, `{"title": "a", "body": "b"}`).Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/courses/999/announcements", "").Code)
+		assert.Equal(t, http.StatusNotFound,
+			serve(http.MethodPost, "/api/admin/announcements", `{"course_id": 999, "title": "a", "body": "b"}`).Code)
+		assert.Equal(t, http.StatusBadRequest,
+			serve(http.MethodPost, "/api/admin/announcements", `{"course_id": 1, "title": "   ", "body": "b"}`).Code)
+		assert.Equal(t, http.StatusBadRequest,
+			serve(http.MethodPost, "/api/admin/announcements", `{"course_id": 1, "title": "`+strings.Repeat("я", 256)+`", "body": "b"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, coursePath+"/announcements?sort=title", "").Code)
+	})
+}
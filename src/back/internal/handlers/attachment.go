@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead - запас на заголовки multipart сверх размера самого файла
+const multipartOverhead = 1 << 20
+
+// inlineContentTypes - типы, которые браузер показывает сам; остальные файлы только скачиваются,
+// чтобы загруженный HTML или SVG не выполнялся в контексте сайта
+var inlineContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+}
+
+type AttachmentHandler struct {
+	service *services.AttachmentService
+	logger  *slog.Logger
+}
+
+func NewAttachmentHandler(service *services.AttachmentService, logger *slog.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListByLecture godoc
+// @Summary      List lecture attachments
+// @Description  Get files attached to a published lecture in upload order; the staff preview at /api/admin/preview/lectures/{id}/attachments also covers drafts
+// @Tags         attachments
+// @Produce      json
+// @Param        id   path      int  true  "Lecture ID"
+// @Success      200  {array}   models.Attachment
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/lectures/{id}/attachments [get]
+func (h *AttachmentHandler) ListByLecture(c *gin.Context) {
+	h.list(c, services.AttachmentOwnerLecture, "Lecture")
+}
+
+// ListByLab godoc
+// @Summary      List lab attachments
+// @Description  Get files attached to a published lab in upload order; the staff preview at /api/admin/preview/labs/{id}/attachments also covers drafts
+// @Tags         attachments
+// @Produce      json
+// @Param        id   path      int  true  "Lab ID"
+// @Success      200  {array}   models.Attachment
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/labs/{id}/attachments [get]
+func (h *AttachmentHandler) ListByLab(c *gin.Context) {
+	h.list(c, services.AttachmentOwnerLab, "Lab")
+}
+
+func (h *AttachmentHandler) list(c *gin.Context, ownerType, what string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	owner := services.AttachmentOwner{Type: ownerType, ID: id}
+	attachments, err := h.service.List(c.Request.Context(), owner, isStaffPreview(c))
+	if errors.Is(err, services.ErrAttachmentOwnerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": what + " not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get attachments", "error", err, "owner", ownerType, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get attachments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// Download godoc
+// @Summary      Download attachment
+// @Description  Download an attachment of a published lecture or lab. Images and PDF are shown inline, other files are sent as downloads; the staff preview at /api/admin/preview/attachments/{id} also covers drafts
+// @Tags         attachments
+// @Produce      octet-stream
+// @Param        id   path      int  true  "Attachment ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/attachments/{id} [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	attachment, content, err := h.service.Open(c.Request.Context(), id, isStaffPreview(c))
+	if errors.Is(err, services.ErrAttachmentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to open attachment", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open attachment"})
+		return
+	}
+	defer content.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(attachment.ContentType)
+	disposition := "attachment"
+	if inlineContentTypes[mediaType] {
+		disposition = "inline"
+	}
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// UploadToLecture godoc
+// @Summary      Upload lecture attachment
+// @Description  Attach a file to a lecture, including drafts. The content type is detected from the file itself; the size is limited per file and per course (admin only)
+// @Tags         admin-attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      int   true  "Lecture ID"
+// @Param        file  formData  file  true  "File to attach"
+// @Success      201   {object}  models.Attachment
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      413   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/lectures/{id}/attachments [post]
+func (h *AttachmentHandler) UploadToLecture(c *gin.Context) {
+	h.upload(c, services.AttachmentOwnerLecture, "Lecture")
+}
+
+// UploadToLab godoc
+// @Summary      Upload lab attachment
+// @Description  Attach a file to a lab, including drafts. The content type is detected from the file itself; the size is limited per file and per course (admin only)
+// @Tags         admin-attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      int   true  "Lab ID"
+// @Param        file  formData  file  true  "File to attach"
+// @Success      201   {object}  models.Attachment
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      413   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/labs/{id}/attachments [post]
+func (h *AttachmentHandler) UploadToLab(c *gin.Context) {
+	h.upload(c, services.AttachmentOwnerLab, "Lab")
+}
+
+func (h *AttachmentHandler) upload(c *gin.Context, ownerType, what string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxFileSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to open uploaded file", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	owner := services.AttachmentOwner{Type: ownerType, ID: id}
+	attachment, err := h.service.Upload(c.Request.Context(), owner, header.Filename, file, header.Size)
+	switch {
+	case errors.Is(err, services.ErrAttachmentOwnerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": what + " not found"})
+		return
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+		return
+	case errors.Is(err, services.ErrAttachmentQuotaExceeded):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Course attachment quota exceeded"})
+		return
+	case err != nil:
+		h.logger.ErrorContext(c.Request.Context(), "Failed to upload attachment", "error", err, "owner", ownerType, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload attachment"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Attachment uploaded", "id", attachment.ID, "owner", ownerType, "owner_id", id, "size", attachment.Size)
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// Delete godoc
+// @Summary      Delete attachment
+// @Description  Delete an attachment and its file permanently (admin only)
+// @Tags         admin-attachments
+// @Produce      json
+// @Param        id   path      int  true  "Attachment ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = h.service.Delete(c.Request.Context(), id)
+	if errors.Is(err, services.ErrAttachmentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to delete attachment", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Attachment deleted", "id", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted"})
+}
+
+// Usage godoc
+// @Summary      Get course attachment usage
+// @Description  Get the total size of a course's attachments and its quota in bytes; attachments of trashed lectures and labs still count (admin only)
+// @Tags         admin-attachments
+// @Produce      json
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {object}  models.AttachmentUsage
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/courses/{id}/attachments/usage [get]
+func (h *AttachmentHandler) Usage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	usage, err := h.service.Usage(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get attachment usage", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get attachment usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/CreateLab/laritmo/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	draft, err := store.Labs.Create(ctx, course.ID, 1, 10, "Калькулятор", "", "", nil, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Labs.SetPublication(ctx, draft.ID, models.PublicationStatusDraft, nil))
+
+	files, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	service := services.NewAttachmentService(store.Attachments, store.Courses, store.Lectures, store.Labs, files,
+		services.AttachmentLimits{MaxFileSize: 1 << 10, CourseQuota: 1536}, slog.Default())
+	handler := NewAttachmentHandler(service, slog.Default())
+
+	router := gin.New()
+	router.GET("/api/lectures/:id/attachments", handler.ListByLecture)
+	router.GET("/api/labs/:id/attachments", handler.ListByLab)
+	router.GET("/api/attachments/:id", handler.Download)
+	router.POST("/api/admin/lectures/:id/attachments", handler.UploadToLecture)
+	router.POST("/api/admin/labs/:id/attachments", handler.UploadToLab)
+	router.DELETE("/api/admin/attachments/:id", handler.Delete)
+	preview := router.Group("/api/admin/preview", StaffPreview())
+	preview.GET("/labs/:id/attachments", handler.ListByLab)
+	preview.GET("/attachments/:id", handler.Download)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	upload := func(path, fileName string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", fileName)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 600)...)
+	w := upload("/api/admin/lectures/"+strconv.Itoa(lecture.ID)+"/attachments", "схема.png", png)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var image models.Attachment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &image))
+	assert.Equal(t, "image/png", image.ContentType)
+	assert.Equal(t, int64(len(png)), image.Size)
+	assert.NotContains(t, w.Body.String(), "storage_key")
+
+	t.Run("list and inline download", func(t *testing.T) {
+		w := get("/api/lectures/" + strconv.Itoa(lecture.ID) + "/attachments")
+		require.Equal(t, http.StatusOK, w.Code)
+		var list []models.Attachment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list, 1)
+		assert.Equal(t, image.ID, list[0].ID)
+
+		w = get("/api/attachments/" + strconv.Itoa(image.ID))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, png, w.Body.Bytes())
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "inline; filename*=utf-8''%D1%81%D1%85%D0%B5%D0%BC%D0%B0.png", w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("draft attachments need the preview", func(t *testing.T) {
+		html := []byte("<!DOCTYPE html><script>alert(1)</script>")
+		w := upload("/api/admin/labs/"+strconv.Itoa(draft.ID)+"/attachments", "task.html", html)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var page models.Attachment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.Equal(t, http.StatusNotFound, get("/api/labs/"+strconv.Itoa(draft.ID)+"/attachments").Code)
+		assert.Equal(t, http.StatusNotFound, get("/api/attachments/"+strconv.Itoa(page.ID)).Code)
+		assert.Equal(t, http.StatusOK, get("/api/admin/preview/labs/"+strconv.Itoa(draft.ID)+"/attachments").Code)
+
+		w = get("/api/admin/preview/attachments/" + strconv.Itoa(page.ID))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename=task.html`, w.Header().Get("Content-Disposition"),
+			"HTML is never rendered by the browser")
+	})
+
+	t.Run("size limits", func(t *testing.T) {
+		w := upload("/api/admin/lectures/"+strconv.Itoa(lecture.ID)+"/attachments", "big.bin", bytes.Repeat([]byte("x"), 2<<20))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "body over the limit is cut off")
+
+		w = upload("/api/admin/lectures/"+strconv.Itoa(lecture.ID)+"/attachments", "big.bin", bytes.Repeat([]byte("x"), 1025))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		w = upload("/api/admin/lectures/"+strconv.Itoa(lecture.ID)+"/attachments", "notes.txt", bytes.Repeat([]byte("x"), 1000))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "quota")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/api/lectures/999/attachments").Code)
+		assert.Equal(t, http.StatusNotFound, upload("/api/admin/labs/999/attachments", "a.txt", []byte("a")).Code)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/lectures/1/attachments", strings.NewReader("{}")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		stored, err := store.Attachments.GetByID(ctx, image.ID)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/admin/attachments/"+strconv.Itoa(image.ID), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, http.StatusNotFound, get("/api/attachments/"+strconv.Itoa(image.ID)).Code)
+		_, err = files.Get(ctx, stored.StorageKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditServiceInterface - интерфейс для чтения журнала аудита
+type AuditServiceInterface interface {
+	List(ctx context.Context, filter repository.AuditFilter, opts repository.ListOptions) ([]models.AuditEntry, int, error)
+	ExportCSV(ctx context.Context, w io.Writer, filter repository.AuditFilter) error
+}
+
+type AuditHandler struct {
+	service AuditServiceInterface
+	logger  *slog.Logger
+}
+
+func NewAuditHandler(service AuditServiceInterface, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// parseAuditFilter разбирает фильтры журнала; при ошибке сам отвечает 400
+func parseAuditFilter(c *gin.Context) (repository.AuditFilter, bool) {
+	filter := repository.AuditFilter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+	}
+
+	var ok bool
+	if filter.UserID, ok = parseIntQuery(c, "user_id"); !ok {
+		return filter, false
+	}
+	if filter.EntityID, ok = parseIntQuery(c, "entity_id"); !ok {
+		return filter, false
+	}
+	if filter.From, ok = parseDateQuery(c, "from", false); !ok {
+		return filter, false
+	}
+	if filter.To, ok = parseDateQuery(c, "to", true); !ok {
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// GetAll godoc
+// @Summary      Get audit log
+// @Description  Get a page of admin write operations with who, what and when, newest first (admin only)
+// @Tags         admin-audit
+// @Produce      json
+// @Param        user_id      query     int     false  "Admin user ID"
+// @Param        action       query     string  false  "Action, e.g. course.update or exam_question.bulk"
+// @Param        entity_type  query     string  false  "Entity type, e.g. course, lecture, exam_question"
+// @Param        entity_id    query     int     false  "Entity ID"
+// @Param        from         query     string  false  "Lower bound of created_at, inclusive (YYYY-MM-DD or RFC 3339)"
+// @Param        to           query     string  false  "Upper bound of created_at, inclusive (YYYY-MM-DD or RFC 3339)"
+// @Param        sort         query     string  false  "Comma-separated sort fields, prefix with - for descending: id, action, entity_type, username, created_at"
+// @Param        limit        query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset       query     int     false  "Page offset"  default(0)
+// @Success      200          {array}   models.AuditEntry
+// @Header       200          {int}     X-Total-Count  "Total number of matching entries"
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/audit [get]
+func (h *AuditHandler) GetAll(c *gin.Context) {
+	filter, ok := parseAuditFilter(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	entries, total, err := h.service.List(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit log"})
+		return
+	}
+
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+
+	setTotalCount(c, total)
+	c.JSON(http.StatusOK, entries)
+}
+
+// ExportCSV godoc
+// @Summary      Export audit log to CSV
+// @Description  Download all audit entries matching the filters as CSV, oldest first (admin only)
+// @Tags         admin-audit
+// @Produce      text/csv
+// @Param        user_id      query     int     false  "Admin user ID"
+// @Param        action       query     string  false  "Action, e.g. course.update"
+// @Param        entity_type  query     string  false  "Entity type"
+// @Param        entity_id    query     int     false  "Entity ID"
+// @Param        from         query     string  false  "Lower bound of created_at, inclusive (YYYY-MM-DD or RFC 3339)"
+// @Param        to           query     string  false  "Upper bound of created_at, inclusive (YYYY-MM-DD or RFC 3339)"
+// @Success      200          {file}    file
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/audit/export [get]
+func (h *AuditHandler) ExportCSV(c *gin.Context) {
+	filter, ok := parseAuditFilter(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.ExportCSV(c.Request.Context(), &buf, filter); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to export audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export audit log"})
+		return
+	}
+
+	filename := "audit_" + time.Now().Format("20060102_150405") + ".csv"
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
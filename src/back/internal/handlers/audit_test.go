@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	service := services.NewAuditService(store.AuditLog, nil)
+	for i, action := range []string{"course.create", "lecture.update", "lecture.delete"} {
+		entityID := i + 1
+		require.NoError(t, service.Record(ctx, &models.AuditEntry{
+			Username:   "admin",
+			Action:     action,
+			EntityType: strings.Split(action, ".")[0],
+			EntityID:   &entityID,
+			IP:         "127.0.0.1",
+			RequestID:  action,
+		}))
+	}
+
+	handler := NewAuditHandler(service, slog.Default())
+	router := gin.New()
+	router.GET("/api/admin/audit", handler.GetAll)
+	router.GET("/api/admin/audit/export", handler.ExportCSV)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/admin/audit?entity_type=lecture&sort=id")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(totalCountHeader))
+	var entries []models.AuditEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "lecture.update", entries[0].Action)
+
+	w = get("/api/admin/audit?from=tomorrow")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = get("/api/admin/audit?sort=ip")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = get("/api/admin/audit/export?action=course.create")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "course.create")
+}
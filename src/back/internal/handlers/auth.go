@@ -1,18 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 
 	"github.com/CreateLab/laritmo/internal/auth"
 	"github.com/CreateLab/laritmo/internal/models"
-	"github.com/CreateLab/laritmo/internal/repository"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserRepository interface {
-	GetByUsername(username string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
 }
 
 type AuthHandler struct {
@@ -21,11 +21,7 @@ type AuthHandler struct {
 	logger     *slog.Logger
 }
 
-func NewAuthHandler(userRepo *repository.UserRepository, jwtManager *auth.JWTManager, logger *slog.Logger) *AuthHandler {
-	return NewAuthHandlerWithRepo(userRepo, jwtManager, logger)
-}
-
-func NewAuthHandlerWithRepo(userRepo UserRepository, jwtManager *auth.JWTManager, logger *slog.Logger) *AuthHandler {
+func NewAuthHandler(userRepo UserRepository, jwtManager *auth.JWTManager, logger *slog.Logger) *AuthHandler {
 	return &AuthHandler{
 		userRepo:   userRepo,
 		jwtManager: jwtManager,
@@ -58,6 +54,7 @@ type LoginResponse struct {
 // @Success      200          {object}  LoginResponse
 // @Failure      400          {object}  map[string]string
 // @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
 // @Failure      500          {object}  map[string]string
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -68,7 +65,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userRepo.GetByUsername(req.Username)
+	user, err := h.userRepo.GetByUsername(c.Request.Context(), req.Username)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "User not found", "username", req.Username)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
@@ -82,6 +79,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if user.DisabledAt != nil {
+		h.logger.WarnContext(c.Request.Context(), "Login of disabled user", "username", req.Username)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+		return
+	}
+
 	token, err := h.jwtManager.GenerateToken(user)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Token generation error", "error", err)
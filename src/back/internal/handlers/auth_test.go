@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -22,7 +23,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) {
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	args := m.Called(username)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -56,7 +57,7 @@ func TestAuthHandler_Login(t *testing.T) {
 
 		mockRepo.On("GetByUsername", "testuser").Return(user, nil)
 
-		handler := NewAuthHandlerWithRepo(mockRepo, jwtManager, logger)
+		handler := NewAuthHandler(mockRepo, jwtManager, logger)
 
 		router := gin.New()
 		router.POST("/login", handler.Login)
@@ -90,7 +91,7 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockRepo.On("GetByUsername", "nonexistent").Return(nil, errors.New("user not found"))
 
-		handler := NewAuthHandlerWithRepo(mockRepo, jwtManager, logger)
+		handler := NewAuthHandler(mockRepo, jwtManager, logger)
 
 		router := gin.New()
 		router.POST("/login", handler.Login)
@@ -135,7 +136,7 @@ func TestAuthHandler_Login(t *testing.T) {
 
 		mockRepo.On("GetByUsername", "testuser").Return(user, nil)
 
-		handler := NewAuthHandlerWithRepo(mockRepo, jwtManager, logger)
+		handler := NewAuthHandler(mockRepo, jwtManager, logger)
 
 		router := gin.New()
 		router.POST("/login", handler.Login)
@@ -161,9 +162,56 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("disabled user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+
+		password := "correctpassword"
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		assert.NoError(t, err)
+
+		disabledAt := time.Now()
+		user := &models.User{
+			ID:           1,
+			Username:     "testuser",
+			Email:        "test@example.com",
+			PasswordHash: string(hashedPassword),
+			Role:         "admin",
+			DisabledAt:   &disabledAt,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+		mockRepo.On("GetByUsername", "testuser").Return(user, nil)
+
+		handler := NewAuthHandler(mockRepo, jwtManager, logger)
+
+		router := gin.New()
+		router.POST("/login", handler.Login)
+
+		reqBody := LoginRequest{
+			Username: "testuser",
+			Password: password,
+		}
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response map[string]string
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Account is disabled", response["error"])
+
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("invalid request format", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
-		handler := NewAuthHandlerWithRepo(mockRepo, jwtManager, logger)
+		handler := NewAuthHandler(mockRepo, jwtManager, logger)
 
 		router := gin.New()
 		router.POST("/login", handler.Login)
@@ -186,7 +234,7 @@ func TestAuthHandler_Login(t *testing.T) {
 
 	t.Run("missing required fields", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
-		handler := NewAuthHandlerWithRepo(mockRepo, jwtManager, logger)
+		handler := NewAuthHandler(mockRepo, jwtManager, logger)
 
 		router := gin.New()
 		router.POST("/login", handler.Login)
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ContentSyncServiceInterface - интерфейс для синхронизации материалов курса с git-репозиторием
+type ContentSyncServiceInterface interface {
+	Sync(ctx context.Context, courseID int, opts services.ContentSyncOptions) (*models.ContentSyncReport, error)
+}
+
+type ContentSyncHandler struct {
+	service   ContentSyncServiceInterface
+	reposRoot string
+	logger    *slog.Logger
+}
+
+func NewContentSyncHandler(service ContentSyncServiceInterface, reposRoot string, logger *slog.Logger) *ContentSyncHandler {
+	return &ContentSyncHandler{
+		service:   service,
+		reposRoot: reposRoot,
+		logger:    logger,
+	}
+}
+
+// Sync godoc
+// @Summary      Sync course materials from git
+// @Description  Upsert lectures and labs of a course from Markdown files of a repository under sync.repos_root and return the diff (admin only)
+// @Tags         admin-courses
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Course ID"
+// @Param        request  body      models.ContentSyncRequest  true  "Sync parameters"
+// @Success      200      {object}  models.ContentSyncReport
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Failure      503      {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/courses/{id}/sync [post]
+func (h *ContentSyncHandler) Sync(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	if h.reposRoot == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Content sync is not configured"})
+		return
+	}
+
+	var req models.ContentSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	repoPath, ok := resolveRepoPath(h.reposRoot, req.RepoPath)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repo_path"})
+		return
+	}
+
+	report, err := h.service.Sync(c.Request.Context(), courseID, services.ContentSyncOptions{
+		RepoPath:     repoPath,
+		Ref:          req.Ref,
+		LecturesGlob: req.LecturesGlob,
+		LabsGlob:     req.LabsGlob,
+		GithubBase:   req.GithubBase,
+		Prune:        req.Prune,
+		DryRun:       req.DryRun,
+	})
+	if errors.Is(err, services.ErrSyncCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidSyncPattern) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File patterns must be relative to the repository root"})
+		return
+	}
+	if errors.Is(err, services.ErrSyncRepoNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Repository not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to sync course content", "error", err, "course_id", courseID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sync course content"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Course content synced",
+		"course_id", courseID,
+		"dry_run", req.DryRun,
+		"lectures_created", len(report.Lectures.Created),
+		"lectures_updated", len(report.Lectures.Updated),
+		"labs_created", len(report.Labs.Created),
+		"labs_updated", len(report.Labs.Updated),
+	)
+	c.JSON(http.StatusOK, report)
+}
+
+// resolveRepoPath разрешает путь относительно root и запрещает выход за его пределы
+func resolveRepoPath(root, repoPath string) (string, bool) {
+	if repoPath == "" || filepath.IsAbs(repoPath) {
+		return "", false
+	}
+
+	resolved := filepath.Join(root, repoPath)
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return resolved, true
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -11,7 +12,7 @@ import (
 )
 
 type CourseHandler struct {
-	repo   *repository.CourseRepository
+	repo   repository.CourseStore
 	logger *slog.Logger
 }
 
@@ -27,7 +28,18 @@ type UpdateCourseRequest struct {
 	Description string `json:"description"`
 }
 
-func NewCourseHandler(repo *repository.CourseRepository, logger *slog.Logger) *CourseHandler {
+// CloneCourseRequest - параметры копии курса для нового семестра
+type CloneCourseRequest struct {
+	Semester string `json:"semester" binding:"required"`
+	// Name и Description по умолчанию берутся из исходного курса
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	// DeadlineShiftDays сдвигает дедлайны лабораторных, например 364, чтобы сохранить дни недели
+	DeadlineShiftDays int  `json:"deadline_shift_days"`
+	WithGradeSheets   bool `json:"with_grade_sheets"`
+}
+
+func NewCourseHandler(repo repository.CourseStore, logger *slog.Logger) *CourseHandler {
 	return &CourseHandler{
 		repo:   repo,
 		logger: logger,
@@ -36,14 +48,37 @@ func NewCourseHandler(repo *repository.CourseRepository, logger *slog.Logger) *C
 
 // GetAll godoc
 // @Summary      Get all courses
-// @Description  Get list of all courses
+// @Description  Get a page of courses with optional semester and archive filters; total count is returned in X-Total-Count
 // @Tags         courses
 // @Produce      json
-// @Success      200  {array}   models.Course
-// @Failure      500  {object}  map[string]string
+// @Param        semester  query     string  false  "Semester filter"
+// @Param        archived  query     bool    false  "Only archived (true) or only active (false) courses"
+// @Param        sort      query     string  false  "Comma-separated sort fields, prefix with - for descending: id, name, semester, created_at, updated_at"
+// @Param        limit     query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset    query     int     false  "Page offset"  default(0)
+// @Success      200       {array}   models.Course
+// @Header       200       {int}     X-Total-Count  "Total number of matching courses"
+// @Failure      400       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
 // @Router       /api/courses [get]
 func (h *CourseHandler) GetAll(c *gin.Context) {
-	courses, err := h.repo.GetAll()
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	archived, ok := parseBoolQuery(c, "archived")
+	if !ok {
+		return
+	}
+
+	filter := repository.CourseFilter{Semester: c.Query("semester"), Archived: archived}
+
+	courses, total, err := h.repo.GetAll(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get courses", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get courses"})
@@ -54,6 +89,7 @@ func (h *CourseHandler) GetAll(c *gin.Context) {
 		courses = []models.Course{}
 	}
 
+	setTotalCount(c, total)
 	c.JSON(http.StatusOK, courses)
 }
 
@@ -75,7 +111,7 @@ func (h *CourseHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	course, err := h.repo.GetByID(id)
+	course, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get course", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course"})
@@ -112,7 +148,7 @@ func (h *CourseHandler) Create(c *gin.Context) {
 		return
 	}
 
-	course, err := h.repo.Create(req.Name, req.Semester, req.Description)
+	course, err := h.repo.Create(c.Request.Context(), req.Name, req.Semester, req.Description)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to create course", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to create course"})
@@ -136,6 +172,7 @@ func (h *CourseHandler) Create(c *gin.Context) {
 // @Failure      400     {object}  map[string]string
 // @Failure      401     {object}  map[string]string
 // @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
 // @Failure      500     {object}  map[string]string
 // @Router       /api/admin/courses/{id} [put]
 func (h *CourseHandler) Update(c *gin.Context) {
@@ -148,7 +185,11 @@ func (h *CourseHandler) Update(c *gin.Context) {
 		return
 	}
 
-	err := h.repo.Update(id, req.Name, req.Semester, req.Description)
+	err := h.repo.Update(c.Request.Context(), id, req.Name, req.Semester, req.Description)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to update course", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to update course"})
@@ -161,7 +202,7 @@ func (h *CourseHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary      Delete course
-// @Description  Delete course by ID
+// @Description  Move course and its materials to trash by ID
 // @Tags         admin-courses
 // @Security     BearerAuth
 // @Produce      json
@@ -174,7 +215,7 @@ func (h *CourseHandler) Update(c *gin.Context) {
 func (h *CourseHandler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	err := h.repo.Delete(id)
+	err := h.repo.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to delete course", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to delete course"})
@@ -184,3 +225,54 @@ func (h *CourseHandler) Delete(c *gin.Context) {
 	h.logger.InfoContext(c.Request.Context(), "Course deleted", "id", id)
 	c.JSON(200, gin.H{"message": "Course deleted"})
 }
+
+// Clone godoc
+// @Summary      Clone course
+// @Description  Copy a course into a new semester with its lectures, labs (deadlines shifted by deadline_shift_days), exam questions and optionally grade sheet links in one transaction (admin only)
+// @Tags         admin-courses
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                 true  "Source course ID"
+// @Param        clone   body      CloneCourseRequest  true  "Clone options"
+// @Success      201     {object}  models.CourseCloneSummary
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /api/admin/courses/{id}/clone [post]
+func (h *CourseHandler) Clone(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req CloneCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	summary, err := h.repo.Clone(c.Request.Context(), id, repository.CourseCloneOptions{
+		Name:              req.Name,
+		Semester:          req.Semester,
+		Description:       req.Description,
+		DeadlineShiftDays: req.DeadlineShiftDays,
+		WithGradeSheets:   req.WithGradeSheets,
+	})
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to clone course", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone course"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Course cloned", "source_id", id, "id", summary.Course.ID)
+	c.JSON(http.StatusCreated, summary)
+}
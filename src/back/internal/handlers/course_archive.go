@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxCourseArchiveUpload ограничивает размер загружаемого архива курса
+const maxCourseArchiveUpload = 64 << 20
+
+// CourseArchiveServiceInterface - интерфейс для экспорта и импорта архивов курсов
+type CourseArchiveServiceInterface interface {
+	Export(ctx context.Context, courseID int, w io.Writer) (*models.Course, error)
+	Import(ctx context.Context, r io.ReaderAt, size int64, opts services.CourseArchiveImportOptions) (*models.CourseImportSummary, error)
+}
+
+type CourseArchiveHandler struct {
+	service CourseArchiveServiceInterface
+	logger  *slog.Logger
+}
+
+func NewCourseArchiveHandler(service CourseArchiveServiceInterface, logger *slog.Logger) *CourseArchiveHandler {
+	return &CourseArchiveHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Export godoc
+// @Summary      Export course archive
+// @Description  Download a course with its lectures, labs, exam questions and grade sheets as a zip archive: manifest.json, Markdown files for lectures and labs, exam_questions.csv (admin only)
+// @Tags         admin-courses
+// @Produce      application/zip
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/courses/{id}/export [get]
+func (h *CourseArchiveHandler) Export(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var buf bytes.Buffer
+	_, err = h.service.Export(c.Request.Context(), id, &buf)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to export course", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export course"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=course_%d.zip", id))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// Import godoc
+// @Summary      Import course archive
+// @Description  Create a new course from an archive produced by the export endpoint. Archives of older schema versions are upgraded on import (admin only)
+// @Tags         admin-courses
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file      formData  file    true   "Course archive (.zip)"
+// @Param        semester  formData  string  false  "Semester of the new course instead of the one in the archive"
+// @Success      201       {object}  models.CourseImportSummary
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      413       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/courses/import [post]
+func (h *CourseArchiveHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCourseArchiveUpload+multipartOverhead)
+	file, header, err := c.Request.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Archive must not exceed %d MB", maxCourseArchiveUpload>>20)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File not found"})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxCourseArchiveUpload {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Archive must not exceed %d MB", maxCourseArchiveUpload>>20)})
+		return
+	}
+
+	summary, err := h.service.Import(c.Request.Context(), file, header.Size, services.CourseArchiveImportOptions{
+		Semester: c.PostForm("semester"),
+	})
+	if errors.Is(err, services.ErrInvalidCourseArchive) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to import course", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import course"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Course imported", "id", summary.Course.ID, "schema_version", summary.SchemaVersion)
+	c.JSON(http.StatusCreated, summary)
+}
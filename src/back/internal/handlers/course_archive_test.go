@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCourseArchiveHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, course.ID, 1, "Введение", "# Привет", "", "", nil)
+	require.NoError(t, err)
+
+	service := services.NewCourseArchiveService(store.Courses, store.Lectures, store.Labs, store.ExamQuestions, store.GradeSheets)
+	handler := NewCourseArchiveHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := gin.New()
+	router.GET("/api/admin/courses/:id/export", handler.Export)
+	router.POST("/api/admin/courses/import", handler.Import)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/courses/999/export", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/courses/1/export", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	archive := w.Body.Bytes()
+
+	upload := func(data []byte, semester string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "course.zip")
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		if semester != "" {
+			require.NoError(t, form.WriteField("semester", semester))
+		}
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/courses/import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w = upload(archive, "2026-fall")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var summary models.CourseImportSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, "2026-fall", summary.Course.Semester)
+	assert.Equal(t, 1, summary.Lectures)
+
+	assert.Equal(t, http.StatusBadRequest, upload([]byte("not a zip"), "").Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload(bytes.Repeat([]byte("x"), maxCourseArchiveUpload+multipartOverhead), "").Code,
+		"body over the limit is cut off")
+}
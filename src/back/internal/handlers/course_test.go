@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCourseTestRouter(store *repository.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewCourseHandler(store.Courses, slog.Default())
+
+	router := gin.New()
+	router.GET("/api/courses", handler.GetAll)
+	router.GET("/api/courses/:id", handler.GetByID)
+	router.POST("/api/admin/courses", handler.Create)
+	router.DELETE("/api/admin/courses/:id", handler.Delete)
+	router.POST("/api/admin/courses/:id/clone", handler.Clone)
+	return router
+}
+
+func TestCourseHandler_GetAll(t *testing.T) {
+	store := memory.NewStore(memory.NewDB())
+	ctx := context.Background()
+	for _, name := range []string{"Go", "Алгоритмы", "Базы данных"} {
+		_, err := store.Courses.Create(ctx, name, "2025-2026", "")
+		require.NoError(t, err)
+	}
+	_, err := store.Courses.Create(ctx, "Архив", "2023-2024", "")
+	require.NoError(t, err)
+
+	router := newCourseTestRouter(store)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedNames  []string
+		expectedTotal  string
+	}{
+		{
+			name:           "default order by semester and name",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"Архив", "Go", "Алгоритмы", "Базы данных"},
+			expectedTotal:  "4",
+		},
+		{
+			name:           "filter and page",
+			query:          "?semester=2025-2026&sort=-name&limit=2",
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"Базы данных", "Алгоритмы"},
+			expectedTotal:  "3",
+		},
+		{
+			name:           "invalid sort",
+			query:          "?sort=password",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/courses"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var courses []models.Course
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &courses))
+			var names []string
+			for _, c := range courses {
+				names = append(names, c.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			assert.Equal(t, tt.expectedTotal, w.Header().Get("X-Total-Count"))
+		})
+	}
+}
+
+func TestCourseHandler_CreateGetDelete(t *testing.T) {
+	store := memory.NewStore(memory.NewDB())
+	router := newCourseTestRouter(store)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/courses",
+		strings.NewReader(`{"name": "Go", "semester": "2025-2026"}`)))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Course
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "Go", created.Name)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/courses/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/admin/courses/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/courses/1", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/courses", strings.NewReader(`{"name": "Go"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCourseHandler_Clone(t *testing.T) {
+	store := memory.NewStore(memory.NewDB())
+	ctx := context.Background()
+	source, err := store.Courses.Create(ctx, "Go", "2024-2025", "Осень")
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, source.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	deadline := "2024-10-01 23:59:59"
+	_, err = store.Labs.Create(ctx, source.ID, 1, 10, "Калькулятор", "", "", &deadline, "", nil)
+	require.NoError(t, err)
+	_, err = store.GradeSheets.Create(ctx, source.ID, "https://example.com/sheet", "", "", nil)
+	require.NoError(t, err)
+
+	router := newCourseTestRouter(store)
+	clone := func(id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/courses/"+id+"/clone", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := clone(strconv.Itoa(source.ID), `{"semester":"2025-2026","deadline_shift_days":365,"with_grade_sheets":true}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var summary models.CourseCloneSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, source.ID, summary.SourceID)
+	assert.Equal(t, "Go", summary.Course.Name)
+	assert.Equal(t, "2025-2026", summary.Course.Semester)
+	assert.Equal(t, 1, summary.Lectures)
+	assert.Equal(t, 1, summary.Labs)
+	assert.Equal(t, 1, summary.GradeSheets)
+
+	labs, err := store.Labs.GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, labs, 1)
+	assert.Equal(t, "2025-10-01", labs[0].Deadline.Format(time.DateOnly))
+
+	assert.Equal(t, http.StatusBadRequest, clone(strconv.Itoa(source.ID), `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, clone("abc", `{"semester":"x"}`).Code)
+	assert.Equal(t, http.StatusNotFound, clone("999", `{"semester":"x"}`).Code)
+}
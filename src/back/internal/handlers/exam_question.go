@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -15,11 +16,11 @@ import (
 )
 
 type ExamQuestionHandler struct {
-	repo   *repository.ExamQuestionRepository
+	repo   repository.ExamQuestionStore
 	logger *slog.Logger
 }
 
-func NewExamQuestionHandler(repo *repository.ExamQuestionRepository, logger *slog.Logger) *ExamQuestionHandler {
+func NewExamQuestionHandler(repo repository.ExamQuestionStore, logger *slog.Logger) *ExamQuestionHandler {
 	return &ExamQuestionHandler{
 		repo:   repo,
 		logger: logger,
@@ -28,29 +29,35 @@ func NewExamQuestionHandler(repo *repository.ExamQuestionRepository, logger *slo
 
 // GetAll godoc
 // @Summary      Get all exam questions
-// @Description  Get list of all exam questions with optional course filter
+// @Description  Get a page of exam questions with optional course and section filters; total count is returned in X-Total-Count
 // @Tags         exam-questions
 // @Produce      json
-// @Param        course_id  query     int  false  "Course ID filter"
+// @Param        course_id  query     int     false  "Course ID filter"
+// @Param        section    query     string  false  "Section filter"
+// @Param        sort       query     string  false  "Comma-separated sort fields, prefix with - for descending: id, course_id, section, number, created_at, updated_at"
+// @Param        limit      query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset     query     int     false  "Page offset"  default(0)
 // @Success      200        {array}   models.ExamQuestion
+// @Header       200        {int}     X-Total-Count  "Total number of matching exam questions"
 // @Failure      400        {object}  map[string]string
 // @Failure      500        {object}  map[string]string
 // @Router       /api/exam-questions [get]
 func (h *ExamQuestionHandler) GetAll(c *gin.Context) {
-	var questions []models.ExamQuestion
-	var err error
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
 
-	if courseIDStr := c.Query("course_id"); courseIDStr != "" {
-		courseID, err := strconv.Atoi(courseIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course_id"})
-			return
-		}
-		questions, err = h.repo.GetByCourseID(courseID)
-	} else {
-		questions, err = h.repo.GetAll()
+	filter := repository.ExamQuestionFilter{Section: c.Query("section")}
+	if filter.CourseID, ok = parseIntQuery(c, "course_id"); !ok {
+		return
 	}
 
+	questions, total, err := h.repo.GetAll(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get exam questions", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get exam questions"})
@@ -61,6 +68,7 @@ func (h *ExamQuestionHandler) GetAll(c *gin.Context) {
 		questions = []models.ExamQuestion{}
 	}
 
+	setTotalCount(c, total)
 	c.JSON(http.StatusOK, questions)
 }
 
@@ -82,7 +90,7 @@ func (h *ExamQuestionHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	question, err := h.repo.GetByID(id)
+	question, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get exam question", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get exam question"})
@@ -141,7 +149,7 @@ func (h *ExamQuestionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	question, err := h.repo.Create(req.CourseID, req.Number, req.Section, req.Question)
+	question, err := h.repo.Create(c.Request.Context(), req.CourseID, req.Number, req.Section, req.Question)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to create exam question", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create exam question"})
@@ -181,7 +189,7 @@ func (h *ExamQuestionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	existingQuestion, err := h.repo.GetByID(id)
+	existingQuestion, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get exam question", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get exam question"})
@@ -192,7 +200,7 @@ func (h *ExamQuestionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	err = h.repo.Update(id, existingQuestion.CourseID, req.Number, req.Section, req.Question)
+	err = h.repo.Update(c.Request.Context(), id, existingQuestion.CourseID, req.Number, req.Section, req.Question)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to update exam question", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update exam question"})
@@ -205,7 +213,7 @@ func (h *ExamQuestionHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary      Delete exam question
-// @Description  Delete exam question by ID (admin only)
+// @Description  Move exam question to trash by ID (admin only)
 // @Tags         admin-exam-questions
 // @Produce      json
 // @Param        id   path      int  true  "Exam Question ID"
@@ -223,7 +231,7 @@ func (h *ExamQuestionHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.repo.Delete(id)
+	err = h.repo.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to delete exam question", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete exam question"})
@@ -271,7 +279,7 @@ func (h *ExamQuestionHandler) BulkCreateJSON(c *gin.Context) {
 		})
 	}
 
-	err := h.repo.BulkCreate(questions)
+	err := h.repo.BulkCreate(c.Request.Context(), questions)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Bulk creation error exam questions", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bulk creation error exam questions"})
@@ -421,7 +429,7 @@ func (h *ExamQuestionHandler) BulkUploadFile(c *gin.Context) {
 		return
 	}
 
-	err = h.repo.BulkCreate(questions)
+	err = h.repo.BulkCreate(c.Request.Context(), questions)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Bulk creation error exam questions", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create questions: %v", err)})
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/CreateLab/laritmo/internal/models"
 	"github.com/CreateLab/laritmo/internal/repository"
@@ -11,39 +13,52 @@ import (
 )
 
 type GradeSheetHandler struct {
-	repo   *repository.GradeSheetRepository
-	logger *slog.Logger
+	repo     repository.GradeSheetStore
+	notifier NotifierInterface
+	logger   *slog.Logger
 }
 
-func NewGradeSheetHandler(repo *repository.GradeSheetRepository, logger *slog.Logger) *GradeSheetHandler {
+func NewGradeSheetHandler(repo repository.GradeSheetStore, notifier NotifierInterface, logger *slog.Logger) *GradeSheetHandler {
 	return &GradeSheetHandler{
-		repo:   repo,
-		logger: logger,
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
 	}
 }
 
 // GetAll godoc
 // @Summary      Get all grade sheets
-// @Description  Get list of all grade sheets with optional course filter
+// @Description  Get a page of published grade sheets with optional course filter; total count is returned in X-Total-Count. The staff preview at /api/admin/preview/grade-sheets also returns drafts and accepts a status filter
 // @Tags         grade-sheets
 // @Produce      json
-// @Param        course_id  query     int  false  "Course ID filter"
+// @Param        course_id  query     int     false  "Course ID filter"
+// @Param        sort       query     string  false  "Comma-separated sort fields, prefix with - for descending: id, course_id, created_at, updated_at"
+// @Param        limit      query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset     query     int     false  "Page offset"  default(0)
 // @Success      200        {array}   models.GradeSheet
+// @Header       200        {int}     X-Total-Count  "Total number of matching grade sheets"
 // @Failure      400        {object}  map[string]string
 // @Failure      500        {object}  map[string]string
 // @Router       /api/grade-sheets [get]
 func (h *GradeSheetHandler) GetAll(c *gin.Context) {
-	var courseID *int
-	if courseIDStr := c.Query("course_id"); courseIDStr != "" {
-		id, err := strconv.Atoi(courseIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course_id"})
-			return
-		}
-		courseID = &id
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	var filter repository.GradeSheetFilter
+	if filter.CourseID, ok = parseIntQuery(c, "course_id"); !ok {
+		return
+	}
+	if filter.Status, ok = parseStatusQuery(c); !ok {
+		return
 	}
 
-	sheets, err := h.repo.GetAll(courseID)
+	sheets, total, err := h.repo.GetAll(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get grade sheets", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get grade sheets"})
@@ -54,12 +69,14 @@ func (h *GradeSheetHandler) GetAll(c *gin.Context) {
 		sheets = []models.GradeSheet{}
 	}
 
+	setTotalCount(c, total)
+
 	c.JSON(http.StatusOK, sheets)
 }
 
 // GetByID godoc
 // @Summary      Get grade sheet by ID
-// @Description  Get grade sheet details by ID
+// @Description  Get published grade sheet details by ID; drafts are available only in the staff preview at /api/admin/preview/grade-sheets/{id}
 // @Tags         grade-sheets
 // @Produce      json
 // @Param        id   path      int  true  "Grade Sheet ID"
@@ -75,14 +92,14 @@ func (h *GradeSheetHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	sheet, err := h.repo.GetByID(id)
+	sheet, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get grade sheet", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get grade sheet"})
 		return
 	}
 
-	if sheet == nil {
+	if sheet == nil || !isVisible(c, sheet.Status) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Grade sheet not found"})
 		return
 	}
@@ -91,19 +108,23 @@ func (h *GradeSheetHandler) GetByID(c *gin.Context) {
 }
 
 type CreateGradeSheetRequest struct {
-	CourseID    int    `json:"course_id" binding:"required"`
-	SheetURL    string `json:"sheet_url" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	CourseID    int        `json:"course_id" binding:"required"`
+	SheetURL    string     `json:"sheet_url" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
 }
 
 type UpdateGradeSheetRequest struct {
-	SheetURL    string `json:"sheet_url" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	SheetURL    string     `json:"sheet_url" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
 }
 
 // Create godoc
 // @Summary      Create grade sheet
-// @Description  Create a new grade sheet (admin only)
+// @Description  Create a new grade sheet; it is published unless status is draft. A draft with publish_at is published automatically at that time. Publishing a grade sheet emails students (admin only)
 // @Tags         admin-gradesheets
 // @Accept       json
 // @Produce      json
@@ -122,21 +143,26 @@ func (h *GradeSheetHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	gradeSheet, err := h.repo.Create(req.CourseID, req.SheetURL, req.Description)
+	gradeSheet, err := h.repo.Create(c.Request.Context(), req.CourseID, req.SheetURL, req.Description, req.Status, req.PublishAt)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to create grade sheet", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create grade sheet"})
 		return
 	}
 
+	h.notifyPublished(c, nil, gradeSheet)
 	h.logger.InfoContext(c.Request.Context(), "Grade sheet created", "id", gradeSheet.ID)
 	c.JSON(http.StatusCreated, gradeSheet)
 }
 
 // Update godoc
 // @Summary      Update grade sheet
-// @Description  Update grade sheet by ID (admin only)
+// @Description  Update grade sheet by ID; status and publish_at are changed only when status is given. Publishing a draft emails students (admin only)
 // @Tags         admin-gradesheets
 // @Accept       json
 // @Produce      json
@@ -146,6 +172,7 @@ func (h *GradeSheetHandler) Create(c *gin.Context) {
 // @Failure      400         {object}  map[string]string
 // @Failure      401         {object}  map[string]string
 // @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
 // @Failure      500         {object}  map[string]string
 // @Security     BearerAuth
 // @Router       /api/admin/grade-sheets/{id} [put]
@@ -162,21 +189,82 @@ func (h *GradeSheetHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	err = h.repo.Update(id, req.SheetURL, req.Description)
+	before, err := h.repo.GetByID(c.Request.Context(), id)
+	if err == nil {
+		err = h.repo.Update(c.Request.Context(), id, req.SheetURL, req.Description)
+	}
+	if err == nil && req.Status != "" {
+		err = h.repo.SetPublication(c.Request.Context(), id, req.Status, req.PublishAt)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grade sheet not found"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to update grade sheet", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update grade sheet"})
 		return
 	}
 
+	if before != nil {
+		after, err := h.repo.GetByID(c.Request.Context(), id)
+		if err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "Failed to get grade sheet", "error", err, "id", id)
+		} else {
+			h.notifyPublished(c, before, after)
+		}
+	}
+
 	h.logger.InfoContext(c.Request.Context(), "Grade sheet updated", "id", id)
 	c.JSON(http.StatusOK, gin.H{"message": "Grade sheet updated"})
 }
 
+// SetPublication godoc
+// @Summary      Set grade sheet publication
+// @Description  Publish a grade sheet or turn it into a draft; a draft with publish_at is published automatically at that time. Publishing a draft emails students (admin only)
+// @Tags         admin-gradesheets
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                 true  "Grade Sheet ID"
+// @Param        publication  body      PublicationRequest  true  "Publication status"
+// @Success      200          {object}  models.GradeSheet
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/grade-sheets/{id}/publication [put]
+func (h *GradeSheetHandler) SetPublication(c *gin.Context) {
+	before, after := setPublication(c, h.logger, "Grade sheet", h.repo.GetByID, h.repo.SetPublication)
+	if before != nil {
+		h.notifyPublished(c, before, after)
+	}
+}
+
+// notifyPublished рассылает письма об оценках, если ведомость стала опубликованной: создана сразу
+// опубликованной (before равен nil) или из черновика. Ошибка рассылки только записывается в лог.
+func (h *GradeSheetHandler) notifyPublished(c *gin.Context, before, after *models.GradeSheet) {
+	if after == nil || after.Status != models.PublicationStatusPublished {
+		return
+	}
+	if before != nil && before.Status == models.PublicationStatusPublished {
+		return
+	}
+
+	if err := h.notifier.GradeSheetPublished(c.Request.Context(), after.ID); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to send grade notifications", "error", err, "id", after.ID)
+	}
+}
+
 // Delete godoc
 // @Summary      Delete grade sheet
-// @Description  Delete grade sheet by ID (admin only)
+// @Description  Move grade sheet to trash by ID (admin only)
 // @Tags         admin-gradesheets
 // @Produce      json
 // @Param        id   path      int  true  "Grade Sheet ID"
@@ -194,7 +282,7 @@ func (h *GradeSheetHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.repo.Delete(id)
+	err = h.repo.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to delete grade sheet", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete grade sheet"})
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// JobRepositoryInterface - интерфейс для чтения состояния фоновых задач
+type JobRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Job, error)
+	GetResult(ctx context.Context, jobID int) (*models.JobResult, error)
+}
+
+type JobHandler struct {
+	repo   JobRepositoryInterface
+	logger *slog.Logger
+}
+
+func NewJobHandler(repo JobRepositoryInterface, logger *slog.Logger) *JobHandler {
+	return &JobHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetByID godoc
+// @Summary      Get background job status
+// @Description  Get status, attempts and last error of a background job (admin only)
+// @Tags         admin-jobs
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  models.Job
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/jobs/{id} [get]
+func (h *JobHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	job, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get job", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadResult godoc
+// @Summary      Download background job result
+// @Description  Download the file produced by a succeeded background job (admin only)
+// @Tags         admin-jobs
+// @Produce      octet-stream
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {file}    binary
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/jobs/{id}/result [get]
+func (h *JobHandler) DownloadResult(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	job, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get job", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status != models.JobStatusSucceeded {
+		c.JSON(http.StatusConflict, gin.H{"error": "Job has not succeeded", "status": job.Status, "progress": job.Progress})
+		return
+	}
+
+	result, err := h.repo.GetResult(c.Request.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get job result", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job result"})
+		return
+	}
+	if result == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job has no result"})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+result.Filename)
+	c.Data(http.StatusOK, result.ContentType, result.Content)
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/CreateLab/laritmo/internal/models"
 	"github.com/CreateLab/laritmo/internal/repository"
@@ -11,39 +13,71 @@ import (
 )
 
 type LabHandler struct {
-	repo   *repository.LabRepository
-	logger *slog.Logger
+	repo      repository.LabStore
+	renderer  MarkdownRendererInterface
+	announcer AnnouncerInterface
+	notifier  NotifierInterface
+	logger    *slog.Logger
 }
 
-func NewLabHandler(repo *repository.LabRepository, logger *slog.Logger) *LabHandler {
+func NewLabHandler(repo repository.LabStore, renderer MarkdownRendererInterface, announcer AnnouncerInterface, notifier NotifierInterface, logger *slog.Logger) *LabHandler {
 	return &LabHandler{
-		repo:   repo,
-		logger: logger,
+		repo:      repo,
+		renderer:  renderer,
+		announcer: announcer,
+		notifier:  notifier,
+		logger:    logger,
 	}
 }
 
 // GetAll godoc
 // @Summary      Get all labs
-// @Description  Get list of all labs with optional course filter
+// @Description  Get a page of published labs with optional course and deadline range filters; total count is returned in X-Total-Count. The staff preview at /api/admin/preview/labs also returns drafts and accepts a status filter
 // @Tags         labs
 // @Produce      json
-// @Param        course_id  query     int  false  "Course ID filter"
-// @Success      200        {array}   models.Lab
-// @Failure      400        {object}  map[string]string
-// @Failure      500        {object}  map[string]string
+// @Param        course_id      query     int     false  "Course ID filter"
+// @Param        deadline_from  query     string  false  "Deadline lower bound, inclusive (YYYY-MM-DD or RFC 3339)"
+// @Param        deadline_to    query     string  false  "Deadline upper bound, inclusive (YYYY-MM-DD or RFC 3339)"
+// @Param        sort           query     string  false  "Comma-separated sort fields, prefix with - for descending: id, course_id, number, title, deadline, max_score, created_at, updated_at"
+// @Param        limit          query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset         query     int     false  "Page offset"  default(0)
+// @Param        render         query     string  false  "Set to html to include rendered HTML and table of contents"  Enums(html)
+// @Success      200            {array}   models.Lab
+// @Header       200            {int}     X-Total-Count  "Total number of matching labs"
+// @Failure      400            {object}  map[string]string
+// @Failure      500            {object}  map[string]string
 // @Router       /api/labs [get]
 func (h *LabHandler) GetAll(c *gin.Context) {
-	var courseID *int
-	if courseIDStr := c.Query("course_id"); courseIDStr != "" {
-		id, err := strconv.Atoi(courseIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course_id"})
-			return
-		}
-		courseID = &id
+	renderHTML, ok := parseRenderParam(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid render parameter"})
+		return
 	}
 
-	labs, err := h.repo.GetAll(courseID)
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	var filter repository.LabFilter
+	if filter.CourseID, ok = parseIntQuery(c, "course_id"); !ok {
+		return
+	}
+	if filter.DeadlineFrom, ok = parseDateQuery(c, "deadline_from", false); !ok {
+		return
+	}
+	if filter.DeadlineTo, ok = parseDateQuery(c, "deadline_to", true); !ok {
+		return
+	}
+	if filter.Status, ok = parseStatusQuery(c); !ok {
+		return
+	}
+
+	labs, total, err := h.repo.GetAll(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get labs", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get labs"})
@@ -54,19 +88,37 @@ func (h *LabHandler) GetAll(c *gin.Context) {
 		labs = []models.Lab{}
 	}
 
+	setTotalCount(c, total)
+
+	if renderHTML {
+		rendered := make([]models.RenderedLab, 0, len(labs))
+		for _, item := range labs {
+			r, err := renderLab(h.renderer, item)
+			if err != nil {
+				h.logger.ErrorContext(c.Request.Context(), "Failed to render lab", "error", err, "id", item.ID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render lab"})
+				return
+			}
+			rendered = append(rendered, *r)
+		}
+		c.JSON(http.StatusOK, rendered)
+		return
+	}
+
 	c.JSON(http.StatusOK, labs)
 }
 
 // GetByID godoc
 // @Summary      Get lab by ID
-// @Description  Get lab details by ID
+// @Description  Get published lab details by ID; drafts are available only in the staff preview at /api/admin/preview/labs/{id}
 // @Tags         labs
 // @Produce      json
-// @Param        id   path      int  true  "Lab ID"
-// @Success      200  {object}  models.Lab
-// @Failure      400  {object}  map[string]string
-// @Failure      404  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Param        id      path      int     true   "Lab ID"
+// @Param        render  query     string  false  "Set to html to include rendered HTML and table of contents"  Enums(html)
+// @Success      200     {object}  models.Lab
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
 // @Router       /api/labs/{id} [get]
 func (h *LabHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -75,44 +127,65 @@ func (h *LabHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	lab, err := h.repo.GetByID(id)
+	renderHTML, ok := parseRenderParam(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid render parameter"})
+		return
+	}
+
+	lab, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get lab", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lab"})
 		return
 	}
 
-	if lab == nil {
+	if lab == nil || !isVisible(c, lab.Status) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Lab not found"})
 		return
 	}
 
+	if renderHTML {
+		rendered, err := renderLab(h.renderer, *lab)
+		if err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "Failed to render lab", "error", err, "id", id)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render lab"})
+			return
+		}
+		c.JSON(http.StatusOK, rendered)
+		return
+	}
+
 	c.JSON(http.StatusOK, lab)
 }
 
 type CreateLabRequest struct {
-	CourseID    int     `json:"course_id" binding:"required"`
-	Number      int     `json:"number" binding:"required"`
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	MaxScore    int     `json:"max_score" binding:"required"`
-	GithubURL   string  `json:"github_url"`
-	Deadline    *string `json:"deadline"`
+	CourseID    int        `json:"course_id" binding:"required"`
+	Number      int        `json:"number" binding:"required"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	MaxScore    int        `json:"max_score" binding:"required"`
+	GithubURL   string     `json:"github_url"`
+	Deadline    *string    `json:"deadline"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
 }
 
 type UpdateLabRequest struct {
-	CourseID    int     `json:"course_id" binding:"required"`
-	Number      int     `json:"number" binding:"required"`
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	MaxScore    int     `json:"max_score" binding:"required"`
-	GithubURL   string  `json:"github_url"`
-	Deadline    *string `json:"deadline"`
+	CourseID    int        `json:"course_id" binding:"required"`
+	Number      int        `json:"number" binding:"required"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	MaxScore    int        `json:"max_score" binding:"required"`
+	GithubURL   string     `json:"github_url"`
+	Deadline    *string    `json:"deadline"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
 }
 
 // Create godoc
 // @Summary      Create lab
-// @Description  Create a new lab
+// @Description  Create a new lab; it is published unless status is draft. A draft with publish_at is published automatically at that time. Publishing a lab emails students
 // @Tags         admin-labs
 // @Security     BearerAuth
 // @Accept       json
@@ -131,21 +204,26 @@ func (h *LabHandler) Create(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	lab, err := h.repo.Create(req.CourseID, req.Number, req.MaxScore, req.Title, req.Description, req.GithubURL, req.Deadline)
+	lab, err := h.repo.Create(c.Request.Context(), req.CourseID, req.Number, req.MaxScore, req.Title, req.Description, req.GithubURL, req.Deadline, req.Status, req.PublishAt)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to create lab", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to create lab"})
 		return
 	}
 
+	h.notifyPublished(c, nil, lab)
 	h.logger.InfoContext(c.Request.Context(), "Lab created", "id", lab.ID)
 	c.JSON(201, lab)
 }
 
 // Update godoc
 // @Summary      Update lab
-// @Description  Update lab by ID
+// @Description  Update lab by ID; status and publish_at are changed only when status is given. Changing the deadline of a published lab posts a course announcement, publishing a draft emails students
 // @Tags         admin-labs
 // @Security     BearerAuth
 // @Accept       json
@@ -156,6 +234,7 @@ func (h *LabHandler) Create(c *gin.Context) {
 // @Failure      400  {object}  map[string]string
 // @Failure      401  {object}  map[string]string
 // @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /api/admin/labs/{id} [put]
 func (h *LabHandler) Update(c *gin.Context) {
@@ -167,21 +246,85 @@ func (h *LabHandler) Update(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	err := h.repo.Update(id, req.CourseID, req.Number, req.MaxScore, req.Title, req.Description, req.GithubURL, req.Deadline)
+	before, err := h.repo.GetByID(c.Request.Context(), id)
+	if err == nil {
+		err = h.repo.Update(c.Request.Context(), id, req.CourseID, req.Number, req.MaxScore, req.Title, req.Description, req.GithubURL, req.Deadline)
+	}
+	if err == nil && req.Status != "" {
+		err = h.repo.SetPublication(c.Request.Context(), id, req.Status, req.PublishAt)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lab not found"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to update lab", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to update lab"})
 		return
 	}
 
+	// объявление не входит в изменение: если оно не создалось, лабораторная всё равно обновлена
+	after, err := h.repo.GetByID(c.Request.Context(), id)
+	if err == nil {
+		err = h.announcer.LabUpdated(c.Request.Context(), before, after)
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to announce lab update", "error", err, "id", id)
+	}
+	if before != nil && after != nil {
+		h.notifyPublished(c, before, after)
+	}
+
 	h.logger.InfoContext(c.Request.Context(), "Lab updated", "id", id)
 	c.JSON(200, gin.H{"message": "Lab updated"})
 }
 
+// SetPublication godoc
+// @Summary      Set lab publication
+// @Description  Publish a lab or turn it into a draft; a draft with publish_at is published automatically at that time. Publishing a draft emails students
+// @Tags         admin-labs
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                 true  "Lab ID"
+// @Param        publication  body      PublicationRequest  true  "Publication status"
+// @Success      200          {object}  models.Lab
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Router       /api/admin/labs/{id}/publication [put]
+func (h *LabHandler) SetPublication(c *gin.Context) {
+	before, after := setPublication(c, h.logger, "Lab", h.repo.GetByID, h.repo.SetPublication)
+	if before != nil {
+		h.notifyPublished(c, before, after)
+	}
+}
+
+// notifyPublished рассылает письма о лабораторной, если она стала опубликованной: создана сразу
+// опубликованной (before равен nil) или из черновика. Ошибка рассылки только записывается в лог.
+func (h *LabHandler) notifyPublished(c *gin.Context, before, after *models.Lab) {
+	if after == nil || after.Status != models.PublicationStatusPublished {
+		return
+	}
+	if before != nil && before.Status == models.PublicationStatusPublished {
+		return
+	}
+
+	if err := h.notifier.LabPublished(c.Request.Context(), after.ID); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to send lab notifications", "error", err, "id", after.ID)
+	}
+}
+
 // Delete godoc
 // @Summary      Delete lab
-// @Description  Delete lab by ID
+// @Description  Move lab to trash by ID
 // @Tags         admin-labs
 // @Security     BearerAuth
 // @Produce      json
@@ -194,7 +337,7 @@ func (h *LabHandler) Update(c *gin.Context) {
 func (h *LabHandler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	err := h.repo.Delete(id)
+	err := h.repo.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to delete lab", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to delete lab"})
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/CreateLab/laritmo/internal/models"
 	"github.com/CreateLab/laritmo/internal/repository"
@@ -11,39 +13,65 @@ import (
 )
 
 type LectureHandler struct {
-	repo   *repository.LectureRepository
-	logger *slog.Logger
+	repo      repository.LectureStore
+	renderer  MarkdownRendererInterface
+	announcer AnnouncerInterface
+	logger    *slog.Logger
 }
 
-func NewLectureHandler(repo *repository.LectureRepository, logger *slog.Logger) *LectureHandler {
+func NewLectureHandler(repo repository.LectureStore, renderer MarkdownRendererInterface, announcer AnnouncerInterface, logger *slog.Logger) *LectureHandler {
 	return &LectureHandler{
-		repo:   repo,
-		logger: logger,
+		repo:      repo,
+		renderer:  renderer,
+		announcer: announcer,
+		logger:    logger,
 	}
 }
 
 // GetAll godoc
 // @Summary      Get all lectures
-// @Description  Get list of all lectures with optional course filter
+// @Description  Get a page of published lectures with optional course and week filters; total count is returned in X-Total-Count. The staff preview at /api/admin/preview/lectures also returns drafts and accepts a status filter
 // @Tags         lectures
 // @Produce      json
-// @Param        course_id  query     int  false  "Course ID filter"
+// @Param        course_id  query     int     false  "Course ID filter"
+// @Param        week       query     int     false  "Week filter"
+// @Param        sort       query     string  false  "Comma-separated sort fields, prefix with - for descending: id, course_id, week, title, created_at, updated_at"
+// @Param        limit      query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset     query     int     false  "Page offset"  default(0)
+// @Param        render     query     string  false  "Set to html to include rendered HTML and table of contents"  Enums(html)
 // @Success      200        {array}   models.Lecture
+// @Header       200        {int}     X-Total-Count  "Total number of matching lectures"
 // @Failure      400        {object}  map[string]string
 // @Failure      500        {object}  map[string]string
 // @Router       /api/lectures [get]
 func (h *LectureHandler) GetAll(c *gin.Context) {
-	var courseID *int
-	if courseIDStr := c.Query("course_id"); courseIDStr != "" {
-		id, err := strconv.Atoi(courseIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course_id"})
-			return
-		}
-		courseID = &id
+	renderHTML, ok := parseRenderParam(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid render parameter"})
+		return
 	}
 
-	lectures, err := h.repo.GetAll(courseID)
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	var filter repository.LectureFilter
+	if filter.CourseID, ok = parseIntQuery(c, "course_id"); !ok {
+		return
+	}
+	if filter.Week, ok = parseIntQuery(c, "week"); !ok {
+		return
+	}
+	if filter.Status, ok = parseStatusQuery(c); !ok {
+		return
+	}
+
+	lectures, total, err := h.repo.GetAll(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get lectures", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lectures"})
@@ -54,19 +82,37 @@ func (h *LectureHandler) GetAll(c *gin.Context) {
 		lectures = []models.Lecture{}
 	}
 
+	setTotalCount(c, total)
+
+	if renderHTML {
+		rendered := make([]models.RenderedLecture, 0, len(lectures))
+		for _, item := range lectures {
+			r, err := renderLecture(h.renderer, item)
+			if err != nil {
+				h.logger.ErrorContext(c.Request.Context(), "Failed to render lecture", "error", err, "id", item.ID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render lecture"})
+				return
+			}
+			rendered = append(rendered, *r)
+		}
+		c.JSON(http.StatusOK, rendered)
+		return
+	}
+
 	c.JSON(http.StatusOK, lectures)
 }
 
 // GetByID godoc
 // @Summary      Get lecture by ID
-// @Description  Get lecture details by ID
+// @Description  Get published lecture details by ID; drafts are available only in the staff preview at /api/admin/preview/lectures/{id}
 // @Tags         lectures
 // @Produce      json
-// @Param        id   path      int  true  "Lecture ID"
-// @Success      200  {object}  models.Lecture
-// @Failure      400  {object}  map[string]string
-// @Failure      404  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Param        id      path      int     true   "Lecture ID"
+// @Param        render  query     string  false  "Set to html to include rendered HTML and table of contents"  Enums(html)
+// @Success      200     {object}  models.Lecture
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
 // @Router       /api/lectures/{id} [get]
 func (h *LectureHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -75,40 +121,61 @@ func (h *LectureHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	lecture, err := h.repo.GetByID(id)
+	renderHTML, ok := parseRenderParam(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid render parameter"})
+		return
+	}
+
+	lecture, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get lecture", "error", err, "id", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lecture"})
 		return
 	}
 
-	if lecture == nil {
+	if lecture == nil || !isVisible(c, lecture.Status) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Lecture not found"})
 		return
 	}
 
+	if renderHTML {
+		rendered, err := renderLecture(h.renderer, *lecture)
+		if err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "Failed to render lecture", "error", err, "id", id)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render lecture"})
+			return
+		}
+		c.JSON(http.StatusOK, rendered)
+		return
+	}
+
 	c.JSON(http.StatusOK, lecture)
 }
 
 type CreateLectureRequest struct {
-	CourseID  int    `json:"course_id" binding:"required"`
-	Week      int    `json:"week" binding:"required"`
-	Title     string `json:"title" binding:"required"`
-	Content   string `json:"content" binding:"required"`
-	GithubURL string `json:"github_url"`
+	CourseID  int        `json:"course_id" binding:"required"`
+	Week      int        `json:"week" binding:"required"`
+	Title     string     `json:"title" binding:"required"`
+	Content   string     `json:"content" binding:"required"`
+	GithubURL string     `json:"github_url"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 type UpdateLectureRequest struct {
-	CourseID  int    `json:"course_id" binding:"required"`
-	Week      int    `json:"week" binding:"required"`
-	Title     string `json:"title" binding:"required"`
-	Content   string `json:"content" binding:"required"`
-	GithubURL string `json:"github_url"`
+	CourseID  int        `json:"course_id" binding:"required"`
+	Week      int        `json:"week" binding:"required"`
+	Title     string     `json:"title" binding:"required"`
+	Content   string     `json:"content" binding:"required"`
+	GithubURL string     `json:"github_url"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 // Create godoc
 // @Summary      Create lecture
-// @Description  Create a new lecture
+// @Description  Create a new lecture; it is published and announced to the course unless status is draft. A draft with publish_at is published automatically at that time
 // @Tags         admin-lectures
 // @Security     BearerAuth
 // @Accept       json
@@ -127,21 +194,26 @@ func (h *LectureHandler) Create(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	lecture, err := h.repo.Create(req.CourseID, req.Week, req.Title, req.Content, req.GithubURL)
+	lecture, err := h.repo.Create(c.Request.Context(), req.CourseID, req.Week, req.Title, req.Content, req.GithubURL, req.Status, req.PublishAt)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to create lecture", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to create lecture"})
 		return
 	}
 
+	h.announcePublished(c, nil, lecture)
 	h.logger.InfoContext(c.Request.Context(), "Lecture created", "id", lecture.ID)
 	c.JSON(201, lecture)
 }
 
 // Update godoc
 // @Summary      Update lecture
-// @Description  Update lecture by ID
+// @Description  Update lecture by ID; status and publish_at are changed only when status is given. Publishing a draft posts a course announcement
 // @Tags         admin-lectures
 // @Security     BearerAuth
 // @Accept       json
@@ -152,6 +224,7 @@ func (h *LectureHandler) Create(c *gin.Context) {
 // @Failure      400      {object}  map[string]string
 // @Failure      401      {object}  map[string]string
 // @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Router       /api/admin/lectures/{id} [put]
 func (h *LectureHandler) Update(c *gin.Context) {
@@ -163,21 +236,82 @@ func (h *LectureHandler) Update(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	err := h.repo.Update(id, req.CourseID, req.Week, req.Title, req.Content, req.GithubURL)
+	before, err := h.repo.GetByID(c.Request.Context(), id)
+	if err == nil {
+		err = h.repo.Update(c.Request.Context(), id, req.CourseID, req.Week, req.Title, req.Content, req.GithubURL)
+	}
+	if err == nil && req.Status != "" {
+		err = h.repo.SetPublication(c.Request.Context(), id, req.Status, req.PublishAt)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lecture not found"})
+		return
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to update lecture", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to update lecture"})
 		return
 	}
 
+	if before != nil {
+		after, err := h.repo.GetByID(c.Request.Context(), id)
+		if err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "Failed to get lecture", "error", err, "id", id)
+		} else {
+			h.announcePublished(c, before, after)
+		}
+	}
+
 	h.logger.InfoContext(c.Request.Context(), "Lecture updated", "id", id)
 	c.JSON(200, gin.H{"message": "Lecture updated"})
 }
 
+// SetPublication godoc
+// @Summary      Set lecture publication
+// @Description  Publish a lecture or turn it into a draft; a draft with publish_at is published automatically at that time. Publishing a draft posts a course announcement
+// @Tags         admin-lectures
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                 true  "Lecture ID"
+// @Param        publication  body      PublicationRequest  true  "Publication status"
+// @Success      200          {object}  models.Lecture
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Router       /api/admin/lectures/{id}/publication [put]
+func (h *LectureHandler) SetPublication(c *gin.Context) {
+	before, after := setPublication(c, h.logger, "Lecture", h.repo.GetByID, h.repo.SetPublication)
+	if before != nil {
+		h.announcePublished(c, before, after)
+	}
+}
+
+// announcePublished объявляет о лекции, если она стала опубликованной: создана сразу опубликованной
+// (before равен nil) или из черновика. Ошибка объявления только записывается в лог.
+func (h *LectureHandler) announcePublished(c *gin.Context, before, after *models.Lecture) {
+	if after == nil || after.Status != models.PublicationStatusPublished {
+		return
+	}
+	if before != nil && before.Status == models.PublicationStatusPublished {
+		return
+	}
+
+	if err := h.announcer.LecturePublished(c.Request.Context(), after.CourseID, after.Title); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to announce lecture", "error", err, "id", after.ID)
+	}
+}
+
 // Delete godoc
 // @Summary      Delete lecture
-// @Description  Delete lecture by ID
+// @Description  Move lecture to trash by ID
 // @Tags         admin-lectures
 // @Security     BearerAuth
 // @Produce      json
@@ -190,7 +324,7 @@ func (h *LectureHandler) Update(c *gin.Context) {
 func (h *LectureHandler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	err := h.repo.Delete(id)
+	err := h.repo.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to delete lecture", "error", err)
 		c.JSON(500, gin.H{"error": "Failed to delete lecture"})
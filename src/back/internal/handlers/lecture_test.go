@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLectureHandler_GetAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	for week, title := range []string{"Введение", "Интерфейсы", "Горутины"} {
+		_, err := store.Lectures.Create(ctx, course.ID, week+1, title, "# "+title, "", "", nil)
+		require.NoError(t, err)
+	}
+
+	handler := NewLectureHandler(store.Lectures, services.NewMarkdownService(), services.NewAnnouncementService(store.Announcements, store.Courses), slog.Default())
+	router := gin.New()
+	router.GET("/api/lectures", handler.GetAll)
+	router.GET("/api/lectures/:id", handler.GetByID)
+
+	t.Run("filter by week", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/lectures?course_id=1&week=2", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var lectures []models.Lecture
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lectures))
+		require.Len(t, lectures, 1)
+		assert.Equal(t, "Интерфейсы", lectures[0].Title)
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+	})
+
+	t.Run("descending week with offset", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/lectures?sort=-week&offset=1", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var lectures []models.Lecture
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lectures))
+		require.Len(t, lectures, 2)
+		assert.Equal(t, 2, lectures[0].Week)
+		assert.Equal(t, 1, lectures[1].Week)
+		assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	})
+
+	t.Run("rendered lecture", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/lectures/3?render=html", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var lecture models.RenderedLecture
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lecture))
+		assert.Contains(t, lecture.ContentHTML, "Горутины")
+	})
+
+	t.Run("missing lecture", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/lectures/42", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
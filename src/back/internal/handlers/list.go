@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxListLimit ограничивает размер запрошенной страницы; без ?limit= список
+	// отдаётся целиком, чтобы клиенты без пагинации ничего не теряли
+	maxListLimit = 500
+
+	totalCountHeader = "X-Total-Count"
+)
+
+// parseListOptions разбирает ?limit=, ?offset= и ?sort=; при ошибке сам отвечает 400
+func parseListOptions(c *gin.Context) (repository.ListOptions, bool) {
+	opts := repository.ListOptions{
+		Sort: c.Query("sort"),
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 500"})
+			return opts, false
+		}
+		opts.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return opts, false
+		}
+		opts.Offset = offset
+	}
+
+	return opts, true
+}
+
+// parseIntQuery разбирает необязательный целочисленный параметр; при ошибке сам отвечает 400
+func parseIntQuery(c *gin.Context, name string) (*int, bool) {
+	str := c.Query(name)
+	if str == "" {
+		return nil, true
+	}
+
+	value, err := strconv.Atoi(str)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return nil, false
+	}
+
+	return &value, true
+}
+
+// parseBoolQuery разбирает необязательный логический параметр; при ошибке сам отвечает 400
+func parseBoolQuery(c *gin.Context, name string) (*bool, bool) {
+	str := c.Query(name)
+	if str == "" {
+		return nil, true
+	}
+
+	value, err := strconv.ParseBool(str)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return nil, false
+	}
+
+	return &value, true
+}
+
+// parseDateQuery разбирает необязательную дату в формате RFC 3339 или YYYY-MM-DD.
+// Для endOfDay дата без времени означает конец указанного дня,
+// чтобы фильтр "по" включал весь день.
+func parseDateQuery(c *gin.Context, name string, endOfDay bool) (*time.Time, bool) {
+	str := c.Query(name)
+	if str == "" {
+		return nil, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, str); err == nil {
+		return &t, true
+	}
+
+	t, err := time.Parse(time.DateOnly, str)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + ": expected YYYY-MM-DD or RFC 3339"})
+		return nil, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	return &t, true
+}
+
+func setTotalCount(c *gin.Context, total int) {
+	c.Header(totalCountHeader, strconv.Itoa(total))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newListTestContext(rawQuery string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/labs?"+rawQuery, nil)
+	return c, w
+}
+
+func TestParseListOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedOK     bool
+		expectedOpts   repository.ListOptions
+		expectedStatus int
+	}{
+		{
+			name:         "defaults",
+			query:        "",
+			expectedOK:   true,
+			expectedOpts: repository.ListOptions{},
+		},
+		{
+			name:         "explicit page and sort",
+			query:        "limit=10&offset=20&sort=-week,title",
+			expectedOK:   true,
+			expectedOpts: repository.ListOptions{Limit: 10, Offset: 20, Sort: "-week,title"},
+		},
+		{
+			name:           "limit too large",
+			query:          "limit=501",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "zero limit",
+			query:          "limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative offset",
+			query:          "offset=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newListTestContext(tt.query)
+
+			opts, ok := parseListOptions(c)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			if tt.expectedOK {
+				assert.Equal(t, tt.expectedOpts, opts)
+			} else {
+				assert.Equal(t, tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestParseDateQuery(t *testing.T) {
+	t.Run("date only as lower bound", func(t *testing.T) {
+		c, _ := newListTestContext("deadline_from=2026-03-01")
+
+		from, ok := parseDateQuery(c, "deadline_from", false)
+
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *from)
+	})
+
+	t.Run("date only as upper bound covers whole day", func(t *testing.T) {
+		c, _ := newListTestContext("deadline_to=2026-03-01")
+
+		to, ok := parseDateQuery(c, "deadline_to", true)
+
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2026, 3, 1, 23, 59, 59, 0, time.UTC), *to)
+	})
+
+	t.Run("RFC 3339 is kept as is", func(t *testing.T) {
+		c, _ := newListTestContext("deadline_to=2026-03-01T12:00:00Z")
+
+		to, ok := parseDateQuery(c, "deadline_to", true)
+
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), *to)
+	})
+
+	t.Run("missing parameter", func(t *testing.T) {
+		c, _ := newListTestContext("")
+
+		to, ok := parseDateQuery(c, "deadline_to", true)
+
+		assert.True(t, ok)
+		assert.Nil(t, to)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		c, w := newListTestContext("deadline_from=01.03.2026")
+
+		_, ok := parseDateQuery(c, "deadline_from", false)
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// NotifierInterface - интерфейс для писем студентам о новых лабораторных и опубликованных оценках
+type NotifierInterface interface {
+	LabPublished(ctx context.Context, id int) error
+	GradeSheetPublished(ctx context.Context, id int) error
+}
+
+// NotificationPreferencesInterface - интерфейс для настроек email-уведомлений пользователя
+type NotificationPreferencesInterface interface {
+	GetPreferences(ctx context.Context, userID int) (*models.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, p *models.NotificationPreferences) error
+}
+
+type NotificationHandler struct {
+	service NotificationPreferencesInterface
+	logger  *slog.Logger
+}
+
+func NewNotificationHandler(service NotificationPreferencesInterface, logger *slog.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// NotificationPreferencesRequest - новые настройки; не указанные виды писем не меняются
+type NotificationPreferencesRequest struct {
+	NewLab           *bool `json:"new_lab"`
+	DeadlineReminder *bool `json:"deadline_reminder"`
+	GradePosted      *bool `json:"grade_posted"`
+}
+
+// GetPreferences godoc
+// @Summary      Get notification preferences
+// @Description  Get which emails the current user receives: new labs, deadline reminders 24 hours ahead and posted grades. All are enabled by default
+// @Tags         notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.NotificationPreferences
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/me/notifications [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get notification preferences", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// SetPreferences godoc
+// @Summary      Update notification preferences
+// @Description  Turn emails of the current user on or off; omitted fields keep their values
+// @Tags         notifications
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        preferences  body      NotificationPreferencesRequest  true  "Notification preferences"
+// @Success      200          {object}  models.NotificationPreferences
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Router       /api/me/notifications [put]
+func (h *NotificationHandler) SetPreferences(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err == nil {
+		if req.NewLab != nil {
+			prefs.NewLab = *req.NewLab
+		}
+		if req.DeadlineReminder != nil {
+			prefs.DeadlineReminder = *req.DeadlineReminder
+		}
+		if req.GradePosted != nil {
+			prefs.GradePosted = *req.GradePosted
+		}
+		err = h.service.SetPreferences(c.Request.Context(), prefs)
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to update notification preferences", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Notification preferences updated", "user_id", userID)
+	c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifier запоминает, о каких материалах разосланы письма
+type fakeNotifier struct {
+	labs        []int
+	gradeSheets []int
+}
+
+func (n *fakeNotifier) LabPublished(ctx context.Context, id int) error {
+	n.labs = append(n.labs, id)
+	return nil
+}
+
+func (n *fakeNotifier) GradeSheetPublished(ctx context.Context, id int) error {
+	n.gradeSheets = append(n.gradeSheets, id)
+	return nil
+}
+
+func TestNotificationHandler_Preferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	user, err := store.Users.Create(ctx, "student", "student@example.com", "hash", models.RoleStudent)
+	require.NoError(t, err)
+
+	handler := NewNotificationHandler(store.Notifications, slog.Default())
+	router := gin.New()
+	me := router.Group("/api/me", func(c *gin.Context) { c.Set("user_id", user.ID) })
+	me.GET("/notifications", handler.GetPreferences)
+	me.PUT("/notifications", handler.SetPreferences)
+
+	serve := func(method, body string) (int, models.NotificationPreferences) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/api/me/notifications", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		var prefs models.NotificationPreferences
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prefs))
+		}
+		return w.Code, prefs
+	}
+
+	code, prefs := serve(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.DefaultNotificationPreferences(user.ID), prefs)
+
+	code, prefs = serve(http.MethodPut, `{"deadline_reminder": false}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, prefs.DeadlineReminder)
+	assert.True(t, prefs.NewLab, "omitted fields keep their values")
+	assert.True(t, prefs.GradePosted)
+
+	code, prefs = serve(http.MethodPut, `{"new_lab": false, "deadline_reminder": true}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.NotificationPreferences{UserID: user.ID, NewLab: false, DeadlineReminder: true, GradePosted: true}, prefs)
+
+	_, prefs = serve(http.MethodGet, "")
+	assert.False(t, prefs.NewLab)
+
+	code, _ = serve(http.MethodPut, `{"new_lab": "no"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGradeSheetHandler_NotifiesOnPublish(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+
+	notifier := &fakeNotifier{}
+	handler := NewGradeSheetHandler(store.GradeSheets, notifier, slog.Default())
+	router := gin.New()
+	router.POST("/api/admin/grade-sheets", handler.Create)
+	router.PUT("/api/admin/grade-sheets/:id", handler.Update)
+	router.PUT("/api/admin/grade-sheets/:id/publication", handler.SetPublication)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(status string) int {
+		w := serve(http.MethodPost, "/api/admin/grade-sheets",
+			`{"course_id": `+strconv.Itoa(course.ID)+`, "sheet_url": "https://example.com/s", "description": "Итоги", "status": "`+status+`"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var sheet models.GradeSheet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sheet))
+		return sheet.ID
+	}
+
+	published := create(models.PublicationStatusPublished)
+	assert.Equal(t, []int{published}, notifier.gradeSheets, "sheet created published is announced")
+
+	draft := create(models.PublicationStatusDraft)
+	assert.Len(t, notifier.gradeSheets, 1, "drafts are not announced")
+
+	path := "/api/admin/grade-sheets/" + strconv.Itoa(draft)
+	require.Equal(t, http.StatusOK, serve(http.MethodPut, path+"/publication", `{"status": "published"}`).Code)
+	assert.Equal(t, []int{published, draft}, notifier.gradeSheets)
+
+	require.Equal(t, http.StatusOK, serve(http.MethodPut, path, `{"sheet_url": "https://example.com/s2", "description": "Итоги", "status": "published"}`).Code)
+	require.Equal(t, http.StatusOK, serve(http.MethodPut, path+"/publication", `{"status": "published"}`).Code)
+	assert.Len(t, notifier.gradeSheets, 2, "already published sheet is not announced again")
+}
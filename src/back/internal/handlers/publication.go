@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// staffPreviewKey - ключ gin-контекста, которым StaffPreview помечает запросы предпросмотра
+const staffPreviewKey = "staff_preview"
+
+// StaffPreview помечает запросы как предпросмотр, и публичные обработчики показывают в них черновики.
+// Подключается только к группе под авторизацией администратора.
+func StaffPreview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(staffPreviewKey, true)
+		c.Next()
+	}
+}
+
+func isStaffPreview(c *gin.Context) bool {
+	return c.GetBool(staffPreviewKey)
+}
+
+// isVisible сообщает, можно ли показать материал с таким статусом; черновики видны только в предпросмотре
+func isVisible(c *gin.Context, status string) bool {
+	return status == models.PublicationStatusPublished || isStaffPreview(c)
+}
+
+// parseStatusQuery возвращает фильтр статуса для списка: публичный запрос видит только опубликованное,
+// предпросмотр - всё или статус из ?status=; при ошибке сам отвечает 400
+func parseStatusQuery(c *gin.Context) (string, bool) {
+	if !isStaffPreview(c) {
+		return models.PublicationStatusPublished, true
+	}
+
+	status := c.Query("status")
+	if status != "" && status != models.PublicationStatusDraft && status != models.PublicationStatusPublished {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return "", false
+	}
+	return status, true
+}
+
+// PublicationRequest - статус материала; publish_at (RFC 3339) задаётся только черновику,
+// и в это время планировщик опубликует его сам
+type PublicationRequest struct {
+	Status    string     `json:"status" binding:"required"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// validatePublication проверяет статус и время публикации из запроса. Пустой статус
+// в запросах создания и изменения означает значение по умолчанию или прежний статус.
+func validatePublication(status string, publishAt *time.Time) error {
+	switch status {
+	case "", models.PublicationStatusDraft, models.PublicationStatusPublished:
+	default:
+		return errors.New("status must be draft or published")
+	}
+
+	if publishAt != nil && status != models.PublicationStatusDraft {
+		return errors.New("publish_at can be set only for drafts")
+	}
+	return nil
+}
+
+// setPublication обрабатывает PUT .../publication для материала типа what ("Lecture", "Lab", ...):
+// проверяет запрос и существование материала, меняет статус и возвращает обновлённый материал.
+// Материал до и после изменения возвращается вызывающему; при ошибке оба равны nil.
+func setPublication[T any](
+	c *gin.Context,
+	logger *slog.Logger,
+	what string,
+	get func(ctx context.Context, id int) (*T, error),
+	set func(ctx context.Context, id int, status string, publishAt *time.Time) error,
+) (before, after *T) {
+	ctx := c.Request.Context()
+	name := strings.ToLower(what)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, nil
+	}
+
+	var req PublicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.ErrorContext(ctx, "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return nil, nil
+	}
+	if err := validatePublication(req.Status, req.PublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil
+	}
+
+	before, err = get(ctx, id)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get "+name, "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get " + name})
+		return nil, nil
+	}
+	if before == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": what + " not found"})
+		return nil, nil
+	}
+
+	if err := set(ctx, id, req.Status, req.PublishAt); err != nil {
+		logger.ErrorContext(ctx, "Failed to update "+name+" publication", "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + name + " publication"})
+		return nil, nil
+	}
+
+	if after, err = get(ctx, id); err != nil {
+		logger.ErrorContext(ctx, "Failed to get "+name, "error", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get " + name})
+		return nil, nil
+	}
+
+	logger.InfoContext(ctx, what+" publication updated", "id", id, "status", req.Status)
+	c.JSON(http.StatusOK, after)
+	return before, after
+}
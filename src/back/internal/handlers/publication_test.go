@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLectureHandler_Publication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "# Введение", "", "", nil)
+	require.NoError(t, err)
+
+	handler := NewLectureHandler(store.Lectures, services.NewMarkdownService(), services.NewAnnouncementService(store.Announcements, store.Courses), slog.Default())
+	router := gin.New()
+	router.GET("/api/lectures", handler.GetAll)
+	router.GET("/api/lectures/:id", handler.GetByID)
+	router.POST("/api/admin/lectures", handler.Create)
+	router.PUT("/api/admin/lectures/:id/publication", handler.SetPublication)
+	preview := router.Group("/api/admin/preview", StaffPreview())
+	preview.GET("/lectures", handler.GetAll)
+	preview.GET("/lectures/:id", handler.GetByID)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/api/admin/lectures",
+		`{"course_id": 1, "week": 2, "title": "Интерфейсы", "content": "текст", "status": "draft", "publish_at": "2030-01-01T09:00:00+03:00"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var draft models.Lecture
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &draft))
+	assert.Equal(t, models.PublicationStatusDraft, draft.Status)
+	require.NotNil(t, draft.PublishAt)
+
+	t.Run("public list and details hide drafts", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/lectures", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var lectures []models.Lecture
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lectures))
+		require.Len(t, lectures, 1)
+		assert.Equal(t, lecture.ID, lectures[0].ID)
+
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/lectures/"+strconv.Itoa(draft.ID), "").Code)
+	})
+
+	t.Run("staff preview shows drafts", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/admin/preview/lectures?status=draft", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var lectures []models.Lecture
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lectures))
+		require.Len(t, lectures, 1)
+		assert.Equal(t, draft.ID, lectures[0].ID)
+
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/admin/preview/lectures/"+strconv.Itoa(draft.ID), "").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/admin/preview/lectures?status=hidden", "").Code)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		path := "/api/admin/lectures/" + strconv.Itoa(draft.ID) + "/publication"
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, path, `{"status": "hidden"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, path, `{"status": "published", "publish_at": "2030-01-01T09:00:00Z"}`).Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPut, "/api/admin/lectures/42/publication", `{"status": "published"}`).Code)
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		w := serve(http.MethodPut, "/api/admin/lectures/"+strconv.Itoa(draft.ID)+"/publication", `{"status": "published"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var published models.Lecture
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &published))
+		assert.Equal(t, models.PublicationStatusPublished, published.Status)
+		assert.Nil(t, published.PublishAt)
+
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/lectures/"+strconv.Itoa(draft.ID), "").Code)
+
+		announcements, _, err := store.Announcements.GetAll(ctx, repository.AnnouncementFilter{}, repository.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, announcements, 1, "publishing a draft is announced once")
+		assert.Equal(t, models.AnnouncementKindLecturePublished, announcements[0].Kind)
+		assert.Contains(t, announcements[0].Title, "Интерфейсы")
+
+		serve(http.MethodPut, "/api/admin/lectures/"+strconv.Itoa(draft.ID)+"/publication", `{"status": "published"}`)
+		_, total, err := store.Announcements.GetAll(ctx, repository.AnnouncementFilter{}, repository.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total, "already published lecture is not announced again")
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// MarkdownRendererInterface - интерфейс для рендеринга Markdown в HTML с кэшированием
+type MarkdownRendererInterface interface {
+	RenderCached(key string, updatedAt time.Time, source, baseURL string) (*models.RenderedContent, error)
+}
+
+// parseRenderParam разбирает ?render=; поддерживается только значение html
+func parseRenderParam(c *gin.Context) (renderHTML bool, ok bool) {
+	switch c.Query("render") {
+	case "":
+		return false, true
+	case "html":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+func renderLecture(renderer MarkdownRendererInterface, lecture models.Lecture) (*models.RenderedLecture, error) {
+	baseURL := ""
+	if lecture.GithubURL != nil {
+		baseURL = *lecture.GithubURL
+	}
+
+	content, err := renderer.RenderCached(fmt.Sprintf("lecture:%d", lecture.ID), lecture.UpdatedAt, lecture.Content, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenderedLecture{
+		Lecture:     lecture,
+		ContentHTML: content.HTML,
+		TOC:         content.TOC,
+	}, nil
+}
+
+func renderLab(renderer MarkdownRendererInterface, lab models.Lab) (*models.RenderedLab, error) {
+	baseURL := ""
+	if lab.GithubURL != nil {
+		baseURL = *lab.GithubURL
+	}
+
+	content, err := renderer.RenderCached(fmt.Sprintf("lab:%d", lab.ID), lab.UpdatedAt, lab.Description, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenderedLab{
+		Lab:             lab,
+		DescriptionHTML: content.HTML,
+		TOC:             content.TOC,
+	}, nil
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/ical"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleServiceInterface - интерфейс для расписаний курсов и календарной ленты
+type ScheduleServiceInterface interface {
+	Get(ctx context.Context, courseID int) (*models.CourseTimetable, error)
+	Set(ctx context.Context, schedule *models.CourseSchedule) (*models.CourseSchedule, error)
+	Delete(ctx context.Context, courseID int) error
+	Calendar(ctx context.Context, courseID int, w io.Writer) error
+}
+
+type ScheduleHandler struct {
+	service ScheduleServiceInterface
+	logger  *slog.Logger
+}
+
+// SetScheduleRequest - дата начала курса и недельные занятия; заменяет расписание целиком
+type SetScheduleRequest struct {
+	StartDate string                 `json:"start_date" binding:"required"`
+	Slots     []TimetableSlotRequest `json:"slots"`
+}
+
+// TimetableSlotRequest - еженедельное занятие: weekday от 1 (понедельник) до 7, start_time в формате HH:MM
+type TimetableSlotRequest struct {
+	Weekday         int    `json:"weekday"`
+	StartTime       string `json:"start_time"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Room            string `json:"room"`
+}
+
+func NewScheduleHandler(service ScheduleServiceInterface, logger *slog.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Get godoc
+// @Summary      Get course schedule
+// @Description  Get the course start date, weekly timetable and lecture dates computed from lecture weeks. Schedule is null for a course without a timetable
+// @Tags         courses
+// @Produce      json
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {object}  models.CourseTimetable
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/courses/{id}/schedule [get]
+func (h *ScheduleHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	timetable, err := h.service.Get(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get schedule", "error", err, "course_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timetable)
+}
+
+// Calendar godoc
+// @Summary      Get course calendar
+// @Description  iCalendar feed with scheduled lectures and lab deadlines for subscribing from calendar apps. Lectures are included only when the course has a schedule
+// @Tags         courses
+// @Produce      text/calendar
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {string}  string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/courses/{id}/calendar.ics [get]
+func (h *ScheduleHandler) Calendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var buf bytes.Buffer
+	err = h.service.Calendar(c.Request.Context(), id, &buf)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to build calendar", "error", err, "course_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build calendar"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=course_%d.ics", id))
+	c.Data(http.StatusOK, ical.ContentType, buf.Bytes())
+}
+
+// Set godoc
+// @Summary      Set course schedule
+// @Description  Replace the course start date and weekly timetable. Week 1 starts on the Monday of the start date week; times are in the configured calendar timezone (admin only)
+// @Tags         admin-courses
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                 true  "Course ID"
+// @Param        schedule  body      SetScheduleRequest  true  "Schedule"
+// @Success      200       {object}  models.CourseSchedule
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Router       /api/admin/courses/{id}/schedule [put]
+func (h *ScheduleHandler) Set(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req SetScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	schedule := &models.CourseSchedule{CourseID: id, StartDate: req.StartDate, Slots: []models.TimetableSlot{}}
+	for _, slot := range req.Slots {
+		schedule.Slots = append(schedule.Slots, models.TimetableSlot{
+			Weekday:         slot.Weekday,
+			StartTime:       slot.StartTime,
+			DurationMinutes: slot.DurationMinutes,
+			Room:            slot.Room,
+		})
+	}
+
+	saved, err := h.service.Set(c.Request.Context(), schedule)
+	if errors.Is(err, services.ErrInvalidSchedule) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to set schedule", "error", err, "course_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set schedule"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Schedule updated", "course_id", id, "slots", len(saved.Slots))
+	c.JSON(http.StatusOK, saved)
+}
+
+// Delete godoc
+// @Summary      Delete course schedule
+// @Description  Remove the course start date and timetable; lectures are no longer dated (admin only)
+// @Tags         admin-courses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/admin/courses/{id}/schedule [delete]
+func (h *ScheduleHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = h.service.Delete(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to delete schedule", "error", err, "course_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Schedule deleted", "course_id", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted"})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Сети", "2026-fall", "")
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, course.ID, 1, "Модель OSI", "", "", "", nil)
+	require.NoError(t, err)
+
+	service := services.NewScheduleService(store.Schedules, store.Courses, store.Lectures, store.Labs, time.UTC)
+	handler := NewScheduleHandler(service, logger)
+	router := gin.New()
+	router.GET("/api/courses/:id/schedule", handler.Get)
+	router.GET("/api/courses/:id/calendar.ics", handler.Calendar)
+	router.PUT("/api/admin/courses/:id/schedule", handler.Set)
+	router.DELETE("/api/admin/courses/:id/schedule", handler.Delete)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	base := "/api/courses/" + strconv.Itoa(course.ID)
+	admin := "/api/admin/courses/" + strconv.Itoa(course.ID) + "/schedule"
+
+	w := do(http.MethodGet, base+"/schedule", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"schedule":null,"lectures":[]}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, admin, `{}`).Code)
+	w = do(http.MethodPut, admin, `{"start_date":"2026-09-01","slots":[{"weekday":8,"start_time":"10:00","duration_minutes":90}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "weekday")
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "/api/admin/courses/999/schedule", `{"start_date":"2026-09-01"}`).Code)
+
+	w = do(http.MethodPut, admin, `{"start_date":"2026-09-01","slots":[{"weekday":2,"start_time":"10:00","duration_minutes":90,"room":"305"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var saved models.CourseSchedule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+	require.Len(t, saved.Slots, 1)
+	assert.Equal(t, "305", saved.Slots[0].Room)
+
+	w = do(http.MethodGet, base+"/schedule", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var timetable models.CourseTimetable
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &timetable))
+	require.Len(t, timetable.Lectures, 1)
+	assert.Equal(t, "2026-09-01", timetable.Lectures[0].Date)
+
+	w = do(http.MethodGet, base+"/calendar.ics", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "DTSTART:20260901T100000Z\r\n")
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/courses/999/calendar.ics", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/courses/abc/schedule", "").Code)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, admin, "").Code)
+	w = do(http.MethodGet, base+"/schedule", "")
+	assert.JSONEq(t, `{"schedule":null,"lectures":[]}`, w.Body.String())
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// SearchServiceInterface - интерфейс для сервиса полнотекстового поиска
+type SearchServiceInterface interface {
+	Search(ctx context.Context, params services.SearchParams) (*models.SearchResult, error)
+}
+
+type SearchHandler struct {
+	service SearchServiceInterface
+	logger  *slog.Logger
+}
+
+func NewSearchHandler(service SearchServiceInterface, logger *slog.Logger) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Search godoc
+// @Summary      Search course materials
+// @Description  Full-text search across lectures, labs and exam questions with ranked results and highlighted snippets
+// @Tags         search
+// @Produce      json
+// @Param        q          query     string  true   "Search query (Russian or English)"
+// @Param        course_id  query     int     false  "Course ID filter"
+// @Param        type       query     string  false  "Comma-separated result types: lecture, lab, exam_question"
+// @Param        limit      query     int     false  "Page size (1-50)"  default(20)
+// @Param        offset     query     int     false  "Page offset"  default(0)
+// @Success      200        {object}  models.SearchResult
+// @Failure      400        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /api/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	params := services.SearchParams{
+		Query:  strings.TrimSpace(c.Query("q")),
+		Limit:  defaultSearchLimit,
+		Offset: 0,
+	}
+
+	if params.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+
+	if courseIDStr := c.Query("course_id"); courseIDStr != "" {
+		id, err := strconv.Atoi(courseIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course_id"})
+			return
+		}
+		params.CourseID = &id
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		for _, t := range strings.Split(typeStr, ",") {
+			t = strings.TrimSpace(t)
+			switch t {
+			case models.SearchTypeLecture, models.SearchTypeLab, models.SearchTypeExamQuestion:
+				params.Types = append(params.Types, t)
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type: must be lecture, lab or exam_question"})
+				return
+			}
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 50"})
+			return
+		}
+		params.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		params.Offset = offset
+	}
+
+	result, err := h.service.Search(c.Request.Context(), params)
+	if errors.Is(err, services.ErrEmptySearchQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query must contain words of at least 3 characters"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to search", "error", err, "query", params.Query)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
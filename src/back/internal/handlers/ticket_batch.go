@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// JobQueueInterface - интерфейс для постановки фоновых задач в очередь
+type JobQueueInterface interface {
+	Enqueue(ctx context.Context, jobType string, payload any, maxAttempts int) (*models.Job, error)
+}
+
+type TicketBatchHandler struct {
+	courseRepo  CourseRepositoryInterface
+	queue       JobQueueInterface
+	maxAttempts int
+	logger      *slog.Logger
+}
+
+func NewTicketBatchHandler(courseRepo CourseRepositoryInterface, queue JobQueueInterface, maxAttempts int, logger *slog.Logger) *TicketBatchHandler {
+	return &TicketBatchHandler{
+		courseRepo:  courseRepo,
+		queue:       queue,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
+
+// GenerateAsync godoc
+// @Summary      Generate tickets document in background
+// @Description  Enqueue generation of a tickets document for one or more courses (up to 1000 tickets per course). Poll the job for progress and download the result when it succeeds (admin only)
+// @Tags         admin-tickets
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.TicketBatchRequest  true  "Generation parameters"
+// @Success      202      {object}  map[string]interface{}
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/tickets/generate-async [post]
+func (h *TicketBatchHandler) GenerateAsync(c *gin.Context) {
+	var req models.TicketBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Validation error", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	for _, courseID := range req.CourseIDs {
+		course, err := h.courseRepo.GetByID(c.Request.Context(), courseID)
+		if err != nil {
+			h.logger.ErrorContext(c.Request.Context(), "Failed to get course", "error", err, "course_id", courseID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course"})
+			return
+		}
+		if course == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Course %d not found", courseID)})
+			return
+		}
+	}
+
+	job, err := h.queue.Enqueue(c.Request.Context(), services.TicketDocumentJobType, req, h.maxAttempts)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to enqueue tickets generation", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue tickets generation"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Tickets generation enqueued", "job_id", job.ID, "courses", len(req.CourseIDs), "ticket_count", req.TicketCount)
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id":     job.ID,
+		"status":     job.Status,
+		"status_url": fmt.Sprintf("/api/admin/jobs/%d", job.ID),
+		"result_url": fmt.Sprintf("/api/admin/jobs/%d/result", job.ID),
+	})
+}
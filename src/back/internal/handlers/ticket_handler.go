@@ -5,9 +5,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,7 +24,7 @@ type DocumentServiceInterface interface {
 
 // CourseRepositoryInterface - интерфейс для репозитория курсов
 type CourseRepositoryInterface interface {
-	GetByID(id int) (*models.Course, error)
+	GetByID(ctx context.Context, id int) (*models.Course, error)
 }
 
 type TicketHandler struct {
@@ -69,7 +69,7 @@ func (h *TicketHandler) GetRandomTicket(c *gin.Context) {
 	}
 
 	// Проверяем существование курса
-	course, err := h.courseRepo.GetByID(courseID)
+	course, err := h.courseRepo.GetByID(c.Request.Context(), courseID)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get course", "error", err, "course_id", courseID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course"})
@@ -131,7 +131,7 @@ func (h *TicketHandler) GenerateTicketsDocument(c *gin.Context) {
 	}
 
 	// Проверяем существование курса
-	course, err := h.courseRepo.GetByID(courseID)
+	course, err := h.courseRepo.GetByID(c.Request.Context(), courseID)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "Failed to get course", "error", err, "course_id", courseID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get course"})
@@ -162,9 +162,7 @@ func (h *TicketHandler) GenerateTicketsDocument(c *gin.Context) {
 	document := h.documentService.GenerateTicketsDocument(tickets)
 
 	// Создаем имя файла из названия курса
-	courseSlug := strings.ToLower(strings.ReplaceAll(course.Name, " ", "_"))
-	courseSlug = strings.ReplaceAll(courseSlug, "/", "_")
-	filename := "tickets_" + courseSlug + ".txt"
+	filename := "tickets_" + services.CourseSlug(course.Name) + ".txt"
 
 	// Устанавливаем заголовки для скачивания файла
 	c.Header("Content-Type", "text/plain; charset=utf-8")
@@ -53,7 +53,7 @@ type MockCourseRepository struct {
 	mock.Mock
 }
 
-func (m *MockCourseRepository) GetByID(id int) (*models.Course, error) {
+func (m *MockCourseRepository) GetByID(ctx context.Context, id int) (*models.Course, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
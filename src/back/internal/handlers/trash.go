@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashServiceInterface - интерфейс для корзины удалённых записей
+type TrashServiceInterface interface {
+	List(ctx context.Context, filter repository.TrashFilter, opts repository.ListOptions) ([]models.TrashItem, int, error)
+	Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error)
+}
+
+type TrashHandler struct {
+	service TrashServiceInterface
+	logger  *slog.Logger
+}
+
+func NewTrashHandler(service TrashServiceInterface, logger *slog.Logger) *TrashHandler {
+	return &TrashHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+var trashTypes = map[string]bool{
+	models.TrashTypeCourse:       true,
+	models.TrashTypeLecture:      true,
+	models.TrashTypeLab:          true,
+	models.TrashTypeGradeSheet:   true,
+	models.TrashTypeExamQuestion: true,
+}
+
+// GetAll godoc
+// @Summary      Get trash
+// @Description  Get a page of soft-deleted courses and materials, recently deleted first. Materials deleted together with their course are restored with it and not listed separately (admin only)
+// @Tags         admin-trash
+// @Produce      json
+// @Param        type       query     string  false  "Item type: course, lecture, lab, grade_sheet, exam_question"
+// @Param        course_id  query     int     false  "Course ID"
+// @Param        sort       query     string  false  "Comma-separated sort fields, prefix with - for descending: type, title, deleted_at"
+// @Param        limit      query     int     false  "Page size (1-500); the whole list when omitted"
+// @Param        offset     query     int     false  "Page offset"  default(0)
+// @Success      200        {array}   models.TrashItem
+// @Header       200        {int}     X-Total-Count  "Total number of matching items"
+// @Failure      400        {object}  map[string]string
+// @Failure      401        {object}  map[string]string
+// @Failure      403        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/trash [get]
+func (h *TrashHandler) GetAll(c *gin.Context) {
+	filter := repository.TrashFilter{Type: c.Query("type")}
+	if filter.Type != "" && !trashTypes[filter.Type] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type"})
+		return
+	}
+
+	var ok bool
+	if filter.CourseID, ok = parseIntQuery(c, "course_id"); !ok {
+		return
+	}
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	items, total, err := h.service.List(c.Request.Context(), filter, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get trash", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trash"})
+		return
+	}
+
+	if items == nil {
+		items = []models.TrashItem{}
+	}
+
+	setTotalCount(c, total)
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreCourse godoc
+// @Summary      Restore course from trash
+// @Description  Restore a soft-deleted course together with the materials deleted with it (admin only)
+// @Tags         admin-trash
+// @Produce      json
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {object}  models.TrashRestoreSummary
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/courses/{id}/restore [post]
+func (h *TrashHandler) RestoreCourse(c *gin.Context) {
+	h.restore(c, models.TrashTypeCourse)
+}
+
+// RestoreLecture godoc
+// @Summary      Restore lecture from trash
+// @Description  Restore a soft-deleted lecture; its course must not be in trash (admin only)
+// @Tags         admin-trash
+// @Produce      json
+// @Param        id   path      int  true  "Lecture ID"
+// @Success      200  {object}  models.TrashRestoreSummary
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/lectures/{id}/restore [post]
+func (h *TrashHandler) RestoreLecture(c *gin.Context) {
+	h.restore(c, models.TrashTypeLecture)
+}
+
+// RestoreLab godoc
+// @Summary      Restore lab from trash
+// @Description  Restore a soft-deleted lab; its course must not be in trash (admin only)
+// @Tags         admin-trash
+// @Produce      json
+// @Param        id   path      int  true  "Lab ID"
+// @Success      200  {object}  models.TrashRestoreSummary
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/labs/{id}/restore [post]
+func (h *TrashHandler) RestoreLab(c *gin.Context) {
+	h.restore(c, models.TrashTypeLab)
+}
+
+// RestoreGradeSheet godoc
+// @Summary      Restore grade sheet from trash
+// @Description  Restore a soft-deleted grade sheet; its course must not be in trash (admin only)
+// @Tags         admin-trash
+// @Produce      json
+// @Param        id   path      int  true  "Grade sheet ID"
+// @Success      200  {object}  models.TrashRestoreSummary
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/grade-sheets/{id}/restore [post]
+func (h *TrashHandler) RestoreGradeSheet(c *gin.Context) {
+	h.restore(c, models.TrashTypeGradeSheet)
+}
+
+// RestoreExamQuestion godoc
+// @Summary      Restore exam question from trash
+// @Description  Restore a soft-deleted exam question; its course must not be in trash (admin only)
+// @Tags         admin-trash
+// @Produce      json
+// @Param        id   path      int  true  "Exam question ID"
+// @Success      200  {object}  models.TrashRestoreSummary
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/exam-questions/{id}/restore [post]
+func (h *TrashHandler) RestoreExamQuestion(c *gin.Context) {
+	h.restore(c, models.TrashTypeExamQuestion)
+}
+
+func (h *TrashHandler) restore(c *gin.Context, itemType string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	summary, err := h.service.Restore(c.Request.Context(), itemType, id)
+	if errors.Is(err, repository.ErrTrashItemNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in trash"})
+		return
+	}
+	if errors.Is(err, repository.ErrTrashCourseDeleted) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Course is in trash, restore it first"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to restore from trash", "error", err, "type", itemType, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore from trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Courses.Delete(ctx, course.ID))
+
+	handler := NewTrashHandler(services.NewTrashService(store.Trash, time.Hour, logger), logger)
+	router := gin.New()
+	router.GET("/api/admin/trash", handler.GetAll)
+	router.POST("/api/admin/courses/:id/restore", handler.RestoreCourse)
+	router.POST("/api/admin/lectures/:id/restore", handler.RestoreLecture)
+	router.PUT("/api/admin/courses/:id", NewCourseHandler(store.Courses, logger).Update)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/courses/"+strconv.Itoa(course.ID), strings.NewReader(`{"name": "Go", "semester": "2025-fall"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "trashed course cannot be edited")
+
+	w = do(http.MethodGet, "/api/admin/trash")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(totalCountHeader))
+	var items []models.TrashItem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, models.TrashTypeCourse, items[0].Type)
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/admin/trash?type=user").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/admin/trash?sort=course_id").Code)
+
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/api/admin/lectures/"+strconv.Itoa(lecture.ID)+"/restore").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/api/admin/courses/999/restore").Code)
+
+	w = do(http.MethodPost, "/api/admin/courses/"+strconv.Itoa(course.ID)+"/restore")
+	require.Equal(t, http.StatusOK, w.Code)
+	var summary models.TrashRestoreSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, 1, summary.Lectures)
+
+	w = do(http.MethodGet, "/api/admin/trash")
+	assert.Equal(t, "0", w.Header().Get(totalCountHeader))
+	assert.JSONEq(t, "[]", w.Body.String())
+}
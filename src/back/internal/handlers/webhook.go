@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBodySize - ограничение на размер тела push-события
+const maxWebhookBodySize = 10 << 20
+
+// WebhookServiceInterface - интерфейс для приёма push-событий git-хостинга
+type WebhookServiceInterface interface {
+	HandlePush(ctx context.Context, provider, deliveryID, signature string, body []byte) (*models.ContentSyncEvent, error)
+}
+
+// ContentSyncEventRepositoryInterface - интерфейс для чтения журнала синхронизаций
+type ContentSyncEventRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int, opts repository.ListOptions) ([]models.ContentSyncEvent, int, error)
+}
+
+type WebhookHandler struct {
+	service WebhookServiceInterface
+	events  ContentSyncEventRepositoryInterface
+	logger  *slog.Logger
+}
+
+func NewWebhookHandler(service WebhookServiceInterface, events ContentSyncEventRepositoryInterface, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		events:  events,
+		logger:  logger,
+	}
+}
+
+// Receive godoc
+// @Summary      Receive git push webhook
+// @Description  Accept a GitHub or Gitea push event signed with the repository secret and queue re-import of changed lectures and labs
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        X-GitHub-Event       header    string  false  "GitHub event type"
+// @Param        X-Hub-Signature-256  header    string  false  "GitHub HMAC-SHA256 signature"
+// @Param        X-Gitea-Event        header    string  false  "Gitea event type"
+// @Param        X-Gitea-Signature    header    string  false  "Gitea HMAC-SHA256 signature"
+// @Success      200  {object}  map[string]string
+// @Success      202  {object}  models.ContentSyncEvent
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/webhooks/git [post]
+func (h *WebhookHandler) Receive(c *gin.Context) {
+	var provider, eventType, deliveryID, signature string
+	switch {
+	case c.GetHeader("X-GitHub-Event") != "":
+		provider = models.WebhookProviderGitHub
+		eventType = c.GetHeader("X-GitHub-Event")
+		deliveryID = c.GetHeader("X-GitHub-Delivery")
+		signature = c.GetHeader("X-Hub-Signature-256")
+	case c.GetHeader("X-Gitea-Event") != "":
+		provider = models.WebhookProviderGitea
+		eventType = c.GetHeader("X-Gitea-Event")
+		deliveryID = c.GetHeader("X-Gitea-Delivery")
+		signature = c.GetHeader("X-Gitea-Signature")
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported webhook provider"})
+		return
+	}
+
+	if eventType != "push" {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	event, err := h.service.HandlePush(c.Request.Context(), provider, deliveryID, signature, body)
+	if errors.Is(err, services.ErrWebhookPayload) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid push payload"})
+		return
+	}
+	if errors.Is(err, services.ErrWebhookUnknownRepository) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Repository is not configured"})
+		return
+	}
+	if errors.Is(err, services.ErrWebhookSignature) {
+		h.logger.WarnContext(c.Request.Context(), "Webhook signature mismatch", "provider", provider, "delivery_id", deliveryID)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to handle push event", "error", err, "provider", provider, "delivery_id", deliveryID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle push event"})
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Push event received",
+		"event_id", event.ID,
+		"course_id", event.CourseID,
+		"status", event.Status,
+		"changed_paths", len(event.ChangedPaths),
+	)
+	c.JSON(http.StatusAccepted, event)
+}
+
+// GetEvents godoc
+// @Summary      List content sync events
+// @Description  Get webhook deliveries of a course with their status and sync report, newest first (admin only)
+// @Tags         admin-courses
+// @Produce      json
+// @Param        id      path      int     true   "Course ID"
+// @Param        limit   query     int     false  "Page size (max 500)"
+// @Param        offset  query     int     false  "Number of items to skip"
+// @Param        sort    query     string  false  "Sort fields: id, status, created_at; prefix with - for descending"
+// @Success      200     {array}   models.ContentSyncEvent
+// @Header       200     {int}     X-Total-Count  "Total number of matching events"
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /api/admin/courses/{id}/sync-events [get]
+func (h *WebhookHandler) GetEvents(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	events, total, err := h.events.GetByCourseID(c.Request.Context(), courseID, opts)
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to get sync events", "error", err, "course_id", courseID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sync events"})
+		return
+	}
+
+	if events == nil {
+		events = []models.ContentSyncEvent{}
+	}
+
+	setTotalCount(c, total)
+	c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) HandlePush(ctx context.Context, provider, deliveryID, signature string, body []byte) (*models.ContentSyncEvent, error) {
+	args := m.Called(provider, deliveryID, signature, string(body))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ContentSyncEvent), args.Error(1)
+}
+
+func TestWebhookHandler_Receive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"ref": "refs/heads/main"}`
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		setupMock      func(m *MockWebhookService)
+		expectedStatus int
+	}{
+		{
+			name: "github push",
+			headers: map[string]string{
+				"X-GitHub-Event":      "push",
+				"X-GitHub-Delivery":   "d-1",
+				"X-Hub-Signature-256": "sha256=abc",
+			},
+			setupMock: func(m *MockWebhookService) {
+				m.On("HandlePush", models.WebhookProviderGitHub, "d-1", "sha256=abc", body).
+					Return(&models.ContentSyncEvent{ID: 1, Status: models.ContentSyncEventQueued}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "gitea push",
+			headers: map[string]string{
+				"X-Gitea-Event":     "push",
+				"X-Gitea-Delivery":  "d-2",
+				"X-Gitea-Signature": "abc",
+			},
+			setupMock: func(m *MockWebhookService) {
+				m.On("HandlePush", models.WebhookProviderGitea, "d-2", "abc", body).
+					Return(&models.ContentSyncEvent{ID: 2, Status: models.ContentSyncEventIgnored}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "github ping is acknowledged",
+			headers:        map[string]string{"X-GitHub-Event": "ping"},
+			setupMock:      func(m *MockWebhookService) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown provider",
+			headers:        map[string]string{},
+			setupMock:      func(m *MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "bad signature",
+			headers: map[string]string{"X-Gitea-Event": "push"},
+			setupMock: func(m *MockWebhookService) {
+				m.On("HandlePush", models.WebhookProviderGitea, "", "", body).Return(nil, services.ErrWebhookSignature)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "unknown repository",
+			headers: map[string]string{"X-Gitea-Event": "push"},
+			setupMock: func(m *MockWebhookService) {
+				m.On("HandlePush", models.WebhookProviderGitea, "", "", body).
+					Return(nil, fmt.Errorf("%w: a/b", services.ErrWebhookUnknownRepository))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			tt.setupMock(mockService)
+			handler := NewWebhookHandler(mockService, nil, slog.Default())
+
+			router := gin.New()
+			router.POST("/api/webhooks/git", handler.Receive)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/webhooks/git", strings.NewReader(body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package ical формирует календари в формате iCalendar (RFC 5545) для подписки
+// из Google Calendar, Outlook и других календарных приложений.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType - MIME-тип календаря
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineOctets - предельная длина строки без перевода строки по RFC 5545
+const maxLineOctets = 75
+
+// Calendar - календарь с событиями
+type Calendar struct {
+	// ProdID - идентификатор программы, создавшей календарь
+	ProdID string
+	// Name показывается приложениями как название подписки
+	Name   string
+	Events []Event
+}
+
+// Event - событие календаря. Время записывается в UTC, поэтому часовые пояса
+// (VTIMEZONE) не нужны; у событий на весь день учитывается только дата Start.
+type Event struct {
+	// UID должен оставаться тем же при обновлении, чтобы приложения заменяли событие, а не дублировали
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Stamp - время последнего изменения события (DTSTAMP)
+	Stamp time.Time
+}
+
+// Write записывает календарь; строки завершаются CRLF и переносятся после 75 байт
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", cal.ProdID)
+	lw.line("CALSCALE", "GREGORIAN")
+	lw.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		lw.line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+
+	for _, e := range cal.Events {
+		lw.line("BEGIN", "VEVENT")
+		lw.line("UID", e.UID)
+		lw.line("DTSTAMP", formatTime(e.Stamp))
+		if e.AllDay {
+			end := e.End
+			if !end.After(e.Start) {
+				end = e.Start.AddDate(0, 0, 1)
+			}
+			lw.line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			lw.line("DTEND;VALUE=DATE", end.Format("20060102"))
+		} else {
+			lw.line("DTSTART", formatTime(e.Start))
+			lw.line("DTEND", formatTime(e.End))
+		}
+		lw.line("SUMMARY", escapeText(e.Summary))
+		if e.Location != "" {
+			lw.line("LOCATION", escapeText(e.Location))
+		}
+		if e.Description != "" {
+			lw.line("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.URL != "" {
+			lw.line("URL", e.URL)
+		}
+		lw.line("END", "VEVENT")
+	}
+
+	lw.line("END", "VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText экранирует значение типа TEXT
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// lineWriter пишет строки содержимого и запоминает первую ошибку записи
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line пишет строку name:value, перенося её на строки продолжения, начинающиеся с пробела;
+// перенос не разрывает многобайтовые символы UTF-8
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Пробел в начале строки продолжения тоже занимает байт
+		limit = maxLineOctets - 1
+	}
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(s)
+	}
+}
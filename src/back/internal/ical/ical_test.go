@@ -0,0 +1,61 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	start := time.Date(2026, 9, 3, 10, 0, 0, 0, moscow)
+	stamp := time.Date(2026, 8, 20, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	err := Write(&buf, Calendar{
+		ProdID: "-//Laritmo//Test//RU",
+		Name:   "Сети, 2026",
+		Events: []Event{
+			{
+				UID:         "lecture-1@test",
+				Summary:     "Лекция 1; введение",
+				Description: strings.Repeat("Очень длинное описание лекции. ", 5) + "\nВторая строка",
+				Location:    "Ауд. 305",
+				Start:       start,
+				End:         start.Add(90 * time.Minute),
+				Stamp:       stamp,
+			},
+			{
+				UID:     "lecture-2@test",
+				Summary: "Лекция 2",
+				Start:   time.Date(2026, 9, 7, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+				Stamp:   stamp,
+			},
+		},
+	})
+	require.NoError(t, err)
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "X-WR-CALNAME:Сети\\, 2026\r\n")
+	assert.Contains(t, out, "DTSTART:20260903T070000Z\r\n", "times are written in UTC")
+	assert.Contains(t, out, "DTEND:20260903T083000Z\r\n")
+	assert.Contains(t, out, "DTSTAMP:20260820T120000Z\r\n")
+	assert.Contains(t, out, `SUMMARY:Лекция 1\; введение`+"\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20260907\r\nDTEND;VALUE=DATE:20260908\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets, line)
+		assert.True(t, utf8.ValidString(line), "folding keeps runes whole: %q", line)
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("Очень длинное описание лекции. ", 5)+"\\nВторая строка\r\n")
+}
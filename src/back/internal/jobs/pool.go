@@ -0,0 +1,238 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// Store - интерфейс хранилища задач (реализуется repository.JobStore)
+type Store interface {
+	Lease(ctx context.Context, workerID string, visibility time.Duration) (*models.Job, error)
+	Extend(ctx context.Context, id int, workerID string, visibility time.Duration) error
+	Complete(ctx context.Context, id int, workerID string) error
+	Retry(ctx context.Context, id int, workerID string, runAt time.Time, errMsg string) error
+	Bury(ctx context.Context, id int, workerID, errMsg string) error
+}
+
+// HandlerFunc выполняет задачу определённого типа
+type HandlerFunc func(ctx context.Context, job *models.Job) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неисправимую: задача сразу уходит в dead-letter без повторов
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type Options struct {
+	Workers           int
+	PollInterval      time.Duration
+	VisibilityTimeout time.Duration
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+}
+
+// Pool - пул воркеров, забирающих задачи из Store
+type Pool struct {
+	store    Store
+	logger   *slog.Logger
+	opts     Options
+	handlers map[string]HandlerFunc
+
+	stop      chan struct{}
+	runCtx    context.Context
+	runCancel context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewPool(store Store, logger *slog.Logger, opts Options) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 5 * time.Minute
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 10 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+
+	return &Pool{
+		store:    store,
+		logger:   logger,
+		opts:     opts,
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Register регистрирует обработчик для типа задач. Вызывать до Start.
+func (p *Pool) Register(jobType string, handler HandlerFunc) {
+	p.handlers[jobType] = handler
+}
+
+func (p *Pool) Start(ctx context.Context) {
+	p.stop = make(chan struct{})
+	p.runCtx, p.runCancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	hostname, _ := os.Hostname()
+	for i := 0; i < p.opts.Workers; i++ {
+		workerID := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), i)
+		p.wg.Add(1)
+		go p.work(workerID)
+	}
+
+	p.logger.InfoContext(ctx, "Job worker pool started", "workers", p.opts.Workers)
+}
+
+// Shutdown прекращает захват новых задач и ждёт завершения текущих.
+// Если ctx истекает раньше, выполняющиеся задачи отменяются и будут
+// повторены после истечения аренды.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.runCancel()
+		p.logger.InfoContext(ctx, "Job worker pool stopped")
+		return nil
+	case <-ctx.Done():
+		p.runCancel()
+		<-done
+		return fmt.Errorf("job worker pool shutdown: %w", ctx.Err())
+	}
+}
+
+func (p *Pool) work(workerID string) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		job, err := p.store.Lease(p.runCtx, workerID, p.opts.VisibilityTimeout)
+		if err != nil {
+			p.logger.ErrorContext(p.runCtx, "Failed to lease job", "error", err, "worker", workerID)
+		}
+
+		if job == nil {
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(p.opts.PollInterval):
+			}
+			continue
+		}
+
+		p.process(workerID, job)
+	}
+}
+
+func (p *Pool) process(workerID string, job *models.Job) {
+	ctx := p.runCtx
+	logger := p.logger.With("job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts)
+
+	handler, ok := p.handlers[job.Type]
+	if !ok {
+		logger.ErrorContext(ctx, "No handler registered for job type")
+		if err := p.store.Bury(context.WithoutCancel(ctx), job.ID, workerID, "no handler registered for job type "+job.Type); err != nil {
+			logger.ErrorContext(ctx, "Failed to bury job", "error", err)
+		}
+		return
+	}
+
+	err := p.run(ctx, workerID, job, handler)
+
+	// Сохраняем результат даже если пул останавливается
+	storeCtx := context.WithoutCancel(ctx)
+
+	if err == nil {
+		if err := p.store.Complete(storeCtx, job.ID, workerID); err != nil {
+			logger.ErrorContext(ctx, "Failed to complete job", "error", err)
+			return
+		}
+		logger.InfoContext(ctx, "Job completed")
+		return
+	}
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		logger.ErrorContext(ctx, "Job moved to dead-letter", "error", err)
+		if err := p.store.Bury(storeCtx, job.ID, workerID, err.Error()); err != nil {
+			logger.ErrorContext(ctx, "Failed to bury job", "error", err)
+		}
+		return
+	}
+
+	runAt := time.Now().Add(p.backoff(job.Attempts))
+	logger.WarnContext(ctx, "Job failed, will retry", "error", err, "run_at", runAt)
+	if err := p.store.Retry(storeCtx, job.ID, workerID, runAt, err.Error()); err != nil {
+		logger.ErrorContext(ctx, "Failed to reschedule job", "error", err)
+	}
+}
+
+// run выполняет обработчик, продлевая аренду задачи, пока он работает
+func (p *Pool) run(ctx context.Context, workerID string, job *models.Job, handler HandlerFunc) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(p.opts.VisibilityTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.store.Extend(ctx, job.ID, workerID, p.opts.VisibilityTimeout); err != nil {
+					p.logger.WarnContext(ctx, "Failed to extend job lease", "error", err, "job_id", job.ID)
+				}
+			}
+		}
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// backoff возвращает задержку перед следующей попыткой: экспоненциальный рост от BaseBackoff до MaxBackoff
+func (p *Pool) backoff(attempt int) time.Duration {
+	delay := p.opts.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= p.opts.MaxBackoff {
+			return p.opts.MaxBackoff
+		}
+	}
+	return delay
+}
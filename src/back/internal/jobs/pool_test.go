@@ -0,0 +1,257 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore - простое хранилище задач в памяти для тестов пула
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[int]*models.Job
+}
+
+func newMemoryStore(jobs ...*models.Job) *memoryStore {
+	s := &memoryStore{jobs: make(map[int]*models.Job)}
+	for _, j := range jobs {
+		if j.Status == "" {
+			j.Status = models.JobStatusPending
+		}
+		s.jobs[j.ID] = j
+	}
+	return s
+}
+
+func (s *memoryStore) Lease(ctx context.Context, workerID string, visibility time.Duration) (*models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, j := range s.jobs {
+		if j.Status == models.JobStatusPending && !j.RunAt.After(now) {
+			j.Status = models.JobStatusRunning
+			j.Attempts++
+			until := now.Add(visibility)
+			j.LockedUntil = &until
+			copied := *j
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Extend(ctx context.Context, id int, workerID string, visibility time.Duration) error {
+	return nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, id int, workerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].Status = models.JobStatusSucceeded
+	return nil
+}
+
+func (s *memoryStore) Retry(ctx context.Context, id int, workerID string, runAt time.Time, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].Status = models.JobStatusPending
+	s.jobs[id].RunAt = runAt
+	s.jobs[id].LastError = &errMsg
+	return nil
+}
+
+func (s *memoryStore) Bury(ctx context.Context, id int, workerID, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].Status = models.JobStatusDead
+	s.jobs[id].LastError = &errMsg
+	return nil
+}
+
+func (s *memoryStore) get(id int) models.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[id]
+}
+
+func testOptions() Options {
+	return Options{
+		Workers:           2,
+		PollInterval:      5 * time.Millisecond,
+		VisibilityTimeout: time.Second,
+		BaseBackoff:       time.Millisecond,
+		MaxBackoff:        4 * time.Millisecond,
+	}
+}
+
+func waitForStatus(t *testing.T, store *memoryStore, id int, status string) models.Job {
+	t.Helper()
+	var job models.Job
+	require.Eventually(t, func() bool {
+		job = store.get(id)
+		return job.Status == status
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestPool_CompletesJob(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "echo", MaxAttempts: 3})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	var calls int
+	var mu sync.Mutex
+	pool.Register("echo", func(ctx context.Context, job *models.Job) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return nil
+	})
+
+	pool.Start(context.Background())
+	job := waitForStatus(t, store, 1, models.JobStatusSucceeded)
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Equal(t, 1, job.Attempts)
+	mu.Lock()
+	assert.Equal(t, 1, calls)
+	mu.Unlock()
+}
+
+func TestPool_RetriesThenDeadLetters(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "flaky", MaxAttempts: 3})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	pool.Register("flaky", func(ctx context.Context, job *models.Job) error {
+		return errors.New("boom")
+	})
+
+	pool.Start(context.Background())
+	job := waitForStatus(t, store, 1, models.JobStatusDead)
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Equal(t, 3, job.Attempts)
+	require.NotNil(t, job.LastError)
+	assert.Equal(t, "boom", *job.LastError)
+}
+
+func TestPool_RetrySucceeds(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "flaky", MaxAttempts: 5})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	pool.Register("flaky", func(ctx context.Context, job *models.Job) error {
+		if job.Attempts < 2 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	pool.Start(context.Background())
+	job := waitForStatus(t, store, 1, models.JobStatusSucceeded)
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Equal(t, 2, job.Attempts)
+}
+
+func TestPool_PermanentErrorSkipsRetries(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "bad", MaxAttempts: 5})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	pool.Register("bad", func(ctx context.Context, job *models.Job) error {
+		return Permanent(errors.New("invalid payload"))
+	})
+
+	pool.Start(context.Background())
+	job := waitForStatus(t, store, 1, models.JobStatusDead)
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Equal(t, 1, job.Attempts)
+}
+
+func TestPool_UnknownTypeIsBuried(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "unknown", MaxAttempts: 5})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	pool.Start(context.Background())
+	job := waitForStatus(t, store, 1, models.JobStatusDead)
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	require.NotNil(t, job.LastError)
+	assert.Contains(t, *job.LastError, "no handler registered")
+}
+
+func TestPool_RecoversFromPanic(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "panics", MaxAttempts: 1})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	pool.Register("panics", func(ctx context.Context, job *models.Job) error {
+		panic("unexpected")
+	})
+
+	pool.Start(context.Background())
+	job := waitForStatus(t, store, 1, models.JobStatusDead)
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	require.NotNil(t, job.LastError)
+	assert.Contains(t, *job.LastError, "panicked")
+}
+
+func TestPool_ShutdownWaitsForRunningJob(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "slow", MaxAttempts: 1})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	started := make(chan struct{})
+	pool.Register("slow", func(ctx context.Context, job *models.Job) error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+
+	pool.Start(context.Background())
+	<-started
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Equal(t, models.JobStatusSucceeded, store.get(1).Status)
+}
+
+func TestPool_ShutdownTimeoutCancelsJob(t *testing.T) {
+	store := newMemoryStore(&models.Job{ID: 1, Type: "stuck", MaxAttempts: 3})
+	pool := NewPool(store, slog.Default(), testOptions())
+
+	started := make(chan struct{})
+	pool.Register("stuck", func(ctx context.Context, job *models.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	pool.Start(context.Background())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := pool.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, models.JobStatusPending, store.get(1).Status)
+}
+
+func TestPool_Backoff(t *testing.T) {
+	pool := NewPool(newMemoryStore(), slog.Default(), Options{
+		BaseBackoff: time.Second,
+		MaxBackoff:  5 * time.Second,
+	})
+
+	assert.Equal(t, time.Second, pool.backoff(1))
+	assert.Equal(t, 2*time.Second, pool.backoff(2))
+	assert.Equal(t, 4*time.Second, pool.backoff(3))
+	assert.Equal(t, 5*time.Second, pool.backoff(4))
+	assert.Equal(t, 5*time.Second, pool.backoff(10))
+}
@@ -0,0 +1,61 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File складывает письма в каталог .eml-файлами - для локального запуска и тестов
+type File struct {
+	dir string
+}
+
+// NewFile создаёт каталог dir, если его нет
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &File{dir: dir}, nil
+}
+
+func (f *File) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := Compose(msg, now)
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to generate file name: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+
+	if err := os.WriteFile(filepath.Join(f.dir, name), data, 0o640); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// Log только пишет получателя и тему письма в лог; используется, пока доставка не настроена
+type Log struct {
+	logger *slog.Logger
+}
+
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (l *Log) Send(ctx context.Context, msg Message) error {
+	if _, _, err := envelope(msg); err != nil {
+		return err
+	}
+	l.logger.InfoContext(ctx, "Email not sent: mail transport is log", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
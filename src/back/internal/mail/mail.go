@@ -0,0 +1,89 @@
+// Package mail отправляет письма: по SMTP, в каталог .eml-файлов или только в лог.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAddress - адрес отправителя или получателя не разбирается; повтор отправки не поможет
+var ErrInvalidAddress = errors.New("invalid email address")
+
+// Message - текстовое письмо одному получателю
+type Message struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Sender - способ доставки писем
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Compose собирает письмо в формате RFC 5322: заголовки в кодировке RFC 2047,
+// текст в UTF-8 в quoted-printable, чтобы кириллица доходила без искажений
+func Compose(msg Message, date time.Time) ([]byte, error) {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from: %v", ErrInvalidAddress, err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to: %v", ErrInvalidAddress, err)
+	}
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate message id: %w", err)
+	}
+	domain := "localhost"
+	if at := strings.LastIndexByte(from.Address, '@'); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	body := bytes.ReplaceAll([]byte(msg.Body), []byte("\r\n"), []byte("\n"))
+	if _, err := qp.Write(bytes.ReplaceAll(body, []byte("\n"), []byte("\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// envelope возвращает адреса отправителя и получателя без отображаемых имён - для команд MAIL FROM и RCPT TO
+func envelope(msg Message) (from, to string, err error) {
+	f, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: from: %v", ErrInvalidAddress, err)
+	}
+	t, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: to: %v", ErrInvalidAddress, err)
+	}
+	return f.Address, t.Address, nil
+}
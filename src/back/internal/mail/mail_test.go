@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var message = Message{
+	From:    "Laritmo <noreply@laritmo.example>",
+	To:      "student@example.com",
+	Subject: "Новая лабораторная: Калькулятор",
+	Body:    "Здравствуйте!\nОпубликована лабораторная 1.",
+}
+
+func TestCompose(t *testing.T) {
+	date := time.Date(2026, 9, 3, 10, 0, 0, 0, time.UTC)
+	data, err := Compose(message, date)
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, message.Subject, subject)
+	assert.Equal(t, `"Laritmo" <noreply@laritmo.example>`, parsed.Header.Get("From"))
+	assert.Equal(t, "Thu, 03 Sep 2026 10:00:00 +0000", parsed.Header.Get("Date"))
+	assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@laritmo.example>"))
+	assert.Contains(t, string(data), "\r\n\r\n", "headers end with CRLF")
+
+	_, err = Compose(Message{From: message.From, To: "not an address"}, date)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+func TestFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	sender, err := NewFile(dir)
+	require.NoError(t, err)
+	require.NoError(t, sender.Send(context.Background(), message))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "<student@example.com>", parsed.Header.Get("To"))
+}
+
+func TestLog(t *testing.T) {
+	var out strings.Builder
+	sender := NewLog(slog.New(slog.NewTextHandler(&out, nil)))
+	require.NoError(t, sender.Send(context.Background(), message))
+	assert.Contains(t, out.String(), "to=student@example.com")
+	assert.NotContains(t, out.String(), "Здравствуйте", "body is not logged")
+}
+
+func TestSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveSMTP(listener, received)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	sender := NewSMTP(SMTPOptions{Host: "127.0.0.1", Port: addr.Port, Timeout: 5 * time.Second})
+	require.NoError(t, sender.Send(context.Background(), message))
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<noreply@laritmo.example>")
+	assert.Contains(t, lines, "RCPT TO:<student@example.com>")
+	assert.Contains(t, lines, "Content-Type: text/plain; charset=utf-8")
+	assert.Equal(t, "QUIT", lines[len(lines)-1])
+}
+
+// serveSMTP - минимальный SMTP-сервер на одно письмо; отправляет в received все полученные строки
+func serveSMTP(listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { _, _ = io.WriteString(conn, s+"\r\n") }
+	reply("220 localhost ESMTP")
+
+	var lines []string
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			received <- lines
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		switch {
+		case inData:
+			if line == "." {
+				inData = false
+				reply("250 OK")
+			}
+		case strings.HasPrefix(line, "EHLO"):
+			reply("250 localhost")
+		case line == "DATA":
+			inData = true
+			reply("354 go ahead")
+		case line == "QUIT":
+			reply("221 bye")
+			received <- lines
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
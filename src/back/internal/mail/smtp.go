@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPOptions - подключение к почтовому серверу
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// ImplicitTLS - TLS с самого подключения (обычно порт 465); иначе используется STARTTLS, если сервер его поддерживает
+	ImplicitTLS bool
+	// Timeout ограничивает подключение и отправку одного письма
+	Timeout time.Duration
+}
+
+// SMTP отправляет письма через почтовый сервер; каждое письмо - отдельное соединение
+type SMTP struct {
+	opts SMTPOptions
+}
+
+func NewSMTP(opts SMTPOptions) *SMTP {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	return &SMTP{opts: opts}
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	from, to, err := envelope(msg)
+	if err != nil {
+		return err
+	}
+	data, err := Compose(msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: s.opts.Host, MinVersion: tls.VersionTLS12}
+	if s.opts.ImplicitTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if !s.opts.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("starttls failed: %w", err)
+			}
+		}
+	}
+	// smtp.PlainAuth сам отказывается отправлять пароль без TLS на чужой хост
+	if s.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return client.Quit()
+}
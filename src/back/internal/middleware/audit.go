@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditBodySize - ответы больше этого размера не сохраняются в журнал как after
+const maxAuditBodySize = 64 << 10
+
+// AuditRecorder - сохранение журнала аудита и снимков сущностей для него
+type AuditRecorder interface {
+	// Snapshot возвращает nil, если тип сущности не поддерживается или её нет
+	Snapshot(ctx context.Context, entityType string, id int) (json.RawMessage, error)
+	Record(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// auditRoute - действие, выведенное из шаблона маршрута
+type auditRoute struct {
+	entityType string
+	action     string
+	// idParam - в пути есть :id сущности
+	idParam bool
+	// crud - обычное создание, изменение или удаление; для остальных действий
+	// (sync, bulk, ...) в after сохраняется JSON-ответ
+	crud bool
+}
+
+// Audit записывает в журнал успешные изменяющие запросы к маршрутам под prefix.
+// Действие выводится из шаблона маршрута: PUT {prefix}/courses/:id - course.update,
+// POST {prefix}/courses/:id/sync - course.sync. Для изменения и удаления сохраняется
+// снимок сущности до запроса, для создания и изменения - после; id созданной
+// сущности берётся из поля id ответа. Должен стоять после AuthMiddleware.
+func Audit(prefix string, recorder AuditRecorder, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		route, ok := parseAuditRoute(c.Request.Method, strings.TrimPrefix(c.FullPath(), prefix))
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		entry := &models.AuditEntry{
+			Username:   c.GetString("username"),
+			Action:     route.action,
+			EntityType: route.entityType,
+			IP:         c.ClientIP(),
+			RequestID:  c.GetString("request_id"),
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(int); ok {
+				entry.UserID = &id
+			}
+		}
+
+		if route.idParam {
+			if id, err := strconv.Atoi(c.Param("id")); err == nil {
+				entry.EntityID = &id
+			}
+		}
+
+		snapshot := func(id int) json.RawMessage {
+			data, err := recorder.Snapshot(ctx, route.entityType, id)
+			if err != nil {
+				logger.WarnContext(ctx, "Failed to take audit snapshot", "error", err, "entity_type", route.entityType, "id", id)
+			}
+			return data
+		}
+
+		if route.crud && entry.EntityID != nil && c.Request.Method != http.MethodPost {
+			entry.Before = snapshot(*entry.EntityID)
+		}
+
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		body := writer.jsonBody()
+		switch {
+		case !route.crud:
+			entry.After = body
+		case c.Request.Method == http.MethodDelete:
+		default:
+			if entry.EntityID == nil {
+				entry.EntityID = responseID(body)
+			}
+			if entry.EntityID != nil {
+				entry.After = snapshot(*entry.EntityID)
+			}
+			if entry.After == nil {
+				entry.After = body
+			}
+		}
+
+		// Ответ уже отправлен: ошибка журнала не должна менять результат запроса
+		if err := recorder.Record(context.WithoutCancel(ctx), entry); err != nil {
+			logger.ErrorContext(ctx, "Failed to record audit entry", "error", err, "action", entry.Action, "request_id", entry.RequestID)
+		}
+	}
+}
+
+// parseAuditRoute разбирает шаблон маршрута вида /courses/:id/sync
+func parseAuditRoute(method, path string) (auditRoute, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] == "" {
+		return auditRoute{}, false
+	}
+
+	route := auditRoute{
+		entityType: strings.TrimSuffix(strings.ReplaceAll(segments[0], "-", "_"), "s"),
+	}
+
+	rest := segments[1:]
+	if len(rest) > 0 && rest[0] == ":id" {
+		route.idParam = true
+		rest = rest[1:]
+	}
+
+	verb := strings.ReplaceAll(strings.Join(rest, "_"), "-", "_")
+	if verb == "" {
+		route.crud = true
+		switch method {
+		case http.MethodPost:
+			verb = "create"
+		case http.MethodPut, http.MethodPatch:
+			verb = "update"
+		case http.MethodDelete:
+			verb = "delete"
+		default:
+			verb = strings.ToLower(method)
+		}
+	}
+	route.action = route.entityType + "." + verb
+
+	return route, true
+}
+
+// responseID достаёт id созданной сущности из JSON-ответа
+func responseID(body json.RawMessage) *int {
+	var created struct {
+		ID *int `json:"id"`
+	}
+	if body == nil || json.Unmarshal(body, &created) != nil {
+		return nil
+	}
+	return created.ID
+}
+
+// auditWriter копирует JSON-ответ для журнала, не задерживая его отправку
+type auditWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > maxAuditBodySize {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// jsonBody возвращает тело ответа, если это JSON умеренного размера
+func (w *auditWriter) jsonBody() json.RawMessage {
+	if w.overflow || !strings.Contains(w.Header().Get("Content-Type"), "json") || !json.Valid(w.body.Bytes()) {
+		return nil
+	}
+	return bytes.Clone(w.body.Bytes())
+}
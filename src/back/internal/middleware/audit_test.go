@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditRecorder struct {
+	names   map[int]string
+	entries []models.AuditEntry
+}
+
+func (r *fakeAuditRecorder) Snapshot(ctx context.Context, entityType string, id int) (json.RawMessage, error) {
+	name, ok := r.names[id]
+	if entityType != "course" || !ok {
+		return nil, nil
+	}
+	return json.RawMessage(fmt.Sprintf(`{"id":%d,"name":%q}`, id, name)), nil
+}
+
+func (r *fakeAuditRecorder) Record(ctx context.Context, entry *models.AuditEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := &fakeAuditRecorder{names: map[int]string{1: "Go"}}
+
+	router := gin.New()
+	router.Use(RequestID())
+	admin := router.Group("/api/admin")
+	admin.Use(func(c *gin.Context) {
+		c.Set("user_id", 42)
+		c.Set("username", "admin")
+		c.Next()
+	})
+	admin.Use(Audit("/api/admin", recorder, slog.Default()))
+
+	admin.GET("/courses/:id", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	admin.POST("/courses", func(c *gin.Context) {
+		recorder.names[2] = "Rust"
+		c.JSON(http.StatusCreated, gin.H{"id": 2, "name": "Rust"})
+	})
+	admin.PUT("/courses/:id", func(c *gin.Context) {
+		recorder.names[1] = "Go 2"
+		c.JSON(http.StatusOK, gin.H{"message": "Course updated"})
+	})
+	admin.DELETE("/courses/:id", func(c *gin.Context) {
+		if c.Param("id") == "404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+			return
+		}
+		delete(recorder.names, 1)
+		c.JSON(http.StatusOK, gin.H{"message": "Course deleted"})
+	})
+	admin.POST("/courses/:id/sync", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"course_id": 1, "dry_run": true})
+	})
+
+	do := func(method, path string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(RequestIDHeader, "req-"+method)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	do(http.MethodGet, "/api/admin/courses/1")
+	do(http.MethodPost, "/api/admin/courses")
+	do(http.MethodPut, "/api/admin/courses/1")
+	do(http.MethodPost, "/api/admin/courses/1/sync")
+	do(http.MethodDelete, "/api/admin/courses/404")
+	do(http.MethodDelete, "/api/admin/courses/1")
+
+	require.Len(t, recorder.entries, 4, "reads and failed writes are not recorded")
+
+	created := recorder.entries[0]
+	assert.Equal(t, "course.create", created.Action)
+	assert.Equal(t, "course", created.EntityType)
+	require.NotNil(t, created.UserID)
+	assert.Equal(t, 42, *created.UserID)
+	assert.Equal(t, "admin", created.Username)
+	assert.Equal(t, "req-POST", created.RequestID)
+	assert.NotEmpty(t, created.IP)
+	require.NotNil(t, created.EntityID)
+	assert.Equal(t, 2, *created.EntityID)
+	assert.Nil(t, created.Before)
+	assert.JSONEq(t, `{"id":2,"name":"Rust"}`, string(created.After))
+
+	updated := recorder.entries[1]
+	assert.Equal(t, "course.update", updated.Action)
+	require.NotNil(t, updated.EntityID)
+	assert.Equal(t, 1, *updated.EntityID)
+	assert.JSONEq(t, `{"id":1,"name":"Go"}`, string(updated.Before))
+	assert.JSONEq(t, `{"id":1,"name":"Go 2"}`, string(updated.After))
+
+	synced := recorder.entries[2]
+	assert.Equal(t, "course.sync", synced.Action)
+	assert.Nil(t, synced.Before)
+	assert.JSONEq(t, `{"course_id":1,"dry_run":true}`, string(synced.After))
+
+	deleted := recorder.entries[3]
+	assert.Equal(t, "course.delete", deleted.Action)
+	assert.JSONEq(t, `{"id":1,"name":"Go 2"}`, string(deleted.Before))
+	assert.Nil(t, deleted.After)
+}
+
+func TestParseAuditRoute(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   auditRoute
+	}{
+		{http.MethodPost, "/exam-questions", auditRoute{entityType: "exam_question", action: "exam_question.create", crud: true}},
+		{http.MethodPut, "/grade-sheets/:id", auditRoute{entityType: "grade_sheet", action: "grade_sheet.update", idParam: true, crud: true}},
+		{http.MethodPost, "/exam-questions/bulk", auditRoute{entityType: "exam_question", action: "exam_question.bulk"}},
+		{http.MethodPost, "/courses/:id/tickets/generate", auditRoute{entityType: "course", action: "course.tickets_generate", idParam: true}},
+		{http.MethodPost, "/tickets/generate-async", auditRoute{entityType: "ticket", action: "ticket.generate_async"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			got, ok := parseAuditRoute(tt.method, tt.path)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := parseAuditRoute(http.MethodPost, "")
+	assert.False(t, ok)
+}
@@ -1,14 +1,26 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/CreateLab/laritmo/internal/auth"
+	"github.com/CreateLab/laritmo/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
+// UserGetter - получение пользователя по ID для проверки, что токен ещё действует
+type UserGetter interface {
+	// GetByID возвращает nil, если пользователя нет
+	GetByID(ctx context.Context, id int) (*models.User, error)
+}
+
+// AuthMiddleware проверяет JWT и то, что его владелец существует и не заблокирован:
+// после laritmo-admin user disable выданные раньше токены перестают действовать.
+// Роль и имя берутся из базы, чтобы смена роли тоже действовала сразу.
+func AuthMiddleware(jwtManager *auth.JWTManager, users UserGetter, logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -33,10 +45,23 @@ func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		user, err := users.GetByID(c.Request.Context(), claims.UserID)
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Failed to get token owner", "error", err, "user_id", claims.UserID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check token"})
+			c.Abort()
+			return
+		}
+		if user == nil || user.DisabledAt != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", user.ID)
+		c.Set("username", user.Username)
+		c.Set("email", user.Email)
+		c.Set("role", user.Role)
 
 		c.Next()
 	}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/auth"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUsers - пользователи по ID
+type fakeUsers map[int]*models.User
+
+func (f fakeUsers) GetByID(ctx context.Context, id int) (*models.User, error) {
+	return f[id], nil
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtManager := auth.NewJWTManager("secret", 1)
+	disabledAt := time.Now()
+	users := fakeUsers{
+		1: {ID: 1, Username: "teacher", Role: models.RoleAdmin},
+		2: {ID: 2, Username: "former", Role: models.RoleAdmin, DisabledAt: &disabledAt},
+	}
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(jwtManager, users, slog.Default()), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("username"))
+	})
+
+	serve := func(user *models.User) *httptest.ResponseRecorder {
+		token, err := jwtManager.GenerateToken(user)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(users[1])
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "teacher", w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, serve(users[2]).Code, "tokens of a disabled user stop working")
+	assert.Equal(t, http.StatusUnauthorized, serve(&models.User{ID: 3, Username: "deleted"}).Code)
+
+	t.Run("demoted admin loses admin access", func(t *testing.T) {
+		admin := &models.User{ID: 4, Username: "assistant", Role: models.RoleAdmin}
+		token, err := jwtManager.GenerateToken(admin)
+		require.NoError(t, err)
+		demoted := *admin
+		demoted.Role = models.RoleStudent
+		users[4] = &demoted
+
+		adminRouter := gin.New()
+		adminRouter.GET("/admin", AuthMiddleware(jwtManager, users, slog.Default()), AdminOnly(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		adminRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
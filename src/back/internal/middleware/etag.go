@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag буферизует ответы GET-запросов и добавляет к успешным заголовок ETag
+// по хэшу тела; если он совпадает с If-None-Match, клиент получает 304 без тела
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buffered
+		c.Next()
+		c.Writer = original
+
+		if buffered.Status() != http.StatusOK {
+			original.Write(buffered.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(buffered.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		original.Header().Set("ETag", etag)
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.Write(buffered.body.Bytes())
+	}
+}
+
+// bufferedWriter копит тело ответа; статус и заголовки gin и так отправляет лениво
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// etagMatches сравнивает If-None-Match с ETag слабым сравнением, как требует RFC 9110
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
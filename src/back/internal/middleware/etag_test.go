@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ETag())
+	router.GET("/courses", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "Go"})
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	router.POST("/courses", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/courses", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.JSONEq(t, `{"name":"Go"}`, first.Body.String())
+	assert.Equal(t, etag, get("/courses", "").Header().Get("ETag"), "same body must give same ETag")
+
+	t.Run("matching If-None-Match returns 304 without body", func(t *testing.T) {
+		w := get("/courses", etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+
+		assert.Equal(t, http.StatusNotModified, get("/courses", `"other", W/`+etag).Code)
+		assert.Equal(t, http.StatusNotModified, get("/courses", "*").Code)
+	})
+
+	t.Run("stale If-None-Match returns body", func(t *testing.T) {
+		w := get("/courses", `"stale"`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"Go"}`, w.Body.String())
+	})
+
+	t.Run("errors are passed through without ETag", func(t *testing.T) {
+		w := get("/missing", "*")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.JSONEq(t, `{"error":"not found"}`, w.Body.String())
+	})
+
+	t.Run("other methods are not buffered", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/courses", nil))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+}
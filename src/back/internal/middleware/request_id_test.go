@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("request_id"))
+	})
+
+	get := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("proxy-id-1")
+	assert.Equal(t, "proxy-id-1", w.Body.String())
+	assert.Equal(t, "proxy-id-1", w.Header().Get(RequestIDHeader))
+
+	w = get("")
+	assert.Len(t, w.Body.String(), 32)
+	assert.Equal(t, w.Body.String(), w.Header().Get(RequestIDHeader))
+
+	w = get(strings.Repeat("x", 65))
+	assert.Len(t, w.Body.String(), 32, "too long id is replaced")
+
+	w = get("bad id")
+	assert.Len(t, w.Body.String(), 32, "id with spaces is replaced")
+}
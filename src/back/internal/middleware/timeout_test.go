@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sets deadline on request context", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/test", QueryTimeout(5*time.Second), func(c *gin.Context) {
+			deadline, ok := c.Request.Context().Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("cancels context after timeout", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/test", QueryTimeout(10*time.Millisecond), func(c *gin.Context) {
+			select {
+			case <-c.Request.Context().Done():
+				c.Status(http.StatusGatewayTimeout)
+			case <-time.After(time.Second):
+				c.Status(http.StatusOK)
+			}
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+}
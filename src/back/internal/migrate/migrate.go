@@ -0,0 +1,297 @@
+// Package migrate применяет SQL-миграции в формате goose без внешней утилиты.
+// Версии хранятся в той же таблице goose_db_version, поэтому базы,
+// мигрированные вручную через goose, продолжают работать без изменений.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+)
+
+// VersionTable - таблица применённых версий, совместимая с goose
+const VersionTable = "goose_db_version"
+
+// lockName - именованная блокировка MySQL, чтобы несколько экземпляров
+// сервера с auto_migrate не применяли одни и те же миграции одновременно
+const lockName = "laritmo_migrate"
+
+// advisoryLockKey - ключ pg_advisory_lock с тем же назначением для PostgreSQL
+const advisoryLockKey int64 = 0x6c61726974 // "larit" в ASCII
+
+// lockTimeout - сколько секунд ждать, пока другой процесс закончит миграции
+const lockTimeout = 300
+
+// Status - состояние миграции в базе
+type Status struct {
+	Migration
+	// AppliedAt - время применения; nil, если миграция ещё не применена
+	AppliedAt *time.Time
+}
+
+type Runner struct {
+	db         *database.DB
+	migrations []Migration
+}
+
+// NewRunner загружает миграции из fsys; они должны быть написаны для диалекта db
+func NewRunner(db *database.DB, fsys fs.FS) (*Runner, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// Up применяет все ещё не применённые миграции по возрастанию версии
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range r.migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := r.apply(ctx, m.Version, m.Up, true); err != nil {
+			return done, fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Down откатывает последнюю применённую миграцию; nil означает, что откатывать нечего
+func (r *Runner) Down(ctx context.Context) (*Migration, error) {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		m := r.migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := r.apply(ctx, m.Version, m.Down, false); err != nil {
+			return nil, fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+		}
+		return &m, nil
+	}
+
+	return nil, nil
+}
+
+// Status возвращает все известные миграции с отметкой о применении
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(r.migrations))
+	for i, m := range r.migrations {
+		statuses[i] = Status{Migration: m}
+		if at, ok := applied[m.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+
+	return statuses, nil
+}
+
+// Version возвращает версию последней применённой миграции или 0, если их нет
+func (r *Runner) Version(ctx context.Context) (int64, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Pending возвращает число ещё не применённых миграций
+func (r *Runner) Pending(ctx context.Context) (int, error) {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// lock берёт блокировку на отдельном соединении: GET_LOCK и pg_advisory_lock действуют в пределах сессии
+func (r *Runner) lock(ctx context.Context) (func(), error) {
+	if r.db.Dialect == database.SQLite {
+		// База SQLite - локальный файл одного процесса, а пул из одного соединения
+		// заблокировался бы, удерживая отдельное соединение под блокировку
+		return func() {}, nil
+	}
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	if r.db.Dialect == database.Postgres {
+		lockCtx, cancel := context.WithTimeout(ctx, lockTimeout*time.Second)
+		defer cancel()
+
+		if _, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+
+		return func() {
+			conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+			conn.Close()
+		}, nil
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("timed out waiting for migration lock held by another process")
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+		conn.Close()
+	}, nil
+}
+
+func (r *Runner) apply(ctx context.Context, version int64, statements []string, up bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	query, args, err := r.db.Dialect.Builder().Insert(VersionTable).
+		Columns("version_id", "is_applied").
+		Values(version, up).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to record version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// applied возвращает применённые версии со временем применения.
+// Каждое применение и откат - отдельная строка, решает последняя запись по версии.
+func (r *Runner) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if err := r.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.db.Dialect.Builder().Select("version_id", "is_applied", "tstamp").
+		From(VersionTable).
+		OrderBy("id DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", VersionTable, err)
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			isApplied bool
+			tstamp    sql.NullTime
+		)
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, fmt.Errorf("scan error for %s: %w", VersionTable, err)
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied && version > 0 {
+			applied[version] = tstamp.Time
+		}
+	}
+
+	return applied, rows.Err()
+}
+
+func (r *Runner) ensureVersionTable(ctx context.Context) error {
+	ddl := `CREATE TABLE IF NOT EXISTS ` + VersionTable + ` (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+		version_id BIGINT NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (id)
+	)`
+	switch r.db.Dialect {
+	case database.Postgres:
+		ddl = `CREATE TABLE IF NOT EXISTS ` + VersionTable + ` (
+		id SERIAL PRIMARY KEY,
+		version_id BIGINT NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp TIMESTAMP NULL DEFAULT now()
+	)`
+	case database.SQLite:
+		ddl = `CREATE TABLE IF NOT EXISTS ` + VersionTable + ` (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied INTEGER NOT NULL,
+		tstamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	}
+
+	_, err := r.db.ExecContext(ctx, ddl)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", VersionTable, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration - одна миграция в формате goose: <version>_<name>.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// Load читает все *.sql из корня fsys и возвращает миграции по возрастанию версии
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	seen := make(map[int64]string)
+	for _, file := range files {
+		version, name, err := parseFileName(file)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, file)
+		}
+		seen[version] = file
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		up, down, err := parseSQL(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: name, Up: up, Down: down})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func parseFileName(file string) (int64, string, error) {
+	base := strings.TrimSuffix(path.Base(file), ".sql")
+	prefix, name, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", fmt.Errorf("invalid migration file name %q: expected <version>_<name>.sql", file)
+	}
+
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", fmt.Errorf("invalid migration version in %q", file)
+	}
+
+	return version, name, nil
+}
+
+// parseSQL делит файл на выражения секций Up и Down по аннотациям goose.
+// Выражение заканчивается ";" в конце строки; блок StatementBegin/StatementEnd
+// выполняется целиком, чтобы внутри можно было писать процедуры и триггеры.
+func parseSQL(data []byte) (up, down []string, err error) {
+	var (
+		section *[]string
+		buf     strings.Builder
+		inBlock bool
+		sawUp   bool
+		scanner = bufio.NewScanner(bytes.NewReader(data))
+		flush   = func() {
+			if stmt := strings.TrimSpace(buf.String()); stmt != "" && section != nil {
+				*section = append(*section, stmt)
+			}
+			buf.Reset()
+		}
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if annotation, ok := strings.CutPrefix(trimmed, "-- +goose "); ok {
+			switch strings.TrimSpace(annotation) {
+			case "Up":
+				flush()
+				section, sawUp = &up, true
+			case "Down":
+				flush()
+				section = &down
+			case "StatementBegin":
+				flush()
+				inBlock = true
+			case "StatementEnd":
+				flush()
+				inBlock = false
+			}
+			continue
+		}
+
+		if section == nil || (buf.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--"))) {
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if inBlock {
+		return nil, nil, fmt.Errorf("missing -- +goose StatementEnd")
+	}
+	flush()
+
+	if !sawUp {
+		return nil, nil, fmt.Errorf("missing -- +goose Up annotation")
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSQL(t *testing.T) {
+	data := `-- +goose Up
+
+-- comment before the first statement
+CREATE TABLE a (
+    id INT PRIMARY KEY
+);
+ALTER TABLE a ADD COLUMN name VARCHAR(10);
+
+-- +goose StatementBegin
+CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW
+BEGIN
+    SET NEW.name = 'x';
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TABLE IF EXISTS a;
+`
+
+	up, down, err := parseSQL([]byte(data))
+	require.NoError(t, err)
+
+	require.Len(t, up, 3)
+	assert.Equal(t, "CREATE TABLE a (\n    id INT PRIMARY KEY\n);", up[0])
+	assert.Equal(t, "ALTER TABLE a ADD COLUMN name VARCHAR(10);", up[1])
+	assert.Contains(t, up[2], "SET NEW.name = 'x';\nEND;")
+	assert.Equal(t, []string{"DROP TABLE IF EXISTS a;"}, down)
+}
+
+func TestParseSQL_Errors(t *testing.T) {
+	_, _, err := parseSQL([]byte("CREATE TABLE a (id INT);"))
+	assert.Error(t, err)
+
+	_, _, err = parseSQL([]byte("-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n"))
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240102000000_second.sql": {Data: []byte("-- +goose Up\nSELECT 2;\n")},
+		"20240101000000_first.sql":  {Data: []byte("-- +goose Up\nSELECT 1;\n-- +goose Down\nSELECT 0;\n")},
+		"README.md":                 {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, int64(20240101000000), migrations[0].Version)
+	assert.Equal(t, "first", migrations[0].Name)
+	assert.Equal(t, []string{"SELECT 0;"}, migrations[0].Down)
+	assert.Equal(t, "second", migrations[1].Name)
+	assert.Empty(t, migrations[1].Down)
+}
+
+func TestLoad_InvalidNames(t *testing.T) {
+	_, err := Load(fstest.MapFS{"init.sql": {Data: []byte("-- +goose Up\n")}})
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{
+		"1_a.sql":  {Data: []byte("-- +goose Up\n")},
+		"01_b.sql": {Data: []byte("-- +goose Up\n")},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Виды объявлений: написанные преподавателем и созданные сервером при изменении материалов
+const (
+	AnnouncementKindManual           = "manual"
+	AnnouncementKindLabDeadline      = "lab_deadline"
+	AnnouncementKindLecturePublished = "lecture_published"
+)
+
+// Announcement - объявление курса; публикуется сразу и попадает в ленту курса
+type Announcement struct {
+	ID        int       `json:"id"`
+	CourseID  int       `json:"course_id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
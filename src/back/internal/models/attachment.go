@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Attachment - файл, прикреплённый к лекции или лабораторной; сам файл лежит в хранилище вложений
+type Attachment struct {
+	ID       int `json:"id"`
+	CourseID int `json:"course_id"`
+	// Задан ровно один из LectureID и LabID
+	LectureID *int   `json:"lecture_id,omitempty"`
+	LabID     *int   `json:"lab_id,omitempty"`
+	FileName  string `json:"file_name"`
+	// ContentType определяется по содержимому файла, а не по заголовку запроса
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AttachmentUsage - занятое вложениями курса место и квота курса в байтах
+type AttachmentUsage struct {
+	CourseID   int   `json:"course_id"`
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+}
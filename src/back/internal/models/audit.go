@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry - запись журнала изменений, сделанных через админский API
+type AuditEntry struct {
+	ID int `json:"id" db:"id"`
+	// UserID и Username - администратор из JWT; имя сохраняется, чтобы запись читалась и после удаления пользователя
+	UserID   *int   `json:"user_id,omitempty" db:"user_id"`
+	Username string `json:"username" db:"username"`
+	// Action - тип сущности и действие: course.update, exam_question.bulk, course.sync
+	Action     string `json:"action" db:"action"`
+	EntityType string `json:"entity_type" db:"entity_type"`
+	EntityID   *int   `json:"entity_id,omitempty" db:"entity_id"`
+	// Before и After - состояние сущности до и после изменения в JSON
+	Before    json.RawMessage `json:"before,omitempty" db:"before_data" swaggertype:"object"`
+	After     json.RawMessage `json:"after,omitempty" db:"after_data" swaggertype:"object"`
+	IP        string          `json:"ip" db:"ip"`
+	RequestID string          `json:"request_id" db:"request_id"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// ContentSyncDiff - изменения одного вида материалов; элементы - номера недель или лабораторных
+type ContentSyncDiff struct {
+	Created   []int `json:"created"`
+	Updated   []int `json:"updated"`
+	Unchanged []int `json:"unchanged"`
+	// Deleted - записи без исходного файла, удалённые при синхронизации с prune
+	Deleted []int `json:"deleted"`
+	// Stale - записи без исходного файла, оставленные в БД
+	Stale []int `json:"stale"`
+	// Skipped - файлы, которые не удалось сопоставить с номером
+	Skipped []string `json:"skipped"`
+}
+
+// ContentSyncCounts - сводка изменений для материалов без сквозной нумерации
+type ContentSyncCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Deleted   int `json:"deleted"`
+	Stale     int `json:"stale"`
+}
+
+// ContentSyncReport - результат синхронизации материалов курса с git-репозиторием
+type ContentSyncReport struct {
+	CourseID int             `json:"course_id"`
+	DryRun   bool            `json:"dry_run"`
+	Lectures ContentSyncDiff `json:"lectures"`
+	Labs     ContentSyncDiff `json:"labs"`
+	// ExamQuestions и GradeSheets заполняются, только если указан файл-источник
+	ExamQuestions *ContentSyncCounts `json:"exam_questions,omitempty"`
+	GradeSheets   *ContentSyncCounts `json:"grade_sheets,omitempty"`
+}
+
+// ContentSyncRequest - параметры синхронизации курса из админки
+type ContentSyncRequest struct {
+	// RepoPath - путь к репозиторию относительно sync.repos_root
+	RepoPath     string `json:"repo_path" binding:"required"`
+	Ref          string `json:"ref"`
+	LecturesGlob string `json:"lectures_glob"`
+	LabsGlob     string `json:"labs_glob"`
+	GithubBase   string `json:"github_base"`
+	Prune        bool   `json:"prune"`
+	DryRun       bool   `json:"dry_run"`
+}
+
+const (
+	WebhookProviderGitHub = "github"
+	WebhookProviderGitea  = "gitea"
+)
+
+const (
+	ContentSyncEventIgnored   = "ignored"
+	ContentSyncEventQueued    = "queued"
+	ContentSyncEventSucceeded = "succeeded"
+	ContentSyncEventFailed    = "failed"
+)
+
+// PushEvent - push из GitHub или Gitea, приведённый к общему виду
+type PushEvent struct {
+	Repository string
+	Ref        string
+	After      string
+	// ChangedPaths - добавленные, изменённые и удалённые файлы всех коммитов push
+	ChangedPaths []string
+}
+
+// ContentSyncEvent - запись о полученном webhook и результате синхронизации по нему
+type ContentSyncEvent struct {
+	ID           int                `json:"id" db:"id"`
+	CourseID     int                `json:"course_id" db:"course_id"`
+	Provider     string             `json:"provider" db:"provider"`
+	DeliveryID   *string            `json:"delivery_id,omitempty" db:"delivery_id"`
+	Repository   string             `json:"repository" db:"repository"`
+	Ref          string             `json:"ref" db:"ref"`
+	CommitSHA    *string            `json:"commit_sha,omitempty" db:"commit_sha"`
+	ChangedPaths []string           `json:"changed_paths" db:"changed_paths"`
+	Status       string             `json:"status" db:"status"`
+	Report       *ContentSyncReport `json:"report,omitempty" db:"report"`
+	Error        *string            `json:"error,omitempty" db:"error"`
+	JobID        *int               `json:"job_id,omitempty" db:"job_id"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// ContentSyncJobPayload - задача синхронизации курса по webhook
+type ContentSyncJobPayload struct {
+	EventID    int    `json:"event_id"`
+	Repository string `json:"repository"`
+}
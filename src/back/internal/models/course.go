@@ -3,10 +3,21 @@ package models
 import "time"
 
 type Course struct {
-	ID          int       `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Semester    string    `json:"semester" db:"semester"`
-	Description string    `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          int        `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	Semester    string     `json:"semester" db:"semester"`
+	Description string     `json:"description" db:"description"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CourseCloneSummary - сколько записей скопировано при клонировании курса
+type CourseCloneSummary struct {
+	Course        *Course `json:"course"`
+	SourceID      int     `json:"source_id"`
+	Lectures      int     `json:"lectures"`
+	Labs          int     `json:"labs"`
+	ExamQuestions int     `json:"exam_questions"`
+	GradeSheets   int     `json:"grade_sheets"`
 }
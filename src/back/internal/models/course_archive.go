@@ -0,0 +1,21 @@
+package models
+
+// CourseContent - курс вместе со всеми материалами, например прочитанный из архива курса
+type CourseContent struct {
+	Course        Course
+	Lectures      []Lecture
+	Labs          []Lab
+	ExamQuestions []ExamQuestion
+	GradeSheets   []GradeSheet
+}
+
+// CourseImportSummary - созданный курс и сколько материалов импортировано вместе с ним
+type CourseImportSummary struct {
+	Course *Course `json:"course"`
+	// SchemaVersion - версия формата архива, из которого импортирован курс
+	SchemaVersion int `json:"schema_version"`
+	Lectures      int `json:"lectures"`
+	Labs          int `json:"labs"`
+	ExamQuestions int `json:"exam_questions"`
+	GradeSheets   int `json:"grade_sheets"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusDead      = "dead"
+)
+
+type Job struct {
+	ID          int             `json:"id" db:"id"`
+	Type        string          `json:"type" db:"type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Status      string          `json:"status" db:"status"`
+	Progress    int             `json:"progress" db:"progress"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts"`
+	LastError   *string         `json:"last_error,omitempty" db:"last_error"`
+	RunAt       time.Time       `json:"run_at" db:"run_at"`
+	LockedBy    *string         `json:"-" db:"locked_by"`
+	LockedUntil *time.Time      `json:"locked_until,omitempty" db:"locked_until"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty" db:"finished_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// JobResult - файл, сформированный фоновой задачей и доступный для скачивания
+type JobResult struct {
+	JobID       int       `json:"job_id" db:"job_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Content     []byte    `json:"-" db:"content"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -11,6 +11,8 @@ type Lab struct {
 	Deadline    *time.Time `json:"deadline,omitempty" db:"deadline"`
 	MaxScore    int        `json:"max_score" db:"max_score"`
 	GithubURL   *string    `json:"github_url,omitempty" db:"github_url"`
+	Status      string     `json:"status" db:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty" db:"publish_at"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 }
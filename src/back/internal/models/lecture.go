@@ -3,12 +3,14 @@ package models
 import "time"
 
 type Lecture struct {
-	ID        int       `json:"id" db:"id"`
-	CourseID  int       `json:"course_id" db:"course_id"`
-	Week      int       `json:"week" db:"week"`
-	Title     string    `json:"title" db:"title"`
-	Content   string    `json:"content" db:"content"`
-	GithubURL *string   `json:"github_url,omitempty" db:"github_url"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        int        `json:"id" db:"id"`
+	CourseID  int        `json:"course_id" db:"course_id"`
+	Week      int        `json:"week" db:"week"`
+	Title     string     `json:"title" db:"title"`
+	Content   string     `json:"content" db:"content"`
+	GithubURL *string    `json:"github_url,omitempty" db:"github_url"`
+	Status    string     `json:"status" db:"status"`
+	PublishAt *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 }
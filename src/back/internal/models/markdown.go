@@ -0,0 +1,28 @@
+package models
+
+// TOCEntry - пункт оглавления, построенного по заголовкам Markdown
+type TOCEntry struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// RenderedContent - Markdown, преобразованный в безопасный HTML
+type RenderedContent struct {
+	HTML string     `json:"html"`
+	TOC  []TOCEntry `json:"toc"`
+}
+
+// RenderedLecture - лекция с отрендеренным содержимым (?render=html)
+type RenderedLecture struct {
+	Lecture
+	ContentHTML string     `json:"content_html"`
+	TOC         []TOCEntry `json:"toc"`
+}
+
+// RenderedLab - лабораторная с отрендеренным описанием (?render=html)
+type RenderedLab struct {
+	Lab
+	DescriptionHTML string     `json:"description_html"`
+	TOC             []TOCEntry `json:"toc"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// Виды email-уведомлений; совпадают с колонками notification_preferences
+const (
+	NotificationNewLab           = "new_lab"
+	NotificationDeadlineReminder = "deadline_reminder"
+	NotificationGradePosted      = "grade_posted"
+)
+
+// NotificationPreferences - какие письма получает пользователь; по умолчанию все включены
+type NotificationPreferences struct {
+	UserID           int  `json:"user_id"`
+	NewLab           bool `json:"new_lab"`
+	DeadlineReminder bool `json:"deadline_reminder"`
+	GradePosted      bool `json:"grade_posted"`
+}
+
+// DefaultNotificationPreferences - настройки пользователя, который их ещё не менял
+func DefaultNotificationPreferences(userID int) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, NewLab: true, DeadlineReminder: true, GradePosted: true}
+}
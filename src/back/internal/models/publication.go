@@ -0,0 +1,17 @@
+package models
+
+// Статусы публикации лекций, лабораторных и ведомостей: черновики видны только в предпросмотре админки,
+// а черновик с publish_at публикуется планировщиком, когда это время наступит
+const (
+	PublicationStatusDraft     = "draft"
+	PublicationStatusPublished = "published"
+)
+
+// PublishedItem - материал, опубликованный планировщиком по наступлении publish_at
+type PublishedItem struct {
+	// Type - lecture, lab или grade_sheet
+	Type     string `json:"type"`
+	ID       int    `json:"id"`
+	CourseID int    `json:"course_id"`
+	Title    string `json:"title"`
+}
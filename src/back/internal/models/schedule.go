@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// CourseSchedule - дата начала курса и недельное расписание занятий
+type CourseSchedule struct {
+	CourseID int `json:"course_id"`
+	// StartDate - дата начала курса в формате 2006-01-02; первая неделя начинается с понедельника этой недели
+	StartDate string          `json:"start_date"`
+	Slots     []TimetableSlot `json:"slots"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TimetableSlot - еженедельное занятие; Weekday по ISO 8601: 1 - понедельник, 7 - воскресенье
+type TimetableSlot struct {
+	ID              int    `json:"id"`
+	Weekday         int    `json:"weekday"`
+	StartTime       string `json:"start_time"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Room            string `json:"room"`
+}
+
+// ScheduledLecture - лекция с датой, вычисленной по номеру недели и расписанию
+type ScheduledLecture struct {
+	LectureID int    `json:"lecture_id"`
+	Week      int    `json:"week"`
+	Title     string `json:"title"`
+	// Start и End отсутствуют у лекций курса без занятий в расписании: тогда Date - понедельник недели
+	Date  string     `json:"date"`
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+	Room  string     `json:"room,omitempty"`
+}
+
+// CourseTimetable - расписание курса вместе с вычисленными датами лекций
+type CourseTimetable struct {
+	Schedule *CourseSchedule    `json:"schedule"`
+	Lectures []ScheduledLecture `json:"lectures"`
+}
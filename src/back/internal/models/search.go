@@ -0,0 +1,25 @@
+package models
+
+const (
+	SearchTypeLecture      = "lecture"
+	SearchTypeLab          = "lab"
+	SearchTypeExamQuestion = "exam_question"
+)
+
+// SearchHit - найденный материал курса
+type SearchHit struct {
+	Type     string  `json:"type"`
+	ID       int     `json:"id"`
+	CourseID int     `json:"course_id"`
+	Title    string  `json:"title"`
+	Snippet  string  `json:"snippet"`
+	Score    float64 `json:"score"`
+	Text     string  `json:"-"`
+}
+
+type SearchResult struct {
+	Items  []SearchHit `json:"items"`
+	Total  int         `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
@@ -15,3 +15,16 @@ type TicketGenerationRequest struct {
 	QuestionsPerTicket int `json:"questionsPerTicket" binding:"required,min=1,max=50"`
 	TicketCount        int `json:"ticketCount" binding:"required,min=1,max=100"`
 }
+
+// TicketBatchRequest - параметры фоновой генерации билетов сразу по нескольким курсам
+type TicketBatchRequest struct {
+	CourseIDs          []int `json:"courseIds" binding:"required,min=1,max=20,dive,min=1"`
+	QuestionsPerTicket int   `json:"questionsPerTicket" binding:"required,min=1,max=50"`
+	TicketCount        int   `json:"ticketCount" binding:"required,min=1,max=1000"`
+}
+
+// CourseTickets - билеты одного курса в составе пакетного документа
+type CourseTickets struct {
+	CourseName string
+	Tickets    []Ticket
+}
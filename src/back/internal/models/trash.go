@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Типы записей в корзине
+const (
+	TrashTypeCourse       = "course"
+	TrashTypeLecture      = "lecture"
+	TrashTypeLab          = "lab"
+	TrashTypeGradeSheet   = "grade_sheet"
+	TrashTypeExamQuestion = "exam_question"
+)
+
+// TrashItem - удалённая запись в корзине. Материалы, удалённые вместе с курсом,
+// отдельно не показываются: они восстанавливаются вместе с ним.
+type TrashItem struct {
+	Type     string `json:"type"`
+	ID       int    `json:"id"`
+	CourseID int    `json:"course_id"`
+	// Title - название курса, лекции или лабораторной, описание ведомости или текст вопроса
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashRestoreSummary - итог восстановления записи; для курса - сколько материалов вернулось вместе с ним
+type TrashRestoreSummary struct {
+	Type          string `json:"type"`
+	ID            int    `json:"id"`
+	Lectures      int    `json:"lectures"`
+	Labs          int    `json:"labs"`
+	GradeSheets   int    `json:"grade_sheets"`
+	ExamQuestions int    `json:"exam_questions"`
+}
@@ -2,12 +2,18 @@ package models
 
 import "time"
 
+const (
+	RoleAdmin   = "admin"
+	RoleStudent = "student"
+)
+
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	Username     string    `json:"username"`
-	PasswordHash string    `json:"-"` // Excluded from JSON response
-	Role         string    `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int        `json:"id"`
+	Email        string     `json:"email"`
+	Username     string     `json:"username"`
+	PasswordHash string     `json:"-"` // Excluded from JSON response
+	Role         string     `json:"role"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+var announcementColumns = []string{"id", "course_id", "title", "body", "kind", "created_at", "updated_at"}
+
+type AnnouncementRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewAnnouncementRepository(db *database.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+// AnnouncementFilter - фильтры списка объявлений
+type AnnouncementFilter struct {
+	CourseID *int
+	Kind     string
+}
+
+var announcementSortable = map[string]string{
+	"id":         "id",
+	"course_id":  "course_id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// GetAll возвращает страницу объявлений, по умолчанию сначала новые, и общее число подходящих под фильтр
+func (r *AnnouncementRepository) GetAll(ctx context.Context, filter AnnouncementFilter, opts ListOptions) ([]models.Announcement, int, error) {
+	builder := r.sb.Select(announcementColumns...).From("announcements")
+
+	if filter.CourseID != nil {
+		builder = builder.Where(sq.Eq{"course_id": *filter.CourseID})
+	}
+	if filter.Kind != "" {
+		builder = builder.Where(sq.Eq{"kind": filter.Kind})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, announcementSortable, "created_at DESC", "id DESC")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var announcements []models.Announcement
+	for rows.Next() {
+		var a models.Announcement
+		if err := rows.Scan(&a.ID, &a.CourseID, &a.Title, &a.Body, &a.Kind, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan error announcement: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read announcements: %w", err)
+	}
+
+	return announcements, total, nil
+}
+
+// GetByID возвращает nil без ошибки, если объявления нет
+func (r *AnnouncementRepository) GetByID(ctx context.Context, id int) (*models.Announcement, error) {
+	query, args, err := r.sb.Select(announcementColumns...).
+		From("announcements").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var a models.Announcement
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&a.ID, &a.CourseID, &a.Title, &a.Body, &a.Kind, &a.CreatedAt, &a.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get announcement: %w", err)
+	}
+
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) Create(ctx context.Context, courseID int, title, body, kind string) (*models.Announcement, error) {
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("announcements").
+		Columns("course_id", "title", "body", "kind").
+		Values(courseID, title, body, kind))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create announcement: %w", err)
+	}
+
+	announcement, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created announcement: %w", err)
+	}
+	if announcement == nil {
+		return nil, fmt.Errorf("created announcement not found")
+	}
+
+	return announcement, nil
+}
+
+func (r *AnnouncementRepository) Update(ctx context.Context, id int, title, body string) error {
+	query, args, err := r.sb.Update("announcements").
+		Set("title", title).
+		Set("body", body).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update announcement: %w", err)
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) Delete(ctx context.Context, id int) error {
+	query, args, err := r.sb.Delete("announcements").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete announcement: %w", err)
+	}
+	return nil
+}
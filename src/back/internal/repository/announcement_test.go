@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnouncementRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewAnnouncementRepository(db)
+	courseID := createTestCourse(t, db, "Сети", "2026-fall")
+	otherID := createTestCourse(t, db, "Go", "2026-fall")
+
+	first, err := repo.Create(ctx, courseID, "Перенос лекции", "Лекция будет в пятницу.", models.AnnouncementKindManual)
+	require.NoError(t, err)
+	assert.Equal(t, "Перенос лекции", first.Title)
+	assert.Equal(t, models.AnnouncementKindManual, first.Kind)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	second, err := repo.Create(ctx, courseID, "Изменён дедлайн", "Новый дедлайн: 08.10.2026 23:59.", models.AnnouncementKindLabDeadline)
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, otherID, "Добро пожаловать", "Курс начался.", models.AnnouncementKindManual)
+	require.NoError(t, err)
+
+	t.Run("list newest first", func(t *testing.T) {
+		announcements, total, err := repo.GetAll(ctx, AnnouncementFilter{CourseID: &courseID}, ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, announcements, 2)
+		assert.Equal(t, second.ID, announcements[0].ID, "same created_at falls back to id")
+
+		announcements, total, err = repo.GetAll(ctx, AnnouncementFilter{Kind: models.AnnouncementKindManual}, ListOptions{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, announcements, 1)
+
+		_, _, err = repo.GetAll(ctx, AnnouncementFilter{}, ListOptions{Sort: "title"})
+		assert.ErrorIs(t, err, ErrInvalidSort)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		require.NoError(t, repo.Update(ctx, first.ID, "Лекция в пятницу", "Аудитория 305."))
+		got, err := repo.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Лекция в пятницу", got.Title)
+		assert.Equal(t, "Аудитория 305.", got.Body)
+
+		require.NoError(t, repo.Delete(ctx, first.ID))
+		got, err = repo.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("purged course takes its announcements", func(t *testing.T) {
+		require.NoError(t, NewCourseRepository(db).Delete(ctx, courseID))
+		_, err := NewTrashRepository(db).Purge(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		_, total, err := repo.GetAll(ctx, AnnouncementFilter{}, ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+	})
+}
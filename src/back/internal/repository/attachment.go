@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+var attachmentColumns = []string{
+	"id", "course_id", "lecture_id", "lab_id", "file_name", "content_type", "size", "storage_key", "created_at",
+}
+
+type AttachmentRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewAttachmentRepository(db *database.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAttachment читает строку attachmentColumns; у вложения без владельца course_id может быть NULL
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	var a models.Attachment
+	var courseID, lectureID, labID sql.NullInt64
+	if err := row.Scan(&a.ID, &courseID, &lectureID, &labID, &a.FileName, &a.ContentType, &a.Size, &a.StorageKey, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	a.CourseID = int(courseID.Int64)
+	if lectureID.Valid {
+		id := int(lectureID.Int64)
+		a.LectureID = &id
+	}
+	if labID.Valid {
+		id := int(labID.Int64)
+		a.LabID = &id
+	}
+	return &a, nil
+}
+
+// Create сохраняет запись о файле, уже положенном в хранилище; задан ровно один из LectureID и LabID
+func (r *AttachmentRepository) Create(ctx context.Context, a *models.Attachment) (*models.Attachment, error) {
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("attachments").
+		Columns("course_id", "lecture_id", "lab_id", "file_name", "content_type", "size", "storage_key").
+		Values(a.CourseID, a.LectureID, a.LabID, a.FileName, a.ContentType, a.Size, a.StorageKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	created, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created attachment: %w", err)
+	}
+	if created == nil {
+		return nil, fmt.Errorf("created attachment not found")
+	}
+
+	return created, nil
+}
+
+// GetByID возвращает nil без ошибки, если вложения нет
+func (r *AttachmentRepository) GetByID(ctx context.Context, id int) (*models.Attachment, error) {
+	query, args, err := r.sb.Select(attachmentColumns...).
+		From("attachments").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	a, err := scanAttachment(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListByLecture возвращает вложения лекции в порядке загрузки
+func (r *AttachmentRepository) ListByLecture(ctx context.Context, lectureID int) ([]models.Attachment, error) {
+	return r.list(ctx, sq.Eq{"lecture_id": lectureID})
+}
+
+// ListByLab возвращает вложения лабораторной в порядке загрузки
+func (r *AttachmentRepository) ListByLab(ctx context.Context, labID int) ([]models.Attachment, error) {
+	return r.list(ctx, sq.Eq{"lab_id": labID})
+}
+
+// ListOrphans возвращает вложения, чьи лекция или лабораторная окончательно удалены
+func (r *AttachmentRepository) ListOrphans(ctx context.Context) ([]models.Attachment, error) {
+	return r.list(ctx, sq.Eq{"lecture_id": nil, "lab_id": nil})
+}
+
+func (r *AttachmentRepository) list(ctx context.Context, where sq.Sqlizer) ([]models.Attachment, error) {
+	query, args, err := r.sb.Select(attachmentColumns...).
+		From("attachments").
+		Where(where).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error attachment: %w", err)
+		}
+		attachments = append(attachments, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// CourseUsage возвращает суммарный размер вложений курса в байтах, включая вложения
+// материалов в корзине: их файлы ещё занимают место в хранилище
+func (r *AttachmentRepository) CourseUsage(ctx context.Context, courseID int) (int64, error) {
+	query, args, err := r.sb.Select("COALESCE(SUM(size), 0)").
+		From("attachments").
+		Where(sq.Eq{"course_id": courseID}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var used int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&used); err != nil {
+		return 0, fmt.Errorf("failed to get attachments usage: %w", err)
+	}
+
+	return used, nil
+}
+
+// Delete удаляет запись о вложении; файл в хранилище удаляет вызывающий
+func (r *AttachmentRepository) Delete(ctx context.Context, id int) error {
+	query, args, err := r.sb.Delete("attachments").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
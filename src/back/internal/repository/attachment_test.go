@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewAttachmentRepository(db)
+	courseID := createTestCourse(t, db, "Сети", "2026-fall")
+
+	lecture, err := NewLectureRepository(db).Create(ctx, courseID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	lab, err := NewLabRepository(db).Create(ctx, courseID, 1, 10, "Сокеты", "", "", nil, "", nil)
+	require.NoError(t, err)
+
+	slides, err := repo.Create(ctx, &models.Attachment{
+		CourseID: courseID, LectureID: &lecture.ID, FileName: "слайды.pdf",
+		ContentType: "application/pdf", Size: 300, StorageKey: "courses/1/a",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, lecture.ID, *slides.LectureID)
+	assert.Nil(t, slides.LabID)
+	assert.Equal(t, "слайды.pdf", slides.FileName)
+	assert.Equal(t, "courses/1/a", slides.StorageKey)
+	assert.False(t, slides.CreatedAt.IsZero())
+
+	_, err = repo.Create(ctx, &models.Attachment{
+		CourseID: courseID, LabID: &lab.ID, FileName: "task.zip",
+		ContentType: "application/zip", Size: 200, StorageKey: "courses/1/b",
+	})
+	require.NoError(t, err)
+
+	byLecture, err := repo.ListByLecture(ctx, lecture.ID)
+	require.NoError(t, err)
+	require.Len(t, byLecture, 1)
+	assert.Equal(t, slides.ID, byLecture[0].ID)
+
+	byLab, err := repo.ListByLab(ctx, lab.ID)
+	require.NoError(t, err)
+	require.Len(t, byLab, 1)
+	assert.Equal(t, "task.zip", byLab[0].FileName)
+
+	used, err := repo.CourseUsage(ctx, courseID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), used)
+
+	t.Run("purged owner leaves an orphan", func(t *testing.T) {
+		require.NoError(t, NewLabRepository(db).Delete(ctx, lab.ID))
+
+		orphans, err := repo.ListOrphans(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, orphans, "attachments of trashed labs are kept")
+
+		_, err = NewTrashRepository(db).Purge(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		orphans, err = repo.ListOrphans(ctx)
+		require.NoError(t, err)
+		require.Len(t, orphans, 1)
+		assert.Equal(t, "courses/1/b", orphans[0].StorageKey)
+		assert.Nil(t, orphans[0].LabID)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, slides.ID))
+
+		got, err := repo.GetByID(ctx, slides.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		used, err := repo.CourseUsage(ctx, courseID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), used)
+	})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+var auditLogColumns = []string{
+	"id", "user_id", "username", "action", "entity_type", "entity_id",
+	"before_data", "after_data", "ip", "request_id", "created_at",
+}
+
+var auditLogSortable = map[string]string{
+	"id":          "id",
+	"action":      "action",
+	"entity_type": "entity_type",
+	"username":    "username",
+	"created_at":  "created_at",
+}
+
+// AuditFilter - фильтры журнала аудита; пустые поля не ограничивают выборку
+type AuditFilter struct {
+	UserID     *int
+	Action     string
+	EntityType string
+	EntityID   *int
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditLogRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewAuditLogRepository(db *database.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+func scanAuditEntry(row sq.RowScanner) (*models.AuditEntry, error) {
+	var e models.AuditEntry
+	var before, after []byte
+	err := row.Scan(&e.ID, &e.UserID, &e.Username, &e.Action, &e.EntityType, &e.EntityID,
+		&before, &after, &e.IP, &e.RequestID, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Before = before
+	e.After = after
+	return &e, nil
+}
+
+// nullJSON передаёт пустой снимок как NULL
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+// Create добавляет запись в журнал; время записи проставляет БД
+func (r *AuditLogRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	query, args, err := r.sb.Insert("audit_log").
+		Columns("user_id", "username", "action", "entity_type", "entity_id", "before_data", "after_data", "ip", "request_id").
+		Values(entry.UserID, entry.Username, entry.Action, entry.EntityType, entry.EntityID,
+			nullJSON(entry.Before), nullJSON(entry.After), entry.IP, entry.RequestID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetAll возвращает страницу журнала, по умолчанию новые записи первыми
+func (r *AuditLogRepository) GetAll(ctx context.Context, filter AuditFilter, opts ListOptions) ([]models.AuditEntry, int, error) {
+	builder := r.sb.Select(auditLogColumns...).From("audit_log")
+
+	if filter.UserID != nil {
+		builder = builder.Where(sq.Eq{"user_id": *filter.UserID})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(sq.Eq{"action": filter.Action})
+	}
+	if filter.EntityType != "" {
+		builder = builder.Where(sq.Eq{"entity_type": filter.EntityType})
+	}
+	if filter.EntityID != nil {
+		builder = builder.Where(sq.Eq{"entity_id": *filter.EntityID})
+	}
+	if filter.From != nil {
+		builder = builder.Where(sq.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(sq.LtOrEq{"created_at": *filter.To})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, auditLogSortable, "created_at DESC")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan error audit entry: %w", err)
+		}
+		entries = append(entries, *e)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAuditLogRepository(newTestDB(t))
+
+	adminID, courseID := 1, 7
+	require.NoError(t, repo.Create(ctx, &models.AuditEntry{
+		UserID:     &adminID,
+		Username:   "admin",
+		Action:     "course.update",
+		EntityType: "course",
+		EntityID:   &courseID,
+		Before:     json.RawMessage(`{"name":"Go"}`),
+		After:      json.RawMessage(`{"name":"Go 2"}`),
+		IP:         "10.0.0.1",
+		RequestID:  "req-1",
+	}))
+	require.NoError(t, repo.Create(ctx, &models.AuditEntry{
+		UserID:     &adminID,
+		Username:   "admin",
+		Action:     "lecture.delete",
+		EntityType: "lecture",
+		IP:         "10.0.0.1",
+		RequestID:  "req-2",
+	}))
+
+	entries, total, err := repo.GetAll(ctx, AuditFilter{}, ListOptions{Sort: "id"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "course.update", entries[0].Action)
+	assert.JSONEq(t, `{"name":"Go"}`, string(entries[0].Before))
+	assert.JSONEq(t, `{"name":"Go 2"}`, string(entries[0].After))
+	require.NotNil(t, entries[0].EntityID)
+	assert.Equal(t, courseID, *entries[0].EntityID)
+	assert.Nil(t, entries[1].Before)
+	assert.Nil(t, entries[1].EntityID)
+
+	entries, total, err = repo.GetAll(ctx, AuditFilter{EntityType: "course", EntityID: &courseID}, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+
+	otherUser := 2
+	_, total, err = repo.GetAll(ctx, AuditFilter{UserID: &otherUser}, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	future := time.Now().Add(time.Hour)
+	_, total, err = repo.GetAll(ctx, AuditFilter{From: &future}, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
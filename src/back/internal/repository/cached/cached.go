@@ -0,0 +1,42 @@
+// Package cached оборачивает хранилища кэшем для публичных GET-запросов.
+// Чтения идут через cache.Fetch, любая запись через обёртку инвалидирует
+// пространства имён, данные которых она могла изменить.
+package cached
+
+import (
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// Пространства имён кэша по таблицам
+const (
+	coursesNamespace       = "courses"
+	lecturesNamespace      = "lectures"
+	examQuestionsNamespace = "exam_questions"
+)
+
+// page - страница списка вместе с общим числом записей
+type page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// Wrap подменяет в store хранилища курсов, лекций и вопросов к экзамену кэширующими обёртками,
+// а корзину и публикацию по расписанию - обёртками, сбрасывающими кэш после изменений
+func Wrap(store *repository.Store, c *cache.Cache) *repository.Store {
+	wrapped := *store
+	wrapped.Courses = NewCourseStore(store.Courses, c)
+	wrapped.Lectures = NewLectureStore(store.Lectures, c)
+	wrapped.ExamQuestions = NewExamQuestionStore(store.ExamQuestions, c)
+	wrapped.Trash = NewTrashStore(store.Trash, c)
+	wrapped.Publication = NewPublicationStore(store.Publication, c)
+	return &wrapped
+}
+
+var (
+	_ repository.CourseStore       = (*CourseStore)(nil)
+	_ repository.LectureStore      = (*LectureStore)(nil)
+	_ repository.ExamQuestionStore = (*ExamQuestionStore)(nil)
+	_ repository.TrashStore        = (*TrashStore)(nil)
+	_ repository.PublicationStore  = (*PublicationStore)(nil)
+)
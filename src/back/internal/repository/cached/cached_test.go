@@ -0,0 +1,101 @@
+package cached
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingQuestions считает обращения к банку вопросов курса
+type countingQuestions struct {
+	repository.ExamQuestionStore
+	loads int
+}
+
+func (s *countingQuestions) GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error) {
+	s.loads++
+	return s.ExamQuestionStore.GetByCourseID(ctx, courseID)
+}
+
+func newTestStore(t *testing.T) (*repository.Store, *repository.Store, *countingQuestions) {
+	t.Helper()
+	inner := memory.NewStore(memory.NewDB())
+	questions := &countingQuestions{ExamQuestionStore: inner.ExamQuestions}
+	inner.ExamQuestions = questions
+
+	c := cache.New(cache.NewLRU(100), time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return Wrap(inner, c), inner, questions
+}
+
+func TestCachedStore(t *testing.T) {
+	ctx := context.Background()
+	store, inner, questions := newTestStore(t)
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	_, err = store.ExamQuestions.Create(ctx, course.ID, 1, "Основы", "Что такое горутина?")
+	require.NoError(t, err)
+
+	t.Run("repeated reads hit the cache", func(t *testing.T) {
+		for range 3 {
+			bank, err := store.ExamQuestions.GetByCourseID(ctx, course.ID)
+			require.NoError(t, err)
+			assert.Len(t, bank, 1)
+		}
+		assert.Equal(t, 1, questions.loads)
+	})
+
+	t.Run("writes through the wrapper invalidate", func(t *testing.T) {
+		_, err := store.ExamQuestions.Create(ctx, course.ID, 2, "Основы", "Что такое канал?")
+		require.NoError(t, err)
+
+		bank, err := store.ExamQuestions.GetByCourseID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Len(t, bank, 2)
+	})
+
+	t.Run("writes bypassing the wrapper stay hidden until invalidation", func(t *testing.T) {
+		require.NoError(t, inner.Courses.Update(ctx, course.ID, "Go 2", "2025-fall", ""))
+
+		cachedCourse, err := store.Courses.GetByID(ctx, course.ID)
+		require.NoError(t, err)
+		require.NotNil(t, cachedCourse)
+
+		require.NoError(t, store.Courses.SetArchived(ctx, course.ID, false))
+		fresh, err := store.Courses.GetByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Go 2", fresh.Name)
+	})
+
+	t.Run("course delete invalidates its materials", func(t *testing.T) {
+		_, err := store.ExamQuestions.GetByCourseID(ctx, course.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Courses.Delete(ctx, course.ID))
+
+		bank, err := store.ExamQuestions.GetByCourseID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Empty(t, bank)
+
+		deleted, err := store.Courses.GetByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Nil(t, deleted)
+	})
+	t.Run("restore from trash invalidates", func(t *testing.T) {
+		_, err := store.Trash.Restore(ctx, models.TrashTypeCourse, course.ID)
+		require.NoError(t, err)
+
+		bank, err := store.ExamQuestions.GetByCourseID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Len(t, bank, 2)
+	})
+}
@@ -0,0 +1,68 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type CourseStore struct {
+	repository.CourseStore
+	cache *cache.Cache
+}
+
+func NewCourseStore(inner repository.CourseStore, c *cache.Cache) *CourseStore {
+	return &CourseStore{CourseStore: inner, cache: c}
+}
+
+type courseListKey struct {
+	Filter repository.CourseFilter
+	Opts   repository.ListOptions
+}
+
+func (s *CourseStore) GetAll(ctx context.Context, filter repository.CourseFilter, opts repository.ListOptions) ([]models.Course, int, error) {
+	p, err := cache.Fetch(ctx, s.cache, coursesNamespace, courseListKey{Filter: filter, Opts: opts}, func() (page[models.Course], error) {
+		courses, total, err := s.CourseStore.GetAll(ctx, filter, opts)
+		return page[models.Course]{Items: courses, Total: total}, err
+	})
+	return p.Items, p.Total, err
+}
+
+func (s *CourseStore) GetByID(ctx context.Context, id int) (*models.Course, error) {
+	return cache.Fetch(ctx, s.cache, coursesNamespace, id, func() (*models.Course, error) {
+		return s.CourseStore.GetByID(ctx, id)
+	})
+}
+
+func (s *CourseStore) Create(ctx context.Context, name, semester, description string) (*models.Course, error) {
+	defer s.cache.Invalidate(ctx, coursesNamespace)
+	return s.CourseStore.Create(ctx, name, semester, description)
+}
+
+func (s *CourseStore) Update(ctx context.Context, id int, name, semester, description string) error {
+	defer s.cache.Invalidate(ctx, coursesNamespace)
+	return s.CourseStore.Update(ctx, id, name, semester, description)
+}
+
+func (s *CourseStore) SetArchived(ctx context.Context, id int, archived bool) error {
+	defer s.cache.Invalidate(ctx, coursesNamespace)
+	return s.CourseStore.SetArchived(ctx, id, archived)
+}
+
+// Delete удаляет и материалы курса, поэтому сбрасывает кэш лекций и вопросов тоже
+func (s *CourseStore) Delete(ctx context.Context, id int) error {
+	defer s.cache.Invalidate(ctx, coursesNamespace, lecturesNamespace, examQuestionsNamespace)
+	return s.CourseStore.Delete(ctx, id)
+}
+
+func (s *CourseStore) Clone(ctx context.Context, sourceID int, opts repository.CourseCloneOptions) (*models.CourseCloneSummary, error) {
+	defer s.cache.Invalidate(ctx, coursesNamespace, lecturesNamespace, examQuestionsNamespace)
+	return s.CourseStore.Clone(ctx, sourceID, opts)
+}
+
+func (s *CourseStore) Import(ctx context.Context, content *models.CourseContent) (*models.CourseImportSummary, error) {
+	defer s.cache.Invalidate(ctx, coursesNamespace, lecturesNamespace, examQuestionsNamespace)
+	return s.CourseStore.Import(ctx, content)
+}
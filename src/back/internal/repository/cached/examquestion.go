@@ -0,0 +1,69 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// ExamQuestionStore кэширует и банк вопросов курса, который TicketService читает на каждый билет
+type ExamQuestionStore struct {
+	repository.ExamQuestionStore
+	cache *cache.Cache
+}
+
+func NewExamQuestionStore(inner repository.ExamQuestionStore, c *cache.Cache) *ExamQuestionStore {
+	return &ExamQuestionStore{ExamQuestionStore: inner, cache: c}
+}
+
+type examQuestionListKey struct {
+	Filter repository.ExamQuestionFilter
+	Opts   repository.ListOptions
+}
+
+func (s *ExamQuestionStore) GetAll(ctx context.Context, filter repository.ExamQuestionFilter, opts repository.ListOptions) ([]models.ExamQuestion, int, error) {
+	p, err := cache.Fetch(ctx, s.cache, examQuestionsNamespace, examQuestionListKey{Filter: filter, Opts: opts}, func() (page[models.ExamQuestion], error) {
+		questions, total, err := s.ExamQuestionStore.GetAll(ctx, filter, opts)
+		return page[models.ExamQuestion]{Items: questions, Total: total}, err
+	})
+	return p.Items, p.Total, err
+}
+
+func (s *ExamQuestionStore) GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error) {
+	return cache.Fetch(ctx, s.cache, examQuestionsNamespace, []any{"course", courseID}, func() ([]models.ExamQuestion, error) {
+		return s.ExamQuestionStore.GetByCourseID(ctx, courseID)
+	})
+}
+
+func (s *ExamQuestionStore) GetByID(ctx context.Context, id int) (*models.ExamQuestion, error) {
+	return cache.Fetch(ctx, s.cache, examQuestionsNamespace, id, func() (*models.ExamQuestion, error) {
+		return s.ExamQuestionStore.GetByID(ctx, id)
+	})
+}
+
+func (s *ExamQuestionStore) Create(ctx context.Context, courseID, number int, section, question string) (*models.ExamQuestion, error) {
+	defer s.cache.Invalidate(ctx, examQuestionsNamespace)
+	return s.ExamQuestionStore.Create(ctx, courseID, number, section, question)
+}
+
+func (s *ExamQuestionStore) Update(ctx context.Context, id, courseID, number int, section, question string) error {
+	defer s.cache.Invalidate(ctx, examQuestionsNamespace)
+	return s.ExamQuestionStore.Update(ctx, id, courseID, number, section, question)
+}
+
+func (s *ExamQuestionStore) Delete(ctx context.Context, id int) error {
+	defer s.cache.Invalidate(ctx, examQuestionsNamespace)
+	return s.ExamQuestionStore.Delete(ctx, id)
+}
+
+func (s *ExamQuestionStore) BulkCreate(ctx context.Context, questions []models.ExamQuestion) error {
+	defer s.cache.Invalidate(ctx, examQuestionsNamespace)
+	return s.ExamQuestionStore.BulkCreate(ctx, questions)
+}
+
+func (s *ExamQuestionStore) DeleteByCourseID(ctx context.Context, courseID int) error {
+	defer s.cache.Invalidate(ctx, examQuestionsNamespace)
+	return s.ExamQuestionStore.DeleteByCourseID(ctx, courseID)
+}
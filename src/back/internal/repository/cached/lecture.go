@@ -0,0 +1,64 @@
+package cached
+
+import (
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type LectureStore struct {
+	repository.LectureStore
+	cache *cache.Cache
+}
+
+func NewLectureStore(inner repository.LectureStore, c *cache.Cache) *LectureStore {
+	return &LectureStore{LectureStore: inner, cache: c}
+}
+
+type lectureListKey struct {
+	Filter repository.LectureFilter
+	Opts   repository.ListOptions
+}
+
+func (s *LectureStore) GetAll(ctx context.Context, filter repository.LectureFilter, opts repository.ListOptions) ([]models.Lecture, int, error) {
+	p, err := cache.Fetch(ctx, s.cache, lecturesNamespace, lectureListKey{Filter: filter, Opts: opts}, func() (page[models.Lecture], error) {
+		lectures, total, err := s.LectureStore.GetAll(ctx, filter, opts)
+		return page[models.Lecture]{Items: lectures, Total: total}, err
+	})
+	return p.Items, p.Total, err
+}
+
+func (s *LectureStore) GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error) {
+	return cache.Fetch(ctx, s.cache, lecturesNamespace, []any{"course", courseID}, func() ([]models.Lecture, error) {
+		return s.LectureStore.GetByCourseID(ctx, courseID)
+	})
+}
+
+func (s *LectureStore) GetByID(ctx context.Context, id int) (*models.Lecture, error) {
+	return cache.Fetch(ctx, s.cache, lecturesNamespace, id, func() (*models.Lecture, error) {
+		return s.LectureStore.GetByID(ctx, id)
+	})
+}
+
+func (s *LectureStore) Create(ctx context.Context, courseID, week int, title, content, githubURL, status string, publishAt *time.Time) (*models.Lecture, error) {
+	defer s.cache.Invalidate(ctx, lecturesNamespace)
+	return s.LectureStore.Create(ctx, courseID, week, title, content, githubURL, status, publishAt)
+}
+
+func (s *LectureStore) Update(ctx context.Context, id, courseID, week int, title, content, githubURL string) error {
+	defer s.cache.Invalidate(ctx, lecturesNamespace)
+	return s.LectureStore.Update(ctx, id, courseID, week, title, content, githubURL)
+}
+
+func (s *LectureStore) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	defer s.cache.Invalidate(ctx, lecturesNamespace)
+	return s.LectureStore.SetPublication(ctx, id, status, publishAt)
+}
+
+func (s *LectureStore) Delete(ctx context.Context, id int) error {
+	defer s.cache.Invalidate(ctx, lecturesNamespace)
+	return s.LectureStore.Delete(ctx, id)
+}
@@ -0,0 +1,28 @@
+package cached
+
+import (
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// PublicationStore сбрасывает кэш лекций, когда планировщик публикует черновики
+type PublicationStore struct {
+	repository.PublicationStore
+	cache *cache.Cache
+}
+
+func NewPublicationStore(inner repository.PublicationStore, c *cache.Cache) *PublicationStore {
+	return &PublicationStore{PublicationStore: inner, cache: c}
+}
+
+func (s *PublicationStore) PublishDue(ctx context.Context, now time.Time) ([]models.PublishedItem, error) {
+	published, err := s.PublicationStore.PublishDue(ctx, now)
+	if len(published) > 0 {
+		s.cache.Invalidate(ctx, lecturesNamespace)
+	}
+	return published, err
+}
@@ -0,0 +1,24 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/CreateLab/laritmo/internal/cache"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// TrashStore не кэширует корзину, но восстановление возвращает записи в публичные списки
+type TrashStore struct {
+	repository.TrashStore
+	cache *cache.Cache
+}
+
+func NewTrashStore(inner repository.TrashStore, c *cache.Cache) *TrashStore {
+	return &TrashStore{TrashStore: inner, cache: c}
+}
+
+func (s *TrashStore) Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error) {
+	defer s.cache.Invalidate(ctx, coursesNamespace, lecturesNamespace, examQuestionsNamespace)
+	return s.TrashStore.Restore(ctx, itemType, id)
+}
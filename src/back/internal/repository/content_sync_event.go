@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+var contentSyncEventColumns = []string{
+	"id", "course_id", "provider", "delivery_id", "repository", "ref", "commit_sha",
+	"changed_paths", "status", "report", "error", "job_id", "created_at", "updated_at",
+}
+
+var contentSyncEventSortable = map[string]string{
+	"id":         "id",
+	"status":     "status",
+	"created_at": "created_at",
+}
+
+type ContentSyncEventRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewContentSyncEventRepository(db *database.DB) *ContentSyncEventRepository {
+	return &ContentSyncEventRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+func scanContentSyncEvent(row sq.RowScanner) (*models.ContentSyncEvent, error) {
+	var e models.ContentSyncEvent
+	var paths []byte
+	var report []byte
+	err := row.Scan(&e.ID, &e.CourseID, &e.Provider, &e.DeliveryID, &e.Repository, &e.Ref, &e.CommitSHA,
+		&paths, &e.Status, &report, &e.Error, &e.JobID, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(paths, &e.ChangedPaths); err != nil {
+		return nil, fmt.Errorf("invalid changed_paths: %w", err)
+	}
+	if report != nil {
+		e.Report = &models.ContentSyncReport{}
+		if err := json.Unmarshal(report, e.Report); err != nil {
+			return nil, fmt.Errorf("invalid report: %w", err)
+		}
+	}
+	return &e, nil
+}
+
+// Create сохраняет событие webhook; ChangedPaths хранятся как JSON-массив
+func (r *ContentSyncEventRepository) Create(ctx context.Context, event *models.ContentSyncEvent) (*models.ContentSyncEvent, error) {
+	paths := event.ChangedPaths
+	if paths == nil {
+		paths = []string{}
+	}
+	pathsJSON, err := json.Marshal(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal changed paths: %w", err)
+	}
+
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("content_sync_events").
+		Columns("course_id", "provider", "delivery_id", "repository", "ref", "commit_sha", "changed_paths", "status").
+		Values(event.CourseID, event.Provider, event.DeliveryID, event.Repository, event.Ref, event.CommitSHA, string(pathsJSON), event.Status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync event: %w", err)
+	}
+
+	created, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created sync event: %w", err)
+	}
+	if created == nil {
+		return nil, fmt.Errorf("created sync event not found")
+	}
+
+	return created, nil
+}
+
+func (r *ContentSyncEventRepository) GetByID(ctx context.Context, id int) (*models.ContentSyncEvent, error) {
+	query, args, err := r.sb.Select(contentSyncEventColumns...).
+		From("content_sync_events").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	event, err := scanContentSyncEvent(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync event: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetByCourseID возвращает страницу событий курса, по умолчанию новые первыми
+func (r *ContentSyncEventRepository) GetByCourseID(ctx context.Context, courseID int, opts ListOptions) ([]models.ContentSyncEvent, int, error) {
+	builder := r.sb.Select(contentSyncEventColumns...).
+		From("content_sync_events").
+		Where(sq.Eq{"course_id": courseID})
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, contentSyncEventSortable, "created_at DESC")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get sync events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.ContentSyncEvent
+	for rows.Next() {
+		e, err := scanContentSyncEvent(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan error sync event: %w", err)
+		}
+		events = append(events, *e)
+	}
+
+	return events, total, nil
+}
+
+// SetJob связывает событие с фоновой задачей синхронизации
+func (r *ContentSyncEventRepository) SetJob(ctx context.Context, id, jobID int) error {
+	query, args, err := r.sb.Update("content_sync_events").
+		Set("job_id", jobID).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to set sync event job: %w", err)
+	}
+
+	return nil
+}
+
+// Finish сохраняет итог синхронизации: отчёт при успехе или текст ошибки
+func (r *ContentSyncEventRepository) Finish(ctx context.Context, id int, status string, report *models.ContentSyncReport, errMsg string) error {
+	builder := r.sb.Update("content_sync_events").
+		Set("status", status).
+		Where(sq.Eq{"id": id})
+
+	if report != nil {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sync report: %w", err)
+		}
+		builder = builder.Set("report", string(data))
+	}
+	if errMsg != "" {
+		builder = builder.Set("error", errMsg)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to finish sync event: %w", err)
+	}
+
+	return nil
+}
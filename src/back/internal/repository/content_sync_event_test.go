@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentSyncEventRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewContentSyncEventRepository(db)
+	courseID := createTestCourse(t, db, "Веб", "2026-fall")
+
+	event, err := repo.Create(ctx, &models.ContentSyncEvent{
+		CourseID:     courseID,
+		Provider:     "github",
+		Repository:   "CreateLab/AspITMO",
+		Ref:          "refs/heads/main",
+		ChangedPaths: []string{"lectures/01.md"},
+		Status:       models.ContentSyncEventQueued,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lectures/01.md"}, event.ChangedPaths)
+	assert.Nil(t, event.Report)
+
+	job, err := NewJobRepository(db).Enqueue(ctx, "content.sync", map[string]int{"event_id": event.ID}, 3)
+	require.NoError(t, err)
+	require.NoError(t, repo.SetJob(ctx, event.ID, job.ID))
+
+	report := &models.ContentSyncReport{CourseID: courseID, Lectures: models.ContentSyncDiff{Created: []int{1}}}
+	require.NoError(t, repo.Finish(ctx, event.ID, models.ContentSyncEventSucceeded, report, ""))
+
+	events, total, err := repo.GetByCourseID(ctx, courseID, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.ContentSyncEventSucceeded, events[0].Status)
+	require.NotNil(t, events[0].JobID)
+	assert.Equal(t, job.ID, *events[0].JobID)
+	require.NotNil(t, events[0].Report)
+	assert.Equal(t, []int{1}, events[0].Report.Lectures.Created)
+}
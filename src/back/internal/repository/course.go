@@ -1,62 +1,111 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 
+	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/models"
 	sq "github.com/Masterminds/squirrel"
 )
 
 type CourseRepository struct {
-	db *sql.DB
+	db *database.DB
+	sb sq.StatementBuilderType
 }
 
-func NewCourseRepository(db *sql.DB) *CourseRepository {
-	return &CourseRepository{db: db}
+func NewCourseRepository(db *database.DB) *CourseRepository {
+	return &CourseRepository{db: db, sb: db.Dialect.Builder()}
 }
 
-func (r *CourseRepository) GetAll() ([]models.Course, error) {
-	query, args, err := sq.Select("id", "name", "semester", "description", "created_at", "updated_at").
+var courseColumns = []string{"id", "name", "semester", "description", "archived_at", "created_at", "updated_at"}
+
+// CourseFilter - фильтры списка курсов
+type CourseFilter struct {
+	Semester string
+	// Archived - nil возвращает все курсы, true/false - только архивные или только активные
+	Archived *bool
+}
+
+func scanCourse(row sq.RowScanner) (*models.Course, error) {
+	var c models.Course
+	if err := row.Scan(&c.ID, &c.Name, &c.Semester, &c.Description, &c.ArchivedAt, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+var courseSortable = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"semester":   "semester",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// GetAll возвращает страницу курсов и общее число курсов, подходящих под фильтр
+func (r *CourseRepository) GetAll(ctx context.Context, filter CourseFilter, opts ListOptions) ([]models.Course, int, error) {
+	builder := r.sb.Select(courseColumns...).
 		From("courses").
-		OrderBy("semester", "name").
-		ToSql()
+		Where(sq.Eq{"deleted_at": nil})
+
+	if filter.Semester != "" {
+		builder = builder.Where(sq.Eq{"semester": filter.Semester})
+	}
+	if filter.Archived != nil {
+		if *filter.Archived {
+			builder = builder.Where(sq.NotEq{"archived_at": nil})
+		} else {
+			builder = builder.Where(sq.Eq{"archived_at": nil})
+		}
+	}
 
+	total, err := countRows(ctx, r.db, builder)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, courseSortable, "semester", "name")
+	if err != nil {
+		return nil, 0, err
 	}
 
-	rows, err := r.db.Query(query, args...)
+	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get courses: %w", err)
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get courses: %w", err)
 	}
 	defer rows.Close()
 
 	var courses []models.Course
 	for rows.Next() {
-		var c models.Course
-		if err := rows.Scan(&c.ID, &c.Name, &c.Semester, &c.Description, &c.CreatedAt, &c.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan error for course: %w", err)
+		c, err := scanCourse(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan error for course: %w", err)
 		}
-		courses = append(courses, c)
+		courses = append(courses, *c)
 	}
 
-	return courses, nil
+	return courses, total, nil
 }
 
-func (r *CourseRepository) GetByID(id int) (*models.Course, error) {
-	query, args, err := sq.Select("id", "name", "semester", "description", "created_at", "updated_at").
+func (r *CourseRepository) GetByID(ctx context.Context, id int) (*models.Course, error) {
+	query, args, err := r.sb.Select(courseColumns...).
 		From("courses").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	var c models.Course
-	err = r.db.QueryRow(query, args...).Scan(&c.ID, &c.Name, &c.Semester, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanCourse(r.db.QueryRowContext(ctx, query, args...))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -64,47 +113,117 @@ func (r *CourseRepository) GetByID(id int) (*models.Course, error) {
 		return nil, fmt.Errorf("failed to get course: %w", err)
 	}
 
-	return &c, nil
+	return c, nil
 }
 
-func (r *CourseRepository) Create(name, semester, description string) (*models.Course, error) {
-	query, args, _ := sq.Insert("courses").
-		Columns("name", "semester", "description").
-		Values(name, semester, description).
+// FindByNameAndSemester возвращает первый курс с таким названием и семестром или nil
+func (r *CourseRepository) FindByNameAndSemester(ctx context.Context, name, semester string) (*models.Course, error) {
+	query, args, err := r.sb.Select(courseColumns...).
+		From("courses").
+		Where(sq.Eq{"name": name, "semester": semester, "deleted_at": nil}).
+		OrderBy("id").
+		Limit(1).
 		ToSql()
 
-	result, err := r.db.Exec(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	c, err := scanCourse(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course: %w", err)
 	}
 
-	id, _ := result.LastInsertId()
+	return c, nil
+}
+
+func (r *CourseRepository) Create(ctx context.Context, name, semester, description string) (*models.Course, error) {
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("courses").
+		Columns("name", "semester", "description").
+		Values(name, semester, description))
+	if err != nil {
+		return nil, err
+	}
 
 	return &models.Course{
-		ID:          int(id),
+		ID:          id,
 		Name:        name,
 		Semester:    semester,
 		Description: description,
 	}, nil
 }
 
-func (r *CourseRepository) Update(id int, name, semester, description string) error {
-	query, args, _ := sq.Update("courses").
+func (r *CourseRepository) Update(ctx context.Context, id int, name, semester, description string) error {
+	query, args, _ := r.sb.Update("courses").
 		Set("name", name).
 		Set("semester", semester).
 		Set("description", description).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	return execAffecting(ctx, r.db, ErrNotFound, query, args...)
 }
 
-func (r *CourseRepository) Delete(id int) error {
-	query, args, _ := sq.Delete("courses").
+// SetArchived переносит курс в архив или возвращает его в число активных
+func (r *CourseRepository) SetArchived(ctx context.Context, id int, archived bool) error {
+	var value any
+	if archived {
+		value = sq.Expr("CURRENT_TIMESTAMP")
+	}
+
+	query, args, err := r.sb.Update("courses").
+		Set("archived_at", value).
 		Where(sq.Eq{"id": id}).
 		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to archive course: %w", err)
+	}
+
+	return nil
+}
+
+// courseContentTables - таблицы материалов курса, которые удаляются и восстанавливаются вместе с ним
+var courseContentTables = []string{"lectures", "labs", "grade_sheets", "exam_questions"}
+
+// Delete переносит курс в корзину вместе со всеми его материалами в одной транзакции.
+// Материалы получают время удаления не раньше, чем у курса, - по нему Restore
+// отличает их от удалённых по отдельности до этого.
+func (r *CourseRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range append([]string{"courses"}, courseContentTables...) {
+		column := "course_id"
+		if table == "courses" {
+			column = "id"
+		}
+
+		query, args, err := r.sb.Update(table).
+			Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+			Where(sq.Eq{column: id, "deleted_at": nil}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to delete %s of course: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	return nil
 }
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ErrCourseNotFound - исходный курс для клонирования не найден
+var ErrCourseNotFound = errors.New("course not found")
+
+// CourseCloneOptions - параметры копии курса
+type CourseCloneOptions struct {
+	// Name - название копии; пустое значение сохраняет название исходного курса
+	Name        string
+	Semester    string
+	Description *string
+	// DeadlineShiftDays сдвигает дедлайны лабораторных на число календарных дней,
+	// например на год вперёд; время суток сохраняется и при переходе на летнее время
+	DeadlineShiftDays int
+	// WithGradeSheets копирует ссылки на ведомости; обычно у нового семестра они свои
+	WithGradeSheets bool
+}
+
+// Clone копирует курс вместе с лекциями, лабораторными и вопросами к экзамену в одной транзакции;
+// материалы из корзины не копируются
+func (r *CourseRepository) Clone(ctx context.Context, sourceID int, opts CourseCloneOptions) (*models.CourseCloneSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.sb.Select(courseColumns...).
+		From("courses").
+		Where(sq.Eq{"id": sourceID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	source, err := scanCourse(tx.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	course := models.Course{Name: source.Name, Semester: opts.Semester, Description: source.Description}
+	if opts.Name != "" {
+		course.Name = opts.Name
+	}
+	if opts.Description != nil {
+		course.Description = *opts.Description
+	}
+
+	course.ID, err = insertID(ctx, tx, r.db.Dialect, r.sb.Insert("courses").
+		Columns("name", "semester", "description").
+		Values(course.Name, course.Semester, course.Description))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create course: %w", err)
+	}
+
+	summary := &models.CourseCloneSummary{Course: &course, SourceID: sourceID}
+
+	// Черновики остаются черновиками, но время публикации относится к исходному семестру и не копируется
+	if summary.Lectures, err = copyCourseRows(ctx, tx, r.sb, "lectures", sourceID, course.ID,
+		"week", "title", "content", "github_url", "status"); err != nil {
+		return nil, err
+	}
+	if summary.ExamQuestions, err = copyCourseRows(ctx, tx, r.sb, "exam_questions", sourceID, course.ID,
+		"number", "section", "question"); err != nil {
+		return nil, err
+	}
+	if opts.WithGradeSheets {
+		if summary.GradeSheets, err = copyCourseRows(ctx, tx, r.sb, "grade_sheets", sourceID, course.ID,
+			"sheet_url", "description", "status"); err != nil {
+			return nil, err
+		}
+	}
+	if summary.Labs, err = copyLabs(ctx, tx, r.sb, sourceID, course.ID, opts.DeadlineShiftDays); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return summary, nil
+}
+
+// copyCourseRows копирует строки таблицы курса через INSERT ... SELECT
+func copyCourseRows(ctx context.Context, tx *sql.Tx, sb sq.StatementBuilderType, table string, sourceID, targetID int, columns ...string) (int, error) {
+	// id нового курса подставляется литералом: параметр в списке SELECT
+	// PostgreSQL не может типизировать, а значение - наш собственный int
+	query, args, err := sb.Insert(table).
+		Columns(append([]string{"course_id"}, columns...)...).
+		Select(sq.Select().
+			Column(strconv.Itoa(targetID)).
+			Columns(columns...).
+			From(table).
+			Where(sq.Eq{"course_id": sourceID, "deleted_at": nil}).
+			OrderBy("id")).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy %s: %w", table, err)
+	}
+
+	copied, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count copied %s: %w", table, err)
+	}
+
+	return int(copied), nil
+}
+
+// copyLabs копирует лабораторные по одной, чтобы сдвинуть дедлайны без диалектных функций дат
+func copyLabs(ctx context.Context, tx *sql.Tx, sb sq.StatementBuilderType, sourceID, targetID int, shiftDays int) (int, error) {
+	query, args, err := sb.Select("number", "title", "description", "deadline", "max_score", "github_url", "status").
+		From("labs").
+		Where(sq.Eq{"course_id": sourceID, "deleted_at": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get labs: %w", err)
+	}
+
+	var labs []models.Lab
+	for rows.Next() {
+		var l models.Lab
+		if err := rows.Scan(&l.Number, &l.Title, &l.Description, &l.Deadline, &l.MaxScore, &l.GithubURL, &l.Status); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan error for lab: %w", err)
+		}
+		labs = append(labs, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to get labs: %w", err)
+	}
+
+	for _, l := range labs {
+		if l.Deadline != nil {
+			shifted := l.Deadline.AddDate(0, 0, shiftDays)
+			l.Deadline = &shifted
+		}
+
+		query, args, err := sb.Insert("labs").
+			Columns("course_id", "number", "title", "description", "deadline", "max_score", "github_url", "status").
+			Values(targetID, l.Number, l.Title, l.Description, l.Deadline, l.MaxScore, l.GithubURL, l.Status).
+			ToSql()
+		if err != nil {
+			return 0, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, fmt.Errorf("failed to copy lab %d: %w", l.Number, err)
+		}
+	}
+
+	return len(labs), nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// Import создаёт курс вместе с материалами в одной транзакции; ID и course_id в content игнорируются
+func (r *CourseRepository) Import(ctx context.Context, content *models.CourseContent) (*models.CourseImportSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	course := models.Course{
+		Name:        content.Course.Name,
+		Semester:    content.Course.Semester,
+		Description: content.Course.Description,
+	}
+	course.ID, err = insertID(ctx, tx, r.db.Dialect, r.sb.Insert("courses").
+		Columns("name", "semester", "description").
+		Values(course.Name, course.Semester, course.Description))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create course: %w", err)
+	}
+
+	exec := func(what string, query string, args []any, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to import %s: %w", what, err)
+		}
+		return nil
+	}
+
+	for _, l := range content.Lectures {
+		query, args, err := r.sb.Insert("lectures").
+			Columns("course_id", "week", "title", "content", "github_url", "status", "publish_at").
+			Values(course.ID, l.Week, l.Title, l.Content, l.GithubURL, importStatus(l.Status), importPublishAt(l.PublishAt)).
+			ToSql()
+		if err := exec(fmt.Sprintf("lecture %q", l.Title), query, args, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range content.Labs {
+		query, args, err := r.sb.Insert("labs").
+			Columns("course_id", "number", "title", "description", "deadline", "max_score", "github_url", "status", "publish_at").
+			Values(course.ID, l.Number, l.Title, l.Description, l.Deadline, l.MaxScore, l.GithubURL, importStatus(l.Status), importPublishAt(l.PublishAt)).
+			ToSql()
+		if err := exec(fmt.Sprintf("lab %d", l.Number), query, args, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, q := range content.ExamQuestions {
+		query, args, err := r.sb.Insert("exam_questions").
+			Columns("course_id", "number", "section", "question").
+			Values(course.ID, q.Number, q.Section, q.Question).
+			ToSql()
+		if err := exec(fmt.Sprintf("exam question %d", q.Number), query, args, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, gs := range content.GradeSheets {
+		query, args, err := r.sb.Insert("grade_sheets").
+			Columns("course_id", "sheet_url", "description", "status", "publish_at").
+			Values(course.ID, gs.SheetURL, gs.Description, importStatus(gs.Status), importPublishAt(gs.PublishAt)).
+			ToSql()
+		if err := exec("grade sheet "+gs.SheetURL, query, args, err); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.CourseImportSummary{
+		Course:        &course,
+		Lectures:      len(content.Lectures),
+		Labs:          len(content.Labs),
+		ExamQuestions: len(content.ExamQuestions),
+		GradeSheets:   len(content.GradeSheets),
+	}, nil
+}
+
+// importStatus подставляет статус по умолчанию для материалов из архивов без статуса
+func importStatus(status string) string {
+	if status == "" {
+		return models.PublicationStatusPublished
+	}
+	return status
+}
+
+// importPublishAt приводит время публикации к UTC, как setPublication
+func importPublishAt(publishAt *time.Time) any {
+	if publishAt == nil {
+		return nil
+	}
+	return publishAt.UTC()
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCourseRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCourseRepository(newTestDB(t))
+
+	created, err := repo.Create(ctx, "Алгоритмы", "2026-fall", "Основы")
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+
+	course, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, course)
+	assert.Equal(t, "Алгоритмы", course.Name)
+	assert.Equal(t, "Основы", course.Description)
+	assert.Nil(t, course.ArchivedAt)
+
+	require.NoError(t, repo.Update(ctx, created.ID, "Алгоритмы и структуры данных", "2026-fall", ""))
+	course, err = repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Алгоритмы и структуры данных", course.Name)
+
+	found, err := repo.FindByNameAndSemester(ctx, "Алгоритмы и структуры данных", "2026-fall")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, created.ID, found.ID)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	course, err = repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Nil(t, course)
+}
+
+func TestCourseRepository_GetAll(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewCourseRepository(db)
+
+	createTestCourse(t, db, "C", "2026-spring")
+	archivedID := createTestCourse(t, db, "A", "2026-fall")
+	createTestCourse(t, db, "B", "2026-fall")
+	require.NoError(t, repo.SetArchived(ctx, archivedID, true))
+
+	courses, total, err := repo.GetAll(ctx, CourseFilter{Semester: "2026-fall"}, ListOptions{Sort: "-name"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, courses, 2)
+	assert.Equal(t, "B", courses[0].Name)
+	assert.Equal(t, "A", courses[1].Name)
+
+	active := false
+	courses, total, err = repo.GetAll(ctx, CourseFilter{Archived: &active}, ListOptions{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, courses, 1)
+
+	courses, total, err = repo.GetAll(ctx, CourseFilter{}, ListOptions{Offset: 1, Sort: "name"})
+	require.NoError(t, err, "offset without limit")
+	assert.Equal(t, 3, total)
+	require.Len(t, courses, 2)
+	assert.Equal(t, "B", courses[0].Name)
+
+	archived := true
+	courses, _, err = repo.GetAll(ctx, CourseFilter{Archived: &archived}, ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, courses, 1)
+	assert.NotNil(t, courses[0].ArchivedAt)
+
+	_, _, err = repo.GetAll(ctx, CourseFilter{}, ListOptions{Sort: "password"})
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestCourseRepository_Clone(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewCourseRepository(db)
+
+	sourceID := createTestCourse(t, db, "Веб-разработка", "2025-fall")
+	lecture, err := NewLectureRepository(db).Create(ctx, sourceID, 1, "HTTP", "Запросы и ответы", "", "", nil)
+	require.NoError(t, err)
+	publishAt := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, NewLectureRepository(db).SetPublication(ctx, lecture.ID, models.PublicationStatusDraft, &publishAt))
+	deadline := "2025-10-01 23:59:00"
+	_, err = NewLabRepository(db).Create(ctx, sourceID, 1, 10, "REST API", "Сервис", "", &deadline, "", nil)
+	require.NoError(t, err)
+	_, err = NewExamQuestionRepository(db).Create(ctx, sourceID, 1, "HTTP", "Что такое REST?")
+	require.NoError(t, err)
+	_, err = NewGradeSheetRepository(db).Create(ctx, sourceID, "https://example.com/sheet", "Ведомость", "", nil)
+	require.NoError(t, err)
+
+	summary, err := repo.Clone(ctx, sourceID, CourseCloneOptions{Semester: "2026-fall", DeadlineShiftDays: 365})
+	require.NoError(t, err)
+	assert.Equal(t, "Веб-разработка", summary.Course.Name)
+	assert.Equal(t, 1, summary.Lectures)
+	assert.Equal(t, 1, summary.Labs)
+	assert.Equal(t, 1, summary.ExamQuestions)
+	assert.Equal(t, 0, summary.GradeSheets)
+
+	labs, err := NewLabRepository(db).GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, labs, 1)
+	require.NotNil(t, labs[0].Deadline)
+	assert.Equal(t, 2026, labs[0].Deadline.Year())
+
+	lectures, err := NewLectureRepository(db).GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, lectures, 1)
+	assert.Equal(t, "HTTP", lectures[0].Title)
+	assert.Equal(t, models.PublicationStatusDraft, lectures[0].Status, "drafts stay drafts")
+	assert.Nil(t, lectures[0].PublishAt, "publish time belongs to the source semester")
+
+	_, err = repo.Clone(ctx, 999, CourseCloneOptions{Semester: "2026-fall"})
+	assert.ErrorIs(t, err, ErrCourseNotFound)
+}
+
+func TestCourseRepository_Import(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewCourseRepository(db)
+
+	deadline := time.Date(2025, 10, 1, 23, 59, 0, 0, time.UTC)
+	description := "Ведомость"
+	summary, err := repo.Import(ctx, &models.CourseContent{
+		Course:        models.Course{ID: 42, Name: "Веб-разработка", Semester: "2025-fall"},
+		Lectures:      []models.Lecture{{Week: 1, Title: "HTTP", Content: "# HTTP"}},
+		Labs:          []models.Lab{{Number: 1, Title: "REST API", MaxScore: 10, Deadline: &deadline}},
+		ExamQuestions: []models.ExamQuestion{{Number: 1, Section: "HTTP", Question: "Что такое REST?"}},
+		GradeSheets:   []models.GradeSheet{{SheetURL: "https://example.com/sheet", Description: &description}},
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, 42, summary.Course.ID, "IDs from the content are not reused")
+	assert.Equal(t, 1, summary.Lectures)
+	assert.Equal(t, 1, summary.GradeSheets)
+
+	labs, err := NewLabRepository(db).GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, labs, 1)
+	require.NotNil(t, labs[0].Deadline)
+	assert.Equal(t, deadline.Day(), labs[0].Deadline.Day())
+
+	questions, err := NewExamQuestionRepository(db).GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, questions, 1)
+	assert.Equal(t, "Что такое REST?", questions[0].Question)
+}
+
+func TestCourseRepository_DeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	courseID := createTestCourse(t, db, "ОС", "2026-fall")
+	lecture, err := NewLectureRepository(db).Create(ctx, courseID, 1, "Процессы", "Планировщик", "", "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, NewCourseRepository(db).Delete(ctx, courseID))
+
+	got, err := NewLectureRepository(db).GetByID(ctx, lecture.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
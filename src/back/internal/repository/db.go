@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ErrNotFound - изменяемой записи нет или она в корзине
+var ErrNotFound = errors.New("record not found")
+
+// execer - выполнение запроса без чтения строк
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// execAffecting выполняет запрос и возвращает errNone, если он не затронул ни одной строки
+func execAffecting(ctx context.Context, db execer, errNone error, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errNone
+	}
+
+	return nil
+}
+
+// querier - общее у *database.DB и *sql.Tx, чтобы помощники работали и внутри транзакции
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertID выполняет INSERT и возвращает id новой строки.
+// PostgreSQL не поддерживает LastInsertId, поэтому там id читается через RETURNING id.
+func insertID(ctx context.Context, db querier, dialect database.Dialect, builder sq.InsertBuilder) (int, error) {
+	if dialect == database.Postgres {
+		query, args, err := builder.Suffix("RETURNING id").ToSql()
+		if err != nil {
+			return 0, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		var id int
+		if err := db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get ID: %w", err)
+	}
+	return int(id), nil
+}
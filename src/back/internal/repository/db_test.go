@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/config"
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/migrate"
+	"github.com/CreateLab/laritmo/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDB открывает чистую базу SQLite в памяти со всеми миграциями.
+// Каждый тест получает свою базу, поэтому тесты не зависят друг от друга и от MySQL.
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	cfg := config.DatabaseConfig{Driver: "sqlite", Path: ":memory:"}
+	db, err := database.Connect(cfg.GetDriver(), cfg.DSN())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	fsys, err := migrations.For(db.Dialect)
+	require.NoError(t, err)
+	runner, err := migrate.NewRunner(db, fsys)
+	require.NoError(t, err)
+	_, err = runner.Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+func createTestCourse(t *testing.T, db *database.DB, name, semester string) int {
+	t.Helper()
+
+	course, err := NewCourseRepository(db).Create(context.Background(), name, semester, "")
+	require.NoError(t, err)
+	return course.ID
+}
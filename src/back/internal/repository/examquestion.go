@@ -1,34 +1,71 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/models"
 	sq "github.com/Masterminds/squirrel"
 )
 
 type ExamQuestionRepository struct {
-	db *sql.DB
+	db *database.DB
+	sb sq.StatementBuilderType
 }
 
-func NewExamQuestionRepository(db *sql.DB) *ExamQuestionRepository {
-	return &ExamQuestionRepository{db: db}
+func NewExamQuestionRepository(db *database.DB) *ExamQuestionRepository {
+	return &ExamQuestionRepository{db: db, sb: db.Dialect.Builder()}
 }
 
 
-func (r *ExamQuestionRepository) GetAll() ([]models.ExamQuestion, error) {
-	query, args, err := sq.Select("id", "course_id", "number", "section", "question", "created_at", "updated_at").
+// ExamQuestionFilter - фильтры списка экзаменационных вопросов
+type ExamQuestionFilter struct {
+	CourseID *int
+	Section  string
+}
+
+var examQuestionSortable = map[string]string{
+	"id":         "id",
+	"course_id":  "course_id",
+	"section":    "section",
+	"number":     "number",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// GetAll возвращает страницу вопросов и общее число вопросов, подходящих под фильтр
+func (r *ExamQuestionRepository) GetAll(ctx context.Context, filter ExamQuestionFilter, opts ListOptions) ([]models.ExamQuestion, int, error) {
+	builder := r.sb.Select("id", "course_id", "number", "section", "question", "created_at", "updated_at").
 		From("exam_questions").
-		OrderBy("course_id", "section", "number").
-		ToSql()
+		Where(sq.Eq{"deleted_at": nil})
+
+	if filter.CourseID != nil {
+		builder = builder.Where(sq.Eq{"course_id": *filter.CourseID})
+	}
+	if filter.Section != "" {
+		builder = builder.Where(sq.Eq{"section": filter.Section})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, 0, err
 	}
 
-	rows, err := r.db.Query(query, args...)
+	builder, err = applyListOptions(builder, opts, examQuestionSortable, "course_id", "section", "number")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get exam questions: %w", err)
+		return nil, 0, err
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get exam questions: %w", err)
 	}
 	defer rows.Close()
 
@@ -36,7 +73,7 @@ func (r *ExamQuestionRepository) GetAll() ([]models.ExamQuestion, error) {
 	for rows.Next() {
 		var q models.ExamQuestion
 		if err := rows.Scan(&q.ID, &q.CourseID, &q.Number, &q.Section, &q.Question, &q.CreatedAt, &q.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan error exam question: %w", err)
+			return nil, 0, fmt.Errorf("scan error exam question: %w", err)
 		}
 		questions = append(questions, q)
 	}
@@ -45,21 +82,21 @@ func (r *ExamQuestionRepository) GetAll() ([]models.ExamQuestion, error) {
 		questions = []models.ExamQuestion{}
 	}
 
-	return questions, nil
+	return questions, total, nil
 }
 
 
-func (r *ExamQuestionRepository) GetByCourseID(courseID int) ([]models.ExamQuestion, error) {
-	query, args, err := sq.Select("id", "course_id", "number", "section", "question", "created_at", "updated_at").
+func (r *ExamQuestionRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error) {
+	query, args, err := r.sb.Select("id", "course_id", "number", "section", "question", "created_at", "updated_at").
 		From("exam_questions").
-		Where(sq.Eq{"course_id": courseID}).
+		Where(sq.Eq{"course_id": courseID, "deleted_at": nil}).
 		OrderBy("section ASC", "number ASC").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exam questions: %w", err)
 	}
@@ -82,17 +119,17 @@ func (r *ExamQuestionRepository) GetByCourseID(courseID int) ([]models.ExamQuest
 }
 
 
-func (r *ExamQuestionRepository) GetByID(id int) (*models.ExamQuestion, error) {
-	query, args, err := sq.Select("id", "course_id", "number", "section", "question", "created_at", "updated_at").
+func (r *ExamQuestionRepository) GetByID(ctx context.Context, id int) (*models.ExamQuestion, error) {
+	query, args, err := r.sb.Select("id", "course_id", "number", "section", "question", "created_at", "updated_at").
 		From("exam_questions").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
 	var q models.ExamQuestion
-	err = r.db.QueryRow(query, args...).Scan(&q.ID, &q.CourseID, &q.Number, &q.Section, &q.Question, &q.CreatedAt, &q.UpdatedAt)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&q.ID, &q.CourseID, &q.Number, &q.Section, &q.Question, &q.CreatedAt, &q.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -104,27 +141,16 @@ func (r *ExamQuestionRepository) GetByID(id int) (*models.ExamQuestion, error) {
 }
 
 
-func (r *ExamQuestionRepository) Create(courseID, number int, section, question string) (*models.ExamQuestion, error) {
-	query, args, err := sq.Insert("exam_questions").
+func (r *ExamQuestionRepository) Create(ctx context.Context, courseID, number int, section, question string) (*models.ExamQuestion, error) {
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("exam_questions").
 		Columns("course_id", "number", "section", "question").
-		Values(courseID, number, section, question).
-		ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
-	}
-
-	result, err := r.db.Exec(query, args...)
+		Values(courseID, number, section, question))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create exam question: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get ID: %w", err)
-	}
-
 	
-	examQuestion, err := r.GetByID(int(id))
+	examQuestion, err := r.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get created exam question: %w", err)
 	}
@@ -136,36 +162,36 @@ func (r *ExamQuestionRepository) Create(courseID, number int, section, question
 }
 
 
-func (r *ExamQuestionRepository) Update(id, courseID, number int, section, question string) error {
-	query, args, err := sq.Update("exam_questions").
+func (r *ExamQuestionRepository) Update(ctx context.Context, id, courseID, number int, section, question string) error {
+	query, args, err := r.sb.Update("exam_questions").
 		Set("course_id", courseID).
 		Set("number", number).
 		Set("section", section).
 		Set("question", question).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.Exec(query, args...)
-	if err != nil {
+	if err := execAffecting(ctx, r.db, ErrNotFound, query, args...); err != nil {
 		return fmt.Errorf("failed to update exam question: %w", err)
 	}
 
 	return nil
 }
 
-
-func (r *ExamQuestionRepository) Delete(id int) error {
-	query, args, err := sq.Delete("exam_questions").
-		Where(sq.Eq{"id": id}).
+// Delete переносит вопрос в корзину
+func (r *ExamQuestionRepository) Delete(ctx context.Context, id int) error {
+	query, args, err := r.sb.Update("exam_questions").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.Exec(query, args...)
+	_, err = r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete exam question: %w", err)
 	}
@@ -174,12 +200,12 @@ func (r *ExamQuestionRepository) Delete(id int) error {
 }
 
 
-func (r *ExamQuestionRepository) BulkCreate(questions []models.ExamQuestion) error {
+func (r *ExamQuestionRepository) BulkCreate(ctx context.Context, questions []models.ExamQuestion) error {
 	if len(questions) == 0 {
 		return nil
 	}
 
-	builder := sq.Insert("exam_questions").
+	builder := r.sb.Insert("exam_questions").
 		Columns("course_id", "number", "section", "question")
 
 	for _, q := range questions {
@@ -191,7 +217,7 @@ func (r *ExamQuestionRepository) BulkCreate(questions []models.ExamQuestion) err
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.Exec(query, args...)
+	_, err = r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to bulk create exam questions: %w", err)
 	}
@@ -199,16 +225,17 @@ func (r *ExamQuestionRepository) BulkCreate(questions []models.ExamQuestion) err
 	return nil
 }
 
-
-func (r *ExamQuestionRepository) DeleteByCourseID(courseID int) error {
-	query, args, err := sq.Delete("exam_questions").
-		Where(sq.Eq{"course_id": courseID}).
+// DeleteByCourseID переносит в корзину все вопросы курса
+func (r *ExamQuestionRepository) DeleteByCourseID(ctx context.Context, courseID int) error {
+	query, args, err := r.sb.Update("exam_questions").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"course_id": courseID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.Exec(query, args...)
+	_, err = r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete exam questions by course_id: %w", err)
 	}
@@ -1,59 +1,97 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/models"
 	sq "github.com/Masterminds/squirrel"
 )
 
 type GradeSheetRepository struct {
-	db *sql.DB
+	db *database.DB
+	sb sq.StatementBuilderType
 }
 
-func NewGradeSheetRepository(db *sql.DB) *GradeSheetRepository {
-	return &GradeSheetRepository{db: db}
+func NewGradeSheetRepository(db *database.DB) *GradeSheetRepository {
+	return &GradeSheetRepository{db: db, sb: db.Dialect.Builder()}
 }
 
 
-func (r *GradeSheetRepository) GetAll(courseID *int) ([]models.GradeSheet, error) {
-	builder := sq.Select("id", "course_id", "sheet_url", "description", "created_at", "updated_at").
+// GradeSheetFilter - фильтры списка ведомостей
+type GradeSheetFilter struct {
+	CourseID *int
+	// Status - draft или published; пустой статус возвращает все ведомости
+	Status string
+}
+
+var gradeSheetSortable = map[string]string{
+	"id":         "id",
+	"course_id":  "course_id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// GetAll возвращает страницу ведомостей и общее число ведомостей, подходящих под фильтр
+func (r *GradeSheetRepository) GetAll(ctx context.Context, filter GradeSheetFilter, opts ListOptions) ([]models.GradeSheet, int, error) {
+	builder := r.sb.Select("id", "course_id", "sheet_url", "description", "status", "publish_at", "created_at", "updated_at").
 		From("grade_sheets").
-		OrderBy("course_id")
+		Where(sq.Eq{"deleted_at": nil})
 
-	if courseID != nil {
-		builder = builder.Where(sq.Eq{"course_id": *courseID})
+	if filter.CourseID != nil {
+		builder = builder.Where(sq.Eq{"course_id": *filter.CourseID})
+	}
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, gradeSheetSortable, "course_id")
+	if err != nil {
+		return nil, 0, err
 	}
 
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get grade sheets: %w", err)
+		return nil, 0, fmt.Errorf("failed to get grade sheets: %w", err)
 	}
 	defer rows.Close()
 
 	var sheets []models.GradeSheet
 	for rows.Next() {
 		var s models.GradeSheet
-		if err := rows.Scan(&s.ID, &s.CourseID, &s.SheetURL, &s.Description, &s.CreatedAt, &s.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan error grade sheet: %w", err)
+		if err := rows.Scan(&s.ID, &s.CourseID, &s.SheetURL, &s.Description, &s.Status, &s.PublishAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan error grade sheet: %w", err)
 		}
 		sheets = append(sheets, s)
 	}
 
-	return sheets, nil
+	return sheets, total, nil
 }
 
 
-func (r *GradeSheetRepository) GetByID(id int) (*models.GradeSheet, error) {
-	query, args, err := sq.Select("id", "course_id", "sheet_url", "description", "created_at", "updated_at").
+// GetByCourseID возвращает все ведомости курса
+func (r *GradeSheetRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.GradeSheet, error) {
+	sheets, _, err := r.GetAll(ctx, GradeSheetFilter{CourseID: &courseID}, ListOptions{})
+	return sheets, err
+}
+
+func (r *GradeSheetRepository) GetByID(ctx context.Context, id int) (*models.GradeSheet, error) {
+	query, args, err := r.sb.Select("id", "course_id", "sheet_url", "description", "status", "publish_at", "created_at", "updated_at").
 		From("grade_sheets").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
 	if err != nil {
@@ -61,7 +99,7 @@ func (r *GradeSheetRepository) GetByID(id int) (*models.GradeSheet, error) {
 	}
 
 	var s models.GradeSheet
-	err = r.db.QueryRow(query, args...).Scan(&s.ID, &s.CourseID, &s.SheetURL, &s.Description, &s.CreatedAt, &s.UpdatedAt)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&s.ID, &s.CourseID, &s.SheetURL, &s.Description, &s.Status, &s.PublishAt, &s.CreatedAt, &s.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -73,27 +111,17 @@ func (r *GradeSheetRepository) GetByID(id int) (*models.GradeSheet, error) {
 }
 
 
-func (r *GradeSheetRepository) Create(courseID int, sheetURL, description string) (*models.GradeSheet, error) {
-	query, args, err := sq.Insert("grade_sheets").
-		Columns("course_id", "sheet_url", "description").
-		Values(courseID, sheetURL, description).
-		ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
-	}
-
-	result, err := r.db.Exec(query, args...)
+func (r *GradeSheetRepository) Create(ctx context.Context, courseID int, sheetURL, description, status string, publishAt *time.Time) (*models.GradeSheet, error) {
+	status, at := publicationValues(status, publishAt)
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("grade_sheets").
+		Columns("course_id", "sheet_url", "description", "status", "publish_at").
+		Values(courseID, sheetURL, description, status, at))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create grade sheet: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get ID: %w", err)
-	}
-
 	
-	gradeSheet, err := r.GetByID(int(id))
+	gradeSheet, err := r.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get created grade sheet: %w", err)
 	}
@@ -105,18 +133,17 @@ func (r *GradeSheetRepository) Create(courseID int, sheetURL, description string
 }
 
 
-func (r *GradeSheetRepository) Update(id int, sheetURL, description string) error {
-	query, args, err := sq.Update("grade_sheets").
+func (r *GradeSheetRepository) Update(ctx context.Context, id int, sheetURL, description string) error {
+	query, args, err := r.sb.Update("grade_sheets").
 		Set("sheet_url", sheetURL).
 		Set("description", description).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.Exec(query, args...)
-	if err != nil {
+	if err := execAffecting(ctx, r.db, ErrNotFound, query, args...); err != nil {
 		return fmt.Errorf("failed to update grade sheet: %w", err)
 	}
 
@@ -124,15 +151,22 @@ func (r *GradeSheetRepository) Update(id int, sheetURL, description string) erro
 }
 
 
-func (r *GradeSheetRepository) Delete(id int) error {
-	query, args, err := sq.Delete("grade_sheets").
-		Where(sq.Eq{"id": id}).
+// SetPublication меняет статус публикации ведомости и время отложенной публикации
+func (r *GradeSheetRepository) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	return setPublication(ctx, r.db, r.sb, "grade_sheets", id, status, publishAt)
+}
+
+// Delete переносит ведомость в корзину
+func (r *GradeSheetRepository) Delete(ctx context.Context, id int) error {
+	query, args, err := r.sb.Update("grade_sheets").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.Exec(query, args...)
+	_, err = r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete grade sheet: %w", err)
 	}
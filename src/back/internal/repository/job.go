@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ErrJobLeaseLost - аренда задачи истекла и её захватил другой воркер или задача уже завершена
+var ErrJobLeaseLost = errors.New("job lease lost")
+
+var jobColumns = []string{
+	"id", "type", "payload", "status", "progress", "attempts", "max_attempts", "last_error",
+	"run_at", "locked_by", "locked_until", "finished_at", "created_at", "updated_at",
+}
+
+type JobRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewJobRepository(db *database.DB) *JobRepository {
+	return &JobRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+func scanJob(row sq.RowScanner) (*models.Job, error) {
+	var j models.Job
+	var payload []byte
+	err := row.Scan(&j.ID, &j.Type, &payload, &j.Status, &j.Progress, &j.Attempts, &j.MaxAttempts, &j.LastError,
+		&j.RunAt, &j.LockedBy, &j.LockedUntil, &j.FinishedAt, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	j.Payload = payload
+	return &j, nil
+}
+
+func (r *JobRepository) Enqueue(ctx context.Context, jobType string, payload any, maxAttempts int) (*models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("jobs").
+		Columns("type", "payload", "status", "max_attempts", "run_at").
+		Values(jobType, string(data), models.JobStatusPending, maxAttempts, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	job, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enqueued job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("enqueued job not found")
+	}
+
+	return job, nil
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, id int) (*models.Job, error) {
+	query, args, err := r.sb.Select(jobColumns...).
+		From("jobs").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Lease захватывает следующую готовую к выполнению задачу: ожидающую или ту,
+// у которой истекла аренда предыдущего воркера. Задача становится невидимой
+// для других воркеров на время visibility. Возвращает nil, если задач нет.
+func (r *JobRepository) Lease(ctx context.Context, workerID string, visibility time.Duration) (*models.Job, error) {
+	for {
+		job, retry, err := r.leaseOnce(ctx, workerID, visibility)
+		if err != nil || !retry {
+			return job, err
+		}
+	}
+}
+
+func (r *JobRepository) leaseOnce(ctx context.Context, workerID string, visibility time.Duration) (*models.Job, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	builder := r.sb.Select(jobColumns...).
+		From("jobs").
+		Where(sq.Or{
+			sq.And{sq.Eq{"status": models.JobStatusPending}, sq.LtOrEq{"run_at": now}},
+			sq.And{sq.Eq{"status": models.JobStatusRunning}, sq.LtOrEq{"locked_until": now}},
+		}).
+		OrderBy("run_at", "id").
+		Limit(1)
+
+	// SQLite не знает FOR UPDATE: транзакция и так берёт блокировку записи на всю базу
+	if r.db.Dialect != database.SQLite {
+		builder = builder.Suffix("FOR UPDATE SKIP LOCKED")
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	job, err := scanJob(tx.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to select job: %w", err)
+	}
+
+	// Воркер упал на последней попытке — аренда истекла, повторять больше нельзя
+	if job.Attempts >= job.MaxAttempts {
+		if err := r.finish(ctx, tx, sq.Eq{"id": job.ID}, models.JobStatusDead, "lease expired after last attempt"); err != nil {
+			return nil, false, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil, true, nil
+	}
+
+	lockedUntil := now.Add(visibility)
+	query, args, err = r.sb.Update("jobs").
+		Set("status", models.JobStatusRunning).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("locked_by", workerID).
+		Set("locked_until", lockedUntil).
+		Where(sq.Eq{"id": job.ID}).
+		ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, false, fmt.Errorf("failed to lease job: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	job.Status = models.JobStatusRunning
+	job.Attempts++
+	job.LockedBy = &workerID
+	job.LockedUntil = &lockedUntil
+
+	return job, false, nil
+}
+
+// leased - условие на задачу, которую всё ещё держит воркер workerID
+func leased(id int, workerID string) sq.Eq {
+	return sq.Eq{"id": id, "locked_by": workerID, "status": models.JobStatusRunning}
+}
+
+// Extend продлевает аренду задачи, пока воркер продолжает над ней работать
+func (r *JobRepository) Extend(ctx context.Context, id int, workerID string, visibility time.Duration) error {
+	query, args, err := r.sb.Update("jobs").
+		Set("locked_until", time.Now().Add(visibility)).
+		Where(leased(id, workerID)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if err := execAffecting(ctx, r.db, ErrJobLeaseLost, query, args...); err != nil {
+		return fmt.Errorf("failed to extend job lease: %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) Complete(ctx context.Context, id int, workerID string) error {
+	return r.finish(ctx, r.db, leased(id, workerID), models.JobStatusSucceeded, "")
+}
+
+// SetProgress сохраняет процент выполнения задачи (0-100)
+func (r *JobRepository) SetProgress(ctx context.Context, id int, workerID string, progress int) error {
+	query, args, err := r.sb.Update("jobs").
+		Set("progress", progress).
+		Where(leased(id, workerID)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if err := execAffecting(ctx, r.db, ErrJobLeaseLost, query, args...); err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+
+	return nil
+}
+
+// SaveResult сохраняет файл-результат задачи, заменяя результат предыдущей попытки
+func (r *JobRepository) SaveResult(ctx context.Context, jobID int, filename, contentType string, content []byte) error {
+	upsert := "ON DUPLICATE KEY UPDATE filename = VALUES(filename), content_type = VALUES(content_type), content = VALUES(content)"
+	if r.db.Dialect != database.MySQL {
+		upsert = "ON CONFLICT (job_id) DO UPDATE SET filename = EXCLUDED.filename, content_type = EXCLUDED.content_type, content = EXCLUDED.content"
+	}
+
+	query, args, err := r.sb.Insert("job_results").
+		Columns("job_id", "filename", "content_type", "content").
+		Values(jobID, filename, contentType, content).
+		Suffix(upsert).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to save job result: %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) GetResult(ctx context.Context, jobID int) (*models.JobResult, error) {
+	query, args, err := r.sb.Select("job_id", "filename", "content_type", "content", "created_at").
+		From("job_results").
+		Where(sq.Eq{"job_id": jobID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var res models.JobResult
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&res.JobID, &res.Filename, &res.ContentType, &res.Content, &res.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job result: %w", err)
+	}
+
+	return &res, nil
+}
+
+// Bury переводит задачу в dead-letter: она больше не будет выполняться
+func (r *JobRepository) Bury(ctx context.Context, id int, workerID, errMsg string) error {
+	return r.finish(ctx, r.db, leased(id, workerID), models.JobStatusDead, errMsg)
+}
+
+// Retry возвращает задачу в очередь с отложенным запуском
+func (r *JobRepository) Retry(ctx context.Context, id int, workerID string, runAt time.Time, errMsg string) error {
+	query, args, err := r.sb.Update("jobs").
+		Set("status", models.JobStatusPending).
+		Set("run_at", runAt).
+		Set("last_error", errMsg).
+		Set("locked_by", nil).
+		Set("locked_until", nil).
+		Where(leased(id, workerID)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if err := execAffecting(ctx, r.db, ErrJobLeaseLost, query, args...); err != nil {
+		return fmt.Errorf("failed to reschedule job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) finish(ctx context.Context, db execer, where sq.Eq, status, errMsg string) error {
+	builder := r.sb.Update("jobs").
+		Set("status", status).
+		Set("locked_by", nil).
+		Set("locked_until", nil).
+		Set("finished_at", time.Now()).
+		Where(where)
+
+	if status == models.JobStatusSucceeded {
+		builder = builder.Set("progress", 100)
+	}
+	if errMsg != "" {
+		builder = builder.Set("last_error", errMsg)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if err := execAffecting(ctx, db, ErrJobLeaseLost, query, args...); err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+
+	return nil
+}
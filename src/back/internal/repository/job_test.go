@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := NewJobRepository(newTestDB(t))
+
+	job, err := repo.Enqueue(ctx, "tickets.document", map[string]int{"course_id": 7}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusPending, job.Status)
+	assert.JSONEq(t, `{"course_id": 7}`, string(job.Payload))
+
+	leased, err := repo.Lease(ctx, "worker-1", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, leased)
+	assert.Equal(t, job.ID, leased.ID)
+	assert.Equal(t, 1, leased.Attempts)
+
+	// Пока аренда действует, задачу не получает другой воркер
+	other, err := repo.Lease(ctx, "worker-2", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	require.NoError(t, repo.SetProgress(ctx, job.ID, "worker-1", 40))
+	require.NoError(t, repo.SetProgress(ctx, job.ID, "worker-1", 40), "unchanged progress keeps the lease")
+	require.ErrorIs(t, repo.SetProgress(ctx, job.ID, "worker-2", 50), ErrJobLeaseLost)
+	require.NoError(t, repo.SaveResult(ctx, job.ID, "first.txt", "text/plain", []byte("first")))
+	require.NoError(t, repo.SaveResult(ctx, job.ID, "tickets.txt", "text/plain", []byte("second")))
+	require.NoError(t, repo.Complete(ctx, job.ID, "worker-1"))
+
+	done, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusSucceeded, done.Status)
+	assert.Equal(t, 100, done.Progress)
+	assert.NotNil(t, done.FinishedAt)
+	assert.Nil(t, done.LockedBy)
+
+	result, err := repo.GetResult(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "tickets.txt", result.Filename)
+	assert.Equal(t, []byte("second"), result.Content)
+}
+
+func TestJobRepository_RetryAndExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	repo := NewJobRepository(newTestDB(t))
+
+	job, err := repo.Enqueue(ctx, "content.sync", map[string]int{"event_id": 1}, 2)
+	require.NoError(t, err)
+
+	_, err = repo.Lease(ctx, "worker-1", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, repo.Retry(ctx, job.ID, "worker-1", time.Now().Add(-time.Second), "boom"))
+
+	// Вторая и последняя попытка: воркер «падает», аренда истекает
+	leased, err := repo.Lease(ctx, "worker-1", -time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, leased)
+	assert.Equal(t, 2, leased.Attempts)
+
+	next, err := repo.Lease(ctx, "worker-2", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	dead, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusDead, dead.Status)
+	require.NotNil(t, dead.LastError)
+	assert.Equal(t, "lease expired after last attempt", *dead.LastError)
+}
+
+func TestJobRepository_StaleWorkerLosesLease(t *testing.T) {
+	ctx := context.Background()
+	repo := NewJobRepository(newTestDB(t))
+
+	job, err := repo.Enqueue(ctx, "tickets.document", map[string]int{"course_id": 7}, 3)
+	require.NoError(t, err)
+
+	// Аренда первого воркера истекает, задачу забирает второй
+	_, err = repo.Lease(ctx, "worker-1", -time.Second)
+	require.NoError(t, err)
+	leased, err := repo.Lease(ctx, "worker-2", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, leased)
+
+	assert.ErrorIs(t, repo.Extend(ctx, job.ID, "worker-1", time.Minute), ErrJobLeaseLost)
+	assert.ErrorIs(t, repo.SetProgress(ctx, job.ID, "worker-1", 90), ErrJobLeaseLost)
+	assert.ErrorIs(t, repo.Complete(ctx, job.ID, "worker-1"), ErrJobLeaseLost)
+	assert.ErrorIs(t, repo.Retry(ctx, job.ID, "worker-1", time.Now(), "boom"), ErrJobLeaseLost)
+	assert.ErrorIs(t, repo.Bury(ctx, job.ID, "worker-1", "boom"), ErrJobLeaseLost)
+
+	running, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusRunning, running.Status)
+	assert.Zero(t, running.Progress)
+	require.NotNil(t, running.LockedBy)
+	assert.Equal(t, "worker-2", *running.LockedBy)
+
+	require.NoError(t, repo.Complete(ctx, job.ID, "worker-2"))
+	assert.ErrorIs(t, repo.Complete(ctx, job.ID, "worker-2"), ErrJobLeaseLost, "finished job cannot be finished again")
+}
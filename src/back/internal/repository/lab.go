@@ -1,59 +1,109 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/models"
 	sq "github.com/Masterminds/squirrel"
 )
 
 type LabRepository struct {
-	db *sql.DB
+	db *database.DB
+	sb sq.StatementBuilderType
 }
 
-func NewLabRepository(db *sql.DB) *LabRepository {
-	return &LabRepository{db: db}
+func NewLabRepository(db *database.DB) *LabRepository {
+	return &LabRepository{db: db, sb: db.Dialect.Builder()}
 }
 
 
-func (r *LabRepository) GetAll(courseID *int) ([]models.Lab, error) {
-	builder := sq.Select("id", "course_id", "number", "title", "description", "deadline", "max_score", "github_url", "created_at", "updated_at").
+// LabFilter - фильтры списка лабораторных; границы дедлайна включительные
+type LabFilter struct {
+	CourseID     *int
+	DeadlineFrom *time.Time
+	DeadlineTo   *time.Time
+	// Status - draft или published; пустой статус возвращает все лабораторные
+	Status string
+}
+
+var labSortable = map[string]string{
+	"id":         "id",
+	"course_id":  "course_id",
+	"number":     "number",
+	"title":      "title",
+	"deadline":   "deadline",
+	"max_score":  "max_score",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// GetAll возвращает страницу лабораторных и общее число лабораторных, подходящих под фильтр
+func (r *LabRepository) GetAll(ctx context.Context, filter LabFilter, opts ListOptions) ([]models.Lab, int, error) {
+	builder := r.sb.Select("id", "course_id", "number", "title", "description", "deadline", "max_score", "github_url", "status", "publish_at", "created_at", "updated_at").
 		From("labs").
-		OrderBy("course_id", "number")
+		Where(sq.Eq{"deleted_at": nil})
+
+	if filter.CourseID != nil {
+		builder = builder.Where(sq.Eq{"course_id": *filter.CourseID})
+	}
+	if filter.DeadlineFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"deadline": *filter.DeadlineFrom})
+	}
+	if filter.DeadlineTo != nil {
+		builder = builder.Where(sq.LtOrEq{"deadline": *filter.DeadlineTo})
+	}
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if courseID != nil {
-		builder = builder.Where(sq.Eq{"course_id": *courseID})
+	builder, err = applyListOptions(builder, opts, labSortable, "course_id", "number")
+	if err != nil {
+		return nil, 0, err
 	}
 
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get labs: %w", err)
+		return nil, 0, fmt.Errorf("failed to get labs: %w", err)
 	}
 	defer rows.Close()
 
 	var labs []models.Lab
 	for rows.Next() {
 		var l models.Lab
-		if err := rows.Scan(&l.ID, &l.CourseID, &l.Number, &l.Title, &l.Description, &l.Deadline, &l.MaxScore, &l.GithubURL, &l.CreatedAt, &l.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan error for lab: %w", err)
+		if err := rows.Scan(&l.ID, &l.CourseID, &l.Number, &l.Title, &l.Description, &l.Deadline, &l.MaxScore, &l.GithubURL, &l.Status, &l.PublishAt, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan error for lab: %w", err)
 		}
 		labs = append(labs, l)
 	}
 
-	return labs, nil
+	return labs, total, nil
 }
 
 
-func (r *LabRepository) GetByID(id int) (*models.Lab, error) {
-	query, args, err := sq.Select("id", "course_id", "number", "title", "description", "deadline", "max_score", "github_url", "created_at", "updated_at").
+// GetByCourseID возвращает все лабораторные курса, упорядоченные по номеру
+func (r *LabRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error) {
+	labs, _, err := r.GetAll(ctx, LabFilter{CourseID: &courseID}, ListOptions{})
+	return labs, err
+}
+
+func (r *LabRepository) GetByID(ctx context.Context, id int) (*models.Lab, error) {
+	query, args, err := r.sb.Select("id", "course_id", "number", "title", "description", "deadline", "max_score", "github_url", "status", "publish_at", "created_at", "updated_at").
 		From("labs").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
 	if err != nil {
@@ -61,7 +111,7 @@ func (r *LabRepository) GetByID(id int) (*models.Lab, error) {
 	}
 
 	var l models.Lab
-	err = r.db.QueryRow(query, args...).Scan(&l.ID, &l.CourseID, &l.Number, &l.Title, &l.Description, &l.Deadline, &l.MaxScore, &l.GithubURL, &l.CreatedAt, &l.UpdatedAt)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&l.ID, &l.CourseID, &l.Number, &l.Title, &l.Description, &l.Deadline, &l.MaxScore, &l.GithubURL, &l.Status, &l.PublishAt, &l.CreatedAt, &l.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -72,32 +122,30 @@ func (r *LabRepository) GetByID(id int) (*models.Lab, error) {
 	return &l, nil
 }
 
-func (r *LabRepository) Create(courseID, number, maxScore int, title, description, githubURL string, deadline *string) (*models.Lab, error) {
-	query, args, _ := sq.Insert("labs").
-		Columns("course_id", "number", "title", "description", "deadline", "max_score", "github_url").
-		Values(courseID, number, title, description, deadline, maxScore, githubURL).
-		ToSql()
-
-	result, err := r.db.Exec(query, args...)
+func (r *LabRepository) Create(ctx context.Context, courseID, number, maxScore int, title, description, githubURL string, deadline *string, status string, publishAt *time.Time) (*models.Lab, error) {
+	status, at := publicationValues(status, publishAt)
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("labs").
+		Columns("course_id", "number", "title", "description", "deadline", "max_score", "github_url", "status", "publish_at").
+		Values(courseID, number, title, description, deadline, maxScore, githubURL, status, at))
 	if err != nil {
 		return nil, err
 	}
 
-	id, _ := result.LastInsertId()
-
 	return &models.Lab{
-		ID:          int(id),
+		ID:          id,
 		CourseID:    courseID,
 		Number:      number,
 		Title:       title,
 		Description: description,
 		MaxScore:    maxScore,
 		GithubURL:   &githubURL,
+		Status:      status,
+		PublishAt:   publishAt,
 	}, nil
 }
 
-func (r *LabRepository) Update(id, courseID, number, maxScore int, title, description, githubURL string, deadline *string) error {
-	query, args, _ := sq.Update("labs").
+func (r *LabRepository) Update(ctx context.Context, id, courseID, number, maxScore int, title, description, githubURL string, deadline *string) error {
+	query, args, _ := r.sb.Update("labs").
 		Set("course_id", courseID).
 		Set("number", number).
 		Set("title", title).
@@ -105,18 +153,24 @@ func (r *LabRepository) Update(id, courseID, number, maxScore int, title, descri
 		Set("deadline", deadline).
 		Set("max_score", maxScore).
 		Set("github_url", githubURL).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	return execAffecting(ctx, r.db, ErrNotFound, query, args...)
+}
+
+// SetPublication меняет статус публикации лабораторной и время отложенной публикации
+func (r *LabRepository) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	return setPublication(ctx, r.db, r.sb, "labs", id, status, publishAt)
 }
 
-func (r *LabRepository) Delete(id int) error {
-	query, args, _ := sq.Delete("labs").
-		Where(sq.Eq{"id": id}).
+// Delete переносит лабораторную в корзину
+func (r *LabRepository) Delete(ctx context.Context, id int) error {
+	query, args, _ := r.sb.Update("labs").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
-	_, err := r.db.Exec(query, args...)
+	_, err := r.db.ExecContext(ctx, query, args...)
 	return err
 }
@@ -1,59 +1,103 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/models"
 	sq "github.com/Masterminds/squirrel"
 )
 
 type LectureRepository struct {
-	db *sql.DB
+	db *database.DB
+	sb sq.StatementBuilderType
 }
 
-func NewLectureRepository(db *sql.DB) *LectureRepository {
-	return &LectureRepository{db: db}
+func NewLectureRepository(db *database.DB) *LectureRepository {
+	return &LectureRepository{db: db, sb: db.Dialect.Builder()}
 }
 
 
-func (r *LectureRepository) GetAll(courseID *int) ([]models.Lecture, error) {
-	builder := sq.Select("id", "course_id", "week", "title", "content", "github_url", "created_at", "updated_at").
+// LectureFilter - фильтры списка лекций
+type LectureFilter struct {
+	CourseID *int
+	Week     *int
+	// Status - draft или published; пустой статус возвращает все лекции
+	Status string
+}
+
+var lectureSortable = map[string]string{
+	"id":         "id",
+	"course_id":  "course_id",
+	"week":       "week",
+	"title":      "title",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// GetAll возвращает страницу лекций и общее число лекций, подходящих под фильтр
+func (r *LectureRepository) GetAll(ctx context.Context, filter LectureFilter, opts ListOptions) ([]models.Lecture, int, error) {
+	builder := r.sb.Select("id", "course_id", "week", "title", "content", "github_url", "status", "publish_at", "created_at", "updated_at").
 		From("lectures").
-		OrderBy("course_id", "week")
+		Where(sq.Eq{"deleted_at": nil})
 
-	if courseID != nil {
-		builder = builder.Where(sq.Eq{"course_id": *courseID})
+	if filter.CourseID != nil {
+		builder = builder.Where(sq.Eq{"course_id": *filter.CourseID})
+	}
+	if filter.Week != nil {
+		builder = builder.Where(sq.Eq{"week": *filter.Week})
+	}
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, lectureSortable, "course_id", "week")
+	if err != nil {
+		return nil, 0, err
 	}
 
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get lectures: %w", err)
+		return nil, 0, fmt.Errorf("failed to get lectures: %w", err)
 	}
 	defer rows.Close()
 
 	var lectures []models.Lecture
 	for rows.Next() {
 		var l models.Lecture
-		if err := rows.Scan(&l.ID, &l.CourseID, &l.Week, &l.Title, &l.Content, &l.GithubURL, &l.CreatedAt, &l.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan error for lecture: %w", err)
+		if err := rows.Scan(&l.ID, &l.CourseID, &l.Week, &l.Title, &l.Content, &l.GithubURL, &l.Status, &l.PublishAt, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan error for lecture: %w", err)
 		}
 		lectures = append(lectures, l)
 	}
 
-	return lectures, nil
+	return lectures, total, nil
 }
 
 
-func (r *LectureRepository) GetByID(id int) (*models.Lecture, error) {
-	query, args, err := sq.Select("id", "course_id", "week", "title", "content", "github_url", "created_at", "updated_at").
+// GetByCourseID возвращает все лекции курса, упорядоченные по неделе
+func (r *LectureRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error) {
+	lectures, _, err := r.GetAll(ctx, LectureFilter{CourseID: &courseID}, ListOptions{})
+	return lectures, err
+}
+
+func (r *LectureRepository) GetByID(ctx context.Context, id int) (*models.Lecture, error) {
+	query, args, err := r.sb.Select("id", "course_id", "week", "title", "content", "github_url", "status", "publish_at", "created_at", "updated_at").
 		From("lectures").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
 	if err != nil {
@@ -61,7 +105,7 @@ func (r *LectureRepository) GetByID(id int) (*models.Lecture, error) {
 	}
 
 	var l models.Lecture
-	err = r.db.QueryRow(query, args...).Scan(&l.ID, &l.CourseID, &l.Week, &l.Title, &l.Content, &l.GithubURL, &l.CreatedAt, &l.UpdatedAt)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&l.ID, &l.CourseID, &l.Week, &l.Title, &l.Content, &l.GithubURL, &l.Status, &l.PublishAt, &l.CreatedAt, &l.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -72,48 +116,52 @@ func (r *LectureRepository) GetByID(id int) (*models.Lecture, error) {
 	return &l, nil
 }
 
-func (r *LectureRepository) Create(courseID, week int, title, content, githubURL string) (*models.Lecture, error) {
-	query, args, _ := sq.Insert("lectures").
-		Columns("course_id", "week", "title", "content", "github_url").
-		Values(courseID, week, title, content, githubURL).
-		ToSql()
-
-	result, err := r.db.Exec(query, args...)
+func (r *LectureRepository) Create(ctx context.Context, courseID, week int, title, content, githubURL, status string, publishAt *time.Time) (*models.Lecture, error) {
+	status, at := publicationValues(status, publishAt)
+	id, err := insertID(ctx, r.db, r.db.Dialect, r.sb.Insert("lectures").
+		Columns("course_id", "week", "title", "content", "github_url", "status", "publish_at").
+		Values(courseID, week, title, content, githubURL, status, at))
 	if err != nil {
 		return nil, err
 	}
 
-	id, _ := result.LastInsertId()
-
 	return &models.Lecture{
-		ID:        int(id),
+		ID:        id,
 		CourseID:  courseID,
 		Week:      week,
 		Title:     title,
 		Content:   content,
 		GithubURL: &githubURL,
+		Status:    status,
+		PublishAt: publishAt,
 	}, nil
 }
 
-func (r *LectureRepository) Update(id, courseID, week int, title, content, githubURL string) error {
-	query, args, _ := sq.Update("lectures").
+func (r *LectureRepository) Update(ctx context.Context, id, courseID, week int, title, content, githubURL string) error {
+	query, args, _ := r.sb.Update("lectures").
 		Set("course_id", courseID).
 		Set("week", week).
 		Set("title", title).
 		Set("content", content).
 		Set("github_url", githubURL).
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	return execAffecting(ctx, r.db, ErrNotFound, query, args...)
+}
+
+// SetPublication меняет статус публикации лекции и время отложенной публикации
+func (r *LectureRepository) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	return setPublication(ctx, r.db, r.sb, "lectures", id, status, publishAt)
 }
 
-func (r *LectureRepository) Delete(id int) error {
-	query, args, _ := sq.Delete("lectures").
-		Where(sq.Eq{"id": id}).
+// Delete переносит лекцию в корзину
+func (r *LectureRepository) Delete(ctx context.Context, id int) error {
+	query, args, _ := r.sb.Update("lectures").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 
-	_, err := r.db.Exec(query, args...)
+	_, err := r.db.ExecContext(ctx, query, args...)
 	return err
 }
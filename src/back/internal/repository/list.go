@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ErrInvalidSort - поле сортировки не поддерживается для данного списка
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// ListOptions - параметры постраничной выборки и сортировки списков
+type ListOptions struct {
+	// Limit - размер страницы, 0 означает выборку без ограничения
+	Limit  int
+	Offset int
+	// Sort - поля через запятую, "-" перед полем задаёт обратный порядок: "-week,title"
+	Sort string
+}
+
+// applyListOptions добавляет к запросу сортировку, LIMIT и OFFSET.
+// sortable сопоставляет публичные имена полей с колонками таблицы;
+// если сортировка не задана, используется defaultOrder.
+// В конец всегда добавляется id, чтобы страницы не пересекались.
+func applyListOptions(builder sq.SelectBuilder, opts ListOptions, sortable map[string]string, defaultOrder ...string) (sq.SelectBuilder, error) {
+	orderBy, err := parseSort(opts.Sort, sortable)
+	if err != nil {
+		return builder, err
+	}
+	if len(orderBy) == 0 {
+		orderBy = defaultOrder
+	}
+	builder = builder.OrderBy(append(orderBy, "id")...)
+
+	if opts.Limit > 0 {
+		builder = builder.Limit(uint64(opts.Limit))
+	} else if opts.Offset > 0 {
+		// MySQL и SQLite не принимают OFFSET без LIMIT; такой предел понимают все три СУБД
+		builder = builder.Limit(math.MaxInt64)
+	}
+	if opts.Offset > 0 {
+		builder = builder.Offset(uint64(opts.Offset))
+	}
+
+	return builder, nil
+}
+
+func parseSort(sort string, sortable map[string]string) ([]string, error) {
+	if sort == "" {
+		return nil, nil
+	}
+
+	var orderBy []string
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			direction = "DESC"
+			field = field[1:]
+		}
+
+		column, ok := sortable[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSort, field)
+		}
+		orderBy = append(orderBy, column+" "+direction)
+	}
+
+	return orderBy, nil
+}
+
+// countRows возвращает число строк, подходящих под фильтры запроса
+func countRows(ctx context.Context, db querier, builder sq.SelectBuilder) (int, error) {
+	query, args, err := builder.RemoveColumns().Columns("COUNT(*)").ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type AnnouncementRepository struct {
+	db *DB
+}
+
+func NewAnnouncementRepository(db *DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+var announcementSortable = map[string]sortField[models.Announcement]{
+	"id":         byKey(func(a models.Announcement) int { return a.ID }),
+	"course_id":  byKey(func(a models.Announcement) int { return a.CourseID }),
+	"created_at": byTime(func(a models.Announcement) *time.Time { return &a.CreatedAt }),
+	"updated_at": byTime(func(a models.Announcement) *time.Time { return &a.UpdatedAt }),
+}
+
+func (r *AnnouncementRepository) GetAll(ctx context.Context, filter repository.AnnouncementFilter, opts repository.ListOptions) ([]models.Announcement, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	announcements := filterRows(r.db.announcements, func(a models.Announcement) bool {
+		return (filter.CourseID == nil || a.CourseID == *filter.CourseID) &&
+			(filter.Kind == "" || a.Kind == filter.Kind)
+	})
+
+	page, err := listPage(announcements, opts, announcementSortable, "-created_at,-id")
+	return page, len(announcements), err
+}
+
+func (r *AnnouncementRepository) GetByID(ctx context.Context, id int) (*models.Announcement, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	a, ok := r.db.announcements[id]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) Create(ctx context.Context, courseID int, title, body, kind string) (*models.Announcement, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	a := models.Announcement{
+		ID:        r.db.nextID("announcements"),
+		CourseID:  courseID,
+		Title:     title,
+		Body:      body,
+		Kind:      kind,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.db.announcements[a.ID] = a
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) Update(ctx context.Context, id int, title, body string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	a, ok := r.db.announcements[id]
+	if !ok {
+		return nil
+	}
+	a.Title, a.Body, a.UpdatedAt = title, body, time.Now()
+	r.db.announcements[id] = a
+	return nil
+}
+
+func (r *AnnouncementRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.announcements, id)
+	return nil
+}
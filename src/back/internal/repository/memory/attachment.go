@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+type AttachmentRepository struct {
+	db *DB
+}
+
+func NewAttachmentRepository(db *DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, a *models.Attachment) (*models.Attachment, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(a.CourseID); err != nil {
+		return nil, err
+	}
+
+	created := *a
+	created.ID = r.db.nextID("attachments")
+	created.LectureID = copyID(a.LectureID)
+	created.LabID = copyID(a.LabID)
+	created.CreatedAt = time.Now()
+	r.db.attachments[created.ID] = created
+	return copyAttachment(created), nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id int) (*models.Attachment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	a, ok := r.db.attachments[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyAttachment(a), nil
+}
+
+func (r *AttachmentRepository) ListByLecture(ctx context.Context, lectureID int) ([]models.Attachment, error) {
+	return r.list(func(a models.Attachment) bool { return a.LectureID != nil && *a.LectureID == lectureID }), nil
+}
+
+func (r *AttachmentRepository) ListByLab(ctx context.Context, labID int) ([]models.Attachment, error) {
+	return r.list(func(a models.Attachment) bool { return a.LabID != nil && *a.LabID == labID }), nil
+}
+
+func (r *AttachmentRepository) ListOrphans(ctx context.Context) ([]models.Attachment, error) {
+	return r.list(func(a models.Attachment) bool { return a.LectureID == nil && a.LabID == nil }), nil
+}
+
+func (r *AttachmentRepository) list(keep func(models.Attachment) bool) []models.Attachment {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attachments := []models.Attachment{}
+	for _, a := range filterRows(r.db.attachments, keep) {
+		attachments = append(attachments, *copyAttachment(a))
+	}
+	slices.SortFunc(attachments, func(a, b models.Attachment) int { return cmp.Compare(a.ID, b.ID) })
+	return attachments
+}
+
+func (r *AttachmentRepository) CourseUsage(ctx context.Context, courseID int) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var used int64
+	for _, a := range r.db.attachments {
+		if a.CourseID == courseID {
+			used += a.Size
+		}
+	}
+	return used, nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.attachments, id)
+	return nil
+}
+
+// detachAttachments повторяет ON DELETE SET NULL для вложений окончательно удалённых
+// курсов, лекций и лабораторных; вызывается под блокировкой
+func (db *DB) detachAttachments() {
+	for id, a := range db.attachments {
+		if _, ok := db.courses[a.CourseID]; !ok {
+			if _, ok := db.trashedCourses[a.CourseID]; !ok {
+				a.CourseID = 0
+			}
+		}
+		if a.LectureID != nil && !db.lectureExists(*a.LectureID) {
+			a.LectureID = nil
+		}
+		if a.LabID != nil && !db.labExists(*a.LabID) {
+			a.LabID = nil
+		}
+		db.attachments[id] = a
+	}
+}
+
+func (db *DB) lectureExists(id int) bool {
+	_, live := db.lectures[id]
+	_, inTrash := db.trashedLectures[id]
+	return live || inTrash
+}
+
+func (db *DB) labExists(id int) bool {
+	_, live := db.labs[id]
+	_, inTrash := db.trashedLabs[id]
+	return live || inTrash
+}
+
+func copyID(id *int) *int {
+	if id == nil {
+		return nil
+	}
+	v := *id
+	return &v
+}
+
+// copyAttachment копирует ссылки на владельца, чтобы вызывающий код не менял хранимую запись
+func copyAttachment(a models.Attachment) *models.Attachment {
+	a.LectureID = copyID(a.LectureID)
+	a.LabID = copyID(a.LabID)
+	return &a
+}
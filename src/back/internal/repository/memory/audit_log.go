@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type AuditLogRepository struct {
+	db *DB
+}
+
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+var auditLogSortable = map[string]sortField[models.AuditEntry]{
+	"id":          byKey(func(e models.AuditEntry) int { return e.ID }),
+	"action":      byKey(func(e models.AuditEntry) string { return e.Action }),
+	"entity_type": byKey(func(e models.AuditEntry) string { return e.EntityType }),
+	"username":    byKey(func(e models.AuditEntry) string { return e.Username }),
+	"created_at":  byTime(func(e models.AuditEntry) *time.Time { return &e.CreatedAt }),
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	e := *copyAuditEntry(*entry)
+	e.ID = r.db.nextID("audit_log")
+	e.CreatedAt = time.Now()
+	r.db.auditLog[e.ID] = e
+	return nil
+}
+
+func (r *AuditLogRepository) GetAll(ctx context.Context, filter repository.AuditFilter, opts repository.ListOptions) ([]models.AuditEntry, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	entries := filterRows(r.db.auditLog, func(e models.AuditEntry) bool {
+		switch {
+		case filter.UserID != nil && (e.UserID == nil || *e.UserID != *filter.UserID):
+			return false
+		case filter.Action != "" && e.Action != filter.Action:
+			return false
+		case filter.EntityType != "" && e.EntityType != filter.EntityType:
+			return false
+		case filter.EntityID != nil && (e.EntityID == nil || *e.EntityID != *filter.EntityID):
+			return false
+		case filter.From != nil && e.CreatedAt.Before(*filter.From):
+			return false
+		case filter.To != nil && e.CreatedAt.After(*filter.To):
+			return false
+		}
+		return true
+	})
+
+	page, err := listPage(entries, opts, auditLogSortable, "-created_at")
+	for i := range page {
+		page[i] = *copyAuditEntry(page[i])
+	}
+	return page, len(entries), err
+}
+
+// copyAuditEntry отдаёт копию записи со своими снимками
+func copyAuditEntry(e models.AuditEntry) *models.AuditEntry {
+	e.Before = slices.Clone(e.Before)
+	e.After = slices.Clone(e.After)
+	return &e
+}
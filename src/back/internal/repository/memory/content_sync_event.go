@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type ContentSyncEventRepository struct {
+	db *DB
+}
+
+func NewContentSyncEventRepository(db *DB) *ContentSyncEventRepository {
+	return &ContentSyncEventRepository{db: db}
+}
+
+var contentSyncEventSortable = map[string]sortField[models.ContentSyncEvent]{
+	"id":         byKey(func(e models.ContentSyncEvent) int { return e.ID }),
+	"status":     byKey(func(e models.ContentSyncEvent) string { return e.Status }),
+	"created_at": byTime(func(e models.ContentSyncEvent) *time.Time { return &e.CreatedAt }),
+}
+
+func (r *ContentSyncEventRepository) Create(ctx context.Context, event *models.ContentSyncEvent) (*models.ContentSyncEvent, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(event.CourseID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	e := models.ContentSyncEvent{
+		ID:           r.db.nextID("content_sync_events"),
+		CourseID:     event.CourseID,
+		Provider:     event.Provider,
+		DeliveryID:   event.DeliveryID,
+		Repository:   event.Repository,
+		Ref:          event.Ref,
+		CommitSHA:    event.CommitSHA,
+		ChangedPaths: slices.Clone(event.ChangedPaths),
+		Status:       event.Status,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if e.ChangedPaths == nil {
+		e.ChangedPaths = []string{}
+	}
+	r.db.contentSyncEvents[e.ID] = e
+	return copyContentSyncEvent(e), nil
+}
+
+func (r *ContentSyncEventRepository) GetByID(ctx context.Context, id int) (*models.ContentSyncEvent, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	e, ok := r.db.contentSyncEvents[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyContentSyncEvent(e), nil
+}
+
+func (r *ContentSyncEventRepository) GetByCourseID(ctx context.Context, courseID int, opts repository.ListOptions) ([]models.ContentSyncEvent, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	events := filterRows(r.db.contentSyncEvents, func(e models.ContentSyncEvent) bool { return e.CourseID == courseID })
+
+	page, err := listPage(events, opts, contentSyncEventSortable, "-created_at")
+	for i := range page {
+		page[i] = *copyContentSyncEvent(page[i])
+	}
+	return page, len(events), err
+}
+
+func (r *ContentSyncEventRepository) SetJob(ctx context.Context, id, jobID int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if e, ok := r.db.contentSyncEvents[id]; ok {
+		e.JobID = &jobID
+		e.UpdatedAt = time.Now()
+		r.db.contentSyncEvents[id] = e
+	}
+	return nil
+}
+
+func (r *ContentSyncEventRepository) Finish(ctx context.Context, id int, status string, report *models.ContentSyncReport, errMsg string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	e, ok := r.db.contentSyncEvents[id]
+	if !ok {
+		return nil
+	}
+	e.Status = status
+	if report != nil {
+		copied := *report
+		e.Report = &copied
+	}
+	if errMsg != "" {
+		e.Error = &errMsg
+	}
+	e.UpdatedAt = time.Now()
+	r.db.contentSyncEvents[id] = e
+	return nil
+}
+
+// copyContentSyncEvent отдаёт копию события со своим срезом путей
+func copyContentSyncEvent(e models.ContentSyncEvent) *models.ContentSyncEvent {
+	e.ChangedPaths = slices.Clone(e.ChangedPaths)
+	return &e
+}
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type CourseRepository struct {
+	db *DB
+}
+
+func NewCourseRepository(db *DB) *CourseRepository {
+	return &CourseRepository{db: db}
+}
+
+var courseSortable = map[string]sortField[models.Course]{
+	"id":         byKey(func(c models.Course) int { return c.ID }),
+	"name":       byKey(func(c models.Course) string { return c.Name }),
+	"semester":   byKey(func(c models.Course) string { return c.Semester }),
+	"created_at": byTime(func(c models.Course) *time.Time { return &c.CreatedAt }),
+	"updated_at": byTime(func(c models.Course) *time.Time { return &c.UpdatedAt }),
+}
+
+func (r *CourseRepository) GetAll(ctx context.Context, filter repository.CourseFilter, opts repository.ListOptions) ([]models.Course, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	courses := filterRows(r.db.courses, func(c models.Course) bool {
+		if filter.Semester != "" && c.Semester != filter.Semester {
+			return false
+		}
+		if filter.Archived != nil && *filter.Archived != (c.ArchivedAt != nil) {
+			return false
+		}
+		return true
+	})
+
+	page, err := listPage(courses, opts, courseSortable, "semester,name")
+	return page, len(courses), err
+}
+
+func (r *CourseRepository) GetByID(ctx context.Context, id int) (*models.Course, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	c, ok := r.db.courses[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *CourseRepository) FindByNameAndSemester(ctx context.Context, name, semester string) (*models.Course, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var found *models.Course
+	for _, c := range r.db.courses {
+		if c.Name == name && c.Semester == semester && (found == nil || c.ID < found.ID) {
+			found = &c
+		}
+	}
+	return found, nil
+}
+
+func (r *CourseRepository) Create(ctx context.Context, name, semester, description string) (*models.Course, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c := r.db.createCourse(models.Course{Name: name, Semester: semester, Description: description})
+	return &c, nil
+}
+
+// createCourse вставляет курс с новым id; вызывается под блокировкой
+func (db *DB) createCourse(c models.Course) models.Course {
+	now := time.Now()
+	c.ID = db.nextID("courses")
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	db.courses[c.ID] = c
+	return c
+}
+
+func (r *CourseRepository) Update(ctx context.Context, id int, name, semester, description string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c, ok := r.db.courses[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	c.Name = name
+	c.Semester = semester
+	c.Description = description
+	c.UpdatedAt = time.Now()
+	r.db.courses[id] = c
+	return nil
+}
+
+func (r *CourseRepository) SetArchived(ctx context.Context, id int, archived bool) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c, ok := r.db.courses[id]
+	if !ok {
+		return nil
+	}
+	c.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		c.ArchivedAt = &now
+	}
+	c.UpdatedAt = time.Now()
+	r.db.courses[id] = c
+	return nil
+}
+
+// Delete переносит курс и его материалы в корзину с одним временем удаления
+func (r *CourseRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.courses[id]; !ok {
+		return nil
+	}
+
+	now := time.Now()
+	trashRows(r.db.courses, r.db.trashedCourses, now, func(c models.Course) bool { return c.ID == id })
+	trashRows(r.db.lectures, r.db.trashedLectures, now, func(l models.Lecture) bool { return l.CourseID == id })
+	trashRows(r.db.labs, r.db.trashedLabs, now, func(l models.Lab) bool { return l.CourseID == id })
+	trashRows(r.db.gradeSheets, r.db.trashedGradeSheets, now, func(s models.GradeSheet) bool { return s.CourseID == id })
+	trashRows(r.db.examQuestions, r.db.trashedExamQuestions, now, func(q models.ExamQuestion) bool { return q.CourseID == id })
+	return nil
+}
+
+// Clone копирует курс вместе с материалами; блокировка делает копию атомарной, как транзакция
+func (r *CourseRepository) Clone(ctx context.Context, sourceID int, opts repository.CourseCloneOptions) (*models.CourseCloneSummary, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	source, ok := r.db.courses[sourceID]
+	if !ok {
+		return nil, repository.ErrCourseNotFound
+	}
+
+	course := models.Course{Name: source.Name, Semester: opts.Semester, Description: source.Description}
+	if opts.Name != "" {
+		course.Name = opts.Name
+	}
+	if opts.Description != nil {
+		course.Description = *opts.Description
+	}
+	course = r.db.createCourse(course)
+
+	summary := &models.CourseCloneSummary{Course: &course, SourceID: sourceID}
+
+	// Черновики остаются черновиками, но время публикации относится к исходному семестру и не копируется
+	for _, l := range courseRows(r.db.lectures, sourceID, func(l models.Lecture) (int, int) { return l.CourseID, l.ID }) {
+		l.CourseID = course.ID
+		l.PublishAt = nil
+		r.db.insertLecture(l)
+		summary.Lectures++
+	}
+	for _, q := range courseRows(r.db.examQuestions, sourceID, func(q models.ExamQuestion) (int, int) { return q.CourseID, q.ID }) {
+		q.CourseID = course.ID
+		r.db.insertExamQuestion(q)
+		summary.ExamQuestions++
+	}
+	if opts.WithGradeSheets {
+		for _, s := range courseRows(r.db.gradeSheets, sourceID, func(s models.GradeSheet) (int, int) { return s.CourseID, s.ID }) {
+			s.CourseID = course.ID
+			s.PublishAt = nil
+			r.db.insertGradeSheet(s)
+			summary.GradeSheets++
+		}
+	}
+	for _, l := range courseRows(r.db.labs, sourceID, func(l models.Lab) (int, int) { return l.CourseID, l.ID }) {
+		l.CourseID = course.ID
+		l.PublishAt = nil
+		if l.Deadline != nil {
+			shifted := l.Deadline.AddDate(0, 0, opts.DeadlineShiftDays)
+			l.Deadline = &shifted
+		}
+		r.db.insertLab(l)
+		summary.Labs++
+	}
+
+	return summary, nil
+}
+
+// Import создаёт курс вместе с материалами под одной блокировкой, как в транзакции
+func (r *CourseRepository) Import(ctx context.Context, content *models.CourseContent) (*models.CourseImportSummary, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	course := r.db.createCourse(models.Course{
+		Name:        content.Course.Name,
+		Semester:    content.Course.Semester,
+		Description: content.Course.Description,
+	})
+
+	for _, l := range content.Lectures {
+		l.CourseID = course.ID
+		r.db.insertLecture(l)
+	}
+	for _, l := range content.Labs {
+		l.CourseID = course.ID
+		r.db.insertLab(l)
+	}
+	for _, q := range content.ExamQuestions {
+		q.CourseID = course.ID
+		r.db.insertExamQuestion(q)
+	}
+	for _, s := range content.GradeSheets {
+		s.CourseID = course.ID
+		r.db.insertGradeSheet(s)
+	}
+
+	return &models.CourseImportSummary{
+		Course:        &course,
+		Lectures:      len(content.Lectures),
+		Labs:          len(content.Labs),
+		ExamQuestions: len(content.ExamQuestions),
+		GradeSheets:   len(content.GradeSheets),
+	}, nil
+}
+
+// courseRows возвращает строки таблицы курса в порядке id; keys извлекает course_id и id строки
+func courseRows[T any](rows map[int]T, courseID int, keys func(T) (int, int)) []T {
+	result := filterRows(rows, func(row T) bool {
+		rowCourseID, _ := keys(row)
+		return rowCourseID == courseID
+	})
+	slices.SortFunc(result, func(a, b T) int {
+		_, idA := keys(a)
+		_, idB := keys(b)
+		return cmp.Compare(idA, idB)
+	})
+	return result
+}
@@ -0,0 +1,200 @@
+// Package memory - хранилища в памяти процесса с тем же поведением, что и SQL-репозитории.
+// Используются в тестах обработчиков и в демо-режиме сервера; данные теряются при остановке.
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// DB - общее состояние всех хранилищ: удаление курса, клонирование и поиск
+// затрагивают сразу несколько сущностей, как внешние ключи и UNION в SQL
+type DB struct {
+	mu sync.RWMutex
+
+	lastID map[string]int
+
+	courses           map[int]models.Course
+	lectures          map[int]models.Lecture
+	labs              map[int]models.Lab
+	examQuestions     map[int]models.ExamQuestion
+	gradeSheets       map[int]models.GradeSheet
+	users             map[int]models.User
+	jobs              map[int]models.Job
+	jobResults        map[int]models.JobResult
+	contentSyncEvents map[int]models.ContentSyncEvent
+	auditLog          map[int]models.AuditEntry
+	schedules         map[int]models.CourseSchedule
+	attachments       map[int]models.Attachment
+	announcements     map[int]models.Announcement
+	// notificationPreferences и deadlineReminders хранятся по user_id и lab_id
+	notificationPreferences map[int]models.NotificationPreferences
+	deadlineReminders       map[int]time.Time
+
+	// Корзина: мягко удалённые записи вынесены из таблиц вместе со временем удаления,
+	// поэтому чтения их не видят без отдельных проверок
+	trashedCourses       map[int]trashed[models.Course]
+	trashedLectures      map[int]trashed[models.Lecture]
+	trashedLabs          map[int]trashed[models.Lab]
+	trashedGradeSheets   map[int]trashed[models.GradeSheet]
+	trashedExamQuestions map[int]trashed[models.ExamQuestion]
+}
+
+func NewDB() *DB {
+	return &DB{
+		lastID:            make(map[string]int),
+		courses:           make(map[int]models.Course),
+		lectures:          make(map[int]models.Lecture),
+		labs:              make(map[int]models.Lab),
+		examQuestions:     make(map[int]models.ExamQuestion),
+		gradeSheets:       make(map[int]models.GradeSheet),
+		users:             make(map[int]models.User),
+		jobs:              make(map[int]models.Job),
+		jobResults:        make(map[int]models.JobResult),
+		contentSyncEvents: make(map[int]models.ContentSyncEvent),
+		auditLog:          make(map[int]models.AuditEntry),
+		schedules:         make(map[int]models.CourseSchedule),
+		attachments:       make(map[int]models.Attachment),
+		announcements:     make(map[int]models.Announcement),
+
+		notificationPreferences: make(map[int]models.NotificationPreferences),
+		deadlineReminders:       make(map[int]time.Time),
+
+		trashedCourses:       make(map[int]trashed[models.Course]),
+		trashedLectures:      make(map[int]trashed[models.Lecture]),
+		trashedLabs:          make(map[int]trashed[models.Lab]),
+		trashedGradeSheets:   make(map[int]trashed[models.GradeSheet]),
+		trashedExamQuestions: make(map[int]trashed[models.ExamQuestion]),
+	}
+}
+
+// NewStore создаёт все хранилища поверх одной базы в памяти
+func NewStore(db *DB) *repository.Store {
+	return &repository.Store{
+		Courses:           NewCourseRepository(db),
+		Lectures:          NewLectureRepository(db),
+		Labs:              NewLabRepository(db),
+		ExamQuestions:     NewExamQuestionRepository(db),
+		GradeSheets:       NewGradeSheetRepository(db),
+		Users:             NewUserRepository(db),
+		Jobs:              NewJobRepository(db),
+		Search:            NewSearchRepository(db),
+		ContentSyncEvents: NewContentSyncEventRepository(db),
+		AuditLog:          NewAuditLogRepository(db),
+		Trash:             NewTrashRepository(db),
+		Schedules:         NewScheduleRepository(db),
+		Publication:       NewPublicationRepository(db),
+		Attachments:       NewAttachmentRepository(db),
+		Announcements:     NewAnnouncementRepository(db),
+		Notifications:     NewNotificationRepository(db),
+	}
+}
+
+// nextID выдаёт следующий id таблицы, как AUTO_INCREMENT; вызывается под блокировкой
+func (db *DB) nextID(table string) int {
+	db.lastID[table]++
+	return db.lastID[table]
+}
+
+// requireCourse повторяет проверку внешнего ключа course_id; вызывается под блокировкой
+func (db *DB) requireCourse(courseID int) error {
+	if _, ok := db.courses[courseID]; !ok {
+		return fmt.Errorf("foreign key violation: course %d does not exist", courseID)
+	}
+	return nil
+}
+
+// filterRows возвращает строки таблицы, для которых keep возвращает true; nil keep берёт все
+func filterRows[T any](rows map[int]T, keep func(T) bool) []T {
+	var result []T
+	for _, row := range rows {
+		if keep == nil || keep(row) {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+// deleteRows удаляет строки таблицы, для которых match возвращает true; вызывается под блокировкой
+func deleteRows[T any](rows map[int]T, match func(T) bool) {
+	for id, row := range rows {
+		if match(row) {
+			delete(rows, id)
+		}
+	}
+}
+
+// sortField сравнивает две строки по одному полю
+type sortField[T any] func(a, b T) int
+
+// listPage сортирует строки и вырезает страницу так же, как applyListOptions в SQL:
+// сортировка берётся из opts.Sort или defaultSort, последним ключом всегда идёт id
+func listPage[T any](rows []T, opts repository.ListOptions, sortable map[string]sortField[T], defaultSort string) ([]T, error) {
+	sort := opts.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	var fields []sortField[T]
+	for _, name := range strings.Split(sort+",id", ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		field, ok := sortable[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", repository.ErrInvalidSort, name)
+		}
+		if desc {
+			asc := field
+			field = func(a, b T) int { return asc(b, a) }
+		}
+		fields = append(fields, field)
+	}
+
+	slices.SortFunc(rows, func(a, b T) int {
+		for _, field := range fields {
+			if c := field(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	if opts.Offset > 0 {
+		rows = rows[min(opts.Offset, len(rows)):]
+	}
+	if opts.Limit > 0 && opts.Limit < len(rows) {
+		rows = rows[:opts.Limit]
+	}
+
+	return rows, nil
+}
+
+// byKey строит sortField из функции, извлекающей упорядочиваемое поле
+func byKey[T any, K cmp.Ordered](key func(T) K) sortField[T] {
+	return func(a, b T) int { return cmp.Compare(key(a), key(b)) }
+}
+
+// byTime сравнивает времена; как в MySQL, NULL идёт раньше любого значения
+func byTime[T any](key func(T) *time.Time) sortField[T] {
+	return func(a, b T) int {
+		ta, tb := key(a), key(b)
+		switch {
+		case ta == nil && tb == nil:
+			return 0
+		case ta == nil:
+			return -1
+		case tb == nil:
+			return 1
+		}
+		return ta.Compare(*tb)
+	}
+}
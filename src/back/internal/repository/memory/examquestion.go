@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type ExamQuestionRepository struct {
+	db *DB
+}
+
+func NewExamQuestionRepository(db *DB) *ExamQuestionRepository {
+	return &ExamQuestionRepository{db: db}
+}
+
+var examQuestionSortable = map[string]sortField[models.ExamQuestion]{
+	"id":         byKey(func(q models.ExamQuestion) int { return q.ID }),
+	"course_id":  byKey(func(q models.ExamQuestion) int { return q.CourseID }),
+	"section":    byKey(func(q models.ExamQuestion) string { return q.Section }),
+	"number":     byKey(func(q models.ExamQuestion) int { return q.Number }),
+	"created_at": byTime(func(q models.ExamQuestion) *time.Time { return &q.CreatedAt }),
+	"updated_at": byTime(func(q models.ExamQuestion) *time.Time { return &q.UpdatedAt }),
+}
+
+func (r *ExamQuestionRepository) GetAll(ctx context.Context, filter repository.ExamQuestionFilter, opts repository.ListOptions) ([]models.ExamQuestion, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	questions := filterRows(r.db.examQuestions, func(q models.ExamQuestion) bool {
+		if filter.CourseID != nil && q.CourseID != *filter.CourseID {
+			return false
+		}
+		if filter.Section != "" && q.Section != filter.Section {
+			return false
+		}
+		return true
+	})
+
+	page, err := listPage(questions, opts, examQuestionSortable, "course_id,section,number")
+	if page == nil {
+		page = []models.ExamQuestion{}
+	}
+	return page, len(questions), err
+}
+
+func (r *ExamQuestionRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error) {
+	questions, _, err := r.GetAll(ctx, repository.ExamQuestionFilter{CourseID: &courseID}, repository.ListOptions{})
+	return questions, err
+}
+
+func (r *ExamQuestionRepository) GetByID(ctx context.Context, id int) (*models.ExamQuestion, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	q, ok := r.db.examQuestions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &q, nil
+}
+
+func (r *ExamQuestionRepository) Create(ctx context.Context, courseID, number int, section, question string) (*models.ExamQuestion, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	q := r.db.insertExamQuestion(models.ExamQuestion{CourseID: courseID, Number: number, Section: section, Question: question})
+	return &q, nil
+}
+
+// insertExamQuestion вставляет вопрос с новым id; вызывается под блокировкой
+func (db *DB) insertExamQuestion(q models.ExamQuestion) models.ExamQuestion {
+	now := time.Now()
+	q.ID = db.nextID("exam_questions")
+	q.CreatedAt = now
+	q.UpdatedAt = now
+	db.examQuestions[q.ID] = q
+	return q
+}
+
+func (r *ExamQuestionRepository) Update(ctx context.Context, id, courseID, number int, section, question string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	q, ok := r.db.examQuestions[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if err := r.db.requireCourse(courseID); err != nil {
+		return err
+	}
+
+	q.CourseID = courseID
+	q.Number = number
+	q.Section = section
+	q.Question = question
+	q.UpdatedAt = time.Now()
+	r.db.examQuestions[id] = q
+	return nil
+}
+
+func (r *ExamQuestionRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	trashRows(r.db.examQuestions, r.db.trashedExamQuestions, time.Now(), func(q models.ExamQuestion) bool { return q.ID == id })
+	return nil
+}
+
+// BulkCreate вставляет все вопросы или ни одного, как один многострочный INSERT
+func (r *ExamQuestionRepository) BulkCreate(ctx context.Context, questions []models.ExamQuestion) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, q := range questions {
+		if err := r.db.requireCourse(q.CourseID); err != nil {
+			return err
+		}
+	}
+	for _, q := range questions {
+		r.db.insertExamQuestion(models.ExamQuestion{CourseID: q.CourseID, Number: q.Number, Section: q.Section, Question: q.Question})
+	}
+	return nil
+}
+
+func (r *ExamQuestionRepository) DeleteByCourseID(ctx context.Context, courseID int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	trashRows(r.db.examQuestions, r.db.trashedExamQuestions, time.Now(), func(q models.ExamQuestion) bool { return q.CourseID == courseID })
+	return nil
+}
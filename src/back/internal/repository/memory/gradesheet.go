@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type GradeSheetRepository struct {
+	db *DB
+}
+
+func NewGradeSheetRepository(db *DB) *GradeSheetRepository {
+	return &GradeSheetRepository{db: db}
+}
+
+var gradeSheetSortable = map[string]sortField[models.GradeSheet]{
+	"id":         byKey(func(s models.GradeSheet) int { return s.ID }),
+	"course_id":  byKey(func(s models.GradeSheet) int { return s.CourseID }),
+	"created_at": byTime(func(s models.GradeSheet) *time.Time { return &s.CreatedAt }),
+	"updated_at": byTime(func(s models.GradeSheet) *time.Time { return &s.UpdatedAt }),
+}
+
+func (r *GradeSheetRepository) GetAll(ctx context.Context, filter repository.GradeSheetFilter, opts repository.ListOptions) ([]models.GradeSheet, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	sheets := filterRows(r.db.gradeSheets, func(s models.GradeSheet) bool {
+		return (filter.CourseID == nil || s.CourseID == *filter.CourseID) &&
+			(filter.Status == "" || s.Status == filter.Status)
+	})
+
+	page, err := listPage(sheets, opts, gradeSheetSortable, "course_id")
+	return page, len(sheets), err
+}
+
+func (r *GradeSheetRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.GradeSheet, error) {
+	sheets, _, err := r.GetAll(ctx, repository.GradeSheetFilter{CourseID: &courseID}, repository.ListOptions{})
+	return sheets, err
+}
+
+func (r *GradeSheetRepository) GetByID(ctx context.Context, id int) (*models.GradeSheet, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	s, ok := r.db.gradeSheets[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *GradeSheetRepository) Create(ctx context.Context, courseID int, sheetURL, description, status string, publishAt *time.Time) (*models.GradeSheet, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	s := r.db.insertGradeSheet(models.GradeSheet{
+		CourseID:    courseID,
+		SheetURL:    sheetURL,
+		Description: &description,
+		Status:      status,
+		PublishAt:   publishAt,
+	})
+	return &s, nil
+}
+
+// insertGradeSheet вставляет ведомость с новым id; вызывается под блокировкой
+func (db *DB) insertGradeSheet(s models.GradeSheet) models.GradeSheet {
+	now := time.Now()
+	s.ID = db.nextID("grade_sheets")
+	s.Status = cmp.Or(s.Status, models.PublicationStatusPublished)
+	s.CreatedAt = now
+	s.UpdatedAt = now
+	db.gradeSheets[s.ID] = s
+	return s
+}
+
+func (r *GradeSheetRepository) Update(ctx context.Context, id int, sheetURL, description string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	s, ok := r.db.gradeSheets[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	s.SheetURL = sheetURL
+	s.Description = &description
+	s.UpdatedAt = time.Now()
+	r.db.gradeSheets[id] = s
+	return nil
+}
+
+func (r *GradeSheetRepository) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	s, ok := r.db.gradeSheets[id]
+	if !ok {
+		return nil
+	}
+	s.Status = status
+	s.PublishAt = publishAt
+	s.UpdatedAt = time.Now()
+	r.db.gradeSheets[id] = s
+	return nil
+}
+
+func (r *GradeSheetRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	trashRows(r.db.gradeSheets, r.db.trashedGradeSheets, time.Now(), func(s models.GradeSheet) bool { return s.ID == id })
+	return nil
+}
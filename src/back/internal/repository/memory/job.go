@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type JobRepository struct {
+	db *DB
+}
+
+func NewJobRepository(db *DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+func (r *JobRepository) Enqueue(ctx context.Context, jobType string, payload any, maxAttempts int) (*models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	job := models.Job{
+		ID:          r.db.nextID("jobs"),
+		Type:        jobType,
+		Payload:     data,
+		Status:      models.JobStatusPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	r.db.jobs[job.ID] = job
+	return copyJob(job), nil
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, id int) (*models.Job, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	job, ok := r.db.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyJob(job), nil
+}
+
+// Lease захватывает следующую готовую задачу по тем же правилам, что и SQL-очередь:
+// ожидающую с наступившим run_at или ту, у которой истекла аренда воркера
+func (r *JobRepository) Lease(ctx context.Context, workerID string, visibility time.Duration) (*models.Job, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for {
+		now := time.Now()
+
+		var next *models.Job
+		for _, job := range r.db.jobs {
+			ready := (job.Status == models.JobStatusPending && !job.RunAt.After(now)) ||
+				(job.Status == models.JobStatusRunning && job.LockedUntil != nil && !job.LockedUntil.After(now))
+			if !ready {
+				continue
+			}
+			if next == nil || job.RunAt.Before(next.RunAt) || (job.RunAt.Equal(next.RunAt) && job.ID < next.ID) {
+				next = &job
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+
+		// Воркер упал на последней попытке — аренда истекла, повторять больше нельзя
+		if next.Attempts >= next.MaxAttempts {
+			r.db.finishJob(*next, models.JobStatusDead, "lease expired after last attempt")
+			continue
+		}
+
+		lockedUntil := now.Add(visibility)
+		next.Status = models.JobStatusRunning
+		next.Attempts++
+		next.LockedBy = &workerID
+		next.LockedUntil = &lockedUntil
+		next.UpdatedAt = now
+		r.db.jobs[next.ID] = *next
+		return copyJob(*next), nil
+	}
+}
+
+// leasedJob возвращает задачу, которую всё ещё держит воркер workerID; вызывается под блокировкой
+func (db *DB) leasedJob(id int, workerID string) (models.Job, error) {
+	job, ok := db.jobs[id]
+	if !ok || job.Status != models.JobStatusRunning || job.LockedBy == nil || *job.LockedBy != workerID {
+		return models.Job{}, repository.ErrJobLeaseLost
+	}
+	return job, nil
+}
+
+func (r *JobRepository) Extend(ctx context.Context, id int, workerID string, visibility time.Duration) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	job, err := r.db.leasedJob(id, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to extend job lease: %w", err)
+	}
+	lockedUntil := time.Now().Add(visibility)
+	job.LockedUntil = &lockedUntil
+	r.db.jobs[id] = job
+	return nil
+}
+
+func (r *JobRepository) Complete(ctx context.Context, id int, workerID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	job, err := r.db.leasedJob(id, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+	r.db.finishJob(job, models.JobStatusSucceeded, "")
+	return nil
+}
+
+func (r *JobRepository) SetProgress(ctx context.Context, id int, workerID string, progress int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	job, err := r.db.leasedJob(id, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+	job.Progress = progress
+	job.UpdatedAt = time.Now()
+	r.db.jobs[id] = job
+	return nil
+}
+
+// SaveResult заменяет результат предыдущей попытки, как upsert по job_id
+func (r *JobRepository) SaveResult(ctx context.Context, jobID int, filename, contentType string, content []byte) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.jobs[jobID]; !ok {
+		return fmt.Errorf("failed to save job result: foreign key violation: job %d does not exist", jobID)
+	}
+
+	result := models.JobResult{
+		JobID:       jobID,
+		Filename:    filename,
+		ContentType: contentType,
+		Content:     bytes.Clone(content),
+		CreatedAt:   time.Now(),
+	}
+	if prev, ok := r.db.jobResults[jobID]; ok {
+		result.CreatedAt = prev.CreatedAt
+	}
+	r.db.jobResults[jobID] = result
+	return nil
+}
+
+func (r *JobRepository) GetResult(ctx context.Context, jobID int) (*models.JobResult, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	result, ok := r.db.jobResults[jobID]
+	if !ok {
+		return nil, nil
+	}
+	result.Content = bytes.Clone(result.Content)
+	return &result, nil
+}
+
+func (r *JobRepository) Bury(ctx context.Context, id int, workerID, errMsg string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	job, err := r.db.leasedJob(id, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+	r.db.finishJob(job, models.JobStatusDead, errMsg)
+	return nil
+}
+
+func (r *JobRepository) Retry(ctx context.Context, id int, workerID string, runAt time.Time, errMsg string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	job, err := r.db.leasedJob(id, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule job: %w", err)
+	}
+	job.Status = models.JobStatusPending
+	job.RunAt = runAt
+	job.LastError = &errMsg
+	job.LockedBy = nil
+	job.LockedUntil = nil
+	job.UpdatedAt = time.Now()
+	r.db.jobs[id] = job
+	return nil
+}
+
+// finishJob завершает задачу; вызывается под блокировкой
+func (db *DB) finishJob(job models.Job, status, errMsg string) {
+	now := time.Now()
+	job.Status = status
+	job.LockedBy = nil
+	job.LockedUntil = nil
+	job.FinishedAt = &now
+	job.UpdatedAt = now
+	if status == models.JobStatusSucceeded {
+		job.Progress = 100
+	}
+	if errMsg != "" {
+		job.LastError = &errMsg
+	}
+	db.jobs[job.ID] = job
+}
+
+// copyJob отдаёт копию задачи, чтобы вызывающий код не менял payload в хранилище
+func copyJob(job models.Job) *models.Job {
+	job.Payload = bytes.Clone(job.Payload)
+	return &job
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// deadlineLayouts - форматы дедлайна, которые принимает колонка DATETIME в SQL-базах
+var deadlineLayouts = []string{time.DateTime, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", time.DateOnly}
+
+type LabRepository struct {
+	db *DB
+}
+
+func NewLabRepository(db *DB) *LabRepository {
+	return &LabRepository{db: db}
+}
+
+var labSortable = map[string]sortField[models.Lab]{
+	"id":         byKey(func(l models.Lab) int { return l.ID }),
+	"course_id":  byKey(func(l models.Lab) int { return l.CourseID }),
+	"number":     byKey(func(l models.Lab) int { return l.Number }),
+	"title":      byKey(func(l models.Lab) string { return l.Title }),
+	"deadline":   byTime(func(l models.Lab) *time.Time { return l.Deadline }),
+	"max_score":  byKey(func(l models.Lab) int { return l.MaxScore }),
+	"created_at": byTime(func(l models.Lab) *time.Time { return &l.CreatedAt }),
+	"updated_at": byTime(func(l models.Lab) *time.Time { return &l.UpdatedAt }),
+}
+
+func (r *LabRepository) GetAll(ctx context.Context, filter repository.LabFilter, opts repository.ListOptions) ([]models.Lab, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	labs := filterRows(r.db.labs, func(l models.Lab) bool {
+		if filter.CourseID != nil && l.CourseID != *filter.CourseID {
+			return false
+		}
+		// Сравнение с NULL в SQL ложно, поэтому лабораторные без дедлайна не попадают в диапазон
+		if filter.DeadlineFrom != nil && (l.Deadline == nil || l.Deadline.Before(*filter.DeadlineFrom)) {
+			return false
+		}
+		if filter.DeadlineTo != nil && (l.Deadline == nil || l.Deadline.After(*filter.DeadlineTo)) {
+			return false
+		}
+		if filter.Status != "" && l.Status != filter.Status {
+			return false
+		}
+		return true
+	})
+
+	page, err := listPage(labs, opts, labSortable, "course_id,number")
+	return page, len(labs), err
+}
+
+func (r *LabRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error) {
+	labs, _, err := r.GetAll(ctx, repository.LabFilter{CourseID: &courseID}, repository.ListOptions{})
+	return labs, err
+}
+
+func (r *LabRepository) GetByID(ctx context.Context, id int) (*models.Lab, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	l, ok := r.db.labs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &l, nil
+}
+
+func (r *LabRepository) Create(ctx context.Context, courseID, number, maxScore int, title, description, githubURL string, deadline *string, status string, publishAt *time.Time) (*models.Lab, error) {
+	parsed, err := parseDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	l := r.db.insertLab(models.Lab{
+		CourseID:    courseID,
+		Number:      number,
+		Title:       title,
+		Description: description,
+		Deadline:    parsed,
+		MaxScore:    maxScore,
+		GithubURL:   &githubURL,
+		Status:      status,
+		PublishAt:   publishAt,
+	})
+	return &l, nil
+}
+
+// insertLab вставляет лабораторную с новым id; вызывается под блокировкой
+func (db *DB) insertLab(l models.Lab) models.Lab {
+	now := time.Now()
+	l.ID = db.nextID("labs")
+	l.Status = cmp.Or(l.Status, models.PublicationStatusPublished)
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	db.labs[l.ID] = l
+	return l
+}
+
+func (r *LabRepository) Update(ctx context.Context, id, courseID, number, maxScore int, title, description, githubURL string, deadline *string) error {
+	parsed, err := parseDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	l, ok := r.db.labs[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if err := r.db.requireCourse(courseID); err != nil {
+		return err
+	}
+
+	l.CourseID = courseID
+	l.Number = number
+	l.Title = title
+	l.Description = description
+	l.Deadline = parsed
+	l.MaxScore = maxScore
+	l.GithubURL = &githubURL
+	l.UpdatedAt = time.Now()
+	r.db.labs[id] = l
+	return nil
+}
+
+func (r *LabRepository) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	l, ok := r.db.labs[id]
+	if !ok {
+		return nil
+	}
+	l.Status = status
+	l.PublishAt = publishAt
+	l.UpdatedAt = time.Now()
+	r.db.labs[id] = l
+	return nil
+}
+
+func (r *LabRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	trashRows(r.db.labs, r.db.trashedLabs, time.Now(), func(l models.Lab) bool { return l.ID == id })
+	return nil
+}
+
+func parseDeadline(deadline *string) (*time.Time, error) {
+	if deadline == nil || *deadline == "" {
+		return nil, nil
+	}
+	for _, layout := range deadlineLayouts {
+		if t, err := time.ParseInLocation(layout, *deadline, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid deadline %q", *deadline)
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type LectureRepository struct {
+	db *DB
+}
+
+func NewLectureRepository(db *DB) *LectureRepository {
+	return &LectureRepository{db: db}
+}
+
+var lectureSortable = map[string]sortField[models.Lecture]{
+	"id":         byKey(func(l models.Lecture) int { return l.ID }),
+	"course_id":  byKey(func(l models.Lecture) int { return l.CourseID }),
+	"week":       byKey(func(l models.Lecture) int { return l.Week }),
+	"title":      byKey(func(l models.Lecture) string { return l.Title }),
+	"created_at": byTime(func(l models.Lecture) *time.Time { return &l.CreatedAt }),
+	"updated_at": byTime(func(l models.Lecture) *time.Time { return &l.UpdatedAt }),
+}
+
+func (r *LectureRepository) GetAll(ctx context.Context, filter repository.LectureFilter, opts repository.ListOptions) ([]models.Lecture, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	lectures := filterRows(r.db.lectures, func(l models.Lecture) bool {
+		if filter.CourseID != nil && l.CourseID != *filter.CourseID {
+			return false
+		}
+		if filter.Week != nil && l.Week != *filter.Week {
+			return false
+		}
+		if filter.Status != "" && l.Status != filter.Status {
+			return false
+		}
+		return true
+	})
+
+	page, err := listPage(lectures, opts, lectureSortable, "course_id,week")
+	return page, len(lectures), err
+}
+
+func (r *LectureRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error) {
+	lectures, _, err := r.GetAll(ctx, repository.LectureFilter{CourseID: &courseID}, repository.ListOptions{})
+	return lectures, err
+}
+
+func (r *LectureRepository) GetByID(ctx context.Context, id int) (*models.Lecture, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	l, ok := r.db.lectures[id]
+	if !ok {
+		return nil, nil
+	}
+	return &l, nil
+}
+
+func (r *LectureRepository) Create(ctx context.Context, courseID, week int, title, content, githubURL, status string, publishAt *time.Time) (*models.Lecture, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	l := r.db.insertLecture(models.Lecture{
+		CourseID:  courseID,
+		Week:      week,
+		Title:     title,
+		Content:   content,
+		GithubURL: &githubURL,
+		Status:    status,
+		PublishAt: publishAt,
+	})
+	return &l, nil
+}
+
+// insertLecture вставляет лекцию с новым id; вызывается под блокировкой
+func (db *DB) insertLecture(l models.Lecture) models.Lecture {
+	now := time.Now()
+	l.ID = db.nextID("lectures")
+	l.Status = cmp.Or(l.Status, models.PublicationStatusPublished)
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	db.lectures[l.ID] = l
+	return l
+}
+
+func (r *LectureRepository) Update(ctx context.Context, id, courseID, week int, title, content, githubURL string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	l, ok := r.db.lectures[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if err := r.db.requireCourse(courseID); err != nil {
+		return err
+	}
+
+	l.CourseID = courseID
+	l.Week = week
+	l.Title = title
+	l.Content = content
+	l.GithubURL = &githubURL
+	l.UpdatedAt = time.Now()
+	r.db.lectures[id] = l
+	return nil
+}
+
+func (r *LectureRepository) SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	l, ok := r.db.lectures[id]
+	if !ok {
+		return nil
+	}
+	l.Status = status
+	l.PublishAt = publishAt
+	l.UpdatedAt = time.Now()
+	r.db.lectures[id] = l
+	return nil
+}
+
+func (r *LectureRepository) Delete(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	trashRows(r.db.lectures, r.db.trashedLectures, time.Now(), func(l models.Lecture) bool { return l.ID == id })
+	return nil
+}
@@ -0,0 +1,385 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCourseRepository_DeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	other, err := store.Courses.Create(ctx, "C#", "2025-2026", "")
+	require.NoError(t, err)
+
+	_, err = store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, other.ID, 1, "Intro", "", "", "", nil)
+	require.NoError(t, err)
+	_, err = store.ExamQuestions.Create(ctx, course.ID, 1, "Основы", "Что такое срез?")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Courses.Delete(ctx, course.ID))
+
+	lectures, total, err := store.Lectures.GetAll(ctx, repository.LectureFilter{}, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, other.ID, lectures[0].CourseID)
+
+	questions, err := store.ExamQuestions.GetByCourseID(ctx, course.ID)
+	require.NoError(t, err)
+	assert.Empty(t, questions)
+
+	_, err = store.Lectures.Create(ctx, course.ID, 2, "Сироты", "", "", "", nil)
+	assert.Error(t, err, "foreign key must be checked")
+}
+
+func TestCourseRepository_Clone(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	source, err := store.Courses.Create(ctx, "Go", "2024-2025", "Осень")
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, source.ID, 1, "Введение", "text", "", "", nil)
+	require.NoError(t, err)
+	deadline := "2024-10-01 23:59:59"
+	_, err = store.Labs.Create(ctx, source.ID, 1, 10, "Калькулятор", "", "", &deadline, "", nil)
+	require.NoError(t, err)
+	_, err = store.GradeSheets.Create(ctx, source.ID, "https://example.com/sheet", "", "", nil)
+	require.NoError(t, err)
+
+	summary, err := store.Courses.Clone(ctx, source.ID, repository.CourseCloneOptions{
+		Semester:          "2025-2026",
+		DeadlineShiftDays: 365,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Go", summary.Course.Name)
+	assert.Equal(t, 1, summary.Lectures)
+	assert.Equal(t, 1, summary.Labs)
+	assert.Equal(t, 0, summary.GradeSheets)
+
+	labs, err := store.Labs.GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, labs, 1)
+	assert.Equal(t, "2025-10-01 23:59:59", labs[0].Deadline.Format(time.DateTime))
+
+	_, err = store.Courses.Clone(ctx, 999, repository.CourseCloneOptions{Semester: "x"})
+	assert.ErrorIs(t, err, repository.ErrCourseNotFound)
+}
+
+func TestListPage(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	for i, title := range []string{"b", "a", "c", "a"} {
+		_, err := store.Lectures.Create(ctx, course.ID, i+1, title, "", "", "", nil)
+		require.NoError(t, err)
+	}
+
+	lectures, total, err := store.Lectures.GetAll(ctx, repository.LectureFilter{}, repository.ListOptions{Sort: "title,-week", Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	var weeks []int
+	for _, l := range lectures {
+		weeks = append(weeks, l.Week)
+	}
+	assert.Equal(t, []int{4, 2, 1}, weeks)
+
+	lectures, _, err = store.Lectures.GetAll(ctx, repository.LectureFilter{}, repository.ListOptions{Offset: 10})
+	require.NoError(t, err)
+	assert.Empty(t, lectures)
+
+	_, _, err = store.Lectures.GetAll(ctx, repository.LectureFilter{}, repository.ListOptions{Sort: "content"})
+	assert.ErrorIs(t, err, repository.ErrInvalidSort)
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(NewDB())
+
+	_, err := users.GetByUsername(ctx, "admin")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	user, err := users.Create(ctx, "admin", "admin@example.com", "hash", models.RoleAdmin)
+	require.NoError(t, err)
+
+	_, err = users.Create(ctx, "admin", "other@example.com", "hash", models.RoleStudent)
+	assert.Error(t, err)
+
+	require.NoError(t, users.SetDisabled(ctx, user.ID, true))
+	found, err := users.GetByUsername(ctx, "admin")
+	require.NoError(t, err)
+	assert.NotNil(t, found.DisabledAt)
+}
+
+func TestJobRepository_LeaseLifecycle(t *testing.T) {
+	ctx := context.Background()
+	jobs := NewJobRepository(NewDB())
+
+	job, err := jobs.Enqueue(ctx, "test", map[string]int{"n": 1}, 1)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"n": 1}`, string(job.Payload))
+
+	leased, err := jobs.Lease(ctx, "w1", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, leased)
+	assert.Equal(t, models.JobStatusRunning, leased.Status)
+	assert.Equal(t, 1, leased.Attempts)
+
+	again, err := jobs.Lease(ctx, "w2", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, again, "leased job must be invisible to other workers")
+
+	require.NoError(t, jobs.SaveResult(ctx, job.ID, "a.docx", "application/octet-stream", []byte("v1")))
+	require.NoError(t, jobs.SaveResult(ctx, job.ID, "a.docx", "application/octet-stream", []byte("v2")))
+	require.ErrorIs(t, jobs.Complete(ctx, job.ID, "w2"), repository.ErrJobLeaseLost, "only the leasing worker finishes the job")
+	require.NoError(t, jobs.Complete(ctx, job.ID, "w1"))
+
+	done, err := jobs.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusSucceeded, done.Status)
+	assert.Equal(t, 100, done.Progress)
+
+	result, err := jobs.GetResult(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), result.Content)
+}
+
+func TestJobRepository_ExpiredLastAttemptIsBuried(t *testing.T) {
+	ctx := context.Background()
+	jobs := NewJobRepository(NewDB())
+
+	job, err := jobs.Enqueue(ctx, "test", nil, 1)
+	require.NoError(t, err)
+
+	_, err = jobs.Lease(ctx, "w1", -time.Second)
+	require.NoError(t, err)
+
+	leased, err := jobs.Lease(ctx, "w2", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, leased)
+
+	dead, err := jobs.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusDead, dead.Status)
+}
+
+func TestSearchRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, course.ID, 1, "Горутины", "Каналы и горутины", "", "", nil)
+	require.NoError(t, err)
+	_, err = store.Lectures.Create(ctx, course.ID, 2, "Интерфейсы", "Горутины не нужны", "", "", nil)
+	require.NoError(t, err)
+	_, err = store.ExamQuestions.Create(ctx, course.ID, 1, "Основы", "Что такое срез?")
+	require.NoError(t, err)
+
+	hits, total, err := store.Search.Search(ctx, "+горутин*", nil, nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "Горутины", hits[0].Title, "title match ranks higher")
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+
+	hits, total, err = store.Search.Search(ctx, "+горутин* +каналы*", nil, nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, models.SearchTypeLecture, hits[0].Type)
+
+	_, total, err = store.Search.Search(ctx, "+срез*", nil, []string{models.SearchTypeLab}, 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestTrashRepository_RestoreAndPurge(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	early, err := store.Lectures.Create(ctx, course.ID, 2, "Удалена раньше", "", "", "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Lectures.Delete(ctx, early.ID))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, store.Courses.Delete(ctx, course.ID))
+
+	items, total, err := store.Trash.List(ctx, repository.TrashFilter{}, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total, "materials of a deleted course are hidden behind the course")
+	assert.Equal(t, models.TrashTypeCourse, items[0].Type)
+
+	_, err = store.Trash.Restore(ctx, models.TrashTypeLecture, lecture.ID)
+	assert.ErrorIs(t, err, repository.ErrTrashCourseDeleted)
+
+	summary, err := store.Trash.Restore(ctx, models.TrashTypeCourse, course.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Lectures, "lecture deleted before the course stays in trash")
+
+	got, err := store.Lectures.GetByID(ctx, lecture.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	purged, err := store.Trash.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = store.Trash.Restore(ctx, models.TrashTypeLecture, early.ID)
+	assert.ErrorIs(t, err, repository.ErrTrashItemNotFound)
+}
+
+func TestPublicationRepository_PublishDue(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	due, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	later, err := store.Lectures.Create(ctx, course.ID, 2, "Интерфейсы", "", "", "", nil)
+	require.NoError(t, err)
+	sheet, err := store.GradeSheets.Create(ctx, course.ID, "https://example.com/sheet", "", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusPublished, sheet.Status)
+
+	require.NoError(t, store.Lectures.SetPublication(ctx, due.ID, models.PublicationStatusDraft, &past))
+	require.NoError(t, store.Lectures.SetPublication(ctx, later.ID, models.PublicationStatusDraft, &future))
+	require.NoError(t, store.GradeSheets.SetPublication(ctx, sheet.ID, models.PublicationStatusDraft, &past))
+
+	published, _, err := store.Lectures.GetAll(ctx, repository.LectureFilter{Status: models.PublicationStatusPublished}, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, published)
+
+	items, err := store.Publication.PublishDue(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []models.PublishedItem{
+		{Type: models.TrashTypeLecture, ID: due.ID, CourseID: course.ID, Title: "Введение"},
+		{Type: models.TrashTypeGradeSheet, ID: sheet.ID, CourseID: course.ID, Title: "https://example.com/sheet"},
+	}, items)
+
+	got, err := store.Lectures.GetByID(ctx, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusDraft, got.Status)
+
+	items, err = store.Publication.PublishDue(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, items, "published items are not reported twice")
+}
+
+func TestAttachmentRepository_PurgeDetaches(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	kept, err := store.Lectures.Create(ctx, course.ID, 2, "Интерфейсы", "", "", "", nil)
+	require.NoError(t, err)
+
+	for _, id := range []int{lecture.ID, kept.ID} {
+		_, err := store.Attachments.Create(ctx, &models.Attachment{
+			CourseID: course.ID, LectureID: &id, FileName: "slides.pdf", Size: 100, StorageKey: fmt.Sprintf("k%d", id),
+		})
+		require.NoError(t, err)
+	}
+
+	used, err := store.Attachments.CourseUsage(ctx, course.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), used)
+
+	require.NoError(t, store.Lectures.Delete(ctx, lecture.ID))
+	orphans, err := store.Attachments.ListOrphans(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, orphans, "attachments of trashed lectures are kept")
+
+	_, err = store.Trash.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	orphans, err = store.Attachments.ListOrphans(ctx)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, fmt.Sprintf("k%d", lecture.ID), orphans[0].StorageKey)
+
+	list, err := store.Attachments.ListByLecture(ctx, kept.ID)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestAnnouncementRepository_PurgedCourse(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Go", "2025-2026", "")
+	require.NoError(t, err)
+	kept, err := store.Courses.Create(ctx, "Сети", "2025-2026", "")
+	require.NoError(t, err)
+	for _, id := range []int{course.ID, kept.ID} {
+		_, err := store.Announcements.Create(ctx, id, "Консультация", "В пятницу.", models.AnnouncementKindManual)
+		require.NoError(t, err)
+	}
+	_, err = store.Announcements.Create(ctx, 999, "Консультация", "В пятницу.", models.AnnouncementKindManual)
+	assert.Error(t, err, "announcements need an existing course")
+
+	require.NoError(t, store.Courses.Delete(ctx, course.ID))
+	_, err = store.Trash.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	announcements, total, err := store.Announcements.GetAll(ctx, repository.AnnouncementFilter{}, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, kept.ID, announcements[0].CourseID)
+}
+
+func TestNotificationRepository_DeadlineReminders(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(NewDB())
+
+	course, err := store.Courses.Create(ctx, "Сети", "2026-fall", "")
+	require.NoError(t, err)
+	soon := "2026-10-20 12:00:00"
+	lab, err := store.Labs.Create(ctx, course.ID, 1, 10, "Сокеты", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+	trashed, err := store.Labs.Create(ctx, course.ID, 2, 10, "HTTP", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Labs.Delete(ctx, trashed.ID))
+
+	from := time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC)
+	due, err := store.Notifications.DueDeadlineReminders(ctx, from, from.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, due, 1, "trashed labs are not reminded")
+	assert.Equal(t, lab.ID, due[0].ID)
+
+	require.NoError(t, store.Notifications.MarkDeadlineReminded(ctx, lab.ID, *due[0].Deadline))
+	assert.Error(t, store.Notifications.MarkDeadlineReminded(ctx, 999, *due[0].Deadline), "reminders need an existing lab")
+
+	due, err = store.Notifications.DueDeadlineReminders(ctx, from, from.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	require.NoError(t, store.Courses.SetArchived(ctx, course.ID, true))
+	moved := "2026-10-20 11:00:00"
+	require.NoError(t, store.Labs.Update(ctx, lab.ID, course.ID, 1, 10, "Сокеты", "описание", "", &moved))
+	due, err = store.Notifications.DueDeadlineReminders(ctx, from, from.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, due, "archived courses are not reminded")
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+type NotificationRepository struct {
+	db *DB
+}
+
+func NewNotificationRepository(db *DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID int) (*models.NotificationPreferences, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	p, ok := r.db.notificationPreferences[userID]
+	if !ok {
+		p = models.DefaultNotificationPreferences(userID)
+	}
+	return &p, nil
+}
+
+func (r *NotificationRepository) SetPreferences(ctx context.Context, p *models.NotificationPreferences) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[p.UserID]; !ok {
+		return fmt.Errorf("foreign key violation: user %d does not exist", p.UserID)
+	}
+	r.db.notificationPreferences[p.UserID] = *p
+	return nil
+}
+
+func (r *NotificationRepository) ListRecipients(ctx context.Context, kind string) ([]models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	enabled := map[string]func(models.NotificationPreferences) bool{
+		models.NotificationNewLab:           func(p models.NotificationPreferences) bool { return p.NewLab },
+		models.NotificationDeadlineReminder: func(p models.NotificationPreferences) bool { return p.DeadlineReminder },
+		models.NotificationGradePosted:      func(p models.NotificationPreferences) bool { return p.GradePosted },
+	}[kind]
+	if enabled == nil {
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	users := filterRows(r.db.users, func(u models.User) bool {
+		if u.Role != models.RoleStudent || u.DisabledAt != nil || u.Email == "" {
+			return false
+		}
+		p, ok := r.db.notificationPreferences[u.ID]
+		return !ok || enabled(p)
+	})
+	slices.SortFunc(users, func(a, b models.User) int { return cmp.Compare(a.ID, b.ID) })
+	return users, nil
+}
+
+func (r *NotificationRepository) DueDeadlineReminders(ctx context.Context, from, to time.Time) ([]models.Lab, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	labs := filterRows(r.db.labs, func(l models.Lab) bool {
+		course, ok := r.db.courses[l.CourseID]
+		if !ok || course.ArchivedAt != nil || l.Status != models.PublicationStatusPublished || l.Deadline == nil {
+			return false
+		}
+		// дедлайн хранится как время на часах, как в колонке без часового пояса
+		deadline := wallClock(*l.Deadline)
+		if !deadline.After(from) || deadline.After(to) {
+			return false
+		}
+		reminded, ok := r.db.deadlineReminders[l.ID]
+		return !ok || !reminded.Equal(*l.Deadline)
+	})
+	slices.SortFunc(labs, func(a, b models.Lab) int {
+		return cmp.Or(a.Deadline.Compare(*b.Deadline), cmp.Compare(a.ID, b.ID))
+	})
+	return labs, nil
+}
+
+func (r *NotificationRepository) MarkDeadlineReminded(ctx context.Context, labID int, deadline time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if !r.db.labExists(labID) {
+		return fmt.Errorf("foreign key violation: lab %d does not exist", labID)
+	}
+	r.db.deadlineReminders[labID] = deadline
+	return nil
+}
+
+// forgetDeadlineReminders повторяет ON DELETE CASCADE для напоминаний об окончательно
+// удалённых лабораторных; вызывается под блокировкой
+func (db *DB) forgetDeadlineReminders() {
+	for labID := range db.deadlineReminders {
+		if !db.labExists(labID) {
+			delete(db.deadlineReminders, labID)
+		}
+	}
+}
+
+// wallClock переносит время на часах в UTC, как его сравнивает SQL с колонкой TIMESTAMP
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
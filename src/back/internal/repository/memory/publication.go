@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// publishRows публикует черновики таблицы, у которых publish_at не позже now; вызывается под блокировкой
+func publishRows[T any](rows map[int]T, itemType string, now time.Time, draft func(T) *time.Time, publish func(*T), describe func(T) (id, courseID int, title string)) []models.PublishedItem {
+	var published []models.PublishedItem
+	for id, row := range rows {
+		if at := draft(row); at == nil || at.After(now) {
+			continue
+		}
+		publish(&row)
+		rows[id] = row
+
+		item := models.PublishedItem{Type: itemType}
+		item.ID, item.CourseID, item.Title = describe(row)
+		published = append(published, item)
+	}
+	slices.SortFunc(published, func(a, b models.PublishedItem) int { return a.ID - b.ID })
+	return published
+}
+
+// gradeSheetTitle повторяет название ведомости из SQL: описание, а без него - ссылка на таблицу
+func gradeSheetTitle(s models.GradeSheet) string {
+	if s.Description != nil && *s.Description != "" {
+		return *s.Description
+	}
+	return s.SheetURL
+}
+
+type PublicationRepository struct {
+	db *DB
+}
+
+func NewPublicationRepository(db *DB) *PublicationRepository {
+	return &PublicationRepository{db: db}
+}
+
+func (r *PublicationRepository) PublishDue(ctx context.Context, now time.Time) ([]models.PublishedItem, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	published := []models.PublishedItem{}
+	published = append(published, publishRows(r.db.lectures, models.TrashTypeLecture, now,
+		func(l models.Lecture) *time.Time { return duePublishAt(l.Status, l.PublishAt) },
+		func(l *models.Lecture) { l.Status = models.PublicationStatusPublished },
+		func(l models.Lecture) (int, int, string) { return l.ID, l.CourseID, l.Title },
+	)...)
+	published = append(published, publishRows(r.db.labs, models.TrashTypeLab, now,
+		func(l models.Lab) *time.Time { return duePublishAt(l.Status, l.PublishAt) },
+		func(l *models.Lab) { l.Status = models.PublicationStatusPublished },
+		func(l models.Lab) (int, int, string) { return l.ID, l.CourseID, l.Title },
+	)...)
+	published = append(published, publishRows(r.db.gradeSheets, models.TrashTypeGradeSheet, now,
+		func(s models.GradeSheet) *time.Time { return duePublishAt(s.Status, s.PublishAt) },
+		func(s *models.GradeSheet) { s.Status = models.PublicationStatusPublished },
+		func(s models.GradeSheet) (int, int, string) { return s.ID, s.CourseID, gradeSheetTitle(s) },
+	)...)
+
+	return published, nil
+}
+
+// duePublishAt возвращает время публикации черновика; у опубликованных материалов его нет
+func duePublishAt(status string, publishAt *time.Time) *time.Time {
+	if status != models.PublicationStatusDraft {
+		return nil
+	}
+	return publishAt
+}
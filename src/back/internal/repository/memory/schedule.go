@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+type ScheduleRepository struct {
+	db *DB
+}
+
+func NewScheduleRepository(db *DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+func (r *ScheduleRepository) Get(ctx context.Context, courseID int) (*models.CourseSchedule, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	s, ok := r.db.schedules[courseID]
+	if !ok {
+		return nil, nil
+	}
+	return copySchedule(s), nil
+}
+
+func (r *ScheduleRepository) Set(ctx context.Context, schedule *models.CourseSchedule) (*models.CourseSchedule, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.db.requireCourse(schedule.CourseID); err != nil {
+		return nil, err
+	}
+
+	s := *copySchedule(*schedule)
+	for i := range s.Slots {
+		s.Slots[i].ID = r.db.nextID("course_timetable_slots")
+	}
+	slices.SortStableFunc(s.Slots, func(a, b models.TimetableSlot) int {
+		return cmp.Or(cmp.Compare(a.Weekday, b.Weekday), cmp.Compare(a.StartTime, b.StartTime), cmp.Compare(a.ID, b.ID))
+	})
+	s.UpdatedAt = time.Now()
+	r.db.schedules[s.CourseID] = s
+	return copySchedule(s), nil
+}
+
+func (r *ScheduleRepository) Delete(ctx context.Context, courseID int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.schedules, courseID)
+	return nil
+}
+
+// copySchedule копирует слоты, чтобы вызывающий код не менял хранимое расписание
+func copySchedule(s models.CourseSchedule) *models.CourseSchedule {
+	s.Slots = append([]models.TimetableSlot{}, s.Slots...)
+	return &s
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+type SearchRepository struct {
+	db *DB
+}
+
+func NewSearchRepository(db *DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search ищет все слова запроса вида "+term* +other*" подстрокой без учёта регистра,
+// как режим SQLite; совпадение в заголовке весит вдвое больше совпадения в тексте
+func (r *SearchRepository) Search(ctx context.Context, booleanQuery string, courseID *int, types []string, limit, offset int) ([]models.SearchHit, int, error) {
+	var terms []string
+	for _, term := range strings.Fields(booleanQuery) {
+		terms = append(terms, strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(term, "+"), "*")))
+	}
+
+	r.db.mu.RLock()
+	var candidates []models.SearchHit
+	for _, l := range r.db.lectures {
+		if l.Status != models.PublicationStatusPublished {
+			continue
+		}
+		candidates = append(candidates, models.SearchHit{Type: models.SearchTypeLecture, ID: l.ID, CourseID: l.CourseID, Title: l.Title, Text: l.Content})
+	}
+	for _, l := range r.db.labs {
+		if l.Status != models.PublicationStatusPublished {
+			continue
+		}
+		candidates = append(candidates, models.SearchHit{Type: models.SearchTypeLab, ID: l.ID, CourseID: l.CourseID, Title: l.Title, Text: l.Description})
+	}
+	for _, q := range r.db.examQuestions {
+		candidates = append(candidates, models.SearchHit{Type: models.SearchTypeExamQuestion, ID: q.ID, CourseID: q.CourseID, Title: q.Section, Text: q.Question})
+	}
+	r.db.mu.RUnlock()
+
+	hits := []models.SearchHit{}
+	for _, h := range candidates {
+		if len(types) > 0 && !slices.Contains(types, h.Type) {
+			continue
+		}
+		if courseID != nil && h.CourseID != *courseID {
+			continue
+		}
+		if score, ok := matchScore(h, terms); ok {
+			h.Score = score
+			hits = append(hits, h)
+		}
+	}
+
+	slices.SortFunc(hits, func(a, b models.SearchHit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+	})
+
+	total := len(hits)
+	hits = hits[min(offset, total):]
+	if limit < len(hits) {
+		hits = hits[:limit]
+	}
+
+	return hits, total, nil
+}
+
+// matchScore возвращает вес совпадения; ok ложно, если хотя бы одно слово не найдено
+func matchScore(h models.SearchHit, terms []string) (float64, bool) {
+	if len(terms) == 0 {
+		return 0, false
+	}
+
+	title := strings.ToLower(h.Title)
+	text := strings.ToLower(h.Text)
+
+	var score float64
+	for _, term := range terms {
+		inTitle := strings.Contains(title, term)
+		inText := strings.Contains(text, term)
+		if !inTitle && !inText {
+			return 0, false
+		}
+		if inTitle {
+			score += 2
+		}
+		if inText {
+			score++
+		}
+	}
+	return score, true
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// trashed - строка в корзине и время её удаления
+type trashed[T any] struct {
+	row       T
+	deletedAt time.Time
+}
+
+// trashRows переносит в корзину строки, для которых match возвращает true; вызывается под блокировкой
+func trashRows[T any](rows map[int]T, trash map[int]trashed[T], at time.Time, match func(T) bool) {
+	for id, row := range rows {
+		if match(row) {
+			trash[id] = trashed[T]{row: row, deletedAt: at}
+			delete(rows, id)
+		}
+	}
+}
+
+// restoreRows возвращает из корзины строки, для которых match возвращает true, и считает их
+func restoreRows[T any](rows map[int]T, trash map[int]trashed[T], match func(trashed[T]) bool) int {
+	restored := 0
+	for id, item := range trash {
+		if match(item) {
+			rows[id] = item.row
+			delete(trash, id)
+			restored++
+		}
+	}
+	return restored
+}
+
+// purgeRows окончательно удаляет строки корзины, для которых match возвращает true, и считает их
+func purgeRows[T any](trash map[int]trashed[T], match func(trashed[T]) bool) int {
+	purged := 0
+	for id, item := range trash {
+		if match(item) {
+			delete(trash, id)
+			purged++
+		}
+	}
+	return purged
+}
+
+type TrashRepository struct {
+	db *DB
+}
+
+func NewTrashRepository(db *DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+var trashSortable = map[string]sortField[models.TrashItem]{
+	"id":         byKey(func(i models.TrashItem) int { return i.ID }),
+	"type":       byKey(func(i models.TrashItem) string { return i.Type }),
+	"title":      byKey(func(i models.TrashItem) string { return i.Title }),
+	"deleted_at": byTime(func(i models.TrashItem) *time.Time { return &i.DeletedAt }),
+}
+
+// trashItems добавляет к items записи корзины одной таблицы; материалы удалённых курсов пропускаются
+func trashItems[T any](db *DB, items []models.TrashItem, itemType string, trash map[int]trashed[T], filter repository.TrashFilter, describe func(T) (id, courseID int, title string)) []models.TrashItem {
+	if filter.Type != "" && filter.Type != itemType {
+		return items
+	}
+
+	for _, t := range trash {
+		id, courseID, title := describe(t.row)
+		if _, courseTrashed := db.trashedCourses[courseID]; courseTrashed && itemType != models.TrashTypeCourse {
+			continue
+		}
+		if filter.CourseID != nil && courseID != *filter.CourseID {
+			continue
+		}
+		items = append(items, models.TrashItem{Type: itemType, ID: id, CourseID: courseID, Title: title, DeletedAt: t.deletedAt})
+	}
+	return items
+}
+
+func (r *TrashRepository) List(ctx context.Context, filter repository.TrashFilter, opts repository.ListOptions) ([]models.TrashItem, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var items []models.TrashItem
+	items = trashItems(r.db, items, models.TrashTypeCourse, r.db.trashedCourses, filter, func(c models.Course) (int, int, string) {
+		return c.ID, c.ID, c.Name
+	})
+	items = trashItems(r.db, items, models.TrashTypeLecture, r.db.trashedLectures, filter, func(l models.Lecture) (int, int, string) {
+		return l.ID, l.CourseID, l.Title
+	})
+	items = trashItems(r.db, items, models.TrashTypeLab, r.db.trashedLabs, filter, func(l models.Lab) (int, int, string) {
+		return l.ID, l.CourseID, l.Title
+	})
+	items = trashItems(r.db, items, models.TrashTypeGradeSheet, r.db.trashedGradeSheets, filter, func(s models.GradeSheet) (int, int, string) {
+		return s.ID, s.CourseID, gradeSheetTitle(s)
+	})
+	items = trashItems(r.db, items, models.TrashTypeExamQuestion, r.db.trashedExamQuestions, filter, func(q models.ExamQuestion) (int, int, string) {
+		return q.ID, q.CourseID, q.Question
+	})
+
+	page, err := listPage(items, opts, trashSortable, "-deleted_at,type")
+	if page == nil && err == nil {
+		page = []models.TrashItem{}
+	}
+	return page, len(items), err
+}
+
+func (r *TrashRepository) Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	summary := &models.TrashRestoreSummary{Type: itemType, ID: id}
+	byID := func(rowID int) bool { return rowID == id }
+
+	if itemType == models.TrashTypeCourse {
+		course, ok := r.db.trashedCourses[id]
+		if !ok {
+			return nil, repository.ErrTrashItemNotFound
+		}
+		withCourse := func(courseID int, deletedAt time.Time) bool {
+			return courseID == id && !deletedAt.Before(course.deletedAt)
+		}
+
+		summary.Lectures = restoreRows(r.db.lectures, r.db.trashedLectures, func(t trashed[models.Lecture]) bool {
+			return withCourse(t.row.CourseID, t.deletedAt)
+		})
+		summary.Labs = restoreRows(r.db.labs, r.db.trashedLabs, func(t trashed[models.Lab]) bool {
+			return withCourse(t.row.CourseID, t.deletedAt)
+		})
+		summary.GradeSheets = restoreRows(r.db.gradeSheets, r.db.trashedGradeSheets, func(t trashed[models.GradeSheet]) bool {
+			return withCourse(t.row.CourseID, t.deletedAt)
+		})
+		summary.ExamQuestions = restoreRows(r.db.examQuestions, r.db.trashedExamQuestions, func(t trashed[models.ExamQuestion]) bool {
+			return withCourse(t.row.CourseID, t.deletedAt)
+		})
+		restoreRows(r.db.courses, r.db.trashedCourses, func(t trashed[models.Course]) bool { return byID(t.row.ID) })
+		return summary, nil
+	}
+
+	var courseID int
+	var restore func()
+	switch itemType {
+	case models.TrashTypeLecture:
+		t, ok := r.db.trashedLectures[id]
+		if !ok {
+			return nil, repository.ErrTrashItemNotFound
+		}
+		courseID = t.row.CourseID
+		restore = func() {
+			restoreRows(r.db.lectures, r.db.trashedLectures, func(t trashed[models.Lecture]) bool { return byID(t.row.ID) })
+		}
+	case models.TrashTypeLab:
+		t, ok := r.db.trashedLabs[id]
+		if !ok {
+			return nil, repository.ErrTrashItemNotFound
+		}
+		courseID = t.row.CourseID
+		restore = func() {
+			restoreRows(r.db.labs, r.db.trashedLabs, func(t trashed[models.Lab]) bool { return byID(t.row.ID) })
+		}
+	case models.TrashTypeGradeSheet:
+		t, ok := r.db.trashedGradeSheets[id]
+		if !ok {
+			return nil, repository.ErrTrashItemNotFound
+		}
+		courseID = t.row.CourseID
+		restore = func() {
+			restoreRows(r.db.gradeSheets, r.db.trashedGradeSheets, func(t trashed[models.GradeSheet]) bool { return byID(t.row.ID) })
+		}
+	case models.TrashTypeExamQuestion:
+		t, ok := r.db.trashedExamQuestions[id]
+		if !ok {
+			return nil, repository.ErrTrashItemNotFound
+		}
+		courseID = t.row.CourseID
+		restore = func() {
+			restoreRows(r.db.examQuestions, r.db.trashedExamQuestions, func(t trashed[models.ExamQuestion]) bool { return byID(t.row.ID) })
+		}
+	default:
+		return nil, repository.ErrTrashItemNotFound
+	}
+
+	if _, ok := r.db.courses[courseID]; !ok {
+		return nil, repository.ErrTrashCourseDeleted
+	}
+	restore()
+	return summary, nil
+}
+
+// Purge повторяет порядок SQL-версии: сначала материалы, затем курсы с каскадным удалением
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	purged := purgeRows(r.db.trashedLectures, func(t trashed[models.Lecture]) bool { return t.deletedAt.Before(before) })
+	purged += purgeRows(r.db.trashedLabs, func(t trashed[models.Lab]) bool { return t.deletedAt.Before(before) })
+	purged += purgeRows(r.db.trashedGradeSheets, func(t trashed[models.GradeSheet]) bool { return t.deletedAt.Before(before) })
+	purged += purgeRows(r.db.trashedExamQuestions, func(t trashed[models.ExamQuestion]) bool { return t.deletedAt.Before(before) })
+
+	for id, course := range r.db.trashedCourses {
+		if !course.deletedAt.Before(before) {
+			continue
+		}
+		delete(r.db.trashedCourses, id)
+		purged++
+
+		inCourse := func(courseID int) bool { return courseID == id }
+		purgeRows(r.db.trashedLectures, func(t trashed[models.Lecture]) bool { return inCourse(t.row.CourseID) })
+		purgeRows(r.db.trashedLabs, func(t trashed[models.Lab]) bool { return inCourse(t.row.CourseID) })
+		purgeRows(r.db.trashedGradeSheets, func(t trashed[models.GradeSheet]) bool { return inCourse(t.row.CourseID) })
+		purgeRows(r.db.trashedExamQuestions, func(t trashed[models.ExamQuestion]) bool { return inCourse(t.row.CourseID) })
+		deleteRows(r.db.contentSyncEvents, func(e models.ContentSyncEvent) bool { return inCourse(e.CourseID) })
+		delete(r.db.schedules, id)
+		deleteRows(r.db.announcements, func(a models.Announcement) bool { return inCourse(a.CourseID) })
+	}
+	r.db.detachAttachments()
+	r.db.forgetDeadlineReminders()
+
+	return purged, nil
+}
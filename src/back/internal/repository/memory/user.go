@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+var userSortable = map[string]sortField[models.User]{
+	"id":         byKey(func(u models.User) int { return u.ID }),
+	"username":   byKey(func(u models.User) string { return u.Username }),
+	"email":      byKey(func(u models.User) string { return u.Email }),
+	"role":       byKey(func(u models.User) string { return u.Role }),
+	"created_at": byTime(func(u models.User) *time.Time { return &u.CreatedAt }),
+}
+
+// GetByUsername возвращает sql.ErrNoRows, если пользователя нет, как и SQL-репозиторий
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, u := range r.db.users {
+		if u.Username == username {
+			return &u, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetByID возвращает nil, если пользователя нет
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	u, ok := r.db.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) GetAll(ctx context.Context, filter repository.UserFilter, opts repository.ListOptions) ([]models.User, int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	users := filterRows(r.db.users, func(u models.User) bool {
+		return filter.Role == "" || u.Role == filter.Role
+	})
+
+	page, err := listPage(users, opts, userSortable, "username")
+	return page, len(users), err
+}
+
+// Create проверяет уникальность username и email, как уникальные индексы таблицы users
+func (r *UserRepository) Create(ctx context.Context, username, email, passwordHash, role string) (*models.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, u := range r.db.users {
+		if u.Username == username || strings.EqualFold(u.Email, email) {
+			return nil, fmt.Errorf("failed to create user: duplicate username or email")
+		}
+	}
+
+	now := time.Now()
+	u := models.User{
+		ID:           r.db.nextID("users"),
+		Email:        email,
+		Username:     username,
+		PasswordHash: passwordHash,
+		Role:         role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	r.db.users[u.ID] = u
+	return &u, nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id int, role string) error {
+	return r.update(id, func(u *models.User) { u.Role = role })
+}
+
+func (r *UserRepository) SetPasswordHash(ctx context.Context, id int, passwordHash string) error {
+	return r.update(id, func(u *models.User) { u.PasswordHash = passwordHash })
+}
+
+func (r *UserRepository) SetDisabled(ctx context.Context, id int, disabled bool) error {
+	return r.update(id, func(u *models.User) {
+		u.DisabledAt = nil
+		if disabled {
+			now := time.Now()
+			u.DisabledAt = &now
+		}
+	})
+}
+
+func (r *UserRepository) update(id int, apply func(u *models.User)) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u, ok := r.db.users[id]
+	if !ok {
+		return nil
+	}
+	apply(&u)
+	u.UpdatedAt = time.Now()
+	r.db.users[id] = u
+	return nil
+}
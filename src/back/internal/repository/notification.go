@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// notificationColumns - колонки notification_preferences по видам уведомлений
+var notificationColumns = map[string]string{
+	models.NotificationNewLab:           "new_lab",
+	models.NotificationDeadlineReminder: "deadline_reminder",
+	models.NotificationGradePosted:      "grade_posted",
+}
+
+type NotificationRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewNotificationRepository(db *database.DB) *NotificationRepository {
+	return &NotificationRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+// GetPreferences возвращает настройки пользователя; если он их не менял - настройки по умолчанию
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID int) (*models.NotificationPreferences, error) {
+	query, args, err := r.sb.Select("user_id", "new_lab", "deadline_reminder", "grade_posted").
+		From("notification_preferences").
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var p models.NotificationPreferences
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&p.UserID, &p.NewLab, &p.DeadlineReminder, &p.GradePosted)
+	if errors.Is(err, sql.ErrNoRows) {
+		p = models.DefaultNotificationPreferences(userID)
+		return &p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return &p, nil
+}
+
+// SetPreferences сохраняет настройки пользователя целиком
+func (r *NotificationRepository) SetPreferences(ctx context.Context, p *models.NotificationPreferences) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.sb.Select("COUNT(*)").
+		From("notification_preferences").
+		Where(sq.Eq{"user_id": p.UserID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check notification preferences: %w", err)
+	}
+
+	var upsert sq.Sqlizer = r.sb.Insert("notification_preferences").
+		Columns("user_id", "new_lab", "deadline_reminder", "grade_posted").
+		Values(p.UserID, p.NewLab, p.DeadlineReminder, p.GradePosted)
+	if exists > 0 {
+		upsert = r.sb.Update("notification_preferences").
+			Set("new_lab", p.NewLab).
+			Set("deadline_reminder", p.DeadlineReminder).
+			Set("grade_posted", p.GradePosted).
+			Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+			Where(sq.Eq{"user_id": p.UserID})
+	}
+	if err := execTx(ctx, tx, upsert, "failed to save notification preferences"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListRecipients возвращает активных студентов с адресом, не отключивших уведомления вида kind, в порядке id
+func (r *NotificationRepository) ListRecipients(ctx context.Context, kind string) ([]models.User, error) {
+	column, ok := notificationColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	columns := make([]string, len(userColumns))
+	for i, c := range userColumns {
+		columns[i] = "u." + c
+	}
+
+	query, args, err := r.sb.Select(columns...).
+		From("users u").
+		LeftJoin("notification_preferences p ON p.user_id = u.id").
+		Where(sq.Eq{"u.role": models.RoleStudent, "u.disabled_at": nil}).
+		Where(sq.NotEq{"u.email": ""}).
+		Where(sq.Or{sq.Eq{"p.id": nil}, sq.Eq{"p." + column: true}}).
+		OrderBy("u.id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error for recipient: %w", err)
+		}
+		users = append(users, *user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recipients: %w", err)
+	}
+
+	return users, nil
+}
+
+// DueDeadlineReminders возвращает опубликованные лабораторные действующих курсов с дедлайном
+// в (from, to], о текущем дедлайне которых ещё не напоминали, в порядке дедлайна
+func (r *NotificationRepository) DueDeadlineReminders(ctx context.Context, from, to time.Time) ([]models.Lab, error) {
+	query, args, err := r.sb.Select("l.id", "l.course_id", "l.number", "l.title", "l.description", "l.deadline",
+		"l.max_score", "l.github_url", "l.status", "l.publish_at", "l.created_at", "l.updated_at", "r.deadline").
+		From("labs l").
+		Join("courses c ON c.id = l.course_id").
+		LeftJoin("lab_deadline_reminders r ON r.lab_id = l.id").
+		Where(sq.Eq{"l.status": models.PublicationStatusPublished, "l.deleted_at": nil, "c.deleted_at": nil, "c.archived_at": nil}).
+		Where(sq.Gt{"l.deadline": from}).
+		Where(sq.LtOrEq{"l.deadline": to}).
+		OrderBy("l.deadline", "l.id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	var labs []models.Lab
+	for rows.Next() {
+		var l models.Lab
+		var reminded *time.Time
+		if err := rows.Scan(&l.ID, &l.CourseID, &l.Number, &l.Title, &l.Description, &l.Deadline, &l.MaxScore,
+			&l.GithubURL, &l.Status, &l.PublishAt, &l.CreatedAt, &l.UpdatedAt, &reminded); err != nil {
+			return nil, fmt.Errorf("scan error for due deadline: %w", err)
+		}
+		// дедлайны сравниваются здесь, а не в SQL: SQLite хранит время строками разного вида
+		if reminded != nil && reminded.Equal(*l.Deadline) {
+			continue
+		}
+		labs = append(labs, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read due deadlines: %w", err)
+	}
+
+	return labs, nil
+}
+
+// MarkDeadlineReminded запоминает, что о дедлайне deadline лабораторной напомнили
+func (r *NotificationRepository) MarkDeadlineReminded(ctx context.Context, labID int, deadline time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := execTx(ctx, tx, r.sb.Delete("lab_deadline_reminders").
+		Where(sq.Eq{"lab_id": labID}), "failed to delete reminder"); err != nil {
+		return err
+	}
+	if err := execTx(ctx, tx, r.sb.Insert("lab_deadline_reminders").
+		Columns("lab_id", "deadline").
+		Values(labID, deadline), "failed to save reminder"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepository_Preferences(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewNotificationRepository(db)
+	users := NewUserRepository(db)
+
+	anna, err := users.Create(ctx, "anna", "anna@example.com", "hash", models.RoleStudent)
+	require.NoError(t, err)
+	quiet, err := users.Create(ctx, "quiet", "quiet@example.com", "hash", models.RoleStudent)
+	require.NoError(t, err)
+	_, err = users.Create(ctx, "noemail", "", "hash", models.RoleStudent)
+	require.NoError(t, err)
+	_, err = users.Create(ctx, "admin", "admin@example.com", "hash", models.RoleAdmin)
+	require.NoError(t, err)
+
+	prefs, err := repo.GetPreferences(ctx, anna.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultNotificationPreferences(anna.ID), *prefs, "defaults until the user changes them")
+
+	require.NoError(t, repo.SetPreferences(ctx, &models.NotificationPreferences{UserID: quiet.ID, GradePosted: true}))
+	require.NoError(t, repo.SetPreferences(ctx, &models.NotificationPreferences{UserID: quiet.ID, NewLab: true, GradePosted: true}))
+	prefs, err = repo.GetPreferences(ctx, quiet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.NotificationPreferences{UserID: quiet.ID, NewLab: true, GradePosted: true}, *prefs)
+
+	recipients := func(kind string) []string {
+		t.Helper()
+		list, err := repo.ListRecipients(ctx, kind)
+		require.NoError(t, err)
+		var names []string
+		for _, u := range list {
+			names = append(names, u.Username)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"anna", "quiet"}, recipients(models.NotificationNewLab))
+	assert.Equal(t, []string{"anna"}, recipients(models.NotificationDeadlineReminder))
+
+	require.NoError(t, users.SetDisabled(ctx, anna.ID, true))
+	assert.Equal(t, []string{"quiet"}, recipients(models.NotificationGradePosted), "disabled users get no email")
+
+	_, err = repo.ListRecipients(ctx, "digest")
+	assert.Error(t, err)
+}
+
+func TestNotificationRepository_DeadlineReminders(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewNotificationRepository(db)
+	labs := NewLabRepository(db)
+	courseID := createTestCourse(t, db, "Сети", "2026-fall")
+	archivedID := createTestCourse(t, db, "Архив", "2025-fall")
+	require.NoError(t, NewCourseRepository(db).SetArchived(ctx, archivedID, true))
+
+	soon := "2026-10-20 12:00:00"
+	later := "2026-10-22 12:00:00"
+	due, err := labs.Create(ctx, courseID, 1, 10, "Сокеты", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+	_, err = labs.Create(ctx, courseID, 2, 10, "HTTP", "описание", "", &later, "", nil)
+	require.NoError(t, err)
+	draft, err := labs.Create(ctx, courseID, 3, 10, "TLS", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, labs.SetPublication(ctx, draft.ID, models.PublicationStatusDraft, nil))
+	_, err = labs.Create(ctx, archivedID, 1, 10, "Старая", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+
+	from := time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	list, err := repo.DueDeadlineReminders(ctx, from, to)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, due.ID, list[0].ID)
+
+	require.NoError(t, repo.MarkDeadlineReminded(ctx, due.ID, *list[0].Deadline))
+	list, err = repo.DueDeadlineReminders(ctx, from, to)
+	require.NoError(t, err)
+	assert.Empty(t, list, "reminded deadline is skipped")
+
+	moved := "2026-10-20 12:30:00"
+	require.NoError(t, labs.Update(ctx, due.ID, courseID, 1, 10, "Сокеты", "описание", "", &moved))
+	list, err = repo.DueDeadlineReminders(ctx, from, to)
+	require.NoError(t, err)
+	require.Len(t, list, 1, "moved deadline is reminded again")
+
+	require.NoError(t, repo.MarkDeadlineReminded(ctx, due.ID, *list[0].Deadline))
+	list, err = repo.DueDeadlineReminders(ctx, from, to)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
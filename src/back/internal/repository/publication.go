@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// publicationTypes - материалы со статусом публикации; таблица и название берутся из trashSources
+var publicationTypes = []string{models.TrashTypeLecture, models.TrashTypeLab, models.TrashTypeGradeSheet}
+
+// publicationValues возвращает статус и время публикации для записи в базу;
+// пустой статус означает опубликованный материал
+func publicationValues(status string, publishAt *time.Time) (string, any) {
+	var at any
+	if publishAt != nil {
+		at = publishAt.UTC()
+	}
+	return cmp.Or(status, models.PublicationStatusPublished), at
+}
+
+// setPublication меняет статус и время публикации материала, не удалённого в корзину
+func setPublication(ctx context.Context, db querier, sb sq.StatementBuilderType, table string, id int, status string, publishAt *time.Time) error {
+	status, at := publicationValues(status, publishAt)
+
+	query, args, err := sb.Update(table).
+		Set("status", status).
+		Set("publish_at", at).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to set publication of %s: %w", table, err)
+	}
+	return nil
+}
+
+type PublicationRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewPublicationRepository(db *database.DB) *PublicationRepository {
+	return &PublicationRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+// PublishDue публикует черновики, у которых publish_at не позже now, и возвращает их
+// в порядке типов и id. Черновики без publish_at остаются черновиками.
+func (r *PublicationRepository) PublishDue(ctx context.Context, now time.Time) ([]models.PublishedItem, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	published := []models.PublishedItem{}
+	for _, itemType := range publicationTypes {
+		src, _ := findTrashSource(itemType)
+		due := sq.Eq{"t.status": models.PublicationStatusDraft, "t.deleted_at": nil}
+
+		query, args, err := r.sb.Select("t.id", "t.course_id", src.title).
+			From(src.table + " t").
+			Where(due).
+			Where(sq.LtOrEq{"t.publish_at": now.UTC()}).
+			OrderBy("t.id").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get due %s: %w", src.table, err)
+		}
+
+		var ids []int
+		for rows.Next() {
+			item := models.PublishedItem{Type: itemType}
+			if err := rows.Scan(&item.ID, &item.CourseID, &item.Title); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan error for due %s: %w", src.table, err)
+			}
+			published = append(published, item)
+			ids = append(ids, item.ID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get due %s: %w", src.table, err)
+		}
+
+		if len(ids) == 0 {
+			continue
+		}
+
+		query, args, err = r.sb.Update(src.table).
+			Set("status", models.PublicationStatusPublished).
+			Where(sq.Eq{"id": ids}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return nil, fmt.Errorf("failed to publish %s: %w", src.table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return published, nil
+}
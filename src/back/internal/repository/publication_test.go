@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicationRepository_PublishDue(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	lectures := NewLectureRepository(db)
+	labs := NewLabRepository(db)
+	sheets := NewGradeSheetRepository(db)
+	courseID := createTestCourse(t, db, "Сети", "2026-fall")
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+
+	lecture, err := lectures.Create(ctx, courseID, 1, "Модель OSI", "текст", "", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusPublished, lecture.Status)
+	require.NoError(t, lectures.SetPublication(ctx, lecture.ID, models.PublicationStatusDraft, &past))
+
+	lab, err := labs.Create(ctx, courseID, 1, 10, "Wireshark", "описание", "", nil, models.PublicationStatusDraft, &future)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusDraft, lab.Status)
+
+	sheet, err := sheets.Create(ctx, courseID, "https://example.com/sheet", "Оценки", models.PublicationStatusDraft, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusDraft, sheet.Status)
+
+	drafts, total, err := lectures.GetAll(ctx, LectureFilter{Status: models.PublicationStatusDraft}, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.NotNil(t, drafts[0].PublishAt)
+	assert.True(t, drafts[0].PublishAt.Equal(past))
+
+	published, err := NewPublicationRepository(db).PublishDue(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, published, 1)
+	assert.Equal(t, models.PublishedItem{Type: models.TrashTypeLecture, ID: lecture.ID, CourseID: courseID, Title: "Модель OSI"}, published[0])
+
+	got, err := lectures.GetByID(ctx, lecture.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusPublished, got.Status)
+
+	gotLab, err := labs.GetByID(ctx, lab.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusDraft, gotLab.Status, "publish_at is still ahead")
+
+	gotSheet, err := sheets.GetByID(ctx, sheet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationStatusDraft, gotSheet.Status, "drafts without publish_at stay drafts")
+
+	published, err = NewPublicationRepository(db).PublishDue(ctx, future)
+	require.NoError(t, err)
+	require.Len(t, published, 1)
+	assert.Equal(t, models.TrashTypeLab, published[0].Type)
+}
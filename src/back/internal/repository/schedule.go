@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// scheduleDateLayout - формат даты начала курса в API и при записи в БД
+const scheduleDateLayout = "2006-01-02"
+
+type ScheduleRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewScheduleRepository(db *database.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+// Get возвращает расписание курса со слотами по дням недели и времени; nil, если расписания нет
+func (r *ScheduleRepository) Get(ctx context.Context, courseID int) (*models.CourseSchedule, error) {
+	query, args, err := r.sb.Select("course_id", "start_date", "updated_at").
+		From("course_schedules").
+		Where(sq.Eq{"course_id": courseID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var schedule models.CourseSchedule
+	var startDate time.Time
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&schedule.CourseID, &startDate, &schedule.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	schedule.StartDate = startDate.Format(scheduleDateLayout)
+
+	query, args, err = r.sb.Select("id", "weekday", "start_time", "duration_minutes", "room").
+		From("course_timetable_slots").
+		Where(sq.Eq{"course_id": courseID}).
+		OrderBy("weekday", "start_time", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timetable slots: %w", err)
+	}
+	defer rows.Close()
+
+	schedule.Slots = []models.TimetableSlot{}
+	for rows.Next() {
+		var slot models.TimetableSlot
+		if err := rows.Scan(&slot.ID, &slot.Weekday, &slot.StartTime, &slot.DurationMinutes, &slot.Room); err != nil {
+			return nil, fmt.Errorf("scan error timetable slot: %w", err)
+		}
+		schedule.Slots = append(schedule.Slots, slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read timetable slots: %w", err)
+	}
+
+	return &schedule, nil
+}
+
+// Set заменяет дату начала и все слоты расписания курса в одной транзакции
+func (r *ScheduleRepository) Set(ctx context.Context, schedule *models.CourseSchedule) (*models.CourseSchedule, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.sb.Select("COUNT(*)").
+		From("course_schedules").
+		Where(sq.Eq{"course_id": schedule.CourseID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check schedule: %w", err)
+	}
+
+	var upsert sq.Sqlizer = r.sb.Insert("course_schedules").
+		Columns("course_id", "start_date").
+		Values(schedule.CourseID, schedule.StartDate)
+	if exists > 0 {
+		// updated_at меняется явно: в MySQL UPDATE с той же датой не трогает строку
+		upsert = r.sb.Update("course_schedules").
+			Set("start_date", schedule.StartDate).
+			Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+			Where(sq.Eq{"course_id": schedule.CourseID})
+	}
+	if err := execTx(ctx, tx, upsert, "failed to save schedule"); err != nil {
+		return nil, err
+	}
+
+	if err := execTx(ctx, tx, r.sb.Delete("course_timetable_slots").
+		Where(sq.Eq{"course_id": schedule.CourseID}), "failed to delete timetable slots"); err != nil {
+		return nil, err
+	}
+
+	if len(schedule.Slots) > 0 {
+		insert := r.sb.Insert("course_timetable_slots").
+			Columns("course_id", "weekday", "start_time", "duration_minutes", "room")
+		for _, slot := range schedule.Slots {
+			insert = insert.Values(schedule.CourseID, slot.Weekday, slot.StartTime, slot.DurationMinutes, slot.Room)
+		}
+		if err := execTx(ctx, tx, insert, "failed to create timetable slots"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	saved, err := r.Get(ctx, schedule.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved schedule: %w", err)
+	}
+	if saved == nil {
+		return nil, fmt.Errorf("saved schedule not found")
+	}
+
+	return saved, nil
+}
+
+// Delete удаляет расписание курса вместе со слотами
+func (r *ScheduleRepository) Delete(ctx context.Context, courseID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"course_timetable_slots", "course_schedules"} {
+		if err := execTx(ctx, tx, r.sb.Delete(table).Where(sq.Eq{"course_id": courseID}), "failed to delete "+table); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func execTx(ctx context.Context, tx *sql.Tx, builder sq.Sqlizer, message string) error {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewScheduleRepository(db)
+	courseID := createTestCourse(t, db, "Сети", "2026-fall")
+
+	schedule, err := repo.Get(ctx, courseID)
+	require.NoError(t, err)
+	assert.Nil(t, schedule)
+
+	saved, err := repo.Set(ctx, &models.CourseSchedule{
+		CourseID:  courseID,
+		StartDate: "2026-09-01",
+		Slots: []models.TimetableSlot{
+			{Weekday: 4, StartTime: "10:00", DurationMinutes: 90, Room: "305"},
+			{Weekday: 2, StartTime: "13:30", DurationMinutes: 90, Room: "101"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09-01", saved.StartDate)
+	require.Len(t, saved.Slots, 2)
+	assert.Equal(t, 2, saved.Slots[0].Weekday, "slots are ordered by weekday")
+	assert.Equal(t, "305", saved.Slots[1].Room)
+
+	saved, err = repo.Set(ctx, &models.CourseSchedule{CourseID: courseID, StartDate: "2026-09-08"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09-08", saved.StartDate)
+	assert.Empty(t, saved.Slots, "slots are replaced")
+
+	require.NoError(t, repo.Delete(ctx, courseID))
+	schedule, err = repo.Get(ctx, courseID)
+	require.NoError(t, err)
+	assert.Nil(t, schedule)
+}
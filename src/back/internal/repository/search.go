@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+type SearchRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewSearchRepository(db *database.DB) *SearchRepository {
+	return &SearchRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+// searchSource описывает таблицу, участвующую в полнотекстовом поиске
+type searchSource struct {
+	hitType     string
+	table       string
+	titleColumn string
+	textColumn  string
+	// drafts - у таблицы есть статус публикации, и черновики не ищутся
+	drafts bool
+}
+
+var searchSources = []searchSource{
+	{hitType: models.SearchTypeLecture, table: "lectures", titleColumn: "title", textColumn: "content", drafts: true},
+	{hitType: models.SearchTypeLab, table: "labs", titleColumn: "title", textColumn: "description", drafts: true},
+	{hitType: models.SearchTypeExamQuestion, table: "exam_questions", titleColumn: "section", textColumn: "question"},
+}
+
+// Search выполняет поиск в режиме BOOLEAN MODE по FULLTEXT индексам.
+// booleanQuery должен быть уже подготовлен (см. services.BuildBooleanQuery);
+// в PostgreSQL он переводится в tsquery с тем же префиксным поиском, в SQLite - в LIKE.
+func (r *SearchRepository) Search(ctx context.Context, booleanQuery string, courseID *int, types []string, limit, offset int) ([]models.SearchHit, int, error) {
+	union, args, err := r.buildUnion(booleanQuery, courseID, types)
+	if err != nil {
+		return nil, 0, err
+	}
+	if union == "" {
+		return []models.SearchHit{}, 0, nil
+	}
+
+	var total int
+	countQuery, err := r.db.Dialect.Rebind("SELECT COUNT(*) FROM (" + union + ") AS hits")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	query, pageArgs, err := r.sb.Select("type", "id", "course_id", "title", "body", "score").
+		From("("+union+") AS hits").
+		OrderBy("score DESC", "type", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var h models.SearchHit
+		if err := rows.Scan(&h.Type, &h.ID, &h.CourseID, &h.Title, &h.Text, &h.Score); err != nil {
+			return nil, 0, fmt.Errorf("scan error search hit: %w", err)
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read search results: %w", err)
+	}
+
+	return hits, total, nil
+}
+
+func (r *SearchRepository) buildUnion(booleanQuery string, courseID *int, types []string) (string, []any, error) {
+	var parts []string
+	var args []any
+
+	for _, src := range searchSources {
+		if len(types) > 0 && !slices.Contains(types, src.hitType) {
+			continue
+		}
+
+		// Части UNION собираются с плейсхолдерами ?, в формат диалекта их переводит внешний запрос
+		builder := sq.Select().
+			Column(fmt.Sprintf("'%s' AS type", src.hitType)).
+			Columns("id", "course_id").
+			Column(src.titleColumn + " AS title").
+			Column(src.textColumn + " AS body").
+			From(src.table).
+			Where(sq.Eq{"deleted_at": nil})
+		if src.drafts {
+			builder = builder.Where(sq.Eq{"status": models.PublicationStatusPublished})
+		}
+
+		switch r.db.Dialect {
+		case database.Postgres:
+			// Выражение совпадает с GIN-индексом из миграции add_fulltext_search_indexes
+			vector := fmt.Sprintf("to_tsvector('simple', %s || ' ' || %s)", src.titleColumn, src.textColumn)
+			tsQuery := postgresTSQuery(booleanQuery)
+			builder = builder.
+				Column(sq.Expr("ts_rank("+vector+", to_tsquery('simple', ?)) AS score", tsQuery)).
+				Where(sq.Expr(vector+" @@ to_tsquery('simple', ?)", tsQuery))
+		case database.SQLite:
+			builder = sqliteMatch(builder, src, booleanQuery)
+		default:
+			match := fmt.Sprintf("MATCH(%s, %s) AGAINST (? IN BOOLEAN MODE)", src.titleColumn, src.textColumn)
+			builder = builder.
+				Column(sq.Expr(match+" AS score", booleanQuery)).
+				Where(sq.Expr(match, booleanQuery))
+		}
+
+		if courseID != nil {
+			builder = builder.Where(sq.Eq{"course_id": *courseID})
+		}
+
+		query, queryArgs, err := builder.ToSql()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		parts = append(parts, query)
+		args = append(args, queryArgs...)
+	}
+
+	return strings.Join(parts, " UNION ALL "), args, nil
+}
+
+// booleanTerms возвращает основы слов из запроса BOOLEAN MODE вида "+term* +other*"
+func booleanTerms(booleanQuery string) []string {
+	terms := strings.Fields(booleanQuery)
+	for i, term := range terms {
+		terms[i] = strings.TrimSuffix(strings.TrimPrefix(term, "+"), "*")
+	}
+	return terms
+}
+
+// postgresTSQuery переводит запрос BOOLEAN MODE в tsquery "term:* & other:*":
+// все слова обязательны, поиск по префиксу
+func postgresTSQuery(booleanQuery string) string {
+	terms := booleanTerms(booleanQuery)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// sqliteMatch ищет каждое слово через LIKE: полнотекстовых индексов в SQLite нет,
+// а режим рассчитан на разработку и тесты. Совпадение в заголовке весит вдвое больше.
+// unicode_lower регистрирует драйвер (database/sqlite.go), слова запроса уже в нижнем регистре.
+func sqliteMatch(builder sq.SelectBuilder, src searchSource, booleanQuery string) sq.SelectBuilder {
+	title := "unicode_lower(" + src.titleColumn + ")"
+	text := "unicode_lower(" + src.textColumn + ")"
+
+	var score []string
+	var scoreArgs []any
+	match := sq.And{}
+	for _, term := range booleanTerms(booleanQuery) {
+		pattern := "%" + term + "%"
+		score = append(score, fmt.Sprintf("(%s LIKE ?) * 2 + (%s LIKE ?)", title, text))
+		scoreArgs = append(scoreArgs, pattern, pattern)
+		match = append(match, sq.Or{sq.Like{title: pattern}, sq.Like{text: pattern}})
+	}
+
+	return builder.
+		Column(sq.Expr(strings.Join(score, " + ")+" AS score", scoreArgs...)).
+		Where(match)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRepository_SQLite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewSearchRepository(db)
+
+	courseID := createTestCourse(t, db, "Веб", "2026-fall")
+	otherID := createTestCourse(t, db, "Сети", "2026-fall")
+
+	_, err := NewLectureRepository(db).Create(ctx, courseID, 1, "Контроллеры", "Маршрутизация запросов", "", "", nil)
+	require.NoError(t, err)
+	_, err = NewLectureRepository(db).Create(ctx, courseID, 2, "Модели", "Контроллеры вызывают сервисы", "", "", nil)
+	require.NoError(t, err)
+	_, err = NewLabRepository(db).Create(ctx, otherID, 1, 10, "Сокеты", "Без контроллеров", "", nil, "", nil)
+	require.NoError(t, err)
+
+	hits, total, err := repo.Search(ctx, "+контроллер*", nil, nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, hits, 3)
+	assert.Equal(t, "Контроллеры", hits[0].Title, "title match ranks first")
+
+	hits, total, err = repo.Search(ctx, "+контроллер* +сервис*", &courseID, nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "Модели", hits[0].Title)
+
+	hits, _, err = repo.Search(ctx, "+контроллер*", nil, []string{models.SearchTypeLab}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, models.SearchTypeLab, hits[0].Type)
+	assert.Equal(t, otherID, hits[0].CourseID)
+}
+
+func TestPostgresTSQuery(t *testing.T) {
+	assert.Equal(t, "контроллер:* & servic:*", postgresTSQuery("+контроллер* +servic*"))
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// CourseStore - хранилище курсов
+type CourseStore interface {
+	GetAll(ctx context.Context, filter CourseFilter, opts ListOptions) ([]models.Course, int, error)
+	// GetByID и FindByNameAndSemester возвращают nil без ошибки, если курса нет
+	GetByID(ctx context.Context, id int) (*models.Course, error)
+	FindByNameAndSemester(ctx context.Context, name, semester string) (*models.Course, error)
+	Create(ctx context.Context, name, semester, description string) (*models.Course, error)
+	Update(ctx context.Context, id int, name, semester, description string) error
+	SetArchived(ctx context.Context, id int, archived bool) error
+	// Delete переносит курс в корзину вместе со всеми его материалами
+	Delete(ctx context.Context, id int) error
+	// Clone возвращает ErrCourseNotFound, если исходного курса нет
+	Clone(ctx context.Context, sourceID int, opts CourseCloneOptions) (*models.CourseCloneSummary, error)
+	Import(ctx context.Context, content *models.CourseContent) (*models.CourseImportSummary, error)
+}
+
+// LectureStore - хранилище лекций
+type LectureStore interface {
+	GetAll(ctx context.Context, filter LectureFilter, opts ListOptions) ([]models.Lecture, int, error)
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error)
+	GetByID(ctx context.Context, id int) (*models.Lecture, error)
+	// Create, как и Create лабораторных и ведомостей, сразу записывает статус публикации;
+	// пустой status означает опубликованный материал
+	Create(ctx context.Context, courseID, week int, title, content, githubURL, status string, publishAt *time.Time) (*models.Lecture, error)
+	Update(ctx context.Context, id, courseID, week int, title, content, githubURL string) error
+	SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error
+	Delete(ctx context.Context, id int) error
+}
+
+// LabStore - хранилище лабораторных; deadline передаётся строкой в формате "2006-01-02 15:04:05"
+type LabStore interface {
+	GetAll(ctx context.Context, filter LabFilter, opts ListOptions) ([]models.Lab, int, error)
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error)
+	GetByID(ctx context.Context, id int) (*models.Lab, error)
+	Create(ctx context.Context, courseID, number, maxScore int, title, description, githubURL string, deadline *string, status string, publishAt *time.Time) (*models.Lab, error)
+	Update(ctx context.Context, id, courseID, number, maxScore int, title, description, githubURL string, deadline *string) error
+	SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ExamQuestionStore - хранилище вопросов к экзамену
+type ExamQuestionStore interface {
+	GetAll(ctx context.Context, filter ExamQuestionFilter, opts ListOptions) ([]models.ExamQuestion, int, error)
+	GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error)
+	GetByID(ctx context.Context, id int) (*models.ExamQuestion, error)
+	Create(ctx context.Context, courseID, number int, section, question string) (*models.ExamQuestion, error)
+	Update(ctx context.Context, id, courseID, number int, section, question string) error
+	Delete(ctx context.Context, id int) error
+	BulkCreate(ctx context.Context, questions []models.ExamQuestion) error
+	DeleteByCourseID(ctx context.Context, courseID int) error
+}
+
+// GradeSheetStore - хранилище ссылок на ведомости
+type GradeSheetStore interface {
+	GetAll(ctx context.Context, filter GradeSheetFilter, opts ListOptions) ([]models.GradeSheet, int, error)
+	GetByCourseID(ctx context.Context, courseID int) ([]models.GradeSheet, error)
+	GetByID(ctx context.Context, id int) (*models.GradeSheet, error)
+	Create(ctx context.Context, courseID int, sheetURL, description, status string, publishAt *time.Time) (*models.GradeSheet, error)
+	Update(ctx context.Context, id int, sheetURL, description string) error
+	SetPublication(ctx context.Context, id int, status string, publishAt *time.Time) error
+	Delete(ctx context.Context, id int) error
+}
+
+// UserStore - хранилище пользователей
+type UserStore interface {
+	// GetByUsername возвращает sql.ErrNoRows, если пользователя нет
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	// GetByID возвращает nil, если пользователя нет
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetAll(ctx context.Context, filter UserFilter, opts ListOptions) ([]models.User, int, error)
+	Create(ctx context.Context, username, email, passwordHash, role string) (*models.User, error)
+	SetRole(ctx context.Context, id int, role string) error
+	SetPasswordHash(ctx context.Context, id int, passwordHash string) error
+	SetDisabled(ctx context.Context, id int, disabled bool) error
+}
+
+// JobStore - очередь фоновых задач и их результаты
+type JobStore interface {
+	Enqueue(ctx context.Context, jobType string, payload any, maxAttempts int) (*models.Job, error)
+	GetByID(ctx context.Context, id int) (*models.Job, error)
+	Lease(ctx context.Context, workerID string, visibility time.Duration) (*models.Job, error)
+	Extend(ctx context.Context, id int, workerID string, visibility time.Duration) error
+	Complete(ctx context.Context, id int, workerID string) error
+	SetProgress(ctx context.Context, id int, workerID string, progress int) error
+	SaveResult(ctx context.Context, jobID int, filename, contentType string, content []byte) error
+	GetResult(ctx context.Context, jobID int) (*models.JobResult, error)
+	Bury(ctx context.Context, id int, workerID, errMsg string) error
+	Retry(ctx context.Context, id int, workerID string, runAt time.Time, errMsg string) error
+}
+
+// SearchStore - поиск по лекциям, лабораторным и вопросам к экзамену
+type SearchStore interface {
+	Search(ctx context.Context, booleanQuery string, courseID *int, types []string, limit, offset int) ([]models.SearchHit, int, error)
+}
+
+// ContentSyncEventStore - журнал webhook-событий и синхронизаций по ним
+type ContentSyncEventStore interface {
+	Create(ctx context.Context, event *models.ContentSyncEvent) (*models.ContentSyncEvent, error)
+	GetByID(ctx context.Context, id int) (*models.ContentSyncEvent, error)
+	GetByCourseID(ctx context.Context, courseID int, opts ListOptions) ([]models.ContentSyncEvent, int, error)
+	SetJob(ctx context.Context, id, jobID int) error
+	Finish(ctx context.Context, id int, status string, report *models.ContentSyncReport, errMsg string) error
+}
+
+// AuditLogStore - журнал изменений через админский API
+type AuditLogStore interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	GetAll(ctx context.Context, filter AuditFilter, opts ListOptions) ([]models.AuditEntry, int, error)
+}
+
+// TrashStore - корзина мягко удалённых курсов и материалов
+type TrashStore interface {
+	List(ctx context.Context, filter TrashFilter, opts ListOptions) ([]models.TrashItem, int, error)
+	// Restore возвращает ErrTrashItemNotFound или ErrTrashCourseDeleted
+	Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error)
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+// ScheduleStore - дата начала и недельное расписание курсов
+type ScheduleStore interface {
+	// Get возвращает nil без ошибки, если расписания нет
+	Get(ctx context.Context, courseID int) (*models.CourseSchedule, error)
+	Set(ctx context.Context, schedule *models.CourseSchedule) (*models.CourseSchedule, error)
+	Delete(ctx context.Context, courseID int) error
+}
+
+// PublicationStore - отложенная публикация черновиков лекций, лабораторных и ведомостей
+type PublicationStore interface {
+	PublishDue(ctx context.Context, now time.Time) ([]models.PublishedItem, error)
+}
+
+// AttachmentStore - записи о файлах лекций и лабораторных; сами файлы лежат в storage.Storage
+type AttachmentStore interface {
+	Create(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	// GetByID возвращает nil без ошибки, если вложения нет
+	GetByID(ctx context.Context, id int) (*models.Attachment, error)
+	ListByLecture(ctx context.Context, lectureID int) ([]models.Attachment, error)
+	ListByLab(ctx context.Context, labID int) ([]models.Attachment, error)
+	// ListOrphans возвращает вложения, чьи лекция или лабораторная окончательно удалены
+	ListOrphans(ctx context.Context) ([]models.Attachment, error)
+	CourseUsage(ctx context.Context, courseID int) (int64, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// AnnouncementStore - объявления курсов
+type AnnouncementStore interface {
+	GetAll(ctx context.Context, filter AnnouncementFilter, opts ListOptions) ([]models.Announcement, int, error)
+	// GetByID возвращает nil без ошибки, если объявления нет
+	GetByID(ctx context.Context, id int) (*models.Announcement, error)
+	Create(ctx context.Context, courseID int, title, body, kind string) (*models.Announcement, error)
+	Update(ctx context.Context, id int, title, body string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// NotificationStore - настройки email-уведомлений и отправленные напоминания о дедлайнах
+type NotificationStore interface {
+	GetPreferences(ctx context.Context, userID int) (*models.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, p *models.NotificationPreferences) error
+	ListRecipients(ctx context.Context, kind string) ([]models.User, error)
+	DueDeadlineReminders(ctx context.Context, from, to time.Time) ([]models.Lab, error)
+	MarkDeadlineReminded(ctx context.Context, labID int, deadline time.Time) error
+}
+
+// Store - набор хранилищ одного бэкенда; сервер работает только через него,
+// поэтому SQL-репозитории можно заменить in-memory реализацией из пакета memory
+type Store struct {
+	Courses           CourseStore
+	Lectures          LectureStore
+	Labs              LabStore
+	ExamQuestions     ExamQuestionStore
+	GradeSheets       GradeSheetStore
+	Users             UserStore
+	Jobs              JobStore
+	Search            SearchStore
+	ContentSyncEvents ContentSyncEventStore
+	AuditLog          AuditLogStore
+	Trash             TrashStore
+	Schedules         ScheduleStore
+	Publication       PublicationStore
+	Attachments       AttachmentStore
+	Announcements     AnnouncementStore
+	Notifications     NotificationStore
+}
+
+// NewStore создаёт SQL-репозитории поверх одного подключения
+func NewStore(db *database.DB) *Store {
+	return &Store{
+		Courses:           NewCourseRepository(db),
+		Lectures:          NewLectureRepository(db),
+		Labs:              NewLabRepository(db),
+		ExamQuestions:     NewExamQuestionRepository(db),
+		GradeSheets:       NewGradeSheetRepository(db),
+		Users:             NewUserRepository(db),
+		Jobs:              NewJobRepository(db),
+		Search:            NewSearchRepository(db),
+		ContentSyncEvents: NewContentSyncEventRepository(db),
+		AuditLog:          NewAuditLogRepository(db),
+		Trash:             NewTrashRepository(db),
+		Schedules:         NewScheduleRepository(db),
+		Publication:       NewPublicationRepository(db),
+		Attachments:       NewAttachmentRepository(db),
+		Announcements:     NewAnnouncementRepository(db),
+		Notifications:     NewNotificationRepository(db),
+	}
+}
+
+var (
+	_ CourseStore           = (*CourseRepository)(nil)
+	_ LectureStore          = (*LectureRepository)(nil)
+	_ LabStore              = (*LabRepository)(nil)
+	_ ExamQuestionStore     = (*ExamQuestionRepository)(nil)
+	_ GradeSheetStore       = (*GradeSheetRepository)(nil)
+	_ UserStore             = (*UserRepository)(nil)
+	_ JobStore              = (*JobRepository)(nil)
+	_ SearchStore           = (*SearchRepository)(nil)
+	_ ContentSyncEventStore = (*ContentSyncEventRepository)(nil)
+	_ AuditLogStore         = (*AuditLogRepository)(nil)
+	_ TrashStore            = (*TrashRepository)(nil)
+	_ ScheduleStore         = (*ScheduleRepository)(nil)
+	_ PublicationStore      = (*PublicationRepository)(nil)
+	_ AttachmentStore       = (*AttachmentRepository)(nil)
+	_ AnnouncementStore     = (*AnnouncementRepository)(nil)
+	_ NotificationStore     = (*NotificationRepository)(nil)
+)
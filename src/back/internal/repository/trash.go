@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+var (
+	// ErrTrashItemNotFound - записи нет в корзине
+	ErrTrashItemNotFound = errors.New("item is not in trash")
+	// ErrTrashCourseDeleted - курс записи тоже в корзине; сначала нужно восстановить его
+	ErrTrashCourseDeleted = errors.New("course of the item is in trash")
+)
+
+// trashSource описывает таблицу с мягким удалением
+type trashSource struct {
+	itemType string
+	table    string
+	// title - выражение для названия записи в корзине
+	title string
+}
+
+var trashSources = []trashSource{
+	{itemType: models.TrashTypeCourse, table: "courses", title: "t.name"},
+	{itemType: models.TrashTypeLecture, table: "lectures", title: "t.title"},
+	{itemType: models.TrashTypeLab, table: "labs", title: "t.title"},
+	{itemType: models.TrashTypeGradeSheet, table: "grade_sheets", title: "COALESCE(NULLIF(t.description, ''), t.sheet_url)"},
+	{itemType: models.TrashTypeExamQuestion, table: "exam_questions", title: "t.question"},
+}
+
+var trashSortable = map[string]string{
+	"type":       "type",
+	"title":      "title",
+	"deleted_at": "deleted_at",
+}
+
+// TrashFilter - фильтры корзины
+type TrashFilter struct {
+	Type     string
+	CourseID *int
+}
+
+type TrashRepository struct {
+	db *database.DB
+	sb sq.StatementBuilderType
+}
+
+func NewTrashRepository(db *database.DB) *TrashRepository {
+	return &TrashRepository{db: db, sb: db.Dialect.Builder()}
+}
+
+func findTrashSource(itemType string) (trashSource, bool) {
+	for _, src := range trashSources {
+		if src.itemType == itemType {
+			return src, true
+		}
+	}
+	return trashSource{}, false
+}
+
+// List возвращает страницу корзины, по умолчанию недавно удалённые первыми
+func (r *TrashRepository) List(ctx context.Context, filter TrashFilter, opts ListOptions) ([]models.TrashItem, int, error) {
+	union, args, err := r.buildUnion(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if union == "" {
+		return []models.TrashItem{}, 0, nil
+	}
+
+	countQuery, err := r.db.Dialect.Rebind("SELECT COUNT(*) FROM (" + union + ") AS items")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count trash items: %w", err)
+	}
+
+	builder, err := applyListOptions(r.sb.Select("type", "id", "course_id", "title", "deleted_at").
+		From("("+union+") AS items"), opts, trashSortable, "deleted_at DESC", "type")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, pageArgs, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get trash: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.Type, &item.ID, &item.CourseID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan error trash item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	return items, total, nil
+}
+
+func (r *TrashRepository) buildUnion(filter TrashFilter) (string, []any, error) {
+	var parts []string
+	var args []any
+
+	for _, src := range trashSources {
+		if filter.Type != "" && filter.Type != src.itemType {
+			continue
+		}
+
+		// Части UNION собираются с плейсхолдерами ?, в формат диалекта их переводит внешний запрос
+		builder := sq.Select().
+			Column(fmt.Sprintf("'%s' AS type", src.itemType)).
+			Column("t.id").
+			Column(src.title + " AS title").
+			Column("t.deleted_at").
+			From(src.table + " t").
+			Where(sq.NotEq{"t.deleted_at": nil})
+
+		courseColumn := "t.course_id"
+		if src.itemType == models.TrashTypeCourse {
+			courseColumn = "t.id"
+		} else {
+			// Материалы удалённого курса показываются только в составе курса
+			builder = builder.
+				Join("courses c ON c.id = t.course_id").
+				Where(sq.Eq{"c.deleted_at": nil})
+		}
+		builder = builder.Column(courseColumn + " AS course_id")
+
+		if filter.CourseID != nil {
+			builder = builder.Where(sq.Eq{courseColumn: *filter.CourseID})
+		}
+
+		query, queryArgs, err := builder.ToSql()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		parts = append(parts, query)
+		args = append(args, queryArgs...)
+	}
+
+	return strings.Join(parts, " UNION ALL "), args, nil
+}
+
+// Restore возвращает запись из корзины. Курс восстанавливается вместе с материалами,
+// удалёнными вместе с ним; материал удалённого курса восстановить нельзя.
+func (r *TrashRepository) Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error) {
+	src, ok := findTrashSource(itemType)
+	if !ok {
+		return nil, ErrTrashItemNotFound
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	courseColumn := "course_id"
+	if itemType == models.TrashTypeCourse {
+		courseColumn = "id"
+	}
+
+	query, args, err := r.sb.Select(courseColumn).
+		From(src.table).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var courseID int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&courseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTrashItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash item: %w", err)
+	}
+
+	summary := &models.TrashRestoreSummary{Type: itemType, ID: id}
+
+	if itemType == models.TrashTypeCourse {
+		counts := map[string]*int{
+			"lectures":       &summary.Lectures,
+			"labs":           &summary.Labs,
+			"grade_sheets":   &summary.GradeSheets,
+			"exam_questions": &summary.ExamQuestions,
+		}
+		for _, table := range courseContentTables {
+			restored, err := r.restoreRows(ctx, tx, table, sq.And{
+				sq.Eq{"course_id": id},
+				sq.Expr("deleted_at >= (SELECT deleted_at FROM courses WHERE id = ?)", id),
+			})
+			if err != nil {
+				return nil, err
+			}
+			*counts[table] = restored
+		}
+	} else {
+		query, args, err := r.sb.Select("COUNT(*)").
+			From("courses").
+			Where(sq.Eq{"id": courseID, "deleted_at": nil}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		var alive int
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&alive); err != nil {
+			return nil, fmt.Errorf("failed to check course: %w", err)
+		}
+		if alive == 0 {
+			return nil, ErrTrashCourseDeleted
+		}
+	}
+
+	if _, err := r.restoreRows(ctx, tx, src.table, sq.Eq{"id": id}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return summary, nil
+}
+
+func (r *TrashRepository) restoreRows(ctx context.Context, tx *sql.Tx, table string, where sq.Sqlizer) (int, error) {
+	query, args, err := r.sb.Update(table).
+		Set("deleted_at", nil).
+		Where(where).
+		Where(sq.NotEq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore %s: %w", table, err)
+	}
+
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count restored %s: %w", table, err)
+	}
+
+	return int(restored), nil
+}
+
+// Purge окончательно удаляет записи, попавшие в корзину раньше before, и возвращает их число.
+// Материалы курса удаляются каскадно вместе с ним и отдельно не считаются.
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for _, table := range append(courseContentTables, "courses") {
+		query, args, err := r.sb.Delete(table).
+			Where(sq.Lt{"deleted_at": before.UTC()}).
+			ToSql()
+		if err != nil {
+			return purged, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		result, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return purged, fmt.Errorf("failed to count purged %s: %w", table, err)
+		}
+		purged += int(deleted)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	trash := NewTrashRepository(db)
+	courses := NewCourseRepository(db)
+	lectures := NewLectureRepository(db)
+	questions := NewExamQuestionRepository(db)
+
+	courseID := createTestCourse(t, db, "Go", "2025-fall")
+	first, err := lectures.Create(ctx, courseID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	second, err := lectures.Create(ctx, courseID, 2, "Горутины", "", "", "", nil)
+	require.NoError(t, err)
+	_, err = questions.Create(ctx, courseID, 1, "Основы", "Что такое срез?")
+	require.NoError(t, err)
+
+	t.Run("deleted lecture is hidden and listed in trash", func(t *testing.T) {
+		require.NoError(t, lectures.Delete(ctx, first.ID))
+
+		deleted, err := lectures.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Nil(t, deleted)
+		assert.ErrorIs(t, lectures.Update(ctx, first.ID, courseID, 1, "Правка", "", ""), ErrNotFound)
+
+		items, total, err := trash.List(ctx, TrashFilter{}, ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, items, 1)
+		assert.Equal(t, models.TrashTypeLecture, items[0].Type)
+		assert.Equal(t, first.ID, items[0].ID)
+		assert.Equal(t, courseID, items[0].CourseID)
+		assert.Equal(t, "Введение", items[0].Title)
+		assert.False(t, items[0].DeletedAt.IsZero())
+	})
+
+	t.Run("course delete hides its content and shows only the course", func(t *testing.T) {
+		// Отдельно удалённая лекция должна отличаться от удалённых вместе с курсом
+		time.Sleep(1100 * time.Millisecond)
+		require.NoError(t, courses.Delete(ctx, courseID))
+
+		course, err := courses.GetByID(ctx, courseID)
+		require.NoError(t, err)
+		assert.Nil(t, course)
+		assert.ErrorIs(t, courses.Update(ctx, courseID, "Go", "2025-fall", "правка"), ErrNotFound)
+		bank, err := questions.GetByCourseID(ctx, courseID)
+		require.NoError(t, err)
+		assert.Empty(t, bank)
+
+		items, total, err := trash.List(ctx, TrashFilter{}, ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, models.TrashTypeCourse, items[0].Type)
+		assert.Equal(t, "Go", items[0].Title)
+
+		_, err = trash.Restore(ctx, models.TrashTypeLecture, second.ID)
+		assert.ErrorIs(t, err, ErrTrashCourseDeleted)
+	})
+
+	t.Run("course restore brings back content deleted with it", func(t *testing.T) {
+		summary, err := trash.Restore(ctx, models.TrashTypeCourse, courseID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Lectures)
+		assert.Equal(t, 1, summary.ExamQuestions)
+
+		restored, err := lectures.GetByCourseID(ctx, courseID)
+		require.NoError(t, err)
+		require.Len(t, restored, 1)
+		assert.Equal(t, second.ID, restored[0].ID)
+
+		_, err = trash.Restore(ctx, models.TrashTypeCourse, courseID)
+		assert.ErrorIs(t, err, ErrTrashItemNotFound)
+
+		summary, err = trash.Restore(ctx, models.TrashTypeLecture, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, summary.ID)
+	})
+
+	t.Run("purge removes only old items", func(t *testing.T) {
+		require.NoError(t, lectures.Delete(ctx, first.ID))
+
+		purged, err := trash.Purge(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, purged)
+
+		purged, err = trash.Purge(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		_, total, err := trash.List(ctx, TrashFilter{}, ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		_, err = trash.Restore(ctx, models.TrashTypeLecture, first.ID)
+		assert.ErrorIs(t, err, ErrTrashItemNotFound)
+	})
+}
@@ -1,42 +1,184 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
+	"github.com/CreateLab/laritmo/internal/database"
 	"github.com/CreateLab/laritmo/internal/models"
 	sq "github.com/Masterminds/squirrel"
 )
 
+var userColumns = []string{
+	"id", "email", "username", "password_hash", "role", "disabled_at", "created_at", "updated_at",
+}
+
+var userSortable = map[string]string{
+	"id":         "id",
+	"username":   "username",
+	"email":      "email",
+	"role":       "role",
+	"created_at": "created_at",
+}
+
 type UserRepository struct {
-	db *sql.DB
+	db *database.DB
+	sb sq.StatementBuilderType
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *database.DB) *UserRepository {
+	return &UserRepository{db: db, sb: db.Dialect.Builder()}
 }
 
-func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
-	query, args, _ := sq.Select(
-		"id", "email", "username", "password_hash", "role", "created_at", "updated_at",
-	).
-		From("users").
-		Where(sq.Eq{"username": username}).
-		ToSql()
+// UserFilter - фильтры списка пользователей
+type UserFilter struct {
+	Role string
+}
 
+func scanUser(row sq.RowScanner) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRow(query, args...).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
 		&user.PasswordHash,
 		&user.Role,
+		&user.DisabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
-
 	return &user, nil
 }
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	query, args, _ := r.sb.Select(userColumns...).
+		From("users").
+		Where(sq.Eq{"username": username}).
+		ToSql()
+
+	return scanUser(r.db.QueryRowContext(ctx, query, args...))
+}
+
+// GetByID возвращает nil, если пользователя нет
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	query, args, err := r.sb.Select(userColumns...).
+		From("users").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetAll возвращает страницу пользователей и их общее число
+func (r *UserRepository) GetAll(ctx context.Context, filter UserFilter, opts ListOptions) ([]models.User, int, error) {
+	builder := r.sb.Select(userColumns...).
+		From("users")
+
+	if filter.Role != "" {
+		builder = builder.Where(sq.Eq{"role": filter.Role})
+	}
+
+	total, err := countRows(ctx, r.db, builder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder, err = applyListOptions(builder, opts, userSortable, "username")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan error for user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	return users, total, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, username, email, passwordHash, role string) (*models.User, error) {
+	query, args, err := r.sb.Insert("users").
+		Columns("username", "email", "password_hash", "role").
+		Values(username, email, passwordHash, role).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	user, err := r.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("created user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id int, role string) error {
+	return r.update(ctx, id, "role", role)
+}
+
+func (r *UserRepository) SetPasswordHash(ctx context.Context, id int, passwordHash string) error {
+	return r.update(ctx, id, "password_hash", passwordHash)
+}
+
+// SetDisabled блокирует или разблокирует вход пользователя
+func (r *UserRepository) SetDisabled(ctx context.Context, id int, disabled bool) error {
+	if disabled {
+		return r.update(ctx, id, "disabled_at", sq.Expr("CURRENT_TIMESTAMP"))
+	}
+	return r.update(ctx, id, "disabled_at", nil)
+}
+
+func (r *UserRepository) update(ctx context.Context, id int, column string, value any) error {
+	query, args, err := r.sb.Update("users").
+		Set(column, value).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update user %s: %w", column, err)
+	}
+
+	return nil
+}
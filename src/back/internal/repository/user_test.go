@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	admin, err := repo.Create(ctx, "admin", "admin@example.com", "hash", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, admin.Role)
+	assert.Nil(t, admin.DisabledAt)
+
+	_, err = repo.Create(ctx, "student", "student@example.com", "hash", models.RoleStudent)
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, "admin", "other@example.com", "hash", models.RoleStudent)
+	assert.Error(t, err, "username must be unique")
+
+	require.NoError(t, repo.SetRole(ctx, admin.ID, models.RoleStudent))
+	require.NoError(t, repo.SetPasswordHash(ctx, admin.ID, "new-hash"))
+	require.NoError(t, repo.SetDisabled(ctx, admin.ID, true))
+
+	user, err := repo.GetByUsername(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleStudent, user.Role)
+	assert.Equal(t, "new-hash", user.PasswordHash)
+	assert.NotNil(t, user.DisabledAt)
+
+	require.NoError(t, repo.SetDisabled(ctx, admin.ID, false))
+	user, err = repo.GetByUsername(ctx, "admin")
+	require.NoError(t, err)
+	assert.Nil(t, user.DisabledAt)
+
+	users, total, err := repo.GetAll(ctx, UserFilter{Role: models.RoleStudent}, ListOptions{Sort: "-username"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, users, 2)
+	assert.Equal(t, "student", users[0].Username)
+
+	_, err = repo.GetByUsername(ctx, "nobody")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
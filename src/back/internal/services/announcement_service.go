@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CreateLab/laritmo/internal/feed"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+const (
+	maxAnnouncementTitleLength = 255
+	// feedEntries - сколько последних объявлений попадает в ленту курса
+	feedEntries = 50
+	// announcementDeadlineLayout - формат дедлайна в тексте объявлений
+	announcementDeadlineLayout = "02.01.2006 15:04"
+)
+
+var (
+	// ErrAnnouncementNotFound - объявления с таким ID нет
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	// ErrInvalidAnnouncement - объявление не прошло проверку; текст ошибки описывает причину
+	ErrInvalidAnnouncement = errors.New("invalid announcement")
+)
+
+// AnnouncementRepositoryInterface - интерфейс для объявлений курсов в БД
+type AnnouncementRepositoryInterface interface {
+	GetAll(ctx context.Context, filter repository.AnnouncementFilter, opts repository.ListOptions) ([]models.Announcement, int, error)
+	GetByID(ctx context.Context, id int) (*models.Announcement, error)
+	Create(ctx context.Context, courseID int, title, body, kind string) (*models.Announcement, error)
+	Update(ctx context.Context, id int, title, body string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// AnnouncementCourseRepositoryInterface - интерфейс для проверки существования курса
+type AnnouncementCourseRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Course, error)
+}
+
+type AnnouncementService struct {
+	repo    AnnouncementRepositoryInterface
+	courses AnnouncementCourseRepositoryInterface
+}
+
+// NewAnnouncementService создаёт сервис объявлений: ручных и автоматических при изменении материалов курса
+func NewAnnouncementService(repo AnnouncementRepositoryInterface, courses AnnouncementCourseRepositoryInterface) *AnnouncementService {
+	return &AnnouncementService{
+		repo:    repo,
+		courses: courses,
+	}
+}
+
+// GetAll возвращает страницу объявлений всех курсов для админки
+func (s *AnnouncementService) GetAll(ctx context.Context, filter repository.AnnouncementFilter, opts repository.ListOptions) ([]models.Announcement, int, error) {
+	return s.repo.GetAll(ctx, filter, opts)
+}
+
+// ListByCourse возвращает страницу объявлений курса, сначала новые.
+// Возвращает repository.ErrCourseNotFound, если курса нет.
+func (s *AnnouncementService) ListByCourse(ctx context.Context, courseID int, opts repository.ListOptions) ([]models.Announcement, int, error) {
+	if _, err := s.course(ctx, courseID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetAll(ctx, repository.AnnouncementFilter{CourseID: &courseID}, opts)
+}
+
+// Create публикует объявление преподавателя
+func (s *AnnouncementService) Create(ctx context.Context, courseID int, title, body string) (*models.Announcement, error) {
+	title, body, err := validateAnnouncement(title, body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.course(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, courseID, title, body, models.AnnouncementKindManual)
+}
+
+// Update меняет заголовок и текст объявления, в том числе автоматического
+func (s *AnnouncementService) Update(ctx context.Context, id int, title, body string) (*models.Announcement, error) {
+	title, body, err := validateAnnouncement(title, body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, id, title, body); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, id)
+}
+
+// Delete удаляет объявление безвозвратно
+func (s *AnnouncementService) Delete(ctx context.Context, id int) error {
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// LabUpdated объявляет об изменении дедлайна опубликованной лабораторной; before и after -
+// лабораторная до и после изменения. Дедлайн черновика студенты не видят, поэтому о нём не объявляется.
+func (s *AnnouncementService) LabUpdated(ctx context.Context, before, after *models.Lab) error {
+	if before == nil || after == nil || after.Status != models.PublicationStatusPublished {
+		return nil
+	}
+	if sameDeadline(before.Deadline, after.Deadline) {
+		return nil
+	}
+
+	title := fmt.Sprintf("Изменён дедлайн: лабораторная %d. %s", after.Number, after.Title)
+	var body strings.Builder
+	if after.Deadline != nil {
+		fmt.Fprintf(&body, "Новый дедлайн: %s.", after.Deadline.Format(announcementDeadlineLayout))
+	} else {
+		body.WriteString("Дедлайн снят.")
+	}
+	if before.Deadline != nil {
+		fmt.Fprintf(&body, " Прежний дедлайн: %s.", before.Deadline.Format(announcementDeadlineLayout))
+	}
+
+	_, err := s.repo.Create(ctx, after.CourseID, truncateTitle(title), body.String(), models.AnnouncementKindLabDeadline)
+	return err
+}
+
+// LecturePublished объявляет о публикации лекции - сразу при сохранении или планировщиком
+func (s *AnnouncementService) LecturePublished(ctx context.Context, courseID int, lectureTitle string) error {
+	title := fmt.Sprintf("Опубликована лекция: %s", lectureTitle)
+	body := fmt.Sprintf("В курсе появилась новая лекция «%s».", lectureTitle)
+
+	_, err := s.repo.Create(ctx, courseID, truncateTitle(title), body, models.AnnouncementKindLecturePublished)
+	return err
+}
+
+// Feed пишет Atom-ленту с последними объявлениями курса.
+// Возвращает repository.ErrCourseNotFound, если курса нет.
+func (s *AnnouncementService) Feed(ctx context.Context, courseID int, w io.Writer) error {
+	course, err := s.course(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	announcements, _, err := s.repo.GetAll(ctx, repository.AnnouncementFilter{CourseID: &courseID},
+		repository.ListOptions{Limit: feedEntries})
+	if err != nil {
+		return fmt.Errorf("failed to get announcements: %w", err)
+	}
+
+	// время ленты берётся из данных, а не из текущего времени, чтобы лента не менялась между запросами
+	f := feed.Feed{
+		ID:      fmt.Sprintf("urn:laritmo:course:%d:announcements", course.ID),
+		Title:   fmt.Sprintf("%s (%s): объявления", course.Name, course.Semester),
+		Author:  "Laritmo",
+		Updated: course.CreatedAt,
+	}
+	for _, a := range announcements {
+		if a.UpdatedAt.After(f.Updated) {
+			f.Updated = a.UpdatedAt
+		}
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:        fmt.Sprintf("urn:laritmo:announcement:%d", a.ID),
+			Title:     a.Title,
+			Content:   a.Body,
+			Published: a.CreatedAt,
+			Updated:   a.UpdatedAt,
+		})
+	}
+
+	return feed.Write(w, f)
+}
+
+func (s *AnnouncementService) get(ctx context.Context, id int) (*models.Announcement, error) {
+	announcement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if announcement == nil {
+		return nil, ErrAnnouncementNotFound
+	}
+	return announcement, nil
+}
+
+func (s *AnnouncementService) course(ctx context.Context, courseID int) (*models.Course, error) {
+	course, err := s.courses.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, repository.ErrCourseNotFound
+	}
+	return course, nil
+}
+
+func validateAnnouncement(title, body string) (string, string, error) {
+	title, body = strings.TrimSpace(title), strings.TrimSpace(body)
+	if title == "" {
+		return "", "", fmt.Errorf("%w: title is required", ErrInvalidAnnouncement)
+	}
+	if utf8.RuneCountInString(title) > maxAnnouncementTitleLength {
+		return "", "", fmt.Errorf("%w: title is longer than %d characters", ErrInvalidAnnouncement, maxAnnouncementTitleLength)
+	}
+	if body == "" {
+		return "", "", fmt.Errorf("%w: body is required", ErrInvalidAnnouncement)
+	}
+	return title, body, nil
+}
+
+// truncateTitle обрезает заголовок автоматического объявления: название материала может быть длинным
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= maxAnnouncementTitleLength {
+		return title
+	}
+	return string(runes[:maxAnnouncementTitleLength-1]) + "…"
+}
+
+func sameDeadline(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnouncementService_LabUpdated(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	service := NewAnnouncementService(store.Announcements, store.Courses)
+
+	old := time.Date(2025, 10, 1, 23, 59, 0, 0, time.UTC)
+	moved := old.Add(7 * 24 * time.Hour)
+	lab := func(status string, deadline *time.Time) *models.Lab {
+		return &models.Lab{CourseID: course.ID, Number: 2, Title: "Калькулятор", Status: status, Deadline: deadline}
+	}
+	published := models.PublicationStatusPublished
+
+	require.NoError(t, service.LabUpdated(ctx, lab(published, &old), lab(published, &old)))
+	require.NoError(t, service.LabUpdated(ctx, lab(models.PublicationStatusDraft, &old),
+		lab(models.PublicationStatusDraft, &moved)), "drafts are not announced")
+	require.NoError(t, service.LabUpdated(ctx, nil, lab(published, &moved)))
+
+	announcements, total, err := service.ListByCourse(ctx, course.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, total, "%v", announcements)
+
+	require.NoError(t, service.LabUpdated(ctx, lab(published, nil), lab(published, &moved)))
+	require.NoError(t, service.LabUpdated(ctx, lab(published, &moved), lab(published, nil)))
+
+	announcements, _, err = service.ListByCourse(ctx, course.ID, repository.ListOptions{Sort: "id"})
+	require.NoError(t, err)
+	require.Len(t, announcements, 2)
+	assert.Equal(t, "Изменён дедлайн: лабораторная 2. Калькулятор", announcements[0].Title)
+	assert.Equal(t, "Новый дедлайн: 08.10.2025 23:59.", announcements[0].Body)
+	assert.Equal(t, "Дедлайн снят. Прежний дедлайн: 08.10.2025 23:59.", announcements[1].Body)
+	assert.Equal(t, models.AnnouncementKindLabDeadline, announcements[1].Kind)
+}
+
+func TestAnnouncementService(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	service := NewAnnouncementService(store.Announcements, store.Courses)
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := service.Create(ctx, course.ID, "", "текст")
+		assert.ErrorIs(t, err, ErrInvalidAnnouncement)
+		_, err = service.Create(ctx, course.ID, "Заголовок", " \n")
+		assert.ErrorIs(t, err, ErrInvalidAnnouncement)
+		_, err = service.Create(ctx, 999, "Заголовок", "текст")
+		assert.ErrorIs(t, err, repository.ErrCourseNotFound)
+		_, err = service.Update(ctx, 999, "Заголовок", "текст")
+		assert.ErrorIs(t, err, ErrAnnouncementNotFound)
+	})
+
+	t.Run("long lecture titles are truncated", func(t *testing.T) {
+		require.NoError(t, service.LecturePublished(ctx, course.ID, strings.Repeat("я", 300)))
+
+		announcements, _, err := service.ListByCourse(ctx, course.ID, repository.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, announcements, 1)
+		assert.Equal(t, maxAnnouncementTitleLength, len([]rune(announcements[0].Title)))
+		assert.True(t, strings.HasSuffix(announcements[0].Title, "…"))
+		assert.Equal(t, models.AnnouncementKindLecturePublished, announcements[0].Kind)
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		_, err := service.Create(ctx, course.ID, "Консультация", "В пятницу в 18:00 <онлайн>.")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, service.Feed(ctx, course.ID, &buf))
+		out := buf.String()
+		assert.Contains(t, out, "<id>urn:laritmo:course:1:announcements</id>")
+		assert.Contains(t, out, "В пятницу в 18:00 &lt;онлайн&gt;.")
+		assert.Less(t, strings.Index(out, "Консультация"), strings.Index(out, "Опубликована лекция"), "newest first")
+
+		assert.ErrorIs(t, service.Feed(ctx, 999, &buf), repository.ErrCourseNotFound)
+	})
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/storage"
+)
+
+const (
+	// AttachmentOwnerLecture и AttachmentOwnerLab - к чему прикреплено вложение
+	AttachmentOwnerLecture = "lecture"
+	AttachmentOwnerLab     = "lab"
+
+	// maxAttachmentNameLength - длина имени файла в байтах, как у file_name в БД
+	maxAttachmentNameLength = 255
+	// sniffLength - сколько первых байт файла нужно http.DetectContentType
+	sniffLength = 512
+)
+
+var (
+	// ErrAttachmentOwnerNotFound - лекции или лабораторной нет, она в корзине или это черновик
+	ErrAttachmentOwnerNotFound = errors.New("attachment owner not found")
+	ErrAttachmentNotFound      = errors.New("attachment not found")
+	ErrAttachmentTooLarge      = errors.New("attachment is too large")
+	// ErrAttachmentQuotaExceeded - файл не помещается в квоту курса
+	ErrAttachmentQuotaExceeded = errors.New("course attachment quota exceeded")
+)
+
+// AttachmentOwner - лекция или лабораторная, к которой прикреплено вложение
+type AttachmentOwner struct {
+	Type string
+	ID   int
+}
+
+// AttachmentRepositoryInterface - интерфейс для записей о вложениях в БД
+type AttachmentRepositoryInterface interface {
+	Create(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	GetByID(ctx context.Context, id int) (*models.Attachment, error)
+	ListByLecture(ctx context.Context, lectureID int) ([]models.Attachment, error)
+	ListByLab(ctx context.Context, labID int) ([]models.Attachment, error)
+	ListOrphans(ctx context.Context) ([]models.Attachment, error)
+	CourseUsage(ctx context.Context, courseID int) (int64, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// AttachmentCourseRepositoryInterface - интерфейс для проверки существования курса
+type AttachmentCourseRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Course, error)
+}
+
+// AttachmentLectureRepositoryInterface - интерфейс для чтения лекции-владельца
+type AttachmentLectureRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Lecture, error)
+}
+
+// AttachmentLabRepositoryInterface - интерфейс для чтения лабораторной-владельца
+type AttachmentLabRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Lab, error)
+}
+
+// AttachmentLimits - ограничения размера в байтах
+type AttachmentLimits struct {
+	MaxFileSize int64
+	CourseQuota int64
+}
+
+type AttachmentService struct {
+	repo     AttachmentRepositoryInterface
+	courses  AttachmentCourseRepositoryInterface
+	lectures AttachmentLectureRepositoryInterface
+	labs     AttachmentLabRepositoryInterface
+	storage  storage.Storage
+	limits   AttachmentLimits
+	logger   *slog.Logger
+}
+
+// NewAttachmentService создаёт сервис вложений; файлы хранятся в storage, записи о них - в repo
+func NewAttachmentService(
+	repo AttachmentRepositoryInterface,
+	courses AttachmentCourseRepositoryInterface,
+	lectures AttachmentLectureRepositoryInterface,
+	labs AttachmentLabRepositoryInterface,
+	storage storage.Storage,
+	limits AttachmentLimits,
+	logger *slog.Logger,
+) *AttachmentService {
+	return &AttachmentService{
+		repo:     repo,
+		courses:  courses,
+		lectures: lectures,
+		labs:     labs,
+		storage:  storage,
+		limits:   limits,
+		logger:   logger,
+	}
+}
+
+// MaxFileSize возвращает наибольший допустимый размер одного файла в байтах
+func (s *AttachmentService) MaxFileSize() int64 {
+	return s.limits.MaxFileSize
+}
+
+// courseOf возвращает курс владельца; черновик считается отсутствующим, если includeDrafts не задан
+func (s *AttachmentService) courseOf(ctx context.Context, owner AttachmentOwner, includeDrafts bool) (int, error) {
+	var courseID int
+	var status string
+
+	switch owner.Type {
+	case AttachmentOwnerLecture:
+		lecture, err := s.lectures.GetByID(ctx, owner.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get lecture: %w", err)
+		}
+		if lecture == nil {
+			return 0, ErrAttachmentOwnerNotFound
+		}
+		courseID, status = lecture.CourseID, lecture.Status
+	case AttachmentOwnerLab:
+		lab, err := s.labs.GetByID(ctx, owner.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get lab: %w", err)
+		}
+		if lab == nil {
+			return 0, ErrAttachmentOwnerNotFound
+		}
+		courseID, status = lab.CourseID, lab.Status
+	default:
+		return 0, fmt.Errorf("unknown attachment owner type %q", owner.Type)
+	}
+
+	if status != models.PublicationStatusPublished && !includeDrafts {
+		return 0, ErrAttachmentOwnerNotFound
+	}
+	return courseID, nil
+}
+
+// ownerOf возвращает владельца вложения; у вложения удалённого материала владельца нет
+func ownerOf(a *models.Attachment) (AttachmentOwner, bool) {
+	switch {
+	case a.LectureID != nil:
+		return AttachmentOwner{Type: AttachmentOwnerLecture, ID: *a.LectureID}, true
+	case a.LabID != nil:
+		return AttachmentOwner{Type: AttachmentOwnerLab, ID: *a.LabID}, true
+	}
+	return AttachmentOwner{}, false
+}
+
+// List возвращает вложения лекции или лабораторной в порядке загрузки
+func (s *AttachmentService) List(ctx context.Context, owner AttachmentOwner, includeDrafts bool) ([]models.Attachment, error) {
+	if _, err := s.courseOf(ctx, owner, includeDrafts); err != nil {
+		return nil, err
+	}
+
+	if owner.Type == AttachmentOwnerLecture {
+		return s.repo.ListByLecture(ctx, owner.ID)
+	}
+	return s.repo.ListByLab(ctx, owner.ID)
+}
+
+// Upload сохраняет файл из size байт r и прикрепляет его к владельцу. Тип содержимого
+// определяется по первым байтам файла, имя очищается от пути и управляющих символов.
+// Квота проверяется до записи, поэтому одновременные загрузки могут немного её превысить.
+func (s *AttachmentService) Upload(ctx context.Context, owner AttachmentOwner, fileName string, r io.Reader, size int64) (*models.Attachment, error) {
+	if size > s.limits.MaxFileSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	courseID, err := s.courseOf(ctx, owner, true)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.CourseUsage(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if used+size > s.limits.CourseQuota {
+		return nil, ErrAttachmentQuotaExceeded
+	}
+
+	br := bufio.NewReaderSize(r, sniffLength)
+	head, err := br.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	key, err := attachmentKey(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		CourseID:    courseID,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: http.DetectContentType(head),
+		Size:        size,
+		StorageKey:  key,
+	}
+	if owner.Type == AttachmentOwnerLecture {
+		attachment.LectureID = &owner.ID
+	} else {
+		attachment.LabID = &owner.ID
+	}
+
+	if err := s.storage.Put(ctx, key, br, size, attachment.ContentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	created, err := s.repo.Create(ctx, attachment)
+	if err != nil {
+		// Файл убирается, даже если клиент уже отключился
+		if delErr := s.storage.Delete(context.WithoutCancel(ctx), key); delErr != nil {
+			s.logger.ErrorContext(ctx, "Failed to delete stored attachment", "error", delErr, "key", key)
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// Open возвращает вложение и его содержимое; вызывающий закрывает reader.
+// Вложения черновиков доступны только при includeDrafts.
+func (s *AttachmentService) Open(ctx context.Context, id int, includeDrafts bool) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	owner, ok := ownerOf(attachment)
+	if !ok {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if _, err := s.courseOf(ctx, owner, includeDrafts); errors.Is(err, ErrAttachmentOwnerNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.logger.ErrorContext(ctx, "Attachment file is missing in storage", "id", id, "key", attachment.StorageKey)
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// Delete удаляет запись о вложении и его файл
+func (s *AttachmentService) Delete(ctx context.Context, id int) error {
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if attachment == nil {
+		return ErrAttachmentNotFound
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	// Файл без записи уже недоступен, поэтому ошибка его удаления только логируется
+	if err := s.storage.Delete(context.WithoutCancel(ctx), attachment.StorageKey); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete stored attachment", "error", err, "id", id, "key", attachment.StorageKey)
+	}
+	return nil
+}
+
+// Usage возвращает занятое вложениями курса место; repository.ErrCourseNotFound, если курса нет
+func (s *AttachmentService) Usage(ctx context.Context, courseID int) (*models.AttachmentUsage, error) {
+	course, err := s.courses.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, repository.ErrCourseNotFound
+	}
+
+	used, err := s.repo.CourseUsage(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AttachmentUsage{CourseID: courseID, UsedBytes: used, QuotaBytes: s.limits.CourseQuota}, nil
+}
+
+// PurgeOrphans удаляет вложения окончательно удалённых лекций и лабораторных вместе с файлами
+func (s *AttachmentService) PurgeOrphans(ctx context.Context) (int, error) {
+	orphans, err := s.repo.ListOrphans(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, a := range orphans {
+		// Сначала файл: если его удаление не удалось, запись останется и попадёт в следующий проход
+		if err := s.storage.Delete(ctx, a.StorageKey); err != nil {
+			return purged, fmt.Errorf("failed to delete stored attachment %d: %w", a.ID, err)
+		}
+		if err := s.repo.Delete(ctx, a.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Run удаляет вложения без владельца сразу и затем каждые interval, пока не отменён ctx
+func (s *AttachmentService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeOrphans(ctx)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to purge orphaned attachments", "error", err)
+		} else if purged > 0 {
+			s.logger.InfoContext(ctx, "Orphaned attachments purged", "purged", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attachmentKey выбирает случайный ключ объекта в каталоге курса; имя файла в ключ
+// не входит, чтобы не зависеть от допустимых в хранилище символов
+func attachmentKey(courseID int) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate attachment key: %w", err)
+	}
+	return fmt.Sprintf("courses/%d/%s", courseID, hex.EncodeToString(buf)), nil
+}
+
+// sanitizeFileName оставляет от имени файла из запроса последний сегмент пути
+// без управляющих символов, не длиннее maxAttachmentNameLength байт
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name))
+
+	for len(name) > maxAttachmentNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+	return name
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/CreateLab/laritmo/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentService(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "", "", "", nil)
+	require.NoError(t, err)
+	draft, err := store.Labs.Create(ctx, course.ID, 1, 10, "Калькулятор", "", "", nil, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Labs.SetPublication(ctx, draft.ID, models.PublicationStatusDraft, nil))
+
+	files, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	service := NewAttachmentService(store.Attachments, store.Courses, store.Lectures, store.Labs, files,
+		AttachmentLimits{MaxFileSize: 100, CourseQuota: 150}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	onLecture := AttachmentOwner{Type: AttachmentOwnerLecture, ID: lecture.ID}
+	onDraft := AttachmentOwner{Type: AttachmentOwnerLab, ID: draft.ID}
+	pdf := "%PDF-1.7\n" + strings.Repeat("x", 51)
+
+	uploaded, err := service.Upload(ctx, onLecture, `C:\Users\teacher\слайды.pdf`, strings.NewReader(pdf), int64(len(pdf)))
+	require.NoError(t, err)
+	assert.Equal(t, "слайды.pdf", uploaded.FileName)
+	assert.Equal(t, "application/pdf", uploaded.ContentType, "type is sniffed from content")
+	assert.Equal(t, course.ID, uploaded.CourseID)
+	assert.True(t, strings.HasPrefix(uploaded.StorageKey, "courses/1/"))
+
+	t.Run("open returns the content", func(t *testing.T) {
+		got, content, err := service.Open(ctx, uploaded.ID, false)
+		require.NoError(t, err)
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, string(data))
+		assert.Equal(t, uploaded.ID, got.ID)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		_, err := service.Upload(ctx, onLecture, "big.bin", strings.NewReader(strings.Repeat("x", 101)), 101)
+		assert.ErrorIs(t, err, ErrAttachmentTooLarge)
+
+		_, err = service.Upload(ctx, onLecture, "fits.txt", strings.NewReader(strings.Repeat("x", 100)), 100)
+		assert.ErrorIs(t, err, ErrAttachmentQuotaExceeded, "60 bytes used, quota 150")
+
+		usage, err := service.Usage(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &models.AttachmentUsage{CourseID: course.ID, UsedBytes: 60, QuotaBytes: 150}, usage)
+
+		_, err = service.Usage(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrCourseNotFound)
+	})
+
+	t.Run("drafts are hidden from the public", func(t *testing.T) {
+		html := "<html><script>alert(1)</script></html>"
+		onDraftFile, err := service.Upload(ctx, onDraft, "task.html", strings.NewReader(html), int64(len(html)))
+		require.NoError(t, err)
+		assert.Equal(t, "text/html; charset=utf-8", onDraftFile.ContentType)
+
+		_, err = service.List(ctx, onDraft, false)
+		assert.ErrorIs(t, err, ErrAttachmentOwnerNotFound)
+		_, _, err = service.Open(ctx, onDraftFile.ID, false)
+		assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+		list, err := service.List(ctx, onDraft, true)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		require.NoError(t, service.Delete(ctx, onDraftFile.ID))
+		_, err = files.Get(ctx, onDraftFile.StorageKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		assert.ErrorIs(t, service.Delete(ctx, onDraftFile.ID), ErrAttachmentNotFound)
+	})
+
+	t.Run("purged lecture attachments are removed", func(t *testing.T) {
+		require.NoError(t, store.Lectures.Delete(ctx, lecture.ID))
+		_, err := store.Trash.Purge(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+
+		purged, err := service.PurgeOrphans(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		_, err = files.Get(ctx, uploaded.StorageKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		got, err := store.Attachments.GetByID(ctx, uploaded.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{`C:\fakepath\лекция 1.pptx`, "лекция 1.pptx"},
+		{"a\x00b\r\n.txt", "ab.txt"},
+		{"", "file"},
+		{"..", "file"},
+		{strings.Repeat("я", 200), strings.Repeat("я", 127)},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sanitizeFileName(tt.name), tt.name)
+	}
+}
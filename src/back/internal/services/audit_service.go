@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// auditExportPageSize - сколько записей читается из БД за раз при выгрузке в CSV
+const auditExportPageSize = 500
+
+// AuditLogRepositoryInterface - интерфейс для журнала аудита в БД
+type AuditLogRepositoryInterface interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	GetAll(ctx context.Context, filter repository.AuditFilter, opts repository.ListOptions) ([]models.AuditEntry, int, error)
+}
+
+// AuditSnapshot загружает текущее состояние сущности; nil без ошибки означает, что её нет
+type AuditSnapshot func(ctx context.Context, id int) (any, error)
+
+// SnapshotOf превращает GetByID хранилища в AuditSnapshot
+func SnapshotOf[T any](get func(ctx context.Context, id int) (*T, error)) AuditSnapshot {
+	return func(ctx context.Context, id int) (any, error) {
+		entity, err := get(ctx, id)
+		if err != nil || entity == nil {
+			return nil, err
+		}
+		return entity, nil
+	}
+}
+
+type AuditService struct {
+	repo      AuditLogRepositoryInterface
+	snapshots map[string]AuditSnapshot
+}
+
+// NewAuditService создаёт сервис журнала; snapshots сопоставляет тип сущности
+// (course, lecture, ...) с загрузкой её состояния для полей before/after
+func NewAuditService(repo AuditLogRepositoryInterface, snapshots map[string]AuditSnapshot) *AuditService {
+	return &AuditService{
+		repo:      repo,
+		snapshots: snapshots,
+	}
+}
+
+// Snapshot возвращает состояние сущности в JSON или nil, если тип не поддерживается или сущности нет
+func (s *AuditService) Snapshot(ctx context.Context, entityType string, id int) (json.RawMessage, error) {
+	snapshot, ok := s.snapshots[entityType]
+	if !ok {
+		return nil, nil
+	}
+
+	entity, err := snapshot(ctx, id)
+	if err != nil || entity == nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s snapshot: %w", entityType, err)
+	}
+	return data, nil
+}
+
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditEntry) error {
+	return s.repo.Create(ctx, entry)
+}
+
+func (s *AuditService) List(ctx context.Context, filter repository.AuditFilter, opts repository.ListOptions) ([]models.AuditEntry, int, error) {
+	return s.repo.GetAll(ctx, filter, opts)
+}
+
+// ExportCSV пишет все подходящие под фильтр записи в порядке id.
+// Журнал читается страницами; новые записи добавляются в конец и не сдвигают уже прочитанные.
+func (s *AuditService) ExportCSV(ctx context.Context, w io.Writer, filter repository.AuditFilter) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"id", "created_at", "user_id", "username", "action", "entity_type", "entity_id",
+		"ip", "request_id", "before", "after",
+	}); err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += auditExportPageSize {
+		entries, _, err := s.repo.GetAll(ctx, filter, repository.ListOptions{
+			Limit:  auditExportPageSize,
+			Offset: offset,
+			Sort:   "id",
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			if err := cw.Write([]string{
+				strconv.Itoa(e.ID),
+				e.CreatedAt.UTC().Format(time.RFC3339),
+				formatOptionalInt(e.UserID),
+				e.Username,
+				e.Action,
+				e.EntityType,
+				formatOptionalInt(e.EntityID),
+				e.IP,
+				e.RequestID,
+				string(e.Before),
+				string(e.After),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < auditExportPageSize {
+			break
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
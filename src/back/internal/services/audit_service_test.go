@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+
+	service := NewAuditService(store.AuditLog, map[string]AuditSnapshot{
+		"course": SnapshotOf(store.Courses.GetByID),
+	})
+
+	data, err := service.Snapshot(ctx, "course", course.ID)
+	require.NoError(t, err)
+	var snapshot models.Course
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+	assert.Equal(t, "Go", snapshot.Name)
+
+	data, err = service.Snapshot(ctx, "course", course.ID+1)
+	require.NoError(t, err)
+	assert.Nil(t, data, "missing entity has no snapshot")
+
+	data, err = service.Snapshot(ctx, "job", 1)
+	require.NoError(t, err)
+	assert.Nil(t, data, "unknown entity type has no snapshot")
+}
+
+func TestAuditService_ExportCSV(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	service := NewAuditService(store.AuditLog, nil)
+
+	// Больше одной страницы выгрузки
+	total := auditExportPageSize + 3
+	adminID := 1
+	for i := 1; i <= total; i++ {
+		entityID := i
+		entry := &models.AuditEntry{
+			UserID:     &adminID,
+			Username:   "admin",
+			Action:     "lecture.update",
+			EntityType: "lecture",
+			EntityID:   &entityID,
+			IP:         "127.0.0.1",
+			RequestID:  fmt.Sprintf("req-%d", i),
+		}
+		if i == 1 {
+			entry.Action = "course.delete"
+			entry.EntityType = "course"
+			entry.Before = json.RawMessage(`{"name":"Go, advanced"}`)
+		}
+		require.NoError(t, service.Record(ctx, entry))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportCSV(ctx, &buf, repository.AuditFilter{}))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, total+1)
+	assert.Equal(t, []string{"id", "created_at", "user_id", "username", "action", "entity_type", "entity_id", "ip", "request_id", "before", "after"}, records[0])
+	assert.Equal(t, "course.delete", records[1][4])
+	assert.Equal(t, `{"name":"Go, advanced"}`, records[1][9])
+	assert.Equal(t, fmt.Sprintf("req-%d", total), records[total][8])
+
+	buf.Reset()
+	require.NoError(t, service.ExportCSV(ctx, &buf, repository.AuditFilter{EntityType: "course"}))
+	records, err = csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+}
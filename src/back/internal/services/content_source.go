@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrSyncRepoNotFound - путь не является рабочей копией или bare-репозиторием
+var ErrSyncRepoNotFound = errors.New("repository not found")
+
+// contentSource - источник Markdown-файлов курса. Пути относительные, через "/".
+type contentSource interface {
+	Match(ctx context.Context, pattern string) ([]string, error)
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+}
+
+// openContentSource открывает рабочую копию как каталог, а bare-репозиторий читает через git на ревизии ref
+func openContentSource(repoPath, ref string) (contentSource, error) {
+	info, err := os.Stat(repoPath)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrSyncRepoNotFound, repoPath)
+	}
+
+	if isBareRepo(repoPath) {
+		if ref == "" {
+			ref = "HEAD"
+		}
+		// Ревизия передаётся git аргументом и не должна читаться как флаг
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("invalid ref %q", ref)
+		}
+		return &gitSource{gitDir: repoPath, ref: ref}, nil
+	}
+
+	return &dirSource{root: repoPath}, nil
+}
+
+func isBareRepo(repoPath string) bool {
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err == nil {
+		return false
+	}
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(repoPath, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// dirSource читает файлы рабочей копии с диска
+type dirSource struct {
+	root string
+}
+
+func (s *dirSource) Match(_ context.Context, pattern string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.root, filepath.FromSlash(pattern)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(s.root, m)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+	return names, nil
+}
+
+func (s *dirSource) ReadFile(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.root, filepath.FromSlash(name)))
+}
+
+// gitSource читает файлы из bare-репозитория без checkout
+type gitSource struct {
+	gitDir string
+	ref    string
+}
+
+func (s *gitSource) Match(ctx context.Context, pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	out, err := s.git(ctx, "ls-tree", "-r", "--name-only", "-z", s.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *gitSource) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	return s.git(ctx, "show", s.ref+":"+name)
+}
+
+func (s *gitSource) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--git-dir", s.gitDir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
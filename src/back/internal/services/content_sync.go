@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+const (
+	DefaultLecturesGlob = "lections/L*.md"
+	DefaultLabsGlob     = "Lab*.md"
+
+	defaultLabMaxScore = 100
+)
+
+// ErrSyncCourseNotFound - курс, в который синхронизируются материалы, не существует
+var ErrSyncCourseNotFound = errors.New("course not found")
+
+// ErrInvalidSyncPattern - шаблон файлов указывает за пределы репозитория
+var ErrInvalidSyncPattern = errors.New("pattern must be relative to repository root")
+
+var syncNumberRe = regexp.MustCompile(`\d+`)
+
+// SyncLectureRepositoryInterface - интерфейс для чтения и записи лекций курса при синхронизации
+type SyncLectureRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error)
+	Create(ctx context.Context, courseID, week int, title, content, githubURL, status string, publishAt *time.Time) (*models.Lecture, error)
+	Update(ctx context.Context, id, courseID, week int, title, content, githubURL string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// SyncLabRepositoryInterface - интерфейс для чтения и записи лабораторных курса при синхронизации
+type SyncLabRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error)
+	Create(ctx context.Context, courseID, number, maxScore int, title, description, githubURL string, deadline *string, status string, publishAt *time.Time) (*models.Lab, error)
+	Update(ctx context.Context, id, courseID, number, maxScore int, title, description, githubURL string, deadline *string) error
+	Delete(ctx context.Context, id int) error
+}
+
+type ContentSyncOptions struct {
+	// RepoPath - рабочая копия или bare-репозиторий
+	RepoPath string
+	// Ref - ревизия bare-репозитория, по умолчанию HEAD; для рабочей копии не используется
+	Ref          string
+	LecturesGlob string
+	LabsGlob     string
+	// ExamQuestionsFile и GradeSheetsFile - необязательные JSON или CSV файлы в репозитории
+	ExamQuestionsFile string
+	GradeSheetsFile   string
+	// GithubBase - префикс ссылки на файл, например https://github.com/CreateLab/AspITMO/blob/main.
+	// Если пуст, ссылки существующих записей не меняются.
+	GithubBase string
+	// Prune удаляет записи, для которых в репозитории больше нет источника
+	Prune  bool
+	DryRun bool
+}
+
+// ContentSyncService сопоставляет Markdown-файлы репозитория с лекциями и лабораторными курса
+type ContentSyncService struct {
+	courses       CourseGetterInterface
+	lectures      SyncLectureRepositoryInterface
+	labs          SyncLabRepositoryInterface
+	examQuestions SyncExamQuestionRepositoryInterface
+	gradeSheets   SyncGradeSheetRepositoryInterface
+	announcer     PublicationAnnouncerInterface
+	logger        *slog.Logger
+}
+
+// NewContentSyncService создаёт синхронизацию материалов курса с репозиторием;
+// о созданных лекциях announcer публикует объявления, как при создании через API
+func NewContentSyncService(
+	courses CourseGetterInterface,
+	lectures SyncLectureRepositoryInterface,
+	labs SyncLabRepositoryInterface,
+	examQuestions SyncExamQuestionRepositoryInterface,
+	gradeSheets SyncGradeSheetRepositoryInterface,
+	announcer PublicationAnnouncerInterface,
+	logger *slog.Logger,
+) *ContentSyncService {
+	return &ContentSyncService{
+		courses:       courses,
+		lectures:      lectures,
+		labs:          labs,
+		examQuestions: examQuestions,
+		gradeSheets:   gradeSheets,
+		announcer:     announcer,
+		logger:        logger,
+	}
+}
+
+// Sync создаёт и обновляет лекции по номеру недели и лабораторные по номеру.
+// Номер берётся из первого числа в имени файла, заголовок - из первого заголовка "# ".
+// В пробном прогоне courseID 0 означает курс, который ещё не создан.
+func (s *ContentSyncService) Sync(ctx context.Context, courseID int, opts ContentSyncOptions) (*models.ContentSyncReport, error) {
+	if opts.LecturesGlob == "" {
+		opts.LecturesGlob = DefaultLecturesGlob
+	}
+	if opts.LabsGlob == "" {
+		opts.LabsGlob = DefaultLabsGlob
+	}
+
+	for _, pattern := range []string{opts.LecturesGlob, opts.LabsGlob, opts.ExamQuestionsFile, opts.GradeSheetsFile} {
+		if pattern != "" && !filepath.IsLocal(filepath.FromSlash(pattern)) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSyncPattern, pattern)
+		}
+	}
+
+	if courseID != 0 || !opts.DryRun {
+		course, err := s.courses.GetByID(ctx, courseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get course: %w", err)
+		}
+		if course == nil {
+			return nil, ErrSyncCourseNotFound
+		}
+	}
+
+	src, err := openContentSource(opts.RepoPath, opts.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ContentSyncReport{
+		CourseID: courseID,
+		DryRun:   opts.DryRun,
+		Lectures: newContentSyncDiff(),
+		Labs:     newContentSyncDiff(),
+	}
+
+	if err := s.syncLectures(ctx, src, courseID, opts, &report.Lectures); err != nil {
+		return nil, fmt.Errorf("failed to sync lectures: %w", err)
+	}
+	if err := s.syncLabs(ctx, src, courseID, opts, &report.Labs); err != nil {
+		return nil, fmt.Errorf("failed to sync labs: %w", err)
+	}
+	if opts.ExamQuestionsFile != "" {
+		if report.ExamQuestions, err = s.syncExamQuestions(ctx, src, courseID, opts); err != nil {
+			return nil, fmt.Errorf("failed to sync exam questions: %w", err)
+		}
+	}
+	if opts.GradeSheetsFile != "" {
+		if report.GradeSheets, err = s.syncGradeSheets(ctx, src, courseID, opts); err != nil {
+			return nil, fmt.Errorf("failed to sync grade sheets: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+func (s *ContentSyncService) syncLectures(ctx context.Context, src contentSource, courseID int, opts ContentSyncOptions, diff *models.ContentSyncDiff) error {
+	files, err := collectSyncFiles(ctx, src, opts.LecturesGlob, diff)
+	if err != nil {
+		return err
+	}
+
+	lectures, err := s.lectures.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	records := make([]syncRecord, 0, len(lectures))
+	for _, l := range lectures {
+		records = append(records, syncRecord{id: l.ID, number: l.Week, title: l.Title, content: l.Content, githubURL: l.GithubURL})
+	}
+
+	return applySync(files, records, opts, diff, syncActions{
+		create: func(f syncFile, githubURL string) error {
+			lecture, err := s.lectures.Create(ctx, courseID, f.number, f.title, f.content, githubURL, models.PublicationStatusPublished, nil)
+			if err != nil {
+				return err
+			}
+			// Лекция уже сохранена, поэтому ошибка объявления не прерывает синхронизацию
+			if err := s.announcer.LecturePublished(ctx, courseID, lecture.Title); err != nil {
+				s.logger.ErrorContext(ctx, "Failed to announce lecture", "error", err, "id", lecture.ID)
+			}
+			return nil
+		},
+		update: func(rec syncRecord, f syncFile, githubURL string) error {
+			return s.lectures.Update(ctx, rec.id, courseID, f.number, f.title, f.content, githubURL)
+		},
+		delete: func(id int) error {
+			return s.lectures.Delete(ctx, id)
+		},
+	})
+}
+
+func (s *ContentSyncService) syncLabs(ctx context.Context, src contentSource, courseID int, opts ContentSyncOptions, diff *models.ContentSyncDiff) error {
+	files, err := collectSyncFiles(ctx, src, opts.LabsGlob, diff)
+	if err != nil {
+		return err
+	}
+
+	labs, err := s.labs.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]models.Lab, len(labs))
+	records := make([]syncRecord, 0, len(labs))
+	for _, l := range labs {
+		byID[l.ID] = l
+		records = append(records, syncRecord{id: l.ID, number: l.Number, title: l.Title, content: l.Description, githubURL: l.GithubURL})
+	}
+
+	return applySync(files, records, opts, diff, syncActions{
+		create: func(f syncFile, githubURL string) error {
+			_, err := s.labs.Create(ctx, courseID, f.number, defaultLabMaxScore, f.title, f.content, githubURL, nil, "", nil)
+			return err
+		},
+		update: func(rec syncRecord, f syncFile, githubURL string) error {
+			// Дедлайн и максимальный балл задаются в админке и при синхронизации сохраняются
+			lab := byID[rec.id]
+			var deadline *string
+			if lab.Deadline != nil {
+				d := lab.Deadline.Format(time.DateTime)
+				deadline = &d
+			}
+			return s.labs.Update(ctx, rec.id, courseID, f.number, lab.MaxScore, f.title, f.content, githubURL, deadline)
+		},
+		delete: func(id int) error {
+			return s.labs.Delete(ctx, id)
+		},
+	})
+}
+
+func newContentSyncDiff() models.ContentSyncDiff {
+	return models.ContentSyncDiff{
+		Created:   []int{},
+		Updated:   []int{},
+		Unchanged: []int{},
+		Deleted:   []int{},
+		Stale:     []int{},
+		Skipped:   []string{},
+	}
+}
+
+// syncFile - Markdown-файл из репозитория, сопоставленный с номером недели или лабораторной
+type syncFile struct {
+	path    string
+	number  int
+	title   string
+	content string
+}
+
+// syncRecord - общие для лекций и лабораторных поля записи в БД
+type syncRecord struct {
+	id        int
+	number    int
+	title     string
+	content   string
+	githubURL *string
+}
+
+type syncActions struct {
+	create func(f syncFile, githubURL string) error
+	update func(rec syncRecord, f syncFile, githubURL string) error
+	delete func(id int) error
+}
+
+// collectSyncFiles читает файлы по шаблону; файлы без номера и с повторяющимся номером попадают в Skipped
+func collectSyncFiles(ctx context.Context, src contentSource, pattern string, diff *models.ContentSyncDiff) ([]syncFile, error) {
+	names, err := src.Match(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	files := make([]syncFile, 0, len(names))
+	for _, name := range names {
+		digits := syncNumberRe.FindString(path.Base(name))
+		if digits == "" {
+			diff.Skipped = append(diff.Skipped, name)
+			continue
+		}
+		number, err := strconv.Atoi(digits)
+		if err != nil || seen[number] {
+			diff.Skipped = append(diff.Skipped, name)
+			continue
+		}
+		seen[number] = true
+
+		content, err := src.ReadFile(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		files = append(files, syncFile{
+			path:    name,
+			number:  number,
+			title:   markdownTitle(string(content), name),
+			content: string(content),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].number < files[j].number })
+	return files, nil
+}
+
+// applySync сравнивает файлы с записями по номеру и применяет изменения, если это не пробный прогон.
+// Если в БД несколько записей с одним номером, обновляется первая, остальные считаются лишними.
+func applySync(files []syncFile, records []syncRecord, opts ContentSyncOptions, diff *models.ContentSyncDiff, actions syncActions) error {
+	byNumber := make(map[int]syncRecord, len(records))
+	var extra []syncRecord
+	for _, rec := range records {
+		if _, ok := byNumber[rec.number]; ok {
+			extra = append(extra, rec)
+			continue
+		}
+		byNumber[rec.number] = rec
+	}
+
+	for _, f := range files {
+		rec, ok := byNumber[f.number]
+		if !ok {
+			if !opts.DryRun {
+				if err := actions.create(f, syncGithubURL(opts.GithubBase, f.path, nil)); err != nil {
+					return fmt.Errorf("failed to create %s: %w", f.path, err)
+				}
+			}
+			diff.Created = append(diff.Created, f.number)
+			continue
+		}
+		delete(byNumber, f.number)
+
+		githubURL := syncGithubURL(opts.GithubBase, f.path, rec.githubURL)
+		if rec.title == f.title && rec.content == f.content && derefString(rec.githubURL) == githubURL {
+			diff.Unchanged = append(diff.Unchanged, f.number)
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := actions.update(rec, f, githubURL); err != nil {
+				return fmt.Errorf("failed to update %s: %w", f.path, err)
+			}
+		}
+		diff.Updated = append(diff.Updated, f.number)
+	}
+
+	for _, rec := range byNumber {
+		extra = append(extra, rec)
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i].number < extra[j].number })
+
+	for _, rec := range extra {
+		if !opts.Prune {
+			diff.Stale = append(diff.Stale, rec.number)
+			continue
+		}
+		if !opts.DryRun {
+			if err := actions.delete(rec.id); err != nil {
+				return fmt.Errorf("failed to delete record %d: %w", rec.id, err)
+			}
+		}
+		diff.Deleted = append(diff.Deleted, rec.number)
+	}
+
+	return nil
+}
+
+func syncGithubURL(base, filePath string, current *string) string {
+	if base == "" {
+		return derefString(current)
+	}
+	return strings.TrimRight(base, "/") + "/" + filePath
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// markdownTitle возвращает текст первого заголовка первого уровня или имя файла без расширения
+func markdownTitle(content, fileName string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		}
+	}
+	return strings.TrimSuffix(path.Base(fileName), path.Ext(fileName))
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// SyncExamQuestionRepositoryInterface - интерфейс для чтения и записи вопросов курса при синхронизации
+type SyncExamQuestionRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error)
+	Create(ctx context.Context, courseID, number int, section, question string) (*models.ExamQuestion, error)
+	Update(ctx context.Context, id, courseID, number int, section, question string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// SyncGradeSheetRepositoryInterface - интерфейс для чтения и записи ведомостей курса при синхронизации
+type SyncGradeSheetRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.GradeSheet, error)
+	Create(ctx context.Context, courseID int, sheetURL, description, status string, publishAt *time.Time) (*models.GradeSheet, error)
+	Update(ctx context.Context, id int, sheetURL, description string) error
+	Delete(ctx context.Context, id int) error
+}
+
+type examQuestionKey struct {
+	section string
+	number  int
+}
+
+// syncExamQuestions сопоставляет вопросы по паре (раздел, номер)
+func (s *ContentSyncService) syncExamQuestions(ctx context.Context, src contentSource, courseID int, opts ContentSyncOptions) (*models.ContentSyncCounts, error) {
+	data, err := src.ReadFile(ctx, opts.ExamQuestionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", opts.ExamQuestionsFile, err)
+	}
+
+	questions, err := parseExamQuestions(opts.ExamQuestionsFile, data)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.examQuestions.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[examQuestionKey]models.ExamQuestion, len(existing))
+	var extra []models.ExamQuestion
+	for _, q := range existing {
+		key := examQuestionKey{section: q.Section, number: q.Number}
+		if _, ok := byKey[key]; ok {
+			extra = append(extra, q)
+			continue
+		}
+		byKey[key] = q
+	}
+
+	counts := &models.ContentSyncCounts{}
+	seen := make(map[examQuestionKey]bool, len(questions))
+	for _, q := range questions {
+		key := examQuestionKey{section: q.Section, number: q.Number}
+		if seen[key] {
+			return nil, fmt.Errorf("%s: duplicate question %d in section %q", opts.ExamQuestionsFile, q.Number, q.Section)
+		}
+		seen[key] = true
+
+		current, ok := byKey[key]
+		switch {
+		case !ok:
+			if !opts.DryRun {
+				if _, err := s.examQuestions.Create(ctx, courseID, q.Number, q.Section, q.Question); err != nil {
+					return nil, fmt.Errorf("failed to create exam question %d: %w", q.Number, err)
+				}
+			}
+			counts.Created++
+		case current.Question == q.Question:
+			counts.Unchanged++
+		default:
+			if !opts.DryRun {
+				if err := s.examQuestions.Update(ctx, current.ID, courseID, q.Number, q.Section, q.Question); err != nil {
+					return nil, fmt.Errorf("failed to update exam question %d: %w", q.Number, err)
+				}
+			}
+			counts.Updated++
+		}
+		delete(byKey, key)
+	}
+
+	for _, q := range byKey {
+		extra = append(extra, q)
+	}
+	for _, q := range extra {
+		if err := pruneRecord(opts, counts, func() error { return s.examQuestions.Delete(ctx, q.ID) }); err != nil {
+			return nil, fmt.Errorf("failed to delete exam question %d: %w", q.ID, err)
+		}
+	}
+
+	return counts, nil
+}
+
+// syncGradeSheets сопоставляет ведомости по ссылке
+func (s *ContentSyncService) syncGradeSheets(ctx context.Context, src contentSource, courseID int, opts ContentSyncOptions) (*models.ContentSyncCounts, error) {
+	data, err := src.ReadFile(ctx, opts.GradeSheetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", opts.GradeSheetsFile, err)
+	}
+
+	sheets, err := parseGradeSheets(opts.GradeSheetsFile, data)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.gradeSheets.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byURL := make(map[string]models.GradeSheet, len(existing))
+	var extra []models.GradeSheet
+	for _, gs := range existing {
+		if _, ok := byURL[gs.SheetURL]; ok {
+			extra = append(extra, gs)
+			continue
+		}
+		byURL[gs.SheetURL] = gs
+	}
+
+	counts := &models.ContentSyncCounts{}
+	seen := make(map[string]bool, len(sheets))
+	for _, gs := range sheets {
+		if seen[gs.SheetURL] {
+			return nil, fmt.Errorf("%s: duplicate grade sheet %s", opts.GradeSheetsFile, gs.SheetURL)
+		}
+		seen[gs.SheetURL] = true
+
+		description := derefString(gs.Description)
+		current, ok := byURL[gs.SheetURL]
+		switch {
+		case !ok:
+			if !opts.DryRun {
+				if _, err := s.gradeSheets.Create(ctx, courseID, gs.SheetURL, description, "", nil); err != nil {
+					return nil, fmt.Errorf("failed to create grade sheet %s: %w", gs.SheetURL, err)
+				}
+			}
+			counts.Created++
+		case derefString(current.Description) == description:
+			counts.Unchanged++
+		default:
+			if !opts.DryRun {
+				if err := s.gradeSheets.Update(ctx, current.ID, gs.SheetURL, description); err != nil {
+					return nil, fmt.Errorf("failed to update grade sheet %s: %w", gs.SheetURL, err)
+				}
+			}
+			counts.Updated++
+		}
+		delete(byURL, gs.SheetURL)
+	}
+
+	for _, gs := range byURL {
+		extra = append(extra, gs)
+	}
+	for _, gs := range extra {
+		if err := pruneRecord(opts, counts, func() error { return s.gradeSheets.Delete(ctx, gs.ID) }); err != nil {
+			return nil, fmt.Errorf("failed to delete grade sheet %d: %w", gs.ID, err)
+		}
+	}
+
+	return counts, nil
+}
+
+// pruneRecord удаляет запись без источника при Prune, иначе считает её устаревшей
+func pruneRecord(opts ContentSyncOptions, counts *models.ContentSyncCounts, del func() error) error {
+	if !opts.Prune {
+		counts.Stale++
+		return nil
+	}
+	if !opts.DryRun {
+		if err := del(); err != nil {
+			return err
+		}
+	}
+	counts.Deleted++
+	return nil
+}
+
+// parseExamQuestions разбирает JSON {"questions": [...]} или CSV с колонками number,section,question -
+// те же форматы, что принимает загрузка вопросов в админке
+func parseExamQuestions(name string, data []byte) ([]models.ExamQuestion, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		var payload struct {
+			Questions []struct {
+				Number   int    `json:"number"`
+				Section  string `json:"section"`
+				Question string `json:"question"`
+			} `json:"questions"`
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("%s: invalid JSON: %w", name, err)
+		}
+
+		questions := make([]models.ExamQuestion, 0, len(payload.Questions))
+		for i, q := range payload.Questions {
+			if q.Section == "" || q.Question == "" {
+				return nil, fmt.Errorf("%s: question %d must have section and question", name, i+1)
+			}
+			questions = append(questions, models.ExamQuestion{Number: q.Number, Section: q.Section, Question: q.Question})
+		}
+		return questions, nil
+
+	case ".csv":
+		records, err := readCSVRows(name, data, 3)
+		if err != nil {
+			return nil, err
+		}
+
+		questions := make([]models.ExamQuestion, 0, len(records))
+		for i, record := range records {
+			number, err := strconv.Atoi(strings.TrimSpace(record[0]))
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid number in row %d: %w", name, i+2, err)
+			}
+			questions = append(questions, models.ExamQuestion{Number: number, Section: record[1], Question: record[2]})
+		}
+		return questions, nil
+
+	default:
+		return nil, fmt.Errorf("%s: only .json and .csv files are supported", name)
+	}
+}
+
+// parseGradeSheets разбирает JSON {"grade_sheets": [...]} или CSV с колонками sheet_url,description
+func parseGradeSheets(name string, data []byte) ([]models.GradeSheet, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		var payload struct {
+			GradeSheets []struct {
+				SheetURL    string `json:"sheet_url"`
+				Description string `json:"description"`
+			} `json:"grade_sheets"`
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("%s: invalid JSON: %w", name, err)
+		}
+
+		sheets := make([]models.GradeSheet, 0, len(payload.GradeSheets))
+		for i, gs := range payload.GradeSheets {
+			if gs.SheetURL == "" {
+				return nil, fmt.Errorf("%s: grade sheet %d must have sheet_url", name, i+1)
+			}
+			description := gs.Description
+			sheets = append(sheets, models.GradeSheet{SheetURL: gs.SheetURL, Description: &description})
+		}
+		return sheets, nil
+
+	case ".csv":
+		records, err := readCSVRows(name, data, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		sheets := make([]models.GradeSheet, 0, len(records))
+		for i, record := range records {
+			sheetURL := strings.TrimSpace(record[0])
+			if sheetURL == "" {
+				return nil, fmt.Errorf("%s: empty sheet_url in row %d", name, i+2)
+			}
+			description := ""
+			if len(record) > 1 {
+				description = record[1]
+			}
+			sheets = append(sheets, models.GradeSheet{SheetURL: sheetURL, Description: &description})
+		}
+		return sheets, nil
+
+	default:
+		return nil, fmt.Errorf("%s: only .json and .csv files are supported", name)
+	}
+}
+
+// readCSVRows возвращает строки данных без заголовка, проверяя минимальное число колонок
+func readCSVRows(name string, data []byte, minColumns int) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read CSV: %w", name, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s: CSV must contain header and at least one data row", name)
+	}
+
+	rows := records[1:]
+	for i, record := range rows {
+		if len(record) < minColumns {
+			return nil, fmt.Errorf("%s: row %d has %d columns, expected at least %d", name, i+2, len(record), minColumns)
+		}
+	}
+	return rows, nil
+}
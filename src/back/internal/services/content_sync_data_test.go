@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExamQuestionStore - хранилище вопросов в памяти
+type fakeExamQuestionStore struct {
+	items  []models.ExamQuestion
+	nextID int
+	writes int
+}
+
+func (s *fakeExamQuestionStore) GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error) {
+	var res []models.ExamQuestion
+	for _, q := range s.items {
+		if q.CourseID == courseID {
+			res = append(res, q)
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeExamQuestionStore) Create(ctx context.Context, courseID, number int, section, question string) (*models.ExamQuestion, error) {
+	s.writes++
+	s.nextID++
+	q := models.ExamQuestion{ID: 300 + s.nextID, CourseID: courseID, Number: number, Section: section, Question: question}
+	s.items = append(s.items, q)
+	return &q, nil
+}
+
+func (s *fakeExamQuestionStore) Update(ctx context.Context, id, courseID, number int, section, question string) error {
+	s.writes++
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items[i] = models.ExamQuestion{ID: id, CourseID: courseID, Number: number, Section: section, Question: question}
+		}
+	}
+	return nil
+}
+
+func (s *fakeExamQuestionStore) Delete(ctx context.Context, id int) error {
+	s.writes++
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// fakeGradeSheetStore - хранилище ведомостей в памяти
+type fakeGradeSheetStore struct {
+	items  []models.GradeSheet
+	nextID int
+}
+
+func (s *fakeGradeSheetStore) GetByCourseID(ctx context.Context, courseID int) ([]models.GradeSheet, error) {
+	var res []models.GradeSheet
+	for _, gs := range s.items {
+		if gs.CourseID == courseID {
+			res = append(res, gs)
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeGradeSheetStore) Create(ctx context.Context, courseID int, sheetURL, description, status string, publishAt *time.Time) (*models.GradeSheet, error) {
+	s.nextID++
+	gs := models.GradeSheet{ID: 400 + s.nextID, CourseID: courseID, SheetURL: sheetURL, Description: &description}
+	s.items = append(s.items, gs)
+	return &gs, nil
+}
+
+func (s *fakeGradeSheetStore) Update(ctx context.Context, id int, sheetURL, description string) error {
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items[i].SheetURL = sheetURL
+			s.items[i].Description = &description
+		}
+	}
+	return nil
+}
+
+func (s *fakeGradeSheetStore) Delete(ctx context.Context, id int) error {
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestContentSyncService_Sync_ExamQuestionsAndGradeSheets(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoFiles(t, repo, map[string]string{
+		"exam/questions.csv": "number,section,question\n1,Основы,Что такое middleware?\n2,Основы,Что такое DI?\n1,Данные,Что такое EF Core?\n",
+		"grades.json":        `{"grade_sheets": [{"sheet_url": "https://sheets.example/a", "description": "Основная"}]}`,
+	})
+
+	questions := &fakeExamQuestionStore{items: []models.ExamQuestion{
+		{ID: 1, CourseID: 1, Number: 1, Section: "Основы", Question: "Что такое middleware?"},
+		{ID: 2, CourseID: 1, Number: 2, Section: "Основы", Question: "Старая формулировка"},
+		{ID: 3, CourseID: 1, Number: 9, Section: "Основы", Question: "Убранный вопрос"},
+	}}
+	sheets := &fakeGradeSheetStore{items: []models.GradeSheet{
+		{ID: 1, CourseID: 1, SheetURL: "https://sheets.example/a", Description: strPtr("Старое описание")},
+	}}
+
+	service := NewContentSyncService(fakeCourseGetter{1: true}, &fakeLectureStore{}, &fakeLabStore{}, questions, sheets, &fakeAnnouncer{}, slog.Default())
+
+	report, err := service.Sync(context.Background(), 1, ContentSyncOptions{
+		RepoPath:          repo,
+		ExamQuestionsFile: "exam/questions.csv",
+		GradeSheetsFile:   "grades.json",
+		Prune:             true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &models.ContentSyncCounts{Created: 1, Updated: 1, Unchanged: 1, Deleted: 1}, report.ExamQuestions)
+	assert.Equal(t, &models.ContentSyncCounts{Updated: 1}, report.GradeSheets)
+
+	require.Len(t, questions.items, 3)
+	assert.Equal(t, "Что такое DI?", questions.items[1].Question)
+	assert.Equal(t, "Данные", questions.items[2].Section)
+	assert.Equal(t, "Основная", *sheets.items[0].Description)
+
+	// Повторный запуск ничего не меняет
+	questions.writes = 0
+	report, err = service.Sync(context.Background(), 1, ContentSyncOptions{
+		RepoPath:          repo,
+		ExamQuestionsFile: "exam/questions.csv",
+		Prune:             true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.ContentSyncCounts{Unchanged: 3}, report.ExamQuestions)
+	assert.Nil(t, report.GradeSheets)
+	assert.Zero(t, questions.writes)
+}
+
+func TestContentSyncService_Sync_DryRunForNewCourse(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoFiles(t, repo, map[string]string{
+		"lections/L1.md":      "# Введение",
+		"exam/questions.json": `{"questions": [{"number": 1, "section": "Основы", "question": "Что такое HTTP?"}]}`,
+	})
+
+	questions := &fakeExamQuestionStore{}
+	service := NewContentSyncService(fakeCourseGetter{}, &fakeLectureStore{}, &fakeLabStore{}, questions, &fakeGradeSheetStore{}, &fakeAnnouncer{}, slog.Default())
+
+	report, err := service.Sync(context.Background(), 0, ContentSyncOptions{
+		RepoPath:          repo,
+		ExamQuestionsFile: "exam/questions.json",
+		DryRun:            true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1}, report.Lectures.Created)
+	assert.Equal(t, 1, report.ExamQuestions.Created)
+	assert.Zero(t, questions.writes)
+}
+
+func TestParseExamQuestions(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		data        string
+		expectedLen int
+		expectError bool
+	}{
+		{
+			name:        "json",
+			file:        "q.json",
+			data:        `{"questions": [{"number": 1, "section": "A", "question": "Q1"}, {"number": 2, "section": "A", "question": "Q2"}]}`,
+			expectedLen: 2,
+		},
+		{
+			name:        "csv",
+			file:        "q.CSV",
+			data:        "number,section,question\n1,A,Q1\n",
+			expectedLen: 1,
+		},
+		{
+			name:        "json without section",
+			file:        "q.json",
+			data:        `{"questions": [{"number": 1, "question": "Q1"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "csv with invalid number",
+			file:        "q.csv",
+			data:        "number,section,question\nодин,A,Q1\n",
+			expectError: true,
+		},
+		{
+			name:        "csv without data rows",
+			file:        "q.csv",
+			data:        "number,section,question\n",
+			expectError: true,
+		},
+		{
+			name:        "unsupported extension",
+			file:        "q.txt",
+			data:        "1,A,Q1",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, err := parseExamQuestions(tt.file, []byte(tt.data))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, questions, tt.expectedLen)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCourseGetter - курс существует, если его ID есть в множестве
+type fakeCourseGetter map[int]bool
+
+func (f fakeCourseGetter) GetByID(ctx context.Context, id int) (*models.Course, error) {
+	if !f[id] {
+		return nil, nil
+	}
+	return &models.Course{ID: id}, nil
+}
+
+// fakeAnnouncer запоминает заголовки объявленных лекций и возвращает err
+type fakeAnnouncer struct {
+	titles []string
+	err    error
+}
+
+func (a *fakeAnnouncer) LecturePublished(ctx context.Context, courseID int, title string) error {
+	a.titles = append(a.titles, title)
+	return a.err
+}
+
+// fakeLectureStore - хранилище лекций в памяти
+type fakeLectureStore struct {
+	items  []models.Lecture
+	nextID int
+	writes int
+}
+
+func (s *fakeLectureStore) GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error) {
+	var res []models.Lecture
+	for _, l := range s.items {
+		if l.CourseID == courseID {
+			res = append(res, l)
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeLectureStore) Create(ctx context.Context, courseID, week int, title, content, githubURL, status string, publishAt *time.Time) (*models.Lecture, error) {
+	s.writes++
+	s.nextID++
+	l := models.Lecture{ID: 100 + s.nextID, CourseID: courseID, Week: week, Title: title, Content: content, GithubURL: &githubURL}
+	s.items = append(s.items, l)
+	return &l, nil
+}
+
+func (s *fakeLectureStore) Update(ctx context.Context, id, courseID, week int, title, content, githubURL string) error {
+	s.writes++
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items[i] = models.Lecture{ID: id, CourseID: courseID, Week: week, Title: title, Content: content, GithubURL: &githubURL}
+		}
+	}
+	return nil
+}
+
+func (s *fakeLectureStore) Delete(ctx context.Context, id int) error {
+	s.writes++
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// fakeLabStore - хранилище лабораторных в памяти
+type fakeLabStore struct {
+	items  []models.Lab
+	nextID int
+}
+
+func (s *fakeLabStore) GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error) {
+	var res []models.Lab
+	for _, l := range s.items {
+		if l.CourseID == courseID {
+			res = append(res, l)
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeLabStore) Create(ctx context.Context, courseID, number, maxScore int, title, description, githubURL string, deadline *string, status string, publishAt *time.Time) (*models.Lab, error) {
+	s.nextID++
+	l := models.Lab{ID: 200 + s.nextID, CourseID: courseID, Number: number, MaxScore: maxScore, Title: title, Description: description, GithubURL: &githubURL}
+	s.items = append(s.items, l)
+	return &l, nil
+}
+
+func (s *fakeLabStore) Update(ctx context.Context, id, courseID, number, maxScore int, title, description, githubURL string, deadline *string) error {
+	for i := range s.items {
+		if s.items[i].ID != id {
+			continue
+		}
+		l := models.Lab{ID: id, CourseID: courseID, Number: number, MaxScore: maxScore, Title: title, Description: description, GithubURL: &githubURL}
+		if deadline != nil {
+			d, err := time.Parse(time.DateTime, *deadline)
+			if err != nil {
+				return err
+			}
+			l.Deadline = &d
+		}
+		s.items[i] = l
+	}
+	return nil
+}
+
+func (s *fakeLabStore) Delete(ctx context.Context, id int) error {
+	for i := range s.items {
+		if s.items[i].ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func writeRepoFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestContentSyncService_Sync_WorkingCopy(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoFiles(t, repo, map[string]string{
+		"lections/L1.md": "# Введение\n\nТекст",
+		"lections/L2.md": "# Контроллеры\n\nНовый текст",
+		"lections/L3.md": "без заголовка",
+		"lections/LX.md": "# Без номера",
+		"Lab1.md":        "# Web API\n\nЗадание",
+		"README.md":      "# Курс",
+	})
+
+	deadline := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	lectures := &fakeLectureStore{items: []models.Lecture{
+		{ID: 1, CourseID: 1, Week: 1, Title: "Введение", Content: "# Введение\n\nТекст", GithubURL: strPtr("https://example.com/blob/main/lections/L1.md")},
+		{ID: 2, CourseID: 1, Week: 2, Title: "Контроллеры", Content: "старый текст", GithubURL: strPtr("https://example.com/blob/main/lections/L2.md")},
+		{ID: 4, CourseID: 1, Week: 4, Title: "Удалённая", Content: "..."},
+		{ID: 5, CourseID: 2, Week: 9, Title: "Чужой курс"},
+	}}
+	labs := &fakeLabStore{items: []models.Lab{
+		{ID: 10, CourseID: 1, Number: 1, Title: "Web API", Description: "старое", MaxScore: 20, Deadline: &deadline},
+	}}
+
+	announcer := &fakeAnnouncer{err: errors.New("announcements are down")}
+	service := NewContentSyncService(fakeCourseGetter{1: true}, lectures, labs, &fakeExamQuestionStore{}, &fakeGradeSheetStore{}, announcer, slog.Default())
+
+	report, err := service.Sync(context.Background(), 1, ContentSyncOptions{
+		RepoPath:   repo,
+		GithubBase: "https://example.com/blob/main/",
+	})
+	require.NoError(t, err, "failed announcement does not fail the sync")
+
+	assert.Equal(t, []int{3}, report.Lectures.Created)
+	assert.Equal(t, []string{"L3"}, announcer.titles, "only created lectures are announced")
+	assert.Equal(t, []int{2}, report.Lectures.Updated)
+	assert.Equal(t, []int{1}, report.Lectures.Unchanged)
+	assert.Equal(t, []int{4}, report.Lectures.Stale)
+	assert.Empty(t, report.Lectures.Deleted)
+	assert.Equal(t, []string{"lections/LX.md"}, report.Lectures.Skipped)
+
+	assert.Equal(t, []int{1}, report.Labs.Updated)
+
+	created, _ := lectures.GetByCourseID(context.Background(), 1)
+	require.Len(t, created, 4)
+	assert.Equal(t, "L3", created[3].Title)
+	assert.Equal(t, "https://example.com/blob/main/lections/L3.md", *created[3].GithubURL)
+
+	// Балл и дедлайн, заданные в админке, не затираются
+	lab := labs.items[0]
+	assert.Equal(t, "# Web API\n\nЗадание", lab.Description)
+	assert.Equal(t, 20, lab.MaxScore)
+	require.NotNil(t, lab.Deadline)
+	assert.True(t, deadline.Equal(*lab.Deadline))
+}
+
+func TestContentSyncService_Sync_PruneAndDryRun(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoFiles(t, repo, map[string]string{
+		"lections/L1.md": "# Введение",
+	})
+
+	newStore := func() *fakeLectureStore {
+		return &fakeLectureStore{items: []models.Lecture{
+			{ID: 1, CourseID: 1, Week: 1, Title: "Введение", Content: "# Введение"},
+			{ID: 2, CourseID: 1, Week: 1, Title: "Дубликат", Content: "# Введение"},
+			{ID: 3, CourseID: 1, Week: 2, Title: "Удалённая"},
+		}}
+	}
+
+	t.Run("prune deletes records without files", func(t *testing.T) {
+		lectures := newStore()
+		service := NewContentSyncService(fakeCourseGetter{1: true}, lectures, &fakeLabStore{}, &fakeExamQuestionStore{}, &fakeGradeSheetStore{}, &fakeAnnouncer{}, slog.Default())
+
+		report, err := service.Sync(context.Background(), 1, ContentSyncOptions{RepoPath: repo, Prune: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{1}, report.Lectures.Unchanged)
+		assert.Equal(t, []int{1, 2}, report.Lectures.Deleted)
+		assert.Len(t, lectures.items, 1)
+		assert.Equal(t, 1, lectures.items[0].ID)
+	})
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		writeRepoFiles(t, repo, map[string]string{"lections/L5.md": "# Новая"})
+		lectures := newStore()
+		announcer := &fakeAnnouncer{}
+		service := NewContentSyncService(fakeCourseGetter{1: true}, lectures, &fakeLabStore{}, &fakeExamQuestionStore{}, &fakeGradeSheetStore{}, announcer, slog.Default())
+
+		report, err := service.Sync(context.Background(), 1, ContentSyncOptions{RepoPath: repo, Prune: true, DryRun: true})
+		require.NoError(t, err)
+
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []int{5}, report.Lectures.Created)
+		assert.Equal(t, []int{1, 2}, report.Lectures.Deleted)
+		assert.Zero(t, lectures.writes)
+		assert.Len(t, lectures.items, 3)
+		assert.Empty(t, announcer.titles)
+	})
+}
+
+func TestContentSyncService_Sync_BareRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	work := t.TempDir()
+	writeRepoFiles(t, work, map[string]string{
+		"lections/L1.md": "# Из репозитория",
+		"Lab2.md":        "# Лабораторная",
+	})
+	bare := filepath.Join(t.TempDir(), "course.git")
+
+	runGit := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = work
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "init")
+	runGit("clone", "-q", "--bare", work, bare)
+
+	lectures := &fakeLectureStore{}
+	labs := &fakeLabStore{}
+	service := NewContentSyncService(fakeCourseGetter{1: true}, lectures, labs, &fakeExamQuestionStore{}, &fakeGradeSheetStore{}, &fakeAnnouncer{}, slog.Default())
+
+	report, err := service.Sync(context.Background(), 1, ContentSyncOptions{RepoPath: bare})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1}, report.Lectures.Created)
+	assert.Equal(t, []int{2}, report.Labs.Created)
+	require.Len(t, lectures.items, 1)
+	assert.Equal(t, "Из репозитория", lectures.items[0].Title)
+	assert.Equal(t, defaultLabMaxScore, labs.items[0].MaxScore)
+}
+
+func TestContentSyncService_Sync_Errors(t *testing.T) {
+	repo := t.TempDir()
+	service := NewContentSyncService(fakeCourseGetter{1: true}, &fakeLectureStore{}, &fakeLabStore{}, &fakeExamQuestionStore{}, &fakeGradeSheetStore{}, &fakeAnnouncer{}, slog.Default())
+
+	t.Run("course not found", func(t *testing.T) {
+		_, err := service.Sync(context.Background(), 2, ContentSyncOptions{RepoPath: repo})
+		assert.ErrorIs(t, err, ErrSyncCourseNotFound)
+	})
+
+	t.Run("repository not found", func(t *testing.T) {
+		_, err := service.Sync(context.Background(), 1, ContentSyncOptions{RepoPath: filepath.Join(repo, "missing")})
+		assert.ErrorIs(t, err, ErrSyncRepoNotFound)
+	})
+
+	t.Run("pattern outside repository", func(t *testing.T) {
+		_, err := service.Sync(context.Background(), 1, ContentSyncOptions{RepoPath: repo, LabsGlob: "../*.md"})
+		assert.ErrorIs(t, err, ErrInvalidSyncPattern)
+	})
+}
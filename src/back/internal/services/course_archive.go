@@ -0,0 +1,451 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// Архив курса - zip, в котором manifest.json описывает курс и материалы, тексты лекций
+// и лабораторных лежат отдельными Markdown-файлами, а вопросы к экзамену - в CSV
+// с колонками number,section,question, как при загрузке вопросов в админке
+const (
+	// CourseArchiveVersion - версия формата, в которой пишутся новые архивы
+	CourseArchiveVersion = 1
+
+	courseArchiveManifest      = "manifest.json"
+	courseArchiveExamQuestions = "exam_questions.csv"
+
+	// maxCourseArchiveFileSize ограничивает распакованный размер одного файла архива
+	maxCourseArchiveFileSize = 32 << 20
+)
+
+// ErrInvalidCourseArchive - архив повреждён, не соответствует формату или записан более новой версией
+var ErrInvalidCourseArchive = errors.New("invalid course archive")
+
+// manifestUpgrades[v] переводит манифест версии v в версию v+1. При изменении формата
+// увеличьте CourseArchiveVersion и добавьте сюда шаг, чтобы старые архивы оставались импортируемыми.
+var manifestUpgrades = map[int]func(manifest map[string]any) error{}
+
+type courseManifest struct {
+	SchemaVersion int                  `json:"schema_version"`
+	ExportedAt    time.Time            `json:"exported_at"`
+	Course        manifestCourse       `json:"course"`
+	Lectures      []manifestLecture    `json:"lectures"`
+	Labs          []manifestLab        `json:"labs"`
+	ExamQuestions string               `json:"exam_questions,omitempty"`
+	GradeSheets   []manifestGradeSheet `json:"grade_sheets"`
+}
+
+type manifestCourse struct {
+	Name        string `json:"name"`
+	Semester    string `json:"semester"`
+	Description string `json:"description"`
+}
+
+type manifestLecture struct {
+	Week      int        `json:"week"`
+	Title     string     `json:"title"`
+	File      string     `json:"file"`
+	GithubURL *string    `json:"github_url,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+type manifestLab struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	File      string     `json:"file"`
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	MaxScore  int        `json:"max_score"`
+	GithubURL *string    `json:"github_url,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+type manifestGradeSheet struct {
+	SheetURL    string     `json:"sheet_url"`
+	Description *string    `json:"description,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+}
+
+// ArchiveCourseRepositoryInterface - интерфейс для чтения и создания курса при экспорте и импорте
+type ArchiveCourseRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Course, error)
+	Import(ctx context.Context, content *models.CourseContent) (*models.CourseImportSummary, error)
+}
+
+// ArchiveLectureRepositoryInterface - интерфейс для чтения лекций курса при экспорте
+type ArchiveLectureRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error)
+}
+
+// ArchiveLabRepositoryInterface - интерфейс для чтения лабораторных курса при экспорте
+type ArchiveLabRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error)
+}
+
+// ArchiveExamQuestionRepositoryInterface - интерфейс для чтения вопросов курса при экспорте
+type ArchiveExamQuestionRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error)
+}
+
+// ArchiveGradeSheetRepositoryInterface - интерфейс для чтения ведомостей курса при экспорте
+type ArchiveGradeSheetRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.GradeSheet, error)
+}
+
+// CourseArchiveImportOptions - параметры импорта архива
+type CourseArchiveImportOptions struct {
+	// Semester заменяет семестр из архива, например при переносе курса на новый учебный год
+	Semester string
+}
+
+type CourseArchiveService struct {
+	courses       ArchiveCourseRepositoryInterface
+	lectures      ArchiveLectureRepositoryInterface
+	labs          ArchiveLabRepositoryInterface
+	examQuestions ArchiveExamQuestionRepositoryInterface
+	gradeSheets   ArchiveGradeSheetRepositoryInterface
+}
+
+func NewCourseArchiveService(
+	courses ArchiveCourseRepositoryInterface,
+	lectures ArchiveLectureRepositoryInterface,
+	labs ArchiveLabRepositoryInterface,
+	examQuestions ArchiveExamQuestionRepositoryInterface,
+	gradeSheets ArchiveGradeSheetRepositoryInterface,
+) *CourseArchiveService {
+	return &CourseArchiveService{
+		courses:       courses,
+		lectures:      lectures,
+		labs:          labs,
+		examQuestions: examQuestions,
+		gradeSheets:   gradeSheets,
+	}
+}
+
+// Export пишет архив курса в w; возвращает repository.ErrCourseNotFound, если курса нет
+func (s *CourseArchiveService) Export(ctx context.Context, courseID int, w io.Writer) (*models.Course, error) {
+	course, err := s.courses.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, repository.ErrCourseNotFound
+	}
+
+	lectures, err := s.lectures.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	labs, err := s.labs.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := s.examQuestions.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	sheets, err := s.gradeSheets.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := courseManifest{
+		SchemaVersion: CourseArchiveVersion,
+		ExportedAt:    time.Now().UTC().Truncate(time.Second),
+		Course: manifestCourse{
+			Name:        course.Name,
+			Semester:    course.Semester,
+			Description: course.Description,
+		},
+		Lectures:    make([]manifestLecture, 0, len(lectures)),
+		Labs:        make([]manifestLab, 0, len(labs)),
+		GradeSheets: make([]manifestGradeSheet, 0, len(sheets)),
+	}
+
+	archive := zip.NewWriter(w)
+	writeFile := func(name string, data []byte) error {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: manifest.ExportedAt})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", name, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s to archive: %w", name, err)
+		}
+		return nil
+	}
+
+	for i, l := range lectures {
+		file := fmt.Sprintf("lectures/%03d.md", i+1)
+		if err := writeFile(file, []byte(l.Content)); err != nil {
+			return nil, err
+		}
+		manifest.Lectures = append(manifest.Lectures, manifestLecture{
+			Week:      l.Week,
+			Title:     l.Title,
+			File:      file,
+			GithubURL: l.GithubURL,
+			Status:    l.Status,
+			PublishAt: l.PublishAt,
+		})
+	}
+
+	for i, l := range labs {
+		file := fmt.Sprintf("labs/%03d.md", i+1)
+		if err := writeFile(file, []byte(l.Description)); err != nil {
+			return nil, err
+		}
+		manifest.Labs = append(manifest.Labs, manifestLab{
+			Number:    l.Number,
+			Title:     l.Title,
+			File:      file,
+			Deadline:  l.Deadline,
+			MaxScore:  l.MaxScore,
+			GithubURL: l.GithubURL,
+			Status:    l.Status,
+			PublishAt: l.PublishAt,
+		})
+	}
+
+	if len(questions) > 0 {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Write([]string{"number", "section", "question"})
+		for _, q := range questions {
+			writer.Write([]string{strconv.Itoa(q.Number), q.Section, q.Question})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write exam questions: %w", err)
+		}
+		if err := writeFile(courseArchiveExamQuestions, buf.Bytes()); err != nil {
+			return nil, err
+		}
+		manifest.ExamQuestions = courseArchiveExamQuestions
+	}
+
+	for _, gs := range sheets {
+		manifest.GradeSheets = append(manifest.GradeSheets, manifestGradeSheet{
+			SheetURL:    gs.SheetURL,
+			Description: gs.Description,
+			Status:      gs.Status,
+			PublishAt:   gs.PublishAt,
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeFile(courseArchiveManifest, data); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return course, nil
+}
+
+// Import создаёт новый курс из архива; ошибки формата оборачивают ErrInvalidCourseArchive
+func (s *CourseArchiveService) Import(ctx context.Context, r io.ReaderAt, size int64, opts CourseArchiveImportOptions) (*models.CourseImportSummary, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCourseArchive, err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	readFile := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing file %s", ErrInvalidCourseArchive, name)
+		}
+		return readArchiveFile(f)
+	}
+
+	data, err := readFile(courseArchiveManifest)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := decodeCourseManifest(data, manifestUpgrades, CourseArchiveVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &models.CourseContent{
+		Course: models.Course{
+			Name:        manifest.Course.Name,
+			Semester:    manifest.Course.Semester,
+			Description: manifest.Course.Description,
+		},
+	}
+	if opts.Semester != "" {
+		content.Course.Semester = opts.Semester
+	}
+	if content.Course.Name == "" || content.Course.Semester == "" {
+		return nil, fmt.Errorf("%w: course name and semester are required", ErrInvalidCourseArchive)
+	}
+
+	for _, l := range manifest.Lectures {
+		if l.Title == "" {
+			return nil, fmt.Errorf("%w: lecture %s has no title", ErrInvalidCourseArchive, l.File)
+		}
+		if err := validateArchiveStatus(l.Status); err != nil {
+			return nil, fmt.Errorf("%w: lecture %s: %v", ErrInvalidCourseArchive, l.File, err)
+		}
+		text, err := readFile(l.File)
+		if err != nil {
+			return nil, err
+		}
+		content.Lectures = append(content.Lectures, models.Lecture{
+			Week:      l.Week,
+			Title:     l.Title,
+			Content:   string(text),
+			GithubURL: l.GithubURL,
+			Status:    l.Status,
+			PublishAt: l.PublishAt,
+		})
+	}
+
+	for _, l := range manifest.Labs {
+		if l.Title == "" {
+			return nil, fmt.Errorf("%w: lab %d has no title", ErrInvalidCourseArchive, l.Number)
+		}
+		if err := validateArchiveStatus(l.Status); err != nil {
+			return nil, fmt.Errorf("%w: lab %d: %v", ErrInvalidCourseArchive, l.Number, err)
+		}
+		text, err := readFile(l.File)
+		if err != nil {
+			return nil, err
+		}
+		content.Labs = append(content.Labs, models.Lab{
+			Number:      l.Number,
+			Title:       l.Title,
+			Description: string(text),
+			Deadline:    l.Deadline,
+			MaxScore:    l.MaxScore,
+			GithubURL:   l.GithubURL,
+			Status:      l.Status,
+			PublishAt:   l.PublishAt,
+		})
+	}
+
+	if manifest.ExamQuestions != "" {
+		data, err := readFile(manifest.ExamQuestions)
+		if err != nil {
+			return nil, err
+		}
+		questions, err := parseExamQuestions(manifest.ExamQuestions, data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCourseArchive, err)
+		}
+		content.ExamQuestions = questions
+	}
+
+	for i, gs := range manifest.GradeSheets {
+		if gs.SheetURL == "" {
+			return nil, fmt.Errorf("%w: grade sheet %d has no sheet_url", ErrInvalidCourseArchive, i+1)
+		}
+		if err := validateArchiveStatus(gs.Status); err != nil {
+			return nil, fmt.Errorf("%w: grade sheet %d: %v", ErrInvalidCourseArchive, i+1, err)
+		}
+		content.GradeSheets = append(content.GradeSheets, models.GradeSheet{
+			SheetURL:    gs.SheetURL,
+			Description: gs.Description,
+			Status:      gs.Status,
+			PublishAt:   gs.PublishAt,
+		})
+	}
+
+	summary, err := s.courses.Import(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	summary.SchemaVersion = manifest.SchemaVersion
+	return summary, nil
+}
+
+// validateArchiveStatus проверяет статус публикации из манифеста; в архивах до появления
+// статусов его нет, и такие материалы импортируются опубликованными
+func validateArchiveStatus(status string) error {
+	switch status {
+	case "", models.PublicationStatusDraft, models.PublicationStatusPublished:
+		return nil
+	}
+	return fmt.Errorf("unknown status %q", status)
+}
+
+// readArchiveFile читает файл архива, не распаковывая больше maxCourseArchiveFileSize
+func readArchiveFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCourseArchive, f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxCourseArchiveFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCourseArchive, f.Name, err)
+	}
+	if len(data) > maxCourseArchiveFileSize {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidCourseArchive, f.Name, maxCourseArchiveFileSize)
+	}
+	return data, nil
+}
+
+// decodeCourseManifest разбирает манифест, поднимая его версию шагами upgrades до current.
+// Первоначальная версия манифеста сохраняется в SchemaVersion результата.
+func decodeCourseManifest(data []byte, upgrades map[int]func(map[string]any) error, current int) (*courseManifest, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCourseArchive, courseArchiveManifest, err)
+	}
+
+	version, ok := raw["schema_version"].(float64)
+	if !ok || version < 1 || version != float64(int(version)) {
+		return nil, fmt.Errorf("%w: %s has no valid schema_version", ErrInvalidCourseArchive, courseArchiveManifest)
+	}
+	original := int(version)
+	if original > current {
+		return nil, fmt.Errorf("%w: schema version %d is newer than supported %d", ErrInvalidCourseArchive, original, current)
+	}
+
+	for v := original; v < current; v++ {
+		upgrade, ok := upgrades[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: no upgrade from schema version %d", ErrInvalidCourseArchive, v)
+		}
+		if err := upgrade(raw); err != nil {
+			return nil, fmt.Errorf("%w: upgrade from schema version %d: %v", ErrInvalidCourseArchive, v, err)
+		}
+	}
+
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	var manifest courseManifest
+	if err := json.Unmarshal(upgraded, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCourseArchive, courseArchiveManifest, err)
+	}
+	manifest.SchemaVersion = original
+
+	return &manifest, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCourseArchiveService(store *repository.Store) *CourseArchiveService {
+	return NewCourseArchiveService(store.Courses, store.Lectures, store.Labs, store.ExamQuestions, store.GradeSheets)
+}
+
+func TestCourseArchiveService_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := memory.NewStore(memory.NewDB())
+	course, err := source.Courses.Create(ctx, "Go", "2025-fall", "Основы языка")
+	require.NoError(t, err)
+	_, err = source.Lectures.Create(ctx, course.ID, 1, "Введение", "# Привет\n\nТекст лекции", "https://github.com/example/go/lecture1.md", "", nil)
+	require.NoError(t, err)
+	deadline := "2025-10-01 23:59:00"
+	lab, err := source.Labs.Create(ctx, course.ID, 1, 10, "Калькулятор", "Сделайте калькулятор", "", &deadline, "", nil)
+	require.NoError(t, err)
+	publishAt := time.Date(2025, 9, 15, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, source.Labs.SetPublication(ctx, lab.ID, models.PublicationStatusDraft, &publishAt))
+	_, err = source.ExamQuestions.Create(ctx, course.ID, 1, "Основы", "Что такое срез, \"slice\"?")
+	require.NoError(t, err)
+	_, err = source.GradeSheets.Create(ctx, course.ID, "https://example.com/sheet", "Ведомость", "", nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	exported, err := newCourseArchiveService(source).Export(ctx, course.ID, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "Go", exported.Name)
+
+	target := memory.NewStore(memory.NewDB())
+	summary, err := newCourseArchiveService(target).Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()), CourseArchiveImportOptions{Semester: "2026-fall"})
+	require.NoError(t, err)
+	assert.Equal(t, CourseArchiveVersion, summary.SchemaVersion)
+	assert.Equal(t, "2026-fall", summary.Course.Semester)
+	assert.Equal(t, "Основы языка", summary.Course.Description)
+	assert.Equal(t, 1, summary.Lectures)
+	assert.Equal(t, 1, summary.Labs)
+	assert.Equal(t, 1, summary.ExamQuestions)
+	assert.Equal(t, 1, summary.GradeSheets)
+
+	lectures, err := target.Lectures.GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, lectures, 1)
+	assert.Equal(t, "# Привет\n\nТекст лекции", lectures[0].Content)
+	require.NotNil(t, lectures[0].GithubURL)
+
+	labs, err := target.Labs.GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, labs, 1)
+	assert.Equal(t, "Сделайте калькулятор", labs[0].Description)
+	assert.Equal(t, models.PublicationStatusDraft, labs[0].Status)
+	require.NotNil(t, labs[0].PublishAt)
+	assert.True(t, labs[0].PublishAt.Equal(publishAt))
+	assert.Equal(t, models.PublicationStatusPublished, lectures[0].Status)
+	require.NotNil(t, labs[0].Deadline)
+	assert.Equal(t, "2025-10-01 23:59", labs[0].Deadline.Format("2006-01-02 15:04"))
+
+	questions, err := target.ExamQuestions.GetByCourseID(ctx, summary.Course.ID)
+	require.NoError(t, err)
+	require.Len(t, questions, 1)
+	assert.Equal(t, "Что такое срез, \"slice\"?", questions[0].Question)
+
+	_, err = newCourseArchiveService(source).Export(ctx, 999, &buf)
+	assert.ErrorIs(t, err, repository.ErrCourseNotFound)
+}
+
+func TestCourseArchiveService_ImportInvalid(t *testing.T) {
+	ctx := context.Background()
+	service := newCourseArchiveService(memory.NewStore(memory.NewDB()))
+
+	archive := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, content := range files {
+			f, err := w.Create(name)
+			require.NoError(t, err)
+			_, err = f.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a zip", []byte("plain text")},
+		{"no manifest", archive(map[string]string{"lectures/001.md": "text"})},
+		{"newer schema", archive(map[string]string{"manifest.json": `{"schema_version": 99, "course": {"name": "Go", "semester": "2025"}}`})},
+		{"missing lecture file", archive(map[string]string{"manifest.json": `{"schema_version": 1, "course": {"name": "Go", "semester": "2025"}, "lectures": [{"week": 1, "title": "Intro", "file": "lectures/001.md"}]}`})},
+		{"no course name", archive(map[string]string{"manifest.json": `{"schema_version": 1, "course": {"semester": "2025"}}`})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Import(ctx, bytes.NewReader(tt.data), int64(len(tt.data)), CourseArchiveImportOptions{})
+			assert.ErrorIs(t, err, ErrInvalidCourseArchive)
+		})
+	}
+}
+
+func TestDecodeCourseManifest_Upgrades(t *testing.T) {
+	// Гипотетическая версия 2 переименовала course.title в course.name
+	upgrades := map[int]func(map[string]any) error{
+		1: func(m map[string]any) error {
+			course := m["course"].(map[string]any)
+			course["name"] = course["title"]
+			delete(course, "title")
+			return nil
+		},
+	}
+
+	manifest, err := decodeCourseManifest([]byte(`{"schema_version": 1, "exported_at": "2025-01-01T00:00:00Z", "course": {"title": "Go", "semester": "2025"}}`), upgrades, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Go", manifest.Course.Name)
+	assert.Equal(t, 1, manifest.SchemaVersion)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), manifest.ExportedAt)
+
+	_, err = decodeCourseManifest([]byte(`{"schema_version": 1}`), nil, 2)
+	assert.ErrorIs(t, err, ErrInvalidCourseArchive)
+}
@@ -33,3 +33,26 @@ func (s *DocumentService) GenerateTicketsDocument(tickets []models.Ticket) []byt
 
 	return []byte(builder.String())
 }
+
+// GenerateBatchDocument генерирует TXT документ с билетами нескольких курсов,
+// разделяя их заголовками курсов
+func (s *DocumentService) GenerateBatchDocument(courses []models.CourseTickets) []byte {
+	var builder strings.Builder
+
+	for i, course := range courses {
+		builder.WriteString(fmt.Sprintf("Курс: %s\n\n", course.CourseName))
+		builder.Write(s.GenerateTicketsDocument(course.Tickets))
+
+		if i < len(courses)-1 {
+			builder.WriteString("\n\n")
+		}
+	}
+
+	return []byte(builder.String())
+}
+
+// CourseSlug преобразует название курса в безопасную часть имени файла
+func CourseSlug(name string) string {
+	slug := strings.ToLower(strings.ReplaceAll(name, " ", "_"))
+	return strings.ReplaceAll(slug, "/", "_")
+}
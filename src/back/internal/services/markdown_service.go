@@ -0,0 +1,307 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdownCacheSize - максимальное количество отрендеренных документов в кэше
+const markdownCacheSize = 512
+
+var baseURLKey = parser.NewContextKey()
+
+type cachedRender struct {
+	updatedAt time.Time
+	// sum - хэш исходника: updated_at хранится с точностью до секунды, и две правки
+	// за одну секунду его не меняют
+	sum     [sha256.Size]byte
+	content *models.RenderedContent
+}
+
+// MarkdownService рендерит Markdown лекций и лабораторных в безопасный HTML
+type MarkdownService struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+
+	mu    sync.Mutex
+	cache map[string]cachedRender
+}
+
+func NewMarkdownService() *MarkdownService {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle("github"),
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(util.Prioritized(&linkRewriter{}, 100)),
+		),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9 _-]+$`)).OnElements("pre", "code", "span")
+	policy.AllowAttrs("tabindex").OnElements("pre")
+
+	return &MarkdownService{
+		md:     md,
+		policy: policy,
+		cache:  make(map[string]cachedRender),
+	}
+}
+
+// Render преобразует Markdown в HTML с оглавлением. Относительные ссылки и
+// изображения разрешаются относительно baseURL (обычно GitHub URL документа).
+func (s *MarkdownService) Render(source, baseURL string) (*models.RenderedContent, error) {
+	src := []byte(source)
+
+	pc := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	if base, err := url.Parse(baseURL); err == nil && base.IsAbs() {
+		pc.Set(baseURLKey, base)
+	}
+
+	doc := s.md.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+
+	var buf bytes.Buffer
+	if err := s.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	return &models.RenderedContent{
+		HTML: s.policy.Sanitize(buf.String()),
+		TOC:  tableOfContents(doc, src),
+	}, nil
+}
+
+// RenderCached рендерит документ, переиспользуя результат, пока не изменились updatedAt и текст
+func (s *MarkdownService) RenderCached(key string, updatedAt time.Time, source, baseURL string) (*models.RenderedContent, error) {
+	sum := sha256.Sum256([]byte(baseURL + "\x00" + source))
+
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+
+	if ok && cached.updatedAt.Equal(updatedAt) && cached.sum == sum {
+		return cached.content, nil
+	}
+
+	content, err := s.Render(source, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.cache) >= markdownCacheSize {
+		// Кэш переполнен — удаляем произвольную запись
+		for k := range s.cache {
+			delete(s.cache, k)
+			break
+		}
+	}
+	s.cache[key] = cachedRender{updatedAt: updatedAt, sum: sum, content: content}
+	s.mu.Unlock()
+
+	return content, nil
+}
+
+func tableOfContents(doc ast.Node, src []byte) []models.TOCEntry {
+	toc := []models.TOCEntry{}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		heading, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		entry := models.TOCEntry{
+			Level: heading.Level,
+			Title: strings.TrimSpace(nodeText(heading, src)),
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				entry.ID = string(b)
+			}
+		}
+		toc = append(toc, entry)
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	return toc
+}
+
+// headingIDs генерирует якоря заголовков, сохраняя кириллицу
+// (стандартный генератор goldmark оставляет только ASCII)
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			b.WriteRune(r)
+			dash = false
+		case unicode.IsSpace(r) || r == '-':
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	id := strings.TrimSuffix(b.String(), "-")
+	if id == "" {
+		id = "heading"
+	}
+
+	unique := id
+	for i := 1; h.used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", id, i)
+	}
+	h.used[unique] = true
+
+	return []byte(unique)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
+
+// nodeText собирает текстовое содержимое inline-узлов
+func nodeText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		default:
+			b.WriteString(nodeText(c, src))
+		}
+	}
+	return b.String()
+}
+
+// linkRewriter превращает относительные ссылки и изображения в абсолютные.
+// Изображения с GitHub перенаправляются на raw.githubusercontent.com, чтобы
+// браузер получал сам файл, а не HTML-страницу.
+type linkRewriter struct{}
+
+func (r *linkRewriter) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	base, ok := pc.Get(baseURLKey).(*url.URL)
+	if !ok {
+		return
+	}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Link:
+			node.Destination = resolveRelative(base, node.Destination, false)
+		case *ast.Image:
+			node.Destination = resolveRelative(base, node.Destination, true)
+		}
+
+		return ast.WalkContinue, nil
+	})
+}
+
+func resolveRelative(base *url.URL, dest []byte, raw bool) []byte {
+	ref, err := url.Parse(string(dest))
+	if err != nil || ref.IsAbs() || ref.Host != "" || strings.HasPrefix(string(dest), "#") {
+		return dest
+	}
+
+	// Путь от корня репозитория, как его понимает GitHub
+	if strings.HasPrefix(ref.Path, "/") {
+		root, ok := githubRepoRoot(base)
+		if !ok {
+			return dest
+		}
+		ref.Path = strings.TrimPrefix(ref.Path, "/")
+		base = root
+	}
+
+	resolved := base.ResolveReference(ref)
+	if raw {
+		resolved = githubRawURL(resolved)
+	}
+
+	return []byte(resolved.String())
+}
+
+// githubRepoRoot возвращает https://github.com/{owner}/{repo}/blob/{ref}/ для URL файла в репозитории
+func githubRepoRoot(u *url.URL) (*url.URL, bool) {
+	if u.Host != "github.com" {
+		return nil, false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 5)
+	if len(parts) < 4 || parts[2] != "blob" {
+		return nil, false
+	}
+
+	root := *u
+	root.Path = "/" + strings.Join(parts[:4], "/") + "/"
+	root.RawPath = ""
+	return &root, true
+}
+
+// githubRawURL преобразует https://github.com/{owner}/{repo}/blob/{ref}/{path}
+// в https://raw.githubusercontent.com/{owner}/{repo}/{ref}/{path}
+func githubRawURL(u *url.URL) *url.URL {
+	if u.Host != "github.com" {
+		return u
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+	if len(parts) < 4 || parts[2] != "blob" {
+		return u
+	}
+
+	raw := *u
+	raw.Host = "raw.githubusercontent.com"
+	raw.Path = "/" + parts[0] + "/" + parts[1] + "/" + parts[3]
+	raw.RawPath = ""
+	return &raw
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lectureURL = "https://github.com/CreateLab/AspITMO/blob/main/lections/L1.md"
+
+func TestMarkdownService_Render(t *testing.T) {
+	service := NewMarkdownService()
+
+	tests := []struct {
+		name           string
+		source         string
+		baseURL        string
+		validateOutput func(*testing.T, *models.RenderedContent)
+	}{
+		{
+			name:    "heading anchors and table of contents",
+			source:  "# Введение в ASP.NET\n\n## Setup & Run\n\n# Введение в ASP.NET\n",
+			baseURL: lectureURL,
+			validateOutput: func(t *testing.T, r *models.RenderedContent) {
+				assert.Contains(t, r.HTML, `<h1 id="введение-в-aspnet">Введение в ASP.NET</h1>`)
+				assert.Contains(t, r.HTML, `<h2 id="setup-run">`)
+				assert.Contains(t, r.HTML, `<h1 id="введение-в-aspnet-1">`)
+				assert.Equal(t, []models.TOCEntry{
+					{Level: 1, ID: "введение-в-aspnet", Title: "Введение в ASP.NET"},
+					{Level: 2, ID: "setup-run", Title: "Setup & Run"},
+					{Level: 1, ID: "введение-в-aspnet-1", Title: "Введение в ASP.NET"},
+				}, r.TOC)
+			},
+		},
+		{
+			name:   "syntax highlighted code block",
+			source: "```csharp\nvar app = WebApplication.Create();\n```\n",
+			validateOutput: func(t *testing.T, r *models.RenderedContent) {
+				assert.Contains(t, r.HTML, `<pre class="chroma">`)
+				assert.Contains(t, r.HTML, `<span class="kt">var</span>`)
+			},
+		},
+		{
+			name:    "relative links and images are rewritten",
+			source:  "![schema](images/pipeline.png) [next](L2.md) [lab](/Lab1.md) [anchor](#setup) [site](https://learn.microsoft.com)",
+			baseURL: lectureURL,
+			validateOutput: func(t *testing.T, r *models.RenderedContent) {
+				assert.Contains(t, r.HTML, `src="https://raw.githubusercontent.com/CreateLab/AspITMO/main/lections/images/pipeline.png"`)
+				assert.Contains(t, r.HTML, `href="https://github.com/CreateLab/AspITMO/blob/main/lections/L2.md"`)
+				assert.Contains(t, r.HTML, `href="https://github.com/CreateLab/AspITMO/blob/main/Lab1.md"`)
+				assert.Contains(t, r.HTML, `href="#setup"`)
+				assert.Contains(t, r.HTML, `href="https://learn.microsoft.com"`)
+			},
+		},
+		{
+			name:   "relative links without base are kept",
+			source: "[next](L2.md)",
+			validateOutput: func(t *testing.T, r *models.RenderedContent) {
+				assert.Contains(t, r.HTML, `href="L2.md"`)
+			},
+		},
+		{
+			name:   "unsafe html is sanitized",
+			source: "Hello <script>alert(1)</script> <a href=\"javascript:alert(1)\" onclick=\"x()\">click</a>",
+			validateOutput: func(t *testing.T, r *models.RenderedContent) {
+				assert.NotContains(t, r.HTML, "<script>")
+				assert.NotContains(t, r.HTML, "javascript:")
+				assert.NotContains(t, r.HTML, "onclick")
+			},
+		},
+		{
+			name:   "empty document",
+			source: "",
+			validateOutput: func(t *testing.T, r *models.RenderedContent) {
+				assert.Empty(t, r.HTML)
+				assert.Empty(t, r.TOC)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Render(tt.source, tt.baseURL)
+			require.NoError(t, err)
+			tt.validateOutput(t, result)
+		})
+	}
+}
+
+func TestMarkdownService_RenderCached(t *testing.T) {
+	service := NewMarkdownService()
+	updatedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	first, err := service.RenderCached("lecture:1", updatedAt, "# Old", "")
+	require.NoError(t, err)
+
+	second, err := service.RenderCached("lecture:1", updatedAt, "# Old", "")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// Две правки за одну секунду: updated_at тот же, но текст другой
+	third, err := service.RenderCached("lecture:1", updatedAt, "# New", "")
+	require.NoError(t, err)
+	assert.Contains(t, third.HTML, "New")
+
+	fourth, err := service.RenderCached("lecture:1", updatedAt.Add(time.Second), "# New", "")
+	require.NoError(t, err)
+	assert.Contains(t, fourth.HTML, "New")
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/jobs"
+	laritmomail "github.com/CreateLab/laritmo/internal/mail"
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// NotificationEmailJobType - тип фоновой задачи отправки одного письма
+const NotificationEmailJobType = "notifications.email"
+
+// deadlineReminderWindow - за сколько до дедлайна студенты получают напоминание
+const deadlineReminderWindow = 24 * time.Hour
+
+// NotificationRepositoryInterface - интерфейс для настроек уведомлений и отправленных напоминаний в БД
+type NotificationRepositoryInterface interface {
+	GetPreferences(ctx context.Context, userID int) (*models.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, p *models.NotificationPreferences) error
+	ListRecipients(ctx context.Context, kind string) ([]models.User, error)
+	DueDeadlineReminders(ctx context.Context, from, to time.Time) ([]models.Lab, error)
+	MarkDeadlineReminded(ctx context.Context, labID int, deadline time.Time) error
+}
+
+// NotificationLabGetterInterface - интерфейс для получения лабораторной по ID
+type NotificationLabGetterInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Lab, error)
+}
+
+// NotificationGradeSheetGetterInterface - интерфейс для получения ведомости по ID
+type NotificationGradeSheetGetterInterface interface {
+	GetByID(ctx context.Context, id int) (*models.GradeSheet, error)
+}
+
+// NotificationService рассылает студентам письма о новых лабораторных, близких дедлайнах
+// и опубликованных оценках. Письма не отправляются сразу, а ставятся в очередь задач
+// по одному на получателя, чтобы сбой SMTP-сервера не терял их и не задерживал запросы.
+type NotificationService struct {
+	repo        NotificationRepositoryInterface
+	labs        NotificationLabGetterInterface
+	gradeSheets NotificationGradeSheetGetterInterface
+	courses     CourseGetterInterface
+	queue       JobEnqueuer
+	from        string
+	maxAttempts int
+	location    *time.Location
+	logger      *slog.Logger
+}
+
+// NewNotificationService создаёт сервис уведомлений; from - адрес отправителя писем,
+// location - часовой пояс, в котором заданы дедлайны лабораторных
+func NewNotificationService(
+	repo NotificationRepositoryInterface,
+	labs NotificationLabGetterInterface,
+	gradeSheets NotificationGradeSheetGetterInterface,
+	courses CourseGetterInterface,
+	queue JobEnqueuer,
+	from string,
+	maxAttempts int,
+	location *time.Location,
+	logger *slog.Logger,
+) *NotificationService {
+	return &NotificationService{
+		repo:        repo,
+		labs:        labs,
+		gradeSheets: gradeSheets,
+		courses:     courses,
+		queue:       queue,
+		from:        from,
+		maxAttempts: maxAttempts,
+		location:    location,
+		logger:      logger,
+	}
+}
+
+// GetPreferences возвращает, какие письма получает пользователь
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int) (*models.NotificationPreferences, error) {
+	return s.repo.GetPreferences(ctx, userID)
+}
+
+// SetPreferences сохраняет, какие письма получает пользователь
+func (s *NotificationService) SetPreferences(ctx context.Context, p *models.NotificationPreferences) error {
+	return s.repo.SetPreferences(ctx, p)
+}
+
+// LabPublished рассылает письма о новой лабораторной; черновики и удалённые лабораторные пропускаются
+func (s *NotificationService) LabPublished(ctx context.Context, id int) error {
+	lab, err := s.labs.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get lab: %w", err)
+	}
+	if lab == nil || lab.Status != models.PublicationStatusPublished {
+		return nil
+	}
+	return s.notify(ctx, models.NotificationNewLab, lab.CourseID, notificationData{Lab: lab, Deadline: formatDeadline(lab.Deadline)})
+}
+
+// GradeSheetPublished рассылает письма об опубликованной ведомости; черновики и удалённые ведомости пропускаются
+func (s *NotificationService) GradeSheetPublished(ctx context.Context, id int) error {
+	sheet, err := s.gradeSheets.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get grade sheet: %w", err)
+	}
+	if sheet == nil || sheet.Status != models.PublicationStatusPublished {
+		return nil
+	}
+	return s.notify(ctx, models.NotificationGradePosted, sheet.CourseID, notificationData{GradeSheet: sheet})
+}
+
+// Published рассылает письма о материале, опубликованном планировщиком; о лекциях писем нет
+func (s *NotificationService) Published(ctx context.Context, item models.PublishedItem) error {
+	switch item.Type {
+	case models.TrashTypeLab:
+		return s.LabPublished(ctx, item.ID)
+	case models.TrashTypeGradeSheet:
+		return s.GradeSheetPublished(ctx, item.ID)
+	}
+	return nil
+}
+
+// SendDeadlineReminders рассылает напоминания о лабораторных, дедлайн которых наступит
+// в ближайшие сутки после now, и возвращает, о скольких лабораторных напомнили.
+// О каждом дедлайне напоминают один раз; после переноса дедлайна напоминание придёт снова.
+func (s *NotificationService) SendDeadlineReminders(ctx context.Context, now time.Time) (int, error) {
+	// Дедлайны хранятся временем на часах часового пояса курса, так же их и сравниваем
+	local := now.In(s.location)
+	from := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+
+	labs, err := s.repo.DueDeadlineReminders(ctx, from, from.Add(deadlineReminderWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, lab := range labs {
+		data := notificationData{Lab: &lab, Deadline: formatDeadline(lab.Deadline)}
+		if err := s.notify(ctx, models.NotificationDeadlineReminder, lab.CourseID, data); err != nil {
+			errs = append(errs, fmt.Errorf("lab %d: %w", lab.ID, err))
+			continue
+		}
+		if err := s.repo.MarkDeadlineReminded(ctx, lab.ID, *lab.Deadline); err != nil {
+			errs = append(errs, fmt.Errorf("lab %d: %w", lab.ID, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// Run рассылает напоминания о дедлайнах сразу и затем каждые interval, пока не отменён ctx
+func (s *NotificationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.SendDeadlineReminders(ctx, time.Now())
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to send deadline reminders", "error", err)
+		}
+		if sent > 0 {
+			s.logger.InfoContext(ctx, "Deadline reminders sent", "labs", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notify ставит в очередь письмо вида kind каждому студенту, не отключившему такие письма
+func (s *NotificationService) notify(ctx context.Context, kind string, courseID int, data notificationData) error {
+	course, err := s.courses.GetByID(ctx, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil
+	}
+	data.Course = *course
+
+	recipients, err := s.repo.ListRecipients(ctx, kind)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, user := range recipients {
+		data.User = user
+		subject, body, err := renderNotification(kind, data)
+		if err != nil {
+			return err
+		}
+		msg := laritmomail.Message{
+			From:    s.from,
+			To:      (&mail.Address{Name: user.Username, Address: user.Email}).String(),
+			Subject: subject,
+			Body:    body,
+		}
+		if _, err := s.queue.Enqueue(ctx, NotificationEmailJobType, msg, s.maxAttempts); err != nil {
+			errs = append(errs, fmt.Errorf("failed to enqueue email to user %d: %w", user.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func formatDeadline(deadline *time.Time) string {
+	if deadline == nil {
+		return ""
+	}
+	return deadline.Format(notificationDeadlineLayout)
+}
+
+// NotificationEmailJob отправляет письмо, поставленное в очередь NotificationService
+type NotificationEmailJob struct {
+	sender laritmomail.Sender
+	logger *slog.Logger
+}
+
+func NewNotificationEmailJob(sender laritmomail.Sender, logger *slog.Logger) *NotificationEmailJob {
+	return &NotificationEmailJob{
+		sender: sender,
+		logger: logger,
+	}
+}
+
+// Handle выполняет задачу; регистрируется в jobs.Pool под NotificationEmailJobType.
+// Ошибки транспорта повторяются, а письмо с неверным адресом сразу уходит в dead-letter.
+func (j *NotificationEmailJob) Handle(ctx context.Context, job *models.Job) error {
+	var msg laritmomail.Message
+	if err := json.Unmarshal(job.Payload, &msg); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+
+	err := j.sender.Send(ctx, msg)
+	if errors.Is(err, laritmomail.ErrInvalidAddress) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	j.logger.InfoContext(ctx, "Email sent", "job_id", job.ID, "subject", msg.Subject)
+	return nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/mail"
+	"testing"
+	"time"
+
+	laritmomail "github.com/CreateLab/laritmo/internal/mail"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSender запоминает отправленные письма
+type fakeSender struct {
+	sent []laritmomail.Message
+	err  error
+}
+
+func (s *fakeSender) Send(ctx context.Context, msg laritmomail.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func newNotificationFixture(t *testing.T) (*repository.Store, *fakeJobQueue, *NotificationService, *models.Course) {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Сети", "2026-fall", "")
+	require.NoError(t, err)
+
+	_, err = store.Users.Create(ctx, "Анна", "anna@example.com", "hash", models.RoleStudent)
+	require.NoError(t, err)
+	quiet, err := store.Users.Create(ctx, "quiet", "quiet@example.com", "hash", models.RoleStudent)
+	require.NoError(t, err)
+	_, err = store.Users.Create(ctx, "admin", "admin@example.com", "hash", models.RoleAdmin)
+	require.NoError(t, err)
+	require.NoError(t, store.Notifications.SetPreferences(ctx, &models.NotificationPreferences{UserID: quiet.ID}))
+
+	queue := &fakeJobQueue{}
+	service := NewNotificationService(store.Notifications, store.Labs, store.GradeSheets, store.Courses, queue,
+		"Laritmo <noreply@example.com>", 3, time.FixedZone("MSK", 3*60*60), slog.Default())
+	return store, queue, service, course
+}
+
+func enqueuedMessages(t *testing.T, queue *fakeJobQueue) []laritmomail.Message {
+	t.Helper()
+	messages := make([]laritmomail.Message, 0, len(queue.jobs))
+	for _, job := range queue.jobs {
+		assert.Equal(t, NotificationEmailJobType, job.Type)
+		assert.Equal(t, 3, job.MaxAttempts)
+		var msg laritmomail.Message
+		require.NoError(t, json.Unmarshal(job.Payload, &msg))
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestNotificationService_LabPublished(t *testing.T) {
+	ctx := context.Background()
+	store, queue, service, course := newNotificationFixture(t)
+
+	deadline := "2026-11-01 23:59:00"
+	lab, err := store.Labs.Create(ctx, course.ID, 2, 10, "Сокеты", "описание", "https://github.com/org/labs", &deadline, "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, service.LabPublished(ctx, lab.ID))
+
+	messages := enqueuedMessages(t, queue)
+	require.Len(t, messages, 1, "only students with the notification enabled get the email")
+	msg := messages[0]
+	assert.Equal(t, "Laritmo <noreply@example.com>", msg.From)
+	to, err := mail.ParseAddress(msg.To)
+	require.NoError(t, err)
+	assert.Equal(t, "anna@example.com", to.Address)
+	assert.Equal(t, "Анна", to.Name)
+	assert.Equal(t, "Сети: лабораторная 2. Сокеты", msg.Subject)
+	assert.Contains(t, msg.Body, "Здравствуйте, Анна!")
+	assert.Contains(t, msg.Body, "Дедлайн: 01.11.2026 23:59.")
+	assert.Contains(t, msg.Body, "Задание: https://github.com/org/labs")
+	assert.Contains(t, msg.Body, "Отключить уведомления можно в настройках профиля.")
+
+	t.Run("drafts and missing labs are skipped", func(t *testing.T) {
+		queue.jobs = nil
+		require.NoError(t, store.Labs.SetPublication(ctx, lab.ID, models.PublicationStatusDraft, nil))
+		require.NoError(t, service.LabPublished(ctx, lab.ID))
+		require.NoError(t, service.LabPublished(ctx, 404))
+		assert.Empty(t, queue.jobs)
+	})
+
+	t.Run("enqueue failure is returned", func(t *testing.T) {
+		require.NoError(t, store.Labs.SetPublication(ctx, lab.ID, models.PublicationStatusPublished, nil))
+		queue.err = errors.New("queue is down")
+		defer func() { queue.err = nil }()
+		assert.ErrorContains(t, service.LabPublished(ctx, lab.ID), "queue is down")
+	})
+}
+
+func TestNotificationService_Published(t *testing.T) {
+	ctx := context.Background()
+	store, queue, service, course := newNotificationFixture(t)
+
+	sheet, err := store.GradeSheets.Create(ctx, course.ID, "https://example.com/grades", "Итоги модуля 1", "", nil)
+	require.NoError(t, err)
+	lecture, err := store.Lectures.Create(ctx, course.ID, 1, "Введение", "текст", "", "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, service.Published(ctx, models.PublishedItem{Type: models.TrashTypeLecture, ID: lecture.ID, CourseID: course.ID}))
+	assert.Empty(t, queue.jobs, "lectures are not emailed")
+
+	require.NoError(t, service.Published(ctx, models.PublishedItem{Type: models.TrashTypeGradeSheet, ID: sheet.ID, CourseID: course.ID}))
+	messages := enqueuedMessages(t, queue)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Сети: опубликованы оценки", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "Итоги модуля 1")
+	assert.Contains(t, messages[0].Body, "Ведомость: https://example.com/grades")
+}
+
+func TestNotificationService_SendDeadlineReminders(t *testing.T) {
+	ctx := context.Background()
+	store, queue, service, course := newNotificationFixture(t)
+
+	// Дедлайны заданы временем на часах курса (UTC+3)
+	soon := "2026-10-20 12:00:00"
+	due, err := store.Labs.Create(ctx, course.ID, 1, 10, "Сокеты", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+	later := "2026-10-22 12:00:00"
+	_, err = store.Labs.Create(ctx, course.ID, 2, 10, "HTTP", "описание", "", &later, "", nil)
+	require.NoError(t, err)
+	draft, err := store.Labs.Create(ctx, course.ID, 3, 10, "TLS", "описание", "", &soon, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Labs.SetPublication(ctx, draft.ID, models.PublicationStatusDraft, nil))
+
+	// 10:00 UTC - 13:00 на часах курса: до дедлайна первой лабораторной меньше суток
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	sent, err := service.SendDeadlineReminders(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	messages := enqueuedMessages(t, queue)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Сети: дедлайн лабораторной 1 20.10.2026 12:00", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "лабораторной 1. Сокеты")
+	assert.NotContains(t, messages[0].Body, "Задание:", "lab without a repository has no link")
+
+	t.Run("each deadline is reminded once", func(t *testing.T) {
+		sent, err := service.SendDeadlineReminders(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		assert.Len(t, queue.jobs, 1)
+	})
+
+	t.Run("moved deadline is reminded again", func(t *testing.T) {
+		moved := "2026-10-20 13:30:00"
+		require.NoError(t, store.Labs.Update(ctx, due.ID, course.ID, 1, 10, "Сокеты", "описание", "", &moved))
+
+		sent, err := service.SendDeadlineReminders(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		messages := enqueuedMessages(t, queue)
+		require.Len(t, messages, 2)
+		assert.Contains(t, messages[1].Subject, "20.10.2026 13:30")
+	})
+
+	t.Run("passed deadlines are not reminded", func(t *testing.T) {
+		sent, err := service.SendDeadlineReminders(ctx, time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+}
+
+func TestNotificationEmailJob_Handle(t *testing.T) {
+	ctx := context.Background()
+	msg := laritmomail.Message{From: "Laritmo <noreply@example.com>", To: "anna@example.com", Subject: "Тема", Body: "Текст"}
+	payload, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	t.Run("sends the message", func(t *testing.T) {
+		sender := &fakeSender{}
+		err := NewNotificationEmailJob(sender, slog.Default()).Handle(ctx, &models.Job{ID: 1, Payload: payload})
+		require.NoError(t, err)
+		assert.Equal(t, []laritmomail.Message{msg}, sender.sent)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		sender := &fakeSender{err: errors.New("connection refused")}
+		err := NewNotificationEmailJob(sender, slog.Default()).Handle(ctx, &models.Job{ID: 1, Payload: payload})
+		assert.EqualError(t, err, "failed to send email: connection refused")
+	})
+
+	t.Run("invalid address and payload", func(t *testing.T) {
+		sender := &fakeSender{err: laritmomail.ErrInvalidAddress}
+		err := NewNotificationEmailJob(sender, slog.Default()).Handle(ctx, &models.Job{ID: 1, Payload: payload})
+		assert.ErrorIs(t, err, laritmomail.ErrInvalidAddress)
+
+		err = NewNotificationEmailJob(&fakeSender{}, slog.Default()).Handle(ctx, &models.Job{ID: 1, Payload: []byte("{")})
+		assert.ErrorContains(t, err, "invalid payload")
+	})
+}
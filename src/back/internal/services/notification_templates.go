@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// notificationDeadlineLayout - формат дедлайна в тексте писем
+const notificationDeadlineLayout = "02.01.2006 15:04"
+
+// notificationData - данные для шаблонов писем; незаполненные поля шаблон вида не использует
+type notificationData struct {
+	User       models.User
+	Course     models.Course
+	Lab        *models.Lab
+	GradeSheet *models.GradeSheet
+	// Deadline - дедлайн лабораторной в notificationDeadlineLayout или пустая строка
+	Deadline string
+}
+
+// notificationFuncs - функции шаблонов; value разыменовывает необязательное поле, nil - пустая строка
+var notificationFuncs = template.FuncMap{
+	"value": func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	},
+}
+
+const notificationFooter = `
+--
+Письмо отправлено автоматически, отвечать на него не нужно.
+Отключить уведомления можно в настройках профиля.
+`
+
+// notificationTemplates - тема и текст письма каждого вида
+var notificationTemplates = map[string]struct{ subject, body *template.Template }{
+	models.NotificationNewLab: {
+		subject: template.Must(template.New("subject").Parse(
+			`{{.Course.Name}}: лабораторная {{.Lab.Number}}. {{.Lab.Title}}`)),
+		body: template.Must(template.New("body").Funcs(notificationFuncs).Parse(`Здравствуйте, {{.User.Username}}!
+
+В курсе «{{.Course.Name}}» опубликована лабораторная {{.Lab.Number}}. {{.Lab.Title}}.
+{{- if .Deadline}}
+Дедлайн: {{.Deadline}}.{{end}}
+Максимальный балл: {{.Lab.MaxScore}}.
+{{- with value .Lab.GithubURL}}
+Задание: {{.}}{{end}}
+` + notificationFooter)),
+	},
+	models.NotificationDeadlineReminder: {
+		subject: template.Must(template.New("subject").Parse(
+			`{{.Course.Name}}: дедлайн лабораторной {{.Lab.Number}} {{.Deadline}}`)),
+		body: template.Must(template.New("body").Funcs(notificationFuncs).Parse(`Здравствуйте, {{.User.Username}}!
+
+Напоминаем: меньше чем через сутки, {{.Deadline}}, заканчивается приём
+лабораторной {{.Lab.Number}}. {{.Lab.Title}} в курсе «{{.Course.Name}}».
+{{- with value .Lab.GithubURL}}
+Задание: {{.}}{{end}}
+` + notificationFooter)),
+	},
+	models.NotificationGradePosted: {
+		subject: template.Must(template.New("subject").Parse(
+			`{{.Course.Name}}: опубликованы оценки`)),
+		body: template.Must(template.New("body").Funcs(notificationFuncs).Parse(`Здравствуйте, {{.User.Username}}!
+
+В курсе «{{.Course.Name}}» опубликована ведомость с оценками.
+{{- with value .GradeSheet.Description}}
+{{.}}{{end}}
+Ведомость: {{.GradeSheet.SheetURL}}
+` + notificationFooter)),
+	},
+}
+
+// renderNotification заполняет шаблон письма вида kind
+func renderNotification(kind string, data notificationData) (subject, body string, err error) {
+	tmpl, ok := notificationTemplates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.subject.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s subject: %w", kind, err)
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := tmpl.body.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s body: %w", kind, err)
+	}
+	return subject, buf.String(), nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// PublicationRepositoryInterface - интерфейс для публикации черновиков по расписанию в БД
+type PublicationRepositoryInterface interface {
+	PublishDue(ctx context.Context, now time.Time) ([]models.PublishedItem, error)
+}
+
+// PublicationAnnouncerInterface - интерфейс для объявлений об опубликованных лекциях
+type PublicationAnnouncerInterface interface {
+	LecturePublished(ctx context.Context, courseID int, title string) error
+}
+
+// PublicationNotifierInterface - интерфейс для писем студентам об опубликованных материалах
+type PublicationNotifierInterface interface {
+	Published(ctx context.Context, item models.PublishedItem) error
+}
+
+type PublicationService struct {
+	repo      PublicationRepositoryInterface
+	announcer PublicationAnnouncerInterface
+	notifier  PublicationNotifierInterface
+	logger    *slog.Logger
+}
+
+// NewPublicationService создаёт планировщик, публикующий черновики с наступившим publish_at,
+// объявляющий об опубликованных лекциях и рассылающий письма о лабораторных и ведомостях
+func NewPublicationService(
+	repo PublicationRepositoryInterface,
+	announcer PublicationAnnouncerInterface,
+	notifier PublicationNotifierInterface,
+	logger *slog.Logger,
+) *PublicationService {
+	return &PublicationService{
+		repo:      repo,
+		announcer: announcer,
+		notifier:  notifier,
+		logger:    logger,
+	}
+}
+
+// PublishDue публикует черновики, время публикации которых уже наступило
+func (s *PublicationService) PublishDue(ctx context.Context) ([]models.PublishedItem, error) {
+	return s.repo.PublishDue(ctx, time.Now())
+}
+
+// Run публикует наступившие черновики сразу и затем каждые interval, пока не отменён ctx
+func (s *PublicationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := s.PublishDue(ctx)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to publish scheduled items", "error", err)
+		}
+		for _, item := range published {
+			s.logger.InfoContext(ctx, "Scheduled item published", "type", item.Type, "id", item.ID, "course_id", item.CourseID)
+			if err := s.notifier.Published(ctx, item); err != nil {
+				s.logger.ErrorContext(ctx, "Failed to send notifications", "error", err, "type", item.Type, "id", item.ID)
+			}
+			if item.Type != models.TrashTypeLecture {
+				continue
+			}
+			if err := s.announcer.LecturePublished(ctx, item.CourseID, item.Title); err != nil {
+				s.logger.ErrorContext(ctx, "Failed to announce lecture", "error", err, "id", item.ID)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
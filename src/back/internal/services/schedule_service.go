@@ -0,0 +1,325 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/ical"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+const (
+	scheduleDateLayout = "2006-01-02"
+	scheduleTimeLayout = "15:04"
+
+	// maxSlotDurationMinutes ограничивает длительность занятия десятью часами
+	maxSlotDurationMinutes = 600
+	// maxTimetableSlots ограничивает число занятий в неделю
+	maxTimetableSlots = 50
+	maxRoomLength     = 100
+)
+
+// ErrInvalidSchedule - расписание не прошло проверку; текст ошибки описывает причину
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ScheduleRepositoryInterface - интерфейс для расписаний курсов в БД
+type ScheduleRepositoryInterface interface {
+	Get(ctx context.Context, courseID int) (*models.CourseSchedule, error)
+	Set(ctx context.Context, schedule *models.CourseSchedule) (*models.CourseSchedule, error)
+	Delete(ctx context.Context, courseID int) error
+}
+
+// ScheduleCourseRepositoryInterface - интерфейс для проверки существования курса
+type ScheduleCourseRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Course, error)
+}
+
+// ScheduleLectureRepositoryInterface - интерфейс для чтения лекций курса
+type ScheduleLectureRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lecture, error)
+}
+
+// ScheduleLabRepositoryInterface - интерфейс для чтения лабораторных курса
+type ScheduleLabRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID int) ([]models.Lab, error)
+}
+
+type ScheduleService struct {
+	schedules ScheduleRepositoryInterface
+	courses   ScheduleCourseRepositoryInterface
+	lectures  ScheduleLectureRepositoryInterface
+	labs      ScheduleLabRepositoryInterface
+	location  *time.Location
+}
+
+// NewScheduleService создаёт сервис расписаний; время занятий и дедлайны лабораторных
+// считаются заданными в часовом поясе location
+func NewScheduleService(
+	schedules ScheduleRepositoryInterface,
+	courses ScheduleCourseRepositoryInterface,
+	lectures ScheduleLectureRepositoryInterface,
+	labs ScheduleLabRepositoryInterface,
+	location *time.Location,
+) *ScheduleService {
+	return &ScheduleService{
+		schedules: schedules,
+		courses:   courses,
+		lectures:  lectures,
+		labs:      labs,
+		location:  location,
+	}
+}
+
+// Get возвращает расписание курса с датами лекций; у курса без расписания Schedule равен nil,
+// а список лекций пуст. Возвращает repository.ErrCourseNotFound, если курса нет.
+func (s *ScheduleService) Get(ctx context.Context, courseID int) (*models.CourseTimetable, error) {
+	if _, err := s.course(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.schedules.Get(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	timetable := &models.CourseTimetable{Schedule: schedule, Lectures: []models.ScheduledLecture{}}
+	if schedule == nil {
+		return timetable, nil
+	}
+
+	lectures, err := s.lectures.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lectures: %w", err)
+	}
+
+	timetable.Lectures, err = s.scheduleLectures(schedule, published(lectures, lectureStatus))
+	if err != nil {
+		return nil, err
+	}
+	return timetable, nil
+}
+
+// Set проверяет и сохраняет расписание, заменяя прежнее целиком
+func (s *ScheduleService) Set(ctx context.Context, schedule *models.CourseSchedule) (*models.CourseSchedule, error) {
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	if _, err := s.course(ctx, schedule.CourseID); err != nil {
+		return nil, err
+	}
+	return s.schedules.Set(ctx, schedule)
+}
+
+// Delete удаляет расписание курса; лекции после этого остаются без дат
+func (s *ScheduleService) Delete(ctx context.Context, courseID int) error {
+	if _, err := s.course(ctx, courseID); err != nil {
+		return err
+	}
+	return s.schedules.Delete(ctx, courseID)
+}
+
+// Calendar пишет iCalendar-ленту курса: лекции по расписанию и дедлайны лабораторных.
+// Лекции попадают в ленту, только если у курса есть расписание; черновики не попадают.
+func (s *ScheduleService) Calendar(ctx context.Context, courseID int, w io.Writer) error {
+	course, err := s.course(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := s.schedules.Get(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	var scheduled []models.ScheduledLecture
+	updated := make(map[int]time.Time)
+	if schedule != nil {
+		lectures, err := s.lectures.GetByCourseID(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to get lectures: %w", err)
+		}
+		lectures = published(lectures, lectureStatus)
+		for _, l := range lectures {
+			updated[l.ID] = l.UpdatedAt
+		}
+		if scheduled, err = s.scheduleLectures(schedule, lectures); err != nil {
+			return err
+		}
+	}
+
+	labs, err := s.labs.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to get labs: %w", err)
+	}
+
+	cal := ical.Calendar{
+		ProdID: "-//Laritmo//Course Calendar//RU",
+		Name:   fmt.Sprintf("%s (%s)", course.Name, course.Semester),
+	}
+
+	for _, l := range scheduled {
+		// DTSTAMP берётся из данных, а не из текущего времени, чтобы лента не менялась между запросами
+		stamp := updated[l.LectureID]
+		if schedule.UpdatedAt.After(stamp) {
+			stamp = schedule.UpdatedAt
+		}
+
+		event := ical.Event{
+			UID:      fmt.Sprintf("lecture-%d@laritmo", l.LectureID),
+			Summary:  fmt.Sprintf("Лекция %d. %s", l.Week, l.Title),
+			Location: l.Room,
+			Stamp:    stamp,
+		}
+		if l.Start != nil {
+			event.Start, event.End = *l.Start, *l.End
+		} else {
+			date, err := time.Parse(scheduleDateLayout, l.Date)
+			if err != nil {
+				return fmt.Errorf("invalid lecture date: %w", err)
+			}
+			event.Start, event.AllDay = date, true
+		}
+		cal.Events = append(cal.Events, event)
+	}
+
+	for _, lab := range published(labs, labStatus) {
+		if lab.Deadline == nil {
+			continue
+		}
+		deadline := s.deadline(*lab.Deadline)
+		event := ical.Event{
+			UID:     fmt.Sprintf("lab-%d-deadline@laritmo", lab.ID),
+			Summary: fmt.Sprintf("Дедлайн: лабораторная %d. %s", lab.Number, lab.Title),
+			Start:   deadline,
+			End:     deadline,
+			Stamp:   lab.UpdatedAt,
+		}
+		if lab.GithubURL != nil {
+			event.URL = *lab.GithubURL
+		}
+		cal.Events = append(cal.Events, event)
+	}
+
+	return ical.Write(w, cal)
+}
+
+func lectureStatus(l models.Lecture) string { return l.Status }
+
+func labStatus(l models.Lab) string { return l.Status }
+
+// published оставляет только опубликованные материалы: черновики не попадают в расписание и календарь
+func published[T any](items []T, status func(T) string) []T {
+	return slices.DeleteFunc(slices.Clone(items), func(item T) bool {
+		return status(item) != models.PublicationStatusPublished
+	})
+}
+
+func (s *ScheduleService) course(ctx context.Context, courseID int) (*models.Course, error) {
+	course, err := s.courses.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, repository.ErrCourseNotFound
+	}
+	return course, nil
+}
+
+// deadline переносит время дедлайна в часовой пояс расписания: дедлайн вводится без пояса,
+// и база возвращает его с тем же временем на часах, но с поясом соединения
+func (s *ScheduleService) deadline(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, s.location)
+}
+
+// scheduleLectures вычисляет даты лекций. Неделя N начинается с понедельника недели start_date
+// плюс N-1 неделя; лекции одной недели по порядку id занимают занятия недели по порядку,
+// а если лекций больше, чем занятий, распределяются по кругу. Без занятий в расписании
+// лекция считается событием на весь понедельник своей недели.
+func (s *ScheduleService) scheduleLectures(schedule *models.CourseSchedule, lectures []models.Lecture) ([]models.ScheduledLecture, error) {
+	start, err := time.ParseInLocation(scheduleDateLayout, schedule.StartDate, s.location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	// time.Weekday считает воскресенье нулевым днём, ISO 8601 - седьмым
+	firstMonday := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+
+	slots := slices.Clone(schedule.Slots)
+	slices.SortStableFunc(slots, func(a, b models.TimetableSlot) int {
+		return cmp.Or(cmp.Compare(a.Weekday, b.Weekday), cmp.Compare(a.StartTime, b.StartTime))
+	})
+
+	lectures = slices.Clone(lectures)
+	slices.SortFunc(lectures, func(a, b models.Lecture) int {
+		return cmp.Or(cmp.Compare(a.Week, b.Week), cmp.Compare(a.ID, b.ID))
+	})
+
+	result := make([]models.ScheduledLecture, 0, len(lectures))
+	inWeek := 0
+	for i, l := range lectures {
+		if i > 0 && lectures[i-1].Week == l.Week {
+			inWeek++
+		} else {
+			inWeek = 0
+		}
+
+		monday := firstMonday.AddDate(0, 0, 7*(l.Week-1))
+		scheduled := models.ScheduledLecture{
+			LectureID: l.ID,
+			Week:      l.Week,
+			Title:     l.Title,
+			Date:      monday.Format(scheduleDateLayout),
+		}
+
+		if len(slots) > 0 {
+			slot := slots[inWeek%len(slots)]
+			at, err := time.Parse(scheduleTimeLayout, slot.StartTime)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slot start time: %w", err)
+			}
+			day := monday.AddDate(0, 0, slot.Weekday-1)
+			begin := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), 0, 0, s.location)
+			end := begin.Add(time.Duration(slot.DurationMinutes) * time.Minute)
+
+			scheduled.Date = day.Format(scheduleDateLayout)
+			scheduled.Start = &begin
+			scheduled.End = &end
+			scheduled.Room = slot.Room
+		}
+
+		result = append(result, scheduled)
+	}
+
+	return result, nil
+}
+
+func validateSchedule(schedule *models.CourseSchedule) error {
+	if _, err := time.Parse(scheduleDateLayout, schedule.StartDate); err != nil {
+		return fmt.Errorf("%w: start_date must be YYYY-MM-DD", ErrInvalidSchedule)
+	}
+	if len(schedule.Slots) > maxTimetableSlots {
+		return fmt.Errorf("%w: at most %d slots are allowed", ErrInvalidSchedule, maxTimetableSlots)
+	}
+
+	for i, slot := range schedule.Slots {
+		if slot.Weekday < 1 || slot.Weekday > 7 {
+			return fmt.Errorf("%w: slot %d: weekday must be from 1 (Monday) to 7 (Sunday)", ErrInvalidSchedule, i+1)
+		}
+		if at, err := time.Parse(scheduleTimeLayout, slot.StartTime); err != nil || at.Format(scheduleTimeLayout) != slot.StartTime {
+			return fmt.Errorf("%w: slot %d: start_time must be HH:MM", ErrInvalidSchedule, i+1)
+		}
+		if slot.DurationMinutes < 1 || slot.DurationMinutes > maxSlotDurationMinutes {
+			return fmt.Errorf("%w: slot %d: duration_minutes must be from 1 to %d", ErrInvalidSchedule, i+1, maxSlotDurationMinutes)
+		}
+		if len([]rune(slot.Room)) > maxRoomLength {
+			return fmt.Errorf("%w: slot %d: room is longer than %d characters", ErrInvalidSchedule, i+1, maxRoomLength)
+		}
+	}
+
+	return nil
+}
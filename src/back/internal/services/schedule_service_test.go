@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleService(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	service := NewScheduleService(store.Schedules, store.Courses, store.Lectures, store.Labs, time.FixedZone("MSK", 3*60*60))
+
+	course, err := store.Courses.Create(ctx, "Сети", "2026-fall", "")
+	require.NoError(t, err)
+	first, err := store.Lectures.Create(ctx, course.ID, 1, "Модель OSI", "", "", "", nil)
+	require.NoError(t, err)
+	second, err := store.Lectures.Create(ctx, course.ID, 1, "Ethernet", "", "", "", nil)
+	require.NoError(t, err)
+	third, err := store.Lectures.Create(ctx, course.ID, 2, "IP", "", "", "", nil)
+	require.NoError(t, err)
+	deadline := "2026-09-10 23:59:00"
+	lab, err := store.Labs.Create(ctx, course.ID, 1, 10, "Wireshark", "", "", &deadline, "", nil)
+	require.NoError(t, err)
+
+	timetable, err := service.Get(ctx, course.ID)
+	require.NoError(t, err)
+	assert.Nil(t, timetable.Schedule)
+	assert.Empty(t, timetable.Lectures)
+
+	t.Run("validation", func(t *testing.T) {
+		for _, schedule := range []models.CourseSchedule{
+			{StartDate: "01.09.2026"},
+			{StartDate: "2026-09-02", Slots: []models.TimetableSlot{{Weekday: 0, StartTime: "10:00", DurationMinutes: 90}}},
+			{StartDate: "2026-09-02", Slots: []models.TimetableSlot{{Weekday: 1, StartTime: "9:00", DurationMinutes: 90}}},
+			{StartDate: "2026-09-02", Slots: []models.TimetableSlot{{Weekday: 1, StartTime: "09:00"}}},
+		} {
+			schedule.CourseID = course.ID
+			_, err := service.Set(ctx, &schedule)
+			assert.ErrorIs(t, err, ErrInvalidSchedule, schedule)
+		}
+
+		_, err := service.Set(ctx, &models.CourseSchedule{CourseID: 999, StartDate: "2026-09-02"})
+		assert.ErrorIs(t, err, repository.ErrCourseNotFound)
+	})
+
+	// Курс начинается в среду, поэтому первая неделя - с понедельника 31 августа
+	_, err = service.Set(ctx, &models.CourseSchedule{
+		CourseID:  course.ID,
+		StartDate: "2026-09-02",
+		Slots: []models.TimetableSlot{
+			{Weekday: 4, StartTime: "12:00", DurationMinutes: 90, Room: "305"},
+			{Weekday: 2, StartTime: "10:00", DurationMinutes: 90, Room: "101"},
+		},
+	})
+	require.NoError(t, err)
+
+	timetable, err = service.Get(ctx, course.ID)
+	require.NoError(t, err)
+	require.Len(t, timetable.Lectures, 3)
+
+	byID := map[int]models.ScheduledLecture{}
+	for _, l := range timetable.Lectures {
+		byID[l.LectureID] = l
+	}
+	assert.Equal(t, "2026-09-01", byID[first.ID].Date)
+	assert.Equal(t, "101", byID[first.ID].Room)
+	require.NotNil(t, byID[first.ID].Start)
+	assert.Equal(t, time.Date(2026, 9, 1, 7, 0, 0, 0, time.UTC), byID[first.ID].Start.UTC())
+	assert.Equal(t, time.Date(2026, 9, 1, 8, 30, 0, 0, time.UTC), byID[first.ID].End.UTC())
+	assert.Equal(t, "2026-09-03", byID[second.ID].Date, "second lecture of the week takes the next slot")
+	assert.Equal(t, "2026-09-08", byID[third.ID].Date)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.Calendar(ctx, course.ID, &buf))
+	ics := buf.String()
+	assert.Equal(t, 4, strings.Count(ics, "BEGIN:VEVENT"))
+	assert.Contains(t, ics, "UID:lecture-"+strconv.Itoa(first.ID)+"@laritmo\r\n")
+	assert.Contains(t, ics, "DTSTART:20260901T070000Z\r\n")
+	assert.Contains(t, ics, "LOCATION:101\r\n")
+	assert.Contains(t, ics, "UID:lab-"+strconv.Itoa(lab.ID)+"-deadline@laritmo\r\n")
+	assert.Contains(t, ics, "DTSTART:20260910T205900Z\r\n", "deadline is read in the calendar timezone")
+
+	var again bytes.Buffer
+	require.NoError(t, service.Calendar(ctx, course.ID, &again))
+	assert.Equal(t, ics, again.String(), "feed is stable between requests")
+
+	t.Run("without slots lectures are all-day events", func(t *testing.T) {
+		_, err := service.Set(ctx, &models.CourseSchedule{CourseID: course.ID, StartDate: "2026-09-02"})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, service.Calendar(ctx, course.ID, &buf))
+		assert.Contains(t, buf.String(), "DTSTART;VALUE=DATE:20260831\r\nDTEND;VALUE=DATE:20260901\r\n")
+	})
+
+	t.Run("drafts are left out", func(t *testing.T) {
+		require.NoError(t, store.Lectures.SetPublication(ctx, third.ID, models.PublicationStatusDraft, nil))
+		require.NoError(t, store.Labs.SetPublication(ctx, lab.ID, models.PublicationStatusDraft, nil))
+		defer store.Lectures.SetPublication(ctx, third.ID, models.PublicationStatusPublished, nil)
+		defer store.Labs.SetPublication(ctx, lab.ID, models.PublicationStatusPublished, nil)
+
+		timetable, err := service.Get(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Len(t, timetable.Lectures, 2)
+
+		var buf bytes.Buffer
+		require.NoError(t, service.Calendar(ctx, course.ID, &buf))
+		assert.Equal(t, 2, strings.Count(buf.String(), "BEGIN:VEVENT"))
+		assert.NotContains(t, buf.String(), "UID:lab-")
+	})
+
+	require.NoError(t, service.Delete(ctx, course.ID))
+	buf.Reset()
+	require.NoError(t, service.Calendar(ctx, course.ID, &buf))
+	assert.Equal(t, 1, strings.Count(buf.String(), "BEGIN:VEVENT"), "only the lab deadline is left")
+
+	assert.ErrorIs(t, service.Calendar(ctx, 999, &buf), repository.ErrCourseNotFound)
+}
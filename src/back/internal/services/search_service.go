@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// ErrEmptySearchQuery - в запросе нет ни одного слова, пригодного для поиска
+var ErrEmptySearchQuery = errors.New("search query has no searchable terms")
+
+const (
+	// minSearchTermLength соответствует innodb_ft_min_token_size по умолчанию
+	minSearchTermLength = 3
+	maxSearchTerms      = 10
+	snippetRadius       = 80
+)
+
+// SearchRepositoryInterface - интерфейс для полнотекстового поиска в БД
+type SearchRepositoryInterface interface {
+	Search(ctx context.Context, booleanQuery string, courseID *int, types []string, limit, offset int) ([]models.SearchHit, int, error)
+}
+
+type SearchParams struct {
+	Query    string
+	CourseID *int
+	Types    []string
+	Limit    int
+	Offset   int
+}
+
+type SearchService struct {
+	repo SearchRepositoryInterface
+}
+
+func NewSearchService(repo SearchRepositoryInterface) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// Search ищет материалы и формирует подсвеченные фрагменты текста
+func (s *SearchService) Search(ctx context.Context, params SearchParams) (*models.SearchResult, error) {
+	booleanQuery, stems := BuildBooleanQuery(params.Query)
+	if booleanQuery == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	hits, total, err := s.repo.Search(ctx, booleanQuery, params.CourseID, params.Types, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	for i := range hits {
+		hits[i].Snippet = HighlightSnippet(hits[i].Text, stems)
+	}
+
+	return &models.SearchResult{
+		Items:  hits,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}, nil
+}
+
+// BuildBooleanQuery превращает пользовательский запрос в запрос MySQL BOOLEAN MODE:
+// операторы удаляются, слова приводятся к основе и ищутся по префиксу,
+// все слова обязательны. Возвращает запрос и список основ для подсветки.
+func BuildBooleanQuery(query string) (string, []string) {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool)
+	var stems []string
+	for _, w := range words {
+		if len([]rune(w)) < minSearchTermLength {
+			continue
+		}
+		stem := stemWord(w)
+		if seen[stem] {
+			continue
+		}
+		seen[stem] = true
+		stems = append(stems, stem)
+		if len(stems) == maxSearchTerms {
+			break
+		}
+	}
+
+	terms := make([]string, len(stems))
+	for i, stem := range stems {
+		terms[i] = "+" + stem + "*"
+	}
+
+	return strings.Join(terms, " "), stems
+}
+
+// Окончания отсортированы по убыванию длины, чтобы сначала отсекались самые длинные
+var russianEndings = []string{
+	"иями",
+	"ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "ией",
+	"ых", "их", "ой", "ей", "ий", "ый", "ая", "яя", "ое", "ее", "ую", "юю", "ов", "ев",
+	"ам", "ям", "ах", "ях", "ом", "ем", "ия", "ие", "ию", "ии",
+	"а", "я", "о", "е", "ы", "и", "у", "ю", "ь",
+}
+
+var englishEndings = []string{"ies", "ing", "ed", "es", "s"}
+
+// stemWord отбрасывает типичные окончания, чтобы префиксный поиск находил
+// другие словоформы («контроллеры» → «контроллер*», «services» → «servic*»)
+func stemWord(word string) string {
+	endings := englishEndings
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			endings = russianEndings
+			break
+		}
+	}
+
+	length := len([]rune(word))
+	for _, ending := range endings {
+		stemLength := length - len([]rune(ending))
+		if stemLength >= 4 && strings.HasSuffix(word, ending) {
+			return string([]rune(word)[:stemLength])
+		}
+	}
+
+	return word
+}
+
+// HighlightSnippet вырезает фрагмент текста вокруг первого совпадения и
+// оборачивает совпадения в <mark>. Результат экранирован и безопасен для HTML.
+func HighlightSnippet(text string, stems []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	type match struct{ start, end int }
+	var matches []match
+	for i := 0; i < len(lower); i++ {
+		if i > 0 && isWordRune(lower[i-1]) {
+			continue
+		}
+		for _, stem := range stems {
+			sr := []rune(stem)
+			if !hasRunePrefix(lower[i:], sr) {
+				continue
+			}
+			end := i + len(sr)
+			for end < len(lower) && isWordRune(lower[end]) {
+				end++
+			}
+			matches = append(matches, match{start: i, end: end})
+			i = end - 1
+			break
+		}
+	}
+
+	start, end := 0, len(runes)
+	if len(matches) > 0 {
+		start = max(matches[0].start-snippetRadius, 0)
+		end = min(matches[0].end+snippetRadius, len(runes))
+	} else if end > 2*snippetRadius {
+		end = 2 * snippetRadius
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	pos := start
+	for _, m := range matches {
+		if m.start < start || m.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		b.WriteString("</mark>")
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
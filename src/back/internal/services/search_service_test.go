@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSearchRepository - мок для SearchRepository
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, booleanQuery string, courseID *int, types []string, limit, offset int) ([]models.SearchHit, int, error) {
+	args := m.Called(booleanQuery, courseID, types, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.SearchHit), args.Int(1), args.Error(2)
+}
+
+func TestBuildBooleanQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedQuery string
+		expectedStems []string
+	}{
+		{
+			name:          "russian word forms",
+			query:         "Контроллеры и маршрутизация",
+			expectedQuery: "+контроллер* +маршрутизац*",
+			expectedStems: []string{"контроллер", "маршрутизац"},
+		},
+		{
+			name:          "english plurals",
+			query:         "middleware services",
+			expectedQuery: "+middleware* +servic*",
+			expectedStems: []string{"middleware", "servic"},
+		},
+		{
+			name:          "boolean operators are stripped",
+			query:         `+docker -"compose" (ef*) <core>`,
+			expectedQuery: "+docker* +compose* +core*",
+			expectedStems: []string{"docker", "compose", "core"},
+		},
+		{
+			name:          "short and duplicate words are skipped",
+			query:         "DI в DI контейнер контейнеры",
+			expectedQuery: "+контейнер*",
+			expectedStems: []string{"контейнер"},
+		},
+		{
+			name:          "no searchable words",
+			query:         "a + b",
+			expectedQuery: "",
+			expectedStems: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, stems := BuildBooleanQuery(tt.query)
+			assert.Equal(t, tt.expectedQuery, query)
+			assert.Equal(t, tt.expectedStems, stems)
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	t.Run("highlights word forms", func(t *testing.T) {
+		snippet := HighlightSnippet("Контроллер принимает запрос. Контроллеры <b>наследуют</b> ControllerBase.", []string{"контроллер"})
+		assert.Equal(t, "<mark>Контроллер</mark> принимает запрос. <mark>Контроллеры</mark> &lt;b&gt;наследуют&lt;/b&gt; ControllerBase.", snippet)
+	})
+
+	t.Run("does not match inside words", func(t *testing.T) {
+		snippet := HighlightSnippet("Subservices are not services", []string{"servic"})
+		assert.Equal(t, "Subservices are not <mark>services</mark>", snippet)
+	})
+
+	t.Run("cuts long text around first match", func(t *testing.T) {
+		text := ""
+		for i := 0; i < 50; i++ {
+			text += "слово "
+		}
+		text += "middleware "
+		for i := 0; i < 50; i++ {
+			text += "текст "
+		}
+
+		snippet := HighlightSnippet(text, []string{"middleware"})
+		assert.Contains(t, snippet, "<mark>middleware</mark>")
+		assert.True(t, len([]rune(snippet)) < 200+len("<mark></mark>"))
+		assert.Equal(t, "…", string([]rune(snippet)[0]))
+	})
+
+	t.Run("no match returns beginning", func(t *testing.T) {
+		snippet := HighlightSnippet("Короткий текст", []string{"docker"})
+		assert.Equal(t, "Короткий текст", snippet)
+	})
+}
+
+func TestSearchService_Search(t *testing.T) {
+	ctx := context.Background()
+	courseID := 1
+
+	t.Run("returns hits with snippets", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		repo.On("Search", "+middleware*", &courseID, []string{models.SearchTypeLecture}, 20, 0).Return([]models.SearchHit{
+			{Type: models.SearchTypeLecture, ID: 3, CourseID: 1, Title: "Pipeline", Text: "Middleware обрабатывает запрос", Score: 1.5},
+		}, 1, nil)
+
+		service := NewSearchService(repo)
+		result, err := service.Search(ctx, SearchParams{
+			Query:    "middleware",
+			CourseID: &courseID,
+			Types:    []string{models.SearchTypeLecture},
+			Limit:    20,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, result.Total)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, "<mark>Middleware</mark> обрабатывает запрос", result.Items[0].Snippet)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		service := NewSearchService(new(MockSearchRepository))
+		_, err := service.Search(ctx, SearchParams{Query: "?!", Limit: 20})
+		assert.ErrorIs(t, err, ErrEmptySearchQuery)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		repo.On("Search", "+docker*", (*int)(nil), []string(nil), 20, 0).Return(nil, 0, errors.New("db down"))
+
+		service := NewSearchService(repo)
+		_, err := service.Search(ctx, SearchParams{Query: "docker", Limit: 20})
+		assert.EqualError(t, err, "failed to search: db down")
+	})
+}
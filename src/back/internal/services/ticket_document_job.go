@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/CreateLab/laritmo/internal/jobs"
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// TicketDocumentJobType - тип фоновой задачи генерации документа с билетами
+const TicketDocumentJobType = "tickets.document"
+
+// JobProgressStore - интерфейс для сохранения прогресса и результата фоновой задачи
+type JobProgressStore interface {
+	SetProgress(ctx context.Context, id int, workerID string, progress int) error
+	SaveResult(ctx context.Context, jobID int, filename, contentType string, content []byte) error
+}
+
+// CourseGetterInterface - интерфейс для получения курса по ID
+type CourseGetterInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Course, error)
+}
+
+// TicketDocumentJob генерирует документ с билетами по нескольким курсам в фоне
+type TicketDocumentJob struct {
+	tickets   *TicketService
+	documents *DocumentService
+	courses   CourseGetterInterface
+	store     JobProgressStore
+	logger    *slog.Logger
+}
+
+func NewTicketDocumentJob(
+	tickets *TicketService,
+	documents *DocumentService,
+	courses CourseGetterInterface,
+	store JobProgressStore,
+	logger *slog.Logger,
+) *TicketDocumentJob {
+	return &TicketDocumentJob{
+		tickets:   tickets,
+		documents: documents,
+		courses:   courses,
+		store:     store,
+		logger:    logger,
+	}
+}
+
+// Handle выполняет задачу; регистрируется в jobs.Pool под TicketDocumentJobType
+func (j *TicketDocumentJob) Handle(ctx context.Context, job *models.Job) error {
+	var req models.TicketBatchRequest
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+	if len(req.CourseIDs) == 0 {
+		return jobs.Permanent(errors.New("no courses requested"))
+	}
+
+	// Прогресс пишется только пока аренда задачи у этого воркера
+	var workerID string
+	if job.LockedBy != nil {
+		workerID = *job.LockedBy
+	}
+
+	total := len(req.CourseIDs) * req.TicketCount
+	done := 0
+	lastProgress := -1
+	report := func() {
+		// Оставляем 100% на момент, когда результат уже сохранён
+		progress := done * 99 / total
+		if progress == lastProgress {
+			return
+		}
+		lastProgress = progress
+		if err := j.store.SetProgress(ctx, job.ID, workerID, progress); err != nil {
+			j.logger.WarnContext(ctx, "Failed to update job progress", "error", err, "job_id", job.ID)
+		}
+	}
+	report()
+
+	sections := make([]models.CourseTickets, 0, len(req.CourseIDs))
+	for _, courseID := range req.CourseIDs {
+		course, err := j.courses.GetByID(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to get course %d: %w", courseID, err)
+		}
+		if course == nil {
+			return jobs.Permanent(fmt.Errorf("course %d not found", courseID))
+		}
+
+		generatedBefore := done
+		tickets, err := j.tickets.GenerateTicketsBatch(ctx, courseID, req.TicketCount, req.QuestionsPerTicket, func(generated int) {
+			done = generatedBefore + generated
+			report()
+		})
+		if errors.Is(err, ErrNotEnoughQuestions) {
+			return jobs.Permanent(fmt.Errorf("course %d: %w", courseID, err))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate tickets for course %d: %w", courseID, err)
+		}
+
+		sections = append(sections, models.CourseTickets{
+			CourseName: course.Name,
+			Tickets:    tickets,
+		})
+	}
+
+	filename := fmt.Sprintf("tickets_batch_%d.txt", job.ID)
+	if len(sections) == 1 {
+		filename = "tickets_" + CourseSlug(sections[0].CourseName) + ".txt"
+	}
+
+	document := j.documents.GenerateBatchDocument(sections)
+	if err := j.store.SaveResult(ctx, job.ID, filename, "text/plain; charset=utf-8", document); err != nil {
+		return fmt.Errorf("failed to save document: %w", err)
+	}
+
+	j.logger.InfoContext(ctx, "Tickets batch document generated", "job_id", job.ID, "courses", len(sections), "ticket_count", total)
+	return nil
+}
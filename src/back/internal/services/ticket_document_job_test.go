@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCourseGetter - мок для получения курса
+type MockCourseGetter struct {
+	mock.Mock
+}
+
+func (m *MockCourseGetter) GetByID(ctx context.Context, id int) (*models.Course, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Course), args.Error(1)
+}
+
+// fakeProgressStore запоминает прогресс и результат задачи
+type fakeProgressStore struct {
+	progress []int
+	filename string
+	content  []byte
+}
+
+func (s *fakeProgressStore) SetProgress(ctx context.Context, id int, workerID string, progress int) error {
+	s.progress = append(s.progress, progress)
+	return nil
+}
+
+func (s *fakeProgressStore) SaveResult(ctx context.Context, jobID int, filename, contentType string, content []byte) error {
+	s.filename = filename
+	s.content = content
+	return nil
+}
+
+func questionBank(courseID, count int) []models.ExamQuestion {
+	questions := make([]models.ExamQuestion, count)
+	for i := range questions {
+		questions[i] = models.ExamQuestion{
+			ID:       courseID*1000 + i,
+			CourseID: courseID,
+			Number:   i + 1,
+			Section:  fmt.Sprintf("Раздел %d", i%3),
+			Question: fmt.Sprintf("Вопрос %d-%d", courseID, i+1),
+		}
+	}
+	return questions
+}
+
+func newBatchJob(t *testing.T, req models.TicketBatchRequest) *models.Job {
+	payload, err := json.Marshal(req)
+	require.NoError(t, err)
+	return &models.Job{ID: 7, Type: TicketDocumentJobType, Payload: payload}
+}
+
+func TestTicketDocumentJob_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("generates document for several courses", func(t *testing.T) {
+		examRepo := new(MockExamQuestionRepository)
+		examRepo.On("GetByCourseID", 1).Return(questionBank(1, 10), nil)
+		examRepo.On("GetByCourseID", 2).Return(questionBank(2, 10), nil)
+
+		courses := new(MockCourseGetter)
+		courses.On("GetByID", 1).Return(&models.Course{ID: 1, Name: "Go"}, nil)
+		courses.On("GetByID", 2).Return(&models.Course{ID: 2, Name: "ASP.NET Core"}, nil)
+
+		store := &fakeProgressStore{}
+		job := NewTicketDocumentJob(NewTicketService(examRepo), NewDocumentService(), courses, store, slog.Default())
+
+		err := job.Handle(ctx, newBatchJob(t, models.TicketBatchRequest{
+			CourseIDs:          []int{1, 2},
+			QuestionsPerTicket: 3,
+			TicketCount:        150,
+		}))
+		require.NoError(t, err)
+
+		text := string(store.content)
+		assert.Equal(t, "tickets_batch_7.txt", store.filename)
+		assert.Contains(t, text, "Курс: Go")
+		assert.Contains(t, text, "Курс: ASP.NET Core")
+		assert.Contains(t, text, "Билет № 150")
+
+		// Прогресс монотонно растёт и не превышает 99 до сохранения результата
+		require.NotEmpty(t, store.progress)
+		assert.Equal(t, 0, store.progress[0])
+		assert.Equal(t, 99, store.progress[len(store.progress)-1])
+		for i := 1; i < len(store.progress); i++ {
+			assert.Greater(t, store.progress[i], store.progress[i-1])
+		}
+	})
+
+	t.Run("single course uses course filename", func(t *testing.T) {
+		examRepo := new(MockExamQuestionRepository)
+		examRepo.On("GetByCourseID", 1).Return(questionBank(1, 5), nil)
+
+		courses := new(MockCourseGetter)
+		courses.On("GetByID", 1).Return(&models.Course{ID: 1, Name: "Test Course"}, nil)
+
+		store := &fakeProgressStore{}
+		job := NewTicketDocumentJob(NewTicketService(examRepo), NewDocumentService(), courses, store, slog.Default())
+
+		err := job.Handle(ctx, newBatchJob(t, models.TicketBatchRequest{
+			CourseIDs:          []int{1},
+			QuestionsPerTicket: 2,
+			TicketCount:        3,
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "tickets_test_course.txt", store.filename)
+	})
+
+	t.Run("not enough questions is permanent", func(t *testing.T) {
+		examRepo := new(MockExamQuestionRepository)
+		examRepo.On("GetByCourseID", 1).Return(questionBank(1, 2), nil)
+
+		courses := new(MockCourseGetter)
+		courses.On("GetByID", 1).Return(&models.Course{ID: 1, Name: "Go"}, nil)
+
+		job := NewTicketDocumentJob(NewTicketService(examRepo), NewDocumentService(), courses, &fakeProgressStore{}, slog.Default())
+
+		err := job.Handle(ctx, newBatchJob(t, models.TicketBatchRequest{
+			CourseIDs:          []int{1},
+			QuestionsPerTicket: 5,
+			TicketCount:        3,
+		}))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotEnoughQuestions)
+	})
+
+	t.Run("missing course", func(t *testing.T) {
+		courses := new(MockCourseGetter)
+		courses.On("GetByID", 3).Return(nil, nil)
+
+		job := NewTicketDocumentJob(NewTicketService(new(MockExamQuestionRepository)), NewDocumentService(), courses, &fakeProgressStore{}, slog.Default())
+
+		err := job.Handle(ctx, newBatchJob(t, models.TicketBatchRequest{
+			CourseIDs:          []int{3},
+			QuestionsPerTicket: 5,
+			TicketCount:        3,
+		}))
+		assert.EqualError(t, err, "course 3 not found")
+	})
+
+	t.Run("course lookup error is retried", func(t *testing.T) {
+		courses := new(MockCourseGetter)
+		courses.On("GetByID", 1).Return(nil, errors.New("connection reset"))
+
+		job := NewTicketDocumentJob(NewTicketService(new(MockExamQuestionRepository)), NewDocumentService(), courses, &fakeProgressStore{}, slog.Default())
+
+		err := job.Handle(ctx, newBatchJob(t, models.TicketBatchRequest{
+			CourseIDs:          []int{1},
+			QuestionsPerTicket: 5,
+			TicketCount:        3,
+		}))
+		assert.EqualError(t, err, "failed to get course 1: connection reset")
+	})
+}
+
+func TestTicketService_GenerateTicketsBatch(t *testing.T) {
+	ctx := context.Background()
+
+	examRepo := new(MockExamQuestionRepository)
+	examRepo.On("GetByCourseID", 1).Return(questionBank(1, 10), nil)
+	service := NewTicketService(examRepo)
+
+	t.Run("allows more than document limit", func(t *testing.T) {
+		var reported int
+		tickets, err := service.GenerateTicketsBatch(ctx, 1, 500, 3, func(generated int) {
+			reported = generated
+		})
+		require.NoError(t, err)
+		assert.Len(t, tickets, 500)
+		assert.Equal(t, 500, reported)
+	})
+
+	t.Run("rejects more than batch limit", func(t *testing.T) {
+		_, err := service.GenerateTicketsBatch(ctx, 1, MaxTicketsPerBatch+1, 3, nil)
+		assert.EqualError(t, err, "ticket count must be between 1 and 1000")
+	})
+
+	t.Run("stops on cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := service.GenerateTicketsBatch(cancelled, 1, 10, 3, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -9,9 +9,19 @@ import (
 	"github.com/CreateLab/laritmo/internal/models"
 )
 
+// ErrNotEnoughQuestions - в банке курса меньше вопросов, чем требуется на билет
+var ErrNotEnoughQuestions = errors.New("not enough questions")
+
+const (
+	// MaxTicketsPerDocument - лимит билетов для синхронной генерации документа
+	MaxTicketsPerDocument = 100
+	// MaxTicketsPerBatch - лимит билетов на курс для фоновой генерации
+	MaxTicketsPerBatch = 1000
+)
+
 // ExamQuestionRepositoryInterface - интерфейс для работы с экзаменационными вопросами
 type ExamQuestionRepositoryInterface interface {
-	GetByCourseID(courseID int) ([]models.ExamQuestion, error)
+	GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error)
 }
 
 type TicketService struct {
@@ -31,13 +41,13 @@ func (s *TicketService) GenerateRandomTicket(ctx context.Context, courseID int,
 	}
 
 	// Получаем все вопросы курса
-	allQuestions, err := s.examRepo.GetByCourseID(courseID)
+	allQuestions, err := s.examRepo.GetByCourseID(ctx, courseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exam questions: %w", err)
 	}
 
 	if len(allQuestions) < questionsCount {
-		return nil, fmt.Errorf("not enough questions: have %d, need %d", len(allQuestions), questionsCount)
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrNotEnoughQuestions, len(allQuestions), questionsCount)
 	}
 
 	// Группируем вопросы по разделам
@@ -67,22 +77,39 @@ func (s *TicketService) GenerateRandomTicket(ctx context.Context, courseID int,
 
 // GenerateMultipleTickets генерирует несколько билетов с минимизацией пересечений
 func (s *TicketService) GenerateMultipleTickets(ctx context.Context, courseID int, ticketCount, questionsPerTicket int) ([]models.Ticket, error) {
-	if ticketCount < 1 || ticketCount > 100 {
-		return nil, errors.New("ticket count must be between 1 and 100")
+	if ticketCount < 1 || ticketCount > MaxTicketsPerDocument {
+		return nil, fmt.Errorf("ticket count must be between 1 and %d", MaxTicketsPerDocument)
 	}
 	if questionsPerTicket < 1 || questionsPerTicket > 50 {
 		return nil, errors.New("questions per ticket must be between 1 and 50")
 	}
 
+	return s.generateTickets(ctx, courseID, ticketCount, questionsPerTicket, nil)
+}
+
+// GenerateTicketsBatch генерирует большое количество билетов для фоновых задач.
+// onTicket вызывается после каждого сгенерированного билета для отчёта о прогрессе.
+func (s *TicketService) GenerateTicketsBatch(ctx context.Context, courseID int, ticketCount, questionsPerTicket int, onTicket func(generated int)) ([]models.Ticket, error) {
+	if ticketCount < 1 || ticketCount > MaxTicketsPerBatch {
+		return nil, fmt.Errorf("ticket count must be between 1 and %d", MaxTicketsPerBatch)
+	}
+	if questionsPerTicket < 1 || questionsPerTicket > 50 {
+		return nil, errors.New("questions per ticket must be between 1 and 50")
+	}
+
+	return s.generateTickets(ctx, courseID, ticketCount, questionsPerTicket, onTicket)
+}
+
+func (s *TicketService) generateTickets(ctx context.Context, courseID int, ticketCount, questionsPerTicket int, onTicket func(generated int)) ([]models.Ticket, error) {
 	// Получаем все вопросы курса
-	allQuestions, err := s.examRepo.GetByCourseID(courseID)
+	allQuestions, err := s.examRepo.GetByCourseID(ctx, courseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exam questions: %w", err)
 	}
 
 	// Проверяем достаточность вопросов
 	if len(allQuestions) < questionsPerTicket {
-		return nil, fmt.Errorf("not enough questions: have %d, need at least %d", len(allQuestions), questionsPerTicket)
+		return nil, fmt.Errorf("%w: have %d, need at least %d", ErrNotEnoughQuestions, len(allQuestions), questionsPerTicket)
 	}
 
 	// Группируем вопросы по разделам
@@ -96,6 +123,10 @@ func (s *TicketService) GenerateMultipleTickets(ctx context.Context, courseID in
 	usedQuestions := make(map[int]int) // question ID -> count of usage
 
 	for i := 0; i < ticketCount; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		selectedQuestions := s.selectQuestionsWithTracking(questionsBySection, questionsPerTicket, usedQuestions, allQuestions)
 
 		questions := make([]models.Question, len(selectedQuestions))
@@ -112,6 +143,10 @@ func (s *TicketService) GenerateMultipleTickets(ctx context.Context, courseID in
 			Number:    i + 1,
 			Questions: questions,
 		}
+
+		if onTicket != nil {
+			onTicket(i + 1)
+		}
 	}
 
 	return tickets, nil
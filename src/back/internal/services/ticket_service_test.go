@@ -15,7 +15,7 @@ type MockExamQuestionRepository struct {
 	mock.Mock
 }
 
-func (m *MockExamQuestionRepository) GetByCourseID(courseID int) ([]models.ExamQuestion, error) {
+func (m *MockExamQuestionRepository) GetByCourseID(ctx context.Context, courseID int) ([]models.ExamQuestion, error) {
 	args := m.Called(courseID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
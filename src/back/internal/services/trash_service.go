@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/CreateLab/laritmo/internal/repository"
+)
+
+// TrashRepositoryInterface - интерфейс для корзины в БД
+type TrashRepositoryInterface interface {
+	List(ctx context.Context, filter repository.TrashFilter, opts repository.ListOptions) ([]models.TrashItem, int, error)
+	Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error)
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+type TrashService struct {
+	repo      TrashRepositoryInterface
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// NewTrashService создаёт сервис корзины; записи старше retention удаляются окончательно
+func NewTrashService(repo TrashRepositoryInterface, retention time.Duration, logger *slog.Logger) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+func (s *TrashService) List(ctx context.Context, filter repository.TrashFilter, opts repository.ListOptions) ([]models.TrashItem, int, error) {
+	return s.repo.List(ctx, filter, opts)
+}
+
+func (s *TrashService) Restore(ctx context.Context, itemType string, id int) (*models.TrashRestoreSummary, error) {
+	return s.repo.Restore(ctx, itemType, id)
+}
+
+// Purge окончательно удаляет записи, пролежавшие в корзине дольше срока хранения
+func (s *TrashService) Purge(ctx context.Context) (int, error) {
+	return s.repo.Purge(ctx, time.Now().Add(-s.retention))
+}
+
+// RunPurge очищает корзину сразу и затем каждые interval, пока не отменён ctx
+func (s *TrashService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.Purge(ctx)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to purge trash", "error", err)
+		} else if purged > 0 {
+			s.logger.InfoContext(ctx, "Trash purged", "purged", purged, "retention", s.retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/CreateLab/laritmo/internal/repository"
+	"github.com/CreateLab/laritmo/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashService_RunPurge(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore(memory.NewDB())
+	course, err := store.Courses.Create(ctx, "Go", "2025-fall", "")
+	require.NoError(t, err)
+	require.NoError(t, store.Courses.Delete(ctx, course.ID))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	kept := NewTrashService(store.Trash, time.Hour, logger)
+	purged, err := kept.Purge(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged, "fresh records are within retention")
+
+	// Отрицательный срок хранения делает просроченными все записи корзины
+	service := NewTrashService(store.Trash, -time.Minute, logger)
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		service.RunPurge(runCtx, time.Hour)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, total, err := service.List(ctx, repository.TrashFilter{}, repository.ListOptions{})
+		return err == nil && total == 0
+	}, time.Second, 10*time.Millisecond, "first purge runs at start")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunPurge did not stop after cancel")
+	}
+}
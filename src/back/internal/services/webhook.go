@@ -0,0 +1,316 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CreateLab/laritmo/internal/config"
+	"github.com/CreateLab/laritmo/internal/jobs"
+	"github.com/CreateLab/laritmo/internal/models"
+)
+
+// ContentSyncJobType - тип фоновой задачи синхронизации курса по webhook
+const ContentSyncJobType = "content.sync"
+
+var (
+	// ErrWebhookUnknownRepository - push пришёл из репозитория, не указанного в sync.webhooks
+	ErrWebhookUnknownRepository = errors.New("repository is not configured for webhooks")
+	// ErrWebhookSignature - подпись отсутствует или не совпадает с секретом репозитория
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+	// ErrWebhookPayload - тело запроса не является push-событием
+	ErrWebhookPayload = errors.New("invalid push payload")
+)
+
+// ContentSyncEventStore - интерфейс для записи событий синхронизации
+type ContentSyncEventStore interface {
+	Create(ctx context.Context, event *models.ContentSyncEvent) (*models.ContentSyncEvent, error)
+	SetJob(ctx context.Context, id, jobID int) error
+	Finish(ctx context.Context, id int, status string, report *models.ContentSyncReport, errMsg string) error
+}
+
+// JobEnqueuer - интерфейс для постановки фоновых задач в очередь
+type JobEnqueuer interface {
+	Enqueue(ctx context.Context, jobType string, payload any, maxAttempts int) (*models.Job, error)
+}
+
+// WebhookService принимает push-события и ставит синхронизацию курса в очередь
+type WebhookService struct {
+	sync        *config.SyncConfig
+	events      ContentSyncEventStore
+	queue       JobEnqueuer
+	maxAttempts int
+}
+
+func NewWebhookService(sync *config.SyncConfig, events ContentSyncEventStore, queue JobEnqueuer, maxAttempts int) *WebhookService {
+	return &WebhookService{
+		sync:        sync,
+		events:      events,
+		queue:       queue,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// HandlePush проверяет подпись, записывает событие и, если push затронул
+// лекции или лабораторные в отслеживаемой ветке, ставит синхронизацию в очередь
+func (s *WebhookService) HandlePush(ctx context.Context, provider, deliveryID, signature string, body []byte) (*models.ContentSyncEvent, error) {
+	push, err := ParsePushEvent(body)
+	if err != nil {
+		return nil, err
+	}
+
+	source := s.sync.FindWebhook(push.Repository)
+	if source == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWebhookUnknownRepository, push.Repository)
+	}
+	if !VerifyWebhookSignature(provider, source.GetSecret(), body, signature) {
+		return nil, ErrWebhookSignature
+	}
+
+	matched := MatchSyncPaths(push.ChangedPaths, source.LecturesGlob, source.LabsGlob)
+
+	event := &models.ContentSyncEvent{
+		CourseID:     source.CourseID,
+		Provider:     provider,
+		Repository:   push.Repository,
+		Ref:          push.Ref,
+		ChangedPaths: matched,
+		Status:       models.ContentSyncEventQueued,
+	}
+	if deliveryID != "" {
+		event.DeliveryID = &deliveryID
+	}
+	if push.After != "" {
+		event.CommitSHA = &push.After
+	}
+	if push.Ref != "refs/heads/"+source.GetBranch() || len(matched) == 0 {
+		event.Status = models.ContentSyncEventIgnored
+	}
+
+	event, err = s.events.Create(ctx, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record sync event: %w", err)
+	}
+	if event.Status == models.ContentSyncEventIgnored {
+		return event, nil
+	}
+
+	job, err := s.queue.Enqueue(ctx, ContentSyncJobType, models.ContentSyncJobPayload{
+		EventID:    event.ID,
+		Repository: source.Repository,
+	}, s.maxAttempts)
+	if err != nil {
+		if finishErr := s.events.Finish(ctx, event.ID, models.ContentSyncEventFailed, nil, err.Error()); finishErr != nil {
+			return nil, errors.Join(err, finishErr)
+		}
+		return nil, fmt.Errorf("failed to enqueue content sync: %w", err)
+	}
+	if err := s.events.SetJob(ctx, event.ID, job.ID); err != nil {
+		return nil, err
+	}
+	event.JobID = &job.ID
+
+	return event, nil
+}
+
+// VerifyWebhookSignature проверяет HMAC-SHA256 тела запроса.
+// GitHub присылает "sha256=<hex>" в X-Hub-Signature-256, Gitea - "<hex>" в X-Gitea-Signature.
+// Пустой секрет никогда не проходит проверку.
+func VerifyWebhookSignature(provider, secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	if provider == models.WebhookProviderGitHub {
+		var ok bool
+		if signature, ok = strings.CutPrefix(signature, "sha256="); !ok {
+			return false
+		}
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// ParsePushEvent разбирает push-событие; у GitHub и Gitea формат совпадает
+func ParsePushEvent(body []byte) (*models.PushEvent, error) {
+	var payload struct {
+		Ref        string `json:"ref"`
+		After      string `json:"after"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Commits []struct {
+			Added    []string `json:"added"`
+			Modified []string `json:"modified"`
+			Removed  []string `json:"removed"`
+		} `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebhookPayload, err)
+	}
+	if payload.Repository.FullName == "" || payload.Ref == "" {
+		return nil, fmt.Errorf("%w: repository and ref are required", ErrWebhookPayload)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, c := range payload.Commits {
+		for _, list := range [][]string{c.Added, c.Modified, c.Removed} {
+			for _, p := range list {
+				if !seen[p] {
+					seen[p] = true
+					paths = append(paths, p)
+				}
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	return &models.PushEvent{
+		Repository:   payload.Repository.FullName,
+		Ref:          payload.Ref,
+		After:        payload.After,
+		ChangedPaths: paths,
+	}, nil
+}
+
+// MatchSyncPaths оставляет пути, подходящие под шаблоны лекций или лабораторных
+func MatchSyncPaths(paths []string, lecturesGlob, labsGlob string) []string {
+	if lecturesGlob == "" {
+		lecturesGlob = DefaultLecturesGlob
+	}
+	if labsGlob == "" {
+		labsGlob = DefaultLabsGlob
+	}
+
+	matched := []string{}
+	for _, p := range paths {
+		lecture, _ := path.Match(lecturesGlob, p)
+		lab, _ := path.Match(labsGlob, p)
+		if lecture || lab {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// RepoUpdater - интерфейс для получения свежего состояния клона репозитория
+type RepoUpdater interface {
+	Update(ctx context.Context, repoPath, branch string) error
+}
+
+// ContentSyncRunner - интерфейс для синхронизации материалов курса
+type ContentSyncRunner interface {
+	Sync(ctx context.Context, courseID int, opts ContentSyncOptions) (*models.ContentSyncReport, error)
+}
+
+// ContentSyncJob обновляет клон репозитория и синхронизирует курс по событию webhook
+type ContentSyncJob struct {
+	sync    *config.SyncConfig
+	updater RepoUpdater
+	runner  ContentSyncRunner
+	events  ContentSyncEventStore
+	logger  *slog.Logger
+}
+
+func NewContentSyncJob(sync *config.SyncConfig, updater RepoUpdater, runner ContentSyncRunner, events ContentSyncEventStore, logger *slog.Logger) *ContentSyncJob {
+	return &ContentSyncJob{
+		sync:    sync,
+		updater: updater,
+		runner:  runner,
+		events:  events,
+		logger:  logger,
+	}
+}
+
+// Handle выполняет задачу; регистрируется в jobs.Pool под ContentSyncJobType.
+// Событие помечается failed только после последней попытки.
+func (j *ContentSyncJob) Handle(ctx context.Context, job *models.Job) error {
+	var payload models.ContentSyncJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+
+	source := j.sync.FindWebhook(payload.Repository)
+	if source == nil {
+		err := fmt.Errorf("%w: %s", ErrWebhookUnknownRepository, payload.Repository)
+		j.fail(ctx, payload.EventID, err)
+		return jobs.Permanent(err)
+	}
+
+	report, err := j.run(ctx, source)
+	if err != nil {
+		if job.Attempts >= job.MaxAttempts {
+			j.fail(ctx, payload.EventID, err)
+		}
+		return err
+	}
+
+	if err := j.events.Finish(ctx, payload.EventID, models.ContentSyncEventSucceeded, report, ""); err != nil {
+		j.logger.WarnContext(ctx, "Failed to record sync result", "error", err, "event_id", payload.EventID)
+	}
+	return nil
+}
+
+func (j *ContentSyncJob) run(ctx context.Context, source *config.WebhookSource) (*models.ContentSyncReport, error) {
+	repoPath := filepath.Join(j.sync.ReposRoot, source.RepoPath)
+	if err := j.updater.Update(ctx, repoPath, source.GetBranch()); err != nil {
+		return nil, fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	return j.runner.Sync(ctx, source.CourseID, ContentSyncOptions{
+		RepoPath:     repoPath,
+		Ref:          source.GetBranch(),
+		LecturesGlob: source.LecturesGlob,
+		LabsGlob:     source.LabsGlob,
+		GithubBase:   source.GithubBase,
+		Prune:        source.Prune,
+	})
+}
+
+func (j *ContentSyncJob) fail(ctx context.Context, eventID int, cause error) {
+	if err := j.events.Finish(ctx, eventID, models.ContentSyncEventFailed, nil, cause.Error()); err != nil {
+		j.logger.WarnContext(ctx, "Failed to record sync failure", "error", err, "event_id", eventID)
+	}
+}
+
+// GitRepoUpdater подтягивает ветку из origin: bare-репозиторий через fetch, рабочую копию через pull --ff-only
+type GitRepoUpdater struct{}
+
+func (GitRepoUpdater) Update(ctx context.Context, repoPath, branch string) error {
+	if strings.HasPrefix(branch, "-") {
+		return fmt.Errorf("invalid branch %q", branch)
+	}
+
+	var args []string
+	if isBareRepo(repoPath) {
+		args = []string{"--git-dir", repoPath, "fetch", "--prune", "origin", "+refs/heads/" + branch + ":refs/heads/" + branch}
+	} else {
+		args = []string{"-C", repoPath, "pull", "--ff-only", "origin", branch}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/config"
+	"github.com/CreateLab/laritmo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// githubPushPayload - сокращённое push-событие GitHub
+const githubPushPayload = `{
+  "ref": "refs/heads/main",
+  "before": "1111111111111111111111111111111111111111",
+  "after": "a9d3f2c1b8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3",
+  "repository": {"id": 1, "name": "AspITMO", "full_name": "CreateLab/AspITMO"},
+  "pusher": {"name": "teacher"},
+  "commits": [
+    {"id": "f1", "message": "Update lecture 2", "added": [], "modified": ["lections/L2.md", "README.md"], "removed": []},
+    {"id": "a9", "message": "Add lab 3", "added": ["Lab3.md"], "modified": ["lections/L2.md"], "removed": ["lections/L9.md"]}
+  ]
+}`
+
+// giteaPushPayload - сокращённое push-событие Gitea
+const giteaPushPayload = `{
+  "ref": "refs/heads/main",
+  "before": "0000000000000000000000000000000000000000",
+  "after": "5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d",
+  "compare_url": "https://git.example/CreateLab/AspITMO/compare/0000...5e4d",
+  "commits": [
+    {"id": "5e4d", "message": "Fix typo", "added": [], "removed": [], "modified": ["Lab1.md"]}
+  ],
+  "repository": {"id": 7, "name": "AspITMO", "full_name": "createlab/aspitmo"}
+}`
+
+func signBody(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// fakeSyncEventStore - журнал событий синхронизации в памяти
+type fakeSyncEventStore struct {
+	events map[int]*models.ContentSyncEvent
+	nextID int
+}
+
+func newFakeSyncEventStore() *fakeSyncEventStore {
+	return &fakeSyncEventStore{events: make(map[int]*models.ContentSyncEvent)}
+}
+
+func (s *fakeSyncEventStore) Create(ctx context.Context, event *models.ContentSyncEvent) (*models.ContentSyncEvent, error) {
+	s.nextID++
+	e := *event
+	e.ID = s.nextID
+	s.events[e.ID] = &e
+	created := e
+	return &created, nil
+}
+
+func (s *fakeSyncEventStore) SetJob(ctx context.Context, id, jobID int) error {
+	s.events[id].JobID = &jobID
+	return nil
+}
+
+func (s *fakeSyncEventStore) Finish(ctx context.Context, id int, status string, report *models.ContentSyncReport, errMsg string) error {
+	s.events[id].Status = status
+	s.events[id].Report = report
+	if errMsg != "" {
+		s.events[id].Error = &errMsg
+	}
+	return nil
+}
+
+// fakeJobQueue запоминает поставленные задачи
+type fakeJobQueue struct {
+	jobs []*models.Job
+	err  error
+}
+
+func (q *fakeJobQueue) Enqueue(ctx context.Context, jobType string, payload any, maxAttempts int) (*models.Job, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	job := &models.Job{ID: 500 + len(q.jobs), Type: jobType, Payload: data, MaxAttempts: maxAttempts}
+	q.jobs = append(q.jobs, job)
+	return job, nil
+}
+
+func testSyncConfig() *config.SyncConfig {
+	return &config.SyncConfig{
+		ReposRoot: "/srv/repos",
+		Webhooks: []config.WebhookSource{
+			{
+				Repository: "CreateLab/AspITMO",
+				CourseID:   1,
+				RepoPath:   "AspITMO",
+				GithubBase: "https://github.com/CreateLab/AspITMO/blob/main",
+				Secret:     "s3cret",
+			},
+		},
+	}
+}
+
+func TestWebhookService_HandlePush(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      string
+		body          string
+		signature     string
+		expectedErr   error
+		expectedState string
+		expectedPaths []string
+	}{
+		{
+			name:          "github push queues sync",
+			provider:      models.WebhookProviderGitHub,
+			body:          githubPushPayload,
+			signature:     "sha256=" + signBody("s3cret", githubPushPayload),
+			expectedState: models.ContentSyncEventQueued,
+			expectedPaths: []string{"Lab3.md", "lections/L2.md", "lections/L9.md"},
+		},
+		{
+			name:          "gitea push queues sync",
+			provider:      models.WebhookProviderGitea,
+			body:          giteaPushPayload,
+			signature:     signBody("s3cret", giteaPushPayload),
+			expectedState: models.ContentSyncEventQueued,
+			expectedPaths: []string{"Lab1.md"},
+		},
+		{
+			name:        "wrong secret",
+			provider:    models.WebhookProviderGitHub,
+			body:        githubPushPayload,
+			signature:   "sha256=" + signBody("other", githubPushPayload),
+			expectedErr: ErrWebhookSignature,
+		},
+		{
+			name:        "github signature without prefix",
+			provider:    models.WebhookProviderGitHub,
+			body:        githubPushPayload,
+			signature:   signBody("s3cret", githubPushPayload),
+			expectedErr: ErrWebhookSignature,
+		},
+		{
+			name:        "unknown repository",
+			provider:    models.WebhookProviderGitHub,
+			body:        `{"ref": "refs/heads/main", "repository": {"full_name": "someone/else"}}`,
+			expectedErr: ErrWebhookUnknownRepository,
+		},
+		{
+			name:        "not a push",
+			provider:    models.WebhookProviderGitHub,
+			body:        `{"zen": "Keep it logically awesome."}`,
+			expectedErr: ErrWebhookPayload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := newFakeSyncEventStore()
+			queue := &fakeJobQueue{}
+			service := NewWebhookService(testSyncConfig(), events, queue, 3)
+
+			event, err := service.HandlePush(context.Background(), tt.provider, "delivery-1", tt.signature, []byte(tt.body))
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, events.events)
+				assert.Empty(t, queue.jobs)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedState, event.Status)
+			assert.Equal(t, 1, event.CourseID)
+			assert.Equal(t, tt.provider, event.Provider)
+			assert.Equal(t, tt.expectedPaths, event.ChangedPaths)
+			require.Len(t, queue.jobs, 1)
+			assert.Equal(t, ContentSyncJobType, queue.jobs[0].Type)
+			assert.Equal(t, 3, queue.jobs[0].MaxAttempts)
+			assert.Equal(t, queue.jobs[0].ID, *events.events[event.ID].JobID)
+
+			var payload models.ContentSyncJobPayload
+			require.NoError(t, json.Unmarshal(queue.jobs[0].Payload, &payload))
+			assert.Equal(t, models.ContentSyncJobPayload{EventID: event.ID, Repository: "CreateLab/AspITMO"}, payload)
+		})
+	}
+}
+
+func TestWebhookService_HandlePush_Ignored(t *testing.T) {
+	otherBranch := `{"ref": "refs/heads/draft", "after": "b1", "repository": {"full_name": "CreateLab/AspITMO"},
+		"commits": [{"modified": ["lections/L1.md"]}]}`
+	readmeOnly := `{"ref": "refs/heads/main", "after": "b2", "repository": {"full_name": "CreateLab/AspITMO"},
+		"commits": [{"modified": ["README.md", "src/Program.cs"]}]}`
+
+	for _, body := range []string{otherBranch, readmeOnly} {
+		events := newFakeSyncEventStore()
+		queue := &fakeJobQueue{}
+		service := NewWebhookService(testSyncConfig(), events, queue, 3)
+
+		event, err := service.HandlePush(context.Background(), models.WebhookProviderGitea, "", signBody("s3cret", body), []byte(body))
+		require.NoError(t, err)
+		assert.Equal(t, models.ContentSyncEventIgnored, event.Status)
+		assert.Nil(t, event.DeliveryID)
+		assert.Len(t, events.events, 1)
+		assert.Empty(t, queue.jobs)
+	}
+}
+
+func TestWebhookService_HandlePush_EnqueueFailure(t *testing.T) {
+	events := newFakeSyncEventStore()
+	service := NewWebhookService(testSyncConfig(), events, &fakeJobQueue{err: errors.New("db is down")}, 3)
+
+	_, err := service.HandlePush(context.Background(), models.WebhookProviderGitea, "d1", signBody("s3cret", giteaPushPayload), []byte(giteaPushPayload))
+	require.Error(t, err)
+	require.Len(t, events.events, 1)
+	assert.Equal(t, models.ContentSyncEventFailed, events.events[1].Status)
+}
+
+func TestVerifyWebhookSignature_EmptySecret(t *testing.T) {
+	body := []byte(giteaPushPayload)
+	assert.False(t, VerifyWebhookSignature(models.WebhookProviderGitea, "", body, signBody("", giteaPushPayload)))
+	assert.False(t, VerifyWebhookSignature(models.WebhookProviderGitea, "s3cret", body, "not-hex"))
+}
+
+// fakeRepoUpdater запоминает обновлённые клоны и может вернуть ошибку
+type fakeRepoUpdater struct {
+	paths []string
+	err   error
+}
+
+func (u *fakeRepoUpdater) Update(ctx context.Context, repoPath, branch string) error {
+	u.paths = append(u.paths, repoPath+"@"+branch)
+	return u.err
+}
+
+// fakeSyncRunner возвращает заданный отчёт и запоминает параметры
+type fakeSyncRunner struct {
+	opts ContentSyncOptions
+}
+
+func (r *fakeSyncRunner) Sync(ctx context.Context, courseID int, opts ContentSyncOptions) (*models.ContentSyncReport, error) {
+	r.opts = opts
+	return &models.ContentSyncReport{CourseID: courseID, Lectures: models.ContentSyncDiff{Updated: []int{2}}}, nil
+}
+
+func TestContentSyncJob_Handle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	payload, _ := json.Marshal(models.ContentSyncJobPayload{EventID: 1, Repository: "CreateLab/AspITMO"})
+
+	t.Run("success", func(t *testing.T) {
+		events := newFakeSyncEventStore()
+		events.events[1] = &models.ContentSyncEvent{ID: 1, Status: models.ContentSyncEventQueued}
+		updater := &fakeRepoUpdater{}
+		runner := &fakeSyncRunner{}
+
+		job := NewContentSyncJob(testSyncConfig(), updater, runner, events, logger)
+		err := job.Handle(context.Background(), &models.Job{Payload: payload, Attempts: 1, MaxAttempts: 3})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"/srv/repos/AspITMO@main"}, updater.paths)
+		assert.Equal(t, "/srv/repos/AspITMO", runner.opts.RepoPath)
+		assert.Equal(t, "main", runner.opts.Ref)
+		assert.Equal(t, "https://github.com/CreateLab/AspITMO/blob/main", runner.opts.GithubBase)
+		assert.Equal(t, models.ContentSyncEventSucceeded, events.events[1].Status)
+		assert.Equal(t, []int{2}, events.events[1].Report.Lectures.Updated)
+	})
+
+	t.Run("failure is recorded on last attempt only", func(t *testing.T) {
+		events := newFakeSyncEventStore()
+		events.events[1] = &models.ContentSyncEvent{ID: 1, Status: models.ContentSyncEventQueued}
+		updater := &fakeRepoUpdater{err: errors.New("network is unreachable")}
+
+		job := NewContentSyncJob(testSyncConfig(), updater, &fakeSyncRunner{}, events, logger)
+
+		err := job.Handle(context.Background(), &models.Job{Payload: payload, Attempts: 1, MaxAttempts: 2})
+		require.Error(t, err)
+		assert.Equal(t, models.ContentSyncEventQueued, events.events[1].Status)
+
+		err = job.Handle(context.Background(), &models.Job{Payload: payload, Attempts: 2, MaxAttempts: 2})
+		require.Error(t, err)
+		assert.Equal(t, models.ContentSyncEventFailed, events.events[1].Status)
+		assert.Contains(t, *events.events[1].Error, "network is unreachable")
+	})
+}
@@ -0,0 +1,37 @@
+// Package storage хранит файлы вложений: в каталоге на диске или в S3-совместимом
+// объектном хранилище. Ключ объекта - путь из сегментов через "/", его выбирает вызывающий код.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound - объекта с таким ключом нет
+var ErrNotFound = errors.New("object not found")
+
+// Storage - хранилище объектов
+type Storage interface {
+	// Put сохраняет объект из size байт r, заменяя существующий с тем же ключом
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get открывает объект на чтение; если его нет, возвращает ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект; отсутствие объекта ошибкой не считается
+	Delete(ctx context.Context, key string) error
+}
+
+// checkKey не пускает ключи, которые вышли бы за пределы каталога или бакета
+func checkKey(key string) error {
+	if key == "" || strings.Contains(key, `\`) {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Package migrations встраивает SQL-миграции в бинарники, чтобы сервер и
+// laritmo-admin могли применять их без каталога migrations рядом с собой.
+// Для каждой СУБД свой каталог с одинаковыми версиями миграций.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/CreateLab/laritmo/internal/database"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// For возвращает миграции в формате goose для диалекта
+func For(dialect database.Dialect) (fs.FS, error) {
+	return fs.Sub(files, string(dialect))
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/CreateLab/laritmo/internal/database"
+	"github.com/CreateLab/laritmo/internal/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dialects = []database.Dialect{database.MySQL, database.Postgres, database.SQLite}
+
+// Встроенный набор должен совпадать с файлами каталога, иначе бинарник применит устаревшие миграции
+func TestFor_MatchesDirectory(t *testing.T) {
+	for _, dialect := range dialects {
+		fsys, err := For(dialect)
+		require.NoError(t, err)
+
+		embedded, err := fs.Glob(fsys, "*.sql")
+		require.NoError(t, err)
+
+		onDisk, err := fs.Glob(os.DirFS(string(dialect)), "*.sql")
+		require.NoError(t, err)
+
+		assert.Equal(t, onDisk, embedded, dialect)
+	}
+}
+
+func TestFor_Parses(t *testing.T) {
+	for _, dialect := range dialects {
+		fsys, err := For(dialect)
+		require.NoError(t, err)
+
+		loaded, err := migrate.Load(fsys)
+		require.NoError(t, err)
+		require.NotEmpty(t, loaded)
+
+		for _, m := range loaded {
+			assert.NotEmpty(t, m.Up, "%s %d_%s has no up statements", dialect, m.Version, m.Name)
+			assert.NotEmpty(t, m.Down, "%s %d_%s has no down statements", dialect, m.Version, m.Name)
+		}
+	}
+}
+
+// Схемы диалектов развиваются синхронно: каждая миграция есть в обоих каталогах
+func TestFor_SameVersions(t *testing.T) {
+	names := make(map[database.Dialect][]string)
+	for _, dialect := range dialects {
+		fsys, err := For(dialect)
+		require.NoError(t, err)
+
+		loaded, err := migrate.Load(fsys)
+		require.NoError(t, err)
+
+		for _, m := range loaded {
+			names[dialect] = append(names[dialect], m.Name)
+		}
+	}
+
+	assert.Equal(t, names[database.MySQL], names[database.Postgres])
+	assert.Equal(t, names[database.MySQL], names[database.SQLite])
+}
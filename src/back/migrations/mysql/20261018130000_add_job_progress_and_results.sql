@@ -0,0 +1,17 @@
+-- +goose Up
+
+ALTER TABLE jobs ADD COLUMN progress INT NOT NULL DEFAULT 0 AFTER status;
+
+CREATE TABLE IF NOT EXISTS job_results (
+    job_id INT PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    content LONGBLOB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- +goose Down
+
+DROP TABLE IF EXISTS job_results;
+ALTER TABLE jobs DROP COLUMN progress;
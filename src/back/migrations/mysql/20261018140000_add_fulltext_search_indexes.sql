@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- InnoDB FULLTEXT разбивает текст по пробелам и пунктуации, поэтому
+-- одинаково работает для русского и английского; словоформы учитываются
+-- на стороне приложения через префиксный поиск (term*)
+ALTER TABLE lectures ADD FULLTEXT INDEX ft_lectures_title_content (title, content);
+ALTER TABLE labs ADD FULLTEXT INDEX ft_labs_title_description (title, description);
+ALTER TABLE exam_questions ADD FULLTEXT INDEX ft_exam_questions_section_question (section, question);
+
+-- +goose Down
+
+ALTER TABLE exam_questions DROP INDEX ft_exam_questions_section_question;
+ALTER TABLE labs DROP INDEX ft_labs_title_description;
+ALTER TABLE lectures DROP INDEX ft_lectures_title_content;
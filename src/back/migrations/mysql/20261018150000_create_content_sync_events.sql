@@ -0,0 +1,24 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS content_sync_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    delivery_id VARCHAR(100),
+    repository VARCHAR(255) NOT NULL,
+    ref VARCHAR(255) NOT NULL,
+    commit_sha VARCHAR(64),
+    changed_paths JSON NOT NULL,
+    status ENUM('ignored', 'queued', 'succeeded', 'failed') NOT NULL,
+    report JSON NULL,
+    error TEXT,
+    job_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    INDEX idx_course_created (course_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- +goose Down
+
+DROP TABLE IF EXISTS content_sync_events;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+ALTER TABLE users
+    ADD COLUMN disabled_at TIMESTAMP NULL AFTER role;
+
+ALTER TABLE courses
+    ADD COLUMN archived_at TIMESTAMP NULL AFTER description,
+    ADD INDEX idx_archived_at (archived_at);
+
+-- +goose Down
+
+ALTER TABLE courses
+    DROP INDEX idx_archived_at,
+    DROP COLUMN archived_at;
+
+ALTER TABLE users
+    DROP COLUMN disabled_at;
@@ -0,0 +1,43 @@
+-- +goose Up
+
+ALTER TABLE courses
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+ALTER TABLE lectures
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+ALTER TABLE labs
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+ALTER TABLE grade_sheets
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+ALTER TABLE exam_questions
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+-- +goose Down
+
+ALTER TABLE exam_questions
+    DROP INDEX idx_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE grade_sheets
+    DROP INDEX idx_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE labs
+    DROP INDEX idx_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE lectures
+    DROP INDEX idx_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE courses
+    DROP INDEX idx_deleted_at,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,33 @@
+-- +goose Up
+
+ALTER TABLE lectures
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published',
+    ADD COLUMN publish_at TIMESTAMP NULL,
+    ADD INDEX idx_status_publish_at (status, publish_at);
+
+ALTER TABLE labs
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published',
+    ADD COLUMN publish_at TIMESTAMP NULL,
+    ADD INDEX idx_status_publish_at (status, publish_at);
+
+ALTER TABLE grade_sheets
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published',
+    ADD COLUMN publish_at TIMESTAMP NULL,
+    ADD INDEX idx_status_publish_at (status, publish_at);
+
+-- +goose Down
+
+ALTER TABLE grade_sheets
+    DROP INDEX idx_status_publish_at,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
+
+ALTER TABLE labs
+    DROP INDEX idx_status_publish_at,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
+
+ALTER TABLE lectures
+    DROP INDEX idx_status_publish_at,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
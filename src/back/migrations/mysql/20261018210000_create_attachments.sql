@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- Ссылки обнуляются, а не удаляются каскадом: файл строки без владельца ещё лежит
+-- в хранилище, и его удаляет фоновая очистка вложений
+CREATE TABLE IF NOT EXISTS attachments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NULL,
+    lecture_id INT NULL,
+    lab_id INT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE SET NULL,
+    FOREIGN KEY (lecture_id) REFERENCES lectures(id) ON DELETE SET NULL,
+    FOREIGN KEY (lab_id) REFERENCES labs(id) ON DELETE SET NULL,
+    INDEX idx_course (course_id),
+    INDEX idx_lecture (lecture_id),
+    INDEX idx_lab (lab_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- +goose Down
+
+DROP TABLE IF EXISTS attachments;
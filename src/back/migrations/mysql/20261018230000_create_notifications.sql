@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- Настройки email-уведомлений; пользователь без строки получает все письма
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    new_lab BOOLEAN NOT NULL DEFAULT TRUE,
+    deadline_reminder BOOLEAN NOT NULL DEFAULT TRUE,
+    grade_posted BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Напоминания о дедлайне: deadline - дедлайн, о котором напомнили; после переноса напоминание придёт снова
+CREATE TABLE IF NOT EXISTS lab_deadline_reminders (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    lab_id INT NOT NULL,
+    deadline TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_lab (lab_id),
+    FOREIGN KEY (lab_id) REFERENCES labs(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- +goose Down
+
+DROP TABLE IF EXISTS lab_deadline_reminders;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,18 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS exam_questions (
+    id SERIAL PRIMARY KEY,
+    lecture_id INT NOT NULL REFERENCES lectures(id) ON DELETE CASCADE,
+    question_type VARCHAR(50) NOT NULL,
+    question_text TEXT NOT NULL,
+    correct_answer TEXT,
+    options JSONB,
+    points INT DEFAULT 1,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_exam_questions_lecture_id ON exam_questions (lecture_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS exam_questions;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+DROP TABLE IF EXISTS exam_questions;
+
+CREATE TABLE exam_questions (
+    id SERIAL PRIMARY KEY,
+    course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    number INT NOT NULL,
+    section VARCHAR(255) NOT NULL,
+    question TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_exam_questions_course_id ON exam_questions (course_id);
+CREATE INDEX idx_exam_questions_course_section_number ON exam_questions (course_id, section, number);
+CREATE TRIGGER exam_questions_updated_at BEFORE UPDATE ON exam_questions FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- +goose Down
+
+DROP TABLE IF EXISTS exam_questions;
@@ -0,0 +1,16 @@
+-- +goose Up
+
+ALTER TABLE jobs ADD COLUMN progress INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS job_results (
+    job_id INT PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    content BYTEA NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS job_results;
+ALTER TABLE jobs DROP COLUMN progress;
@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- Конфигурация simple не зависит от языка, как и InnoDB FULLTEXT в MySQL;
+-- словоформы учитываются на стороне приложения через префиксный поиск (term:*).
+-- Выражения должны совпадать с запросами в repository/search.go, иначе индекс не используется
+CREATE INDEX ft_lectures_title_content ON lectures USING GIN (to_tsvector('simple', title || ' ' || content));
+CREATE INDEX ft_labs_title_description ON labs USING GIN (to_tsvector('simple', title || ' ' || description));
+CREATE INDEX ft_exam_questions_section_question ON exam_questions USING GIN (to_tsvector('simple', section || ' ' || question));
+
+-- +goose Down
+
+DROP INDEX IF EXISTS ft_exam_questions_section_question;
+DROP INDEX IF EXISTS ft_labs_title_description;
+DROP INDEX IF EXISTS ft_lectures_title_content;
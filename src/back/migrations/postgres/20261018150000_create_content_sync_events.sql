@@ -0,0 +1,24 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS content_sync_events (
+    id SERIAL PRIMARY KEY,
+    course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    delivery_id VARCHAR(100),
+    repository VARCHAR(255) NOT NULL,
+    ref VARCHAR(255) NOT NULL,
+    commit_sha VARCHAR(64),
+    changed_paths JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('ignored', 'queued', 'succeeded', 'failed')),
+    report JSONB NULL,
+    error TEXT,
+    job_id INT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_content_sync_events_course_created ON content_sync_events (course_id, created_at);
+CREATE TRIGGER content_sync_events_updated_at BEFORE UPDATE ON content_sync_events FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- +goose Down
+
+DROP TABLE IF EXISTS content_sync_events;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ NULL;
+
+ALTER TABLE courses ADD COLUMN archived_at TIMESTAMPTZ NULL;
+CREATE INDEX idx_courses_archived_at ON courses (archived_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_courses_archived_at;
+ALTER TABLE courses DROP COLUMN archived_at;
+
+ALTER TABLE users DROP COLUMN disabled_at;
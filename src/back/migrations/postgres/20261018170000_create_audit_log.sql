@@ -0,0 +1,22 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    user_id INT NULL,
+    username VARCHAR(100) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id INT NULL,
+    before_data JSONB NULL,
+    after_data JSONB NULL,
+    ip VARCHAR(45) NOT NULL,
+    request_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_audit_log_created ON audit_log (created_at);
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id);
+CREATE INDEX idx_audit_log_user ON audit_log (user_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS audit_log;
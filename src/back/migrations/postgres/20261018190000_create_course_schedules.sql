@@ -0,0 +1,25 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS course_schedules (
+    id SERIAL PRIMARY KEY,
+    course_id INT NOT NULL UNIQUE REFERENCES courses(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TRIGGER course_schedules_updated_at BEFORE UPDATE ON course_schedules FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS course_timetable_slots (
+    id SERIAL PRIMARY KEY,
+    course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL,
+    start_time CHAR(5) NOT NULL,
+    duration_minutes INT NOT NULL,
+    room VARCHAR(100) NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_course_timetable_slots_course ON course_timetable_slots (course_id, weekday, start_time);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS course_timetable_slots;
+DROP TABLE IF EXISTS course_schedules;
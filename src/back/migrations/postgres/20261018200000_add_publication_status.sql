@@ -0,0 +1,27 @@
+-- +goose Up
+
+ALTER TABLE lectures ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published';
+ALTER TABLE lectures ADD COLUMN publish_at TIMESTAMPTZ NULL;
+CREATE INDEX idx_lectures_status_publish_at ON lectures (status, publish_at);
+
+ALTER TABLE labs ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published';
+ALTER TABLE labs ADD COLUMN publish_at TIMESTAMPTZ NULL;
+CREATE INDEX idx_labs_status_publish_at ON labs (status, publish_at);
+
+ALTER TABLE grade_sheets ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'published';
+ALTER TABLE grade_sheets ADD COLUMN publish_at TIMESTAMPTZ NULL;
+CREATE INDEX idx_grade_sheets_status_publish_at ON grade_sheets (status, publish_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_grade_sheets_status_publish_at;
+ALTER TABLE grade_sheets DROP COLUMN publish_at;
+ALTER TABLE grade_sheets DROP COLUMN status;
+
+DROP INDEX IF EXISTS idx_labs_status_publish_at;
+ALTER TABLE labs DROP COLUMN publish_at;
+ALTER TABLE labs DROP COLUMN status;
+
+DROP INDEX IF EXISTS idx_lectures_status_publish_at;
+ALTER TABLE lectures DROP COLUMN publish_at;
+ALTER TABLE lectures DROP COLUMN status;
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- Ссылки обнуляются, а не удаляются каскадом: файл строки без владельца ещё лежит
+-- в хранилище, и его удаляет фоновая очистка вложений
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    course_id INT NULL REFERENCES courses(id) ON DELETE SET NULL,
+    lecture_id INT NULL REFERENCES lectures(id) ON DELETE SET NULL,
+    lab_id INT NULL REFERENCES labs(id) ON DELETE SET NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_attachments_course ON attachments (course_id);
+CREATE INDEX idx_attachments_lecture ON attachments (lecture_id);
+CREATE INDEX idx_attachments_lab ON attachments (lab_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- Настройки email-уведомлений; пользователь без строки получает все письма
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    new_lab BOOLEAN NOT NULL DEFAULT TRUE,
+    deadline_reminder BOOLEAN NOT NULL DEFAULT TRUE,
+    grade_posted BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TRIGGER notification_preferences_updated_at BEFORE UPDATE ON notification_preferences FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Напоминания о дедлайне: deadline - дедлайн, о котором напомнили; после переноса напоминание придёт снова
+CREATE TABLE IF NOT EXISTS lab_deadline_reminders (
+    id SERIAL PRIMARY KEY,
+    lab_id INT NOT NULL UNIQUE REFERENCES labs(id) ON DELETE CASCADE,
+    deadline TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS lab_deadline_reminders;
+DROP TABLE IF EXISTS notification_preferences;
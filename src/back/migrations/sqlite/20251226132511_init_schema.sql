@@ -0,0 +1,78 @@
+-- +goose Up
+
+-- updated_at в MySQL обновляется через ON UPDATE CURRENT_TIMESTAMP, здесь - триггерами
+CREATE TABLE IF NOT EXISTS courses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    semester VARCHAR(50) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_courses_semester ON courses (semester);
+-- +goose StatementBegin
+CREATE TRIGGER courses_updated_at AFTER UPDATE ON courses FOR EACH ROW
+BEGIN
+    UPDATE courses SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+CREATE TABLE IF NOT EXISTS lectures (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    week INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    github_url VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_lectures_course_week ON lectures (course_id, week);
+-- +goose StatementBegin
+CREATE TRIGGER lectures_updated_at AFTER UPDATE ON lectures FOR EACH ROW
+BEGIN
+    UPDATE lectures SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+CREATE TABLE IF NOT EXISTS labs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    deadline TIMESTAMP NULL,
+    max_score INTEGER DEFAULT 100,
+    github_url VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_labs_course_number ON labs (course_id, number);
+-- +goose StatementBegin
+CREATE TRIGGER labs_updated_at AFTER UPDATE ON labs FOR EACH ROW
+BEGIN
+    UPDATE labs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+CREATE TABLE IF NOT EXISTS grade_sheets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    sheet_url VARCHAR(500) NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementBegin
+CREATE TRIGGER grade_sheets_updated_at AFTER UPDATE ON grade_sheets FOR EACH ROW
+BEGIN
+    UPDATE grade_sheets SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TABLE IF EXISTS grade_sheets;
+DROP TABLE IF EXISTS labs;
+DROP TABLE IF EXISTS lectures;
+DROP TABLE IF EXISTS courses;
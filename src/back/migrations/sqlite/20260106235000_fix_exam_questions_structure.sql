@@ -0,0 +1,25 @@
+-- +goose Up
+
+DROP TABLE IF EXISTS exam_questions;
+
+CREATE TABLE exam_questions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    section VARCHAR(255) NOT NULL,
+    question TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_exam_questions_course_id ON exam_questions (course_id);
+CREATE INDEX idx_exam_questions_course_section_number ON exam_questions (course_id, section, number);
+-- +goose StatementBegin
+CREATE TRIGGER exam_questions_updated_at AFTER UPDATE ON exam_questions FOR EACH ROW
+BEGIN
+    UPDATE exam_questions SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TABLE IF EXISTS exam_questions;
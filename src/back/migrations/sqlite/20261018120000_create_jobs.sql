@@ -0,0 +1,29 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'succeeded', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    last_error TEXT,
+    run_at TIMESTAMP NOT NULL,
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_jobs_status_run_at ON jobs (status, run_at);
+CREATE INDEX idx_jobs_status_locked_until ON jobs (status, locked_until);
+-- +goose StatementBegin
+CREATE TRIGGER jobs_updated_at AFTER UPDATE ON jobs FOR EACH ROW
+BEGIN
+    UPDATE jobs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- В SQLite поиск идёт через LIKE (см. repository/search.go), индексы не нужны;
+-- миграция оставлена, чтобы версии совпадали с другими диалектами
+SELECT 1;
+
+-- +goose Down
+
+SELECT 1;
@@ -0,0 +1,29 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS content_sync_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    delivery_id VARCHAR(100),
+    repository VARCHAR(255) NOT NULL,
+    ref VARCHAR(255) NOT NULL,
+    commit_sha VARCHAR(64),
+    changed_paths TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('ignored', 'queued', 'succeeded', 'failed')),
+    report TEXT NULL,
+    error TEXT,
+    job_id INTEGER NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_content_sync_events_course_created ON content_sync_events (course_id, created_at);
+-- +goose StatementBegin
+CREATE TRIGGER content_sync_events_updated_at AFTER UPDATE ON content_sync_events FOR EACH ROW
+BEGIN
+    UPDATE content_sync_events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TABLE IF EXISTS content_sync_events;
@@ -0,0 +1,33 @@
+-- +goose Up
+
+ALTER TABLE courses ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_courses_deleted_at ON courses (deleted_at);
+
+ALTER TABLE lectures ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_lectures_deleted_at ON lectures (deleted_at);
+
+ALTER TABLE labs ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_labs_deleted_at ON labs (deleted_at);
+
+ALTER TABLE grade_sheets ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_grade_sheets_deleted_at ON grade_sheets (deleted_at);
+
+ALTER TABLE exam_questions ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_exam_questions_deleted_at ON exam_questions (deleted_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_exam_questions_deleted_at;
+ALTER TABLE exam_questions DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_grade_sheets_deleted_at;
+ALTER TABLE grade_sheets DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_labs_deleted_at;
+ALTER TABLE labs DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_lectures_deleted_at;
+ALTER TABLE lectures DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_courses_deleted_at;
+ALTER TABLE courses DROP COLUMN deleted_at;
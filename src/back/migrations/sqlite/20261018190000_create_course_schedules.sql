@@ -0,0 +1,30 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS course_schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL UNIQUE REFERENCES courses(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementBegin
+CREATE TRIGGER course_schedules_updated_at AFTER UPDATE ON course_schedules FOR EACH ROW
+BEGIN
+    UPDATE course_schedules SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+CREATE TABLE IF NOT EXISTS course_timetable_slots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    weekday INTEGER NOT NULL,
+    start_time CHAR(5) NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    room VARCHAR(100) NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_course_timetable_slots_course ON course_timetable_slots (course_id, weekday, start_time);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS course_timetable_slots;
+DROP TABLE IF EXISTS course_schedules;
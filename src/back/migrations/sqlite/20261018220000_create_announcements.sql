@@ -0,0 +1,23 @@
+-- +goose Up
+
+-- kind: manual - написано преподавателем, lab_deadline и lecture_published - созданы сервером
+CREATE TABLE IF NOT EXISTS announcements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    kind VARCHAR(32) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_announcements_course_created ON announcements (course_id, created_at);
+-- +goose StatementBegin
+CREATE TRIGGER announcements_updated_at AFTER UPDATE ON announcements FOR EACH ROW
+BEGIN
+    UPDATE announcements SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+
+DROP TABLE IF EXISTS announcements;
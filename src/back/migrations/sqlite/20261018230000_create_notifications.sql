@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- Настройки email-уведомлений; пользователь без строки получает все письма
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    new_lab BOOLEAN NOT NULL DEFAULT TRUE,
+    deadline_reminder BOOLEAN NOT NULL DEFAULT TRUE,
+    grade_posted BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementBegin
+CREATE TRIGGER notification_preferences_updated_at AFTER UPDATE ON notification_preferences FOR EACH ROW
+BEGIN
+    UPDATE notification_preferences SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- Напоминания о дедлайне: deadline - дедлайн, о котором напомнили; после переноса напоминание придёт снова
+CREATE TABLE IF NOT EXISTS lab_deadline_reminders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    lab_id INTEGER NOT NULL UNIQUE REFERENCES labs(id) ON DELETE CASCADE,
+    deadline TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS lab_deadline_reminders;
+DROP TABLE IF EXISTS notification_preferences;